@@ -365,6 +365,7 @@ func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status
 		updates["swapheight"] = 0
 		updates["swaptime"] = 0
 		updates["swapnonce"] = 0
+		updates["swapexpiry"] = 0
 	}
 	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
 	if err == nil {
@@ -375,6 +376,23 @@ func UpdateRouterSwapResultStatus(fromChainID, txid string, logindex int, status
 	return mgoError(err)
 }
 
+// UpdateRouterSwapNonce update swap nonce of the swap tx rebuilt with fresh account data (eg. cosmos sequence),
+// unlike UpdateRouterSwapResult it overwrites the swap nonce already set.
+func UpdateRouterSwapNonce(fromChainID, txid string, logindex int, swapnonce uint64) error {
+	updateResultLock.Lock()
+	defer updateResultLock.Unlock()
+
+	key := GetRouterSwapKey(fromChainID, txid, logindex)
+	updates := bson.M{"swapnonce": swapnonce, "timestamp": time.Now().Unix()}
+	_, err := collRouterSwapResult.UpdateByID(clientCtx, key, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update swap nonce success", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce)
+	} else {
+		log.Error("mongodb update swap nonce failed", "chainid", fromChainID, "txid", txid, "logindex", logindex, "swapnonce", swapnonce, "err", err)
+	}
+	return mgoError(err)
+}
+
 // UpdateRouterOldSwapTxs update old swaptxs by appending `swapTx`
 func UpdateRouterOldSwapTxs(fromChainID, txid string, logindex int, swapTx string) error {
 	if swapTx == "" {
@@ -741,6 +759,9 @@ func UpdateRouterSwapResult(fromChainID, txid string, logindex int, items *SwapR
 	if items.SwapValue != "" {
 		updates["swapvalue"] = items.SwapValue
 	}
+	if items.SwapExpiry != 0 {
+		updates["swapexpiry"] = items.SwapExpiry
+	}
 	if items.Memo != "" {
 		updates["memo"] = items.Memo
 	} else if items.Status == MatchTxNotStable {
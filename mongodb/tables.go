@@ -98,6 +98,7 @@ type MgoSwapResult struct {
 	SwapTime    uint64     `bson:"swaptime"`
 	SwapValue   string     `bson:"swapvalue"`
 	SwapNonce   uint64     `bson:"swapnonce"`
+	SwapExpiry  uint64     `bson:"swapexpiry,omitempty" json:"swapexpiry,omitempty"`
 	Status      SwapStatus `bson:"status"`
 	InitTime    int64      `bson:"inittime"`
 	Timestamp   int64      `bson:"timestamp"`
@@ -119,6 +120,7 @@ type SwapResultUpdateItems struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapExpiry uint64
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
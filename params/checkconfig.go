@@ -58,6 +58,10 @@ func (config *RouterConfig) CheckConfig(isServer bool) (err error) {
 			return err
 		}
 	}
+	err = config.Extra.CheckTestMode()
+	if err != nil {
+		return err
+	}
 
 	if isServer {
 		err = config.Server.CheckConfig()
@@ -20,9 +20,14 @@ const (
 // CustomizeConfigFunc customize config items
 var CustomizeConfigFunc func(*RouterConfig)
 
-// IsTestMode used for testing
+// IsTestMode used for testing.
+// It bypasses safety checks (eg. the to chain ID check when building tx),
+// so it is refused at startup unless `AllowTestMode` of extra config is also set.
 var IsTestMode bool
 
+// ErrTestModeNotAllowed test mode is enabled without the confirmation flag
+var ErrTestModeNotAllowed = errors.New("test mode is not allowed without 'AllowTestMode' in extra config")
+
 // IsReload is reloading config
 var IsReload bool
 
@@ -164,6 +169,7 @@ type ExtraConfig struct {
 	UsePendingBalance     bool `toml:",omitempty" json:",omitempty"`
 	DontPanicInInitRouter bool `toml:",omitempty" json:",omitempty"`
 	DontCheckInInitRouter bool `toml:",omitempty" json:",omitempty"`
+	AllowTestMode         bool `toml:",omitempty" json:",omitempty"` // confirm test mode is intended
 
 	MinReserveFee    map[string]uint64 `toml:",omitempty" json:",omitempty"`
 	BaseFeePercent   map[string]int64  `toml:",omitempty" json:",omitempty"` // key is chain ID
@@ -210,6 +216,9 @@ type LocalChainConfig struct {
 	ChargeFeeOnDestChain   map[string][]string `toml:",omitempty" json:",omitempty"`
 	FeeReceiverOnDestChain string              `toml:",omitempty" json:",omitempty"`
 
+	// tokenID -> decimals
+	TokenDecimalsOverride map[string]uint8 `toml:",omitempty" json:",omitempty"`
+
 	forbidSwapoutTokenIDMap map[string]struct{}
 
 	lock *sync.Mutex
@@ -778,6 +787,19 @@ func GetExtraConfig() *ExtraConfig {
 	return routerConfig.Extra
 }
 
+// CheckTestMode refuse test mode unless it is confirmed by `AllowTestMode`
+func (c *ExtraConfig) CheckTestMode() error {
+	if !IsTestMode {
+		return nil
+	}
+	if c == nil || !c.AllowTestMode {
+		log.Error("test mode is enabled without confirmation, it bypasses safety checks and must not be used in production")
+		return ErrTestModeNotAllowed
+	}
+	log.Warn("TEST MODE IS ENABLED, safety checks are bypassed, do not use it in production")
+	return nil
+}
+
 // SetExtraConfig set extra config (used by testing)
 func SetExtraConfig(extra *ExtraConfig) error {
 	if err := extra.CheckConfig(); err != nil {
@@ -1307,6 +1329,17 @@ func ChargeFeeOnDestChain(tokenID, fromChainID, toChainID string) bool {
 	return false
 }
 
+// GetTokenDecimalsOverride get the authoritative decimals of the token on the chain
+func GetTokenDecimalsOverride(chainID, tokenID string) (decimals uint8, exist bool) {
+	c := GetLocalChainConfig(chainID)
+	for tid, decimals := range c.TokenDecimalsOverride {
+		if strings.EqualFold(tid, tokenID) {
+			return decimals, true
+		}
+	}
+	return 0, false
+}
+
 // GetAttestationServer get attestation server
 func GetAttestationServer() string {
 	if GetExtraConfig() != nil {
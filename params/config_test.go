@@ -0,0 +1,33 @@
+package params
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckTestMode(t *testing.T) {
+	defer func(isTestMode bool) { IsTestMode = isTestMode }(IsTestMode)
+
+	IsTestMode = false
+	if err := (*ExtraConfig)(nil).CheckTestMode(); err != nil {
+		t.Errorf("check test mode should pass if test mode is disabled, but have %v", err)
+	}
+
+	IsTestMode = true
+	if err := (*ExtraConfig)(nil).CheckTestMode(); !errors.Is(err, ErrTestModeNotAllowed) {
+		t.Errorf("test mode without extra config should fail with %v, but have %v", ErrTestModeNotAllowed, err)
+	}
+	if err := (&ExtraConfig{}).CheckTestMode(); !errors.Is(err, ErrTestModeNotAllowed) {
+		t.Errorf("test mode without confirmation should fail with %v, but have %v", ErrTestModeNotAllowed, err)
+	}
+	if err := (&ExtraConfig{AllowTestMode: true}).CheckTestMode(); err != nil {
+		t.Errorf("confirmed test mode should pass, but have %v", err)
+	}
+
+	config := NewRouterConfig()
+	config.Identifier = RouterSwapPrefixID + "_test"
+	config.SwapType = "erc20swap"
+	if err := config.CheckConfig(false); !errors.Is(err, ErrTestModeNotAllowed) {
+		t.Errorf("router config check should refuse unconfirmed test mode, but have %v", err)
+	}
+}
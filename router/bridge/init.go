@@ -136,6 +136,13 @@ func InitRouterBridges(isServer bool) {
 				}
 				wg2.Wait()
 			}
+
+			if checker, ok := bridge.(interface{ CheckTokenConfigs() error }); ok {
+				if err := checker.CheckTokenConfigs(); err != nil {
+					logErrFunc("check token configs failed", "chainID", chainID, "err", err)
+					return
+				}
+			}
 		}(wg, chainID)
 	}
 	wg.Wait()
@@ -441,6 +448,14 @@ func InitTokenConfig(b tokens.IBridge, tokenID string, chainID *big.Int) {
 		logErrFunc("check token config failed", "tokenID", tokenID, "chainID", chainID, "tokenAddr", tokenAddr, "err", err)
 		return
 	}
+	// the decimals override is authoritative, every caller of the token config uses it
+	if decimals, exist := params.GetTokenDecimalsOverride(chainID.String(), tokenID); exist {
+		if decimals != tokenCfg.Decimals {
+			log.Warn("override token decimals", "chainID", chainID, "tokenID", tokenID, "decimals", tokenCfg.Decimals, "override", decimals)
+		}
+		tokenCfg.Decimals = decimals
+		tokenCfg.DecimalsOverride = &decimals
+	}
 	router.InitOnchainCustomConfig(chainID, tokenID)
 	b.SetTokenConfig(tokenAddr, tokenCfg)
 
@@ -3,6 +3,7 @@ package tokens
 import (
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -57,6 +58,11 @@ func InitRouterSwapType(swapTypeStr string) {
 	log.Info("init router swap type success", "swaptype", routerSwapType.String())
 }
 
+// SetRouterSwapType set router swap type (eg. restore it in tests)
+func SetRouterSwapType(swapType SwapType) {
+	routerSwapType = swapType
+}
+
 // GetRouterSwapType get router swap type
 func GetRouterSwapType() SwapType {
 	return routerSwapType
@@ -167,6 +173,39 @@ func (b *CrossChainBridgeBase) GetTokenConfig(token string) *TokenConfig {
 	return nil
 }
 
+// GetMPCTokenConfigs get the mpc accounts (the router contract of the chain and of the tokens) and their tokens,
+// the mpc of a token is got by getRouterMPC (eg. `router.GetRouterMPC`), default to the router contract of the chain.
+// The mpc accounts are sorted, and it fails with ErrNoRouterMPC if there is none.
+func (b *CrossChainBridgeBase) GetMPCTokenConfigs(getRouterMPC func(tokenID, chainID string) (string, error)) (mpcs []string, tokenCfgs map[string][]*TokenConfig, err error) {
+	tokenCfgs = make(map[string][]*TokenConfig) // mpc -> tokens
+	if mpc := b.ChainConfig.RouterContract; mpc != "" {
+		tokenCfgs[mpc] = nil
+	}
+	b.TokenConfigMap.Range(func(key, value interface{}) bool {
+		tokenCfg, ok := value.(*TokenConfig)
+		if !ok || tokenCfg == nil {
+			return true
+		}
+		mpc, errf := getRouterMPC(tokenCfg.TokenID, b.ChainConfig.ChainID)
+		if errf != nil || mpc == "" {
+			mpc = b.ChainConfig.RouterContract
+		}
+		if mpc != "" {
+			tokenCfgs[mpc] = append(tokenCfgs[mpc], tokenCfg)
+		}
+		return true
+	})
+	if len(tokenCfgs) == 0 {
+		return nil, nil, ErrNoRouterMPC
+	}
+	mpcs = make([]string, 0, len(tokenCfgs))
+	for mpc := range tokenCfgs {
+		mpcs = append(mpcs, mpc)
+	}
+	sort.Strings(mpcs)
+	return mpcs, tokenCfgs, nil
+}
+
 // GetRouterContract get router contract
 func (b *CrossChainBridgeBase) GetRouterContract(token string) string {
 	if token != "" {
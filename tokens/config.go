@@ -38,6 +38,11 @@ type TokenConfig struct {
 	RouterVersion   string
 	Extra           string
 
+	// DecimalsOverride the authoritative decimals of the token used to calc the swap value,
+	// which takes precedence over the decimals registered on chain (eg. cosmos denom metadata).
+	// It is set by `TokenDecimalsOverride` of the local chain config, and `Decimals` is replaced by it.
+	DecimalsOverride *uint8 `toml:",omitempty" json:",omitempty"`
+
 	// calced value
 	underlying string
 
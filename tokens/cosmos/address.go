@@ -1,30 +1,55 @@
 package cosmos
 
 import (
+	"bytes"
 	"encoding/hex"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/btcsuite/btcd/btcec"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
 	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	"github.com/cosmos/cosmos-sdk/types/bech32"
 )
 
-// IsValidAddress check address
+var (
+	// ErrAccountPubKeyMismatch the on chain public key of the signer differs from the configed one
+	ErrAccountPubKeyMismatch = errors.New("account public key mismatch")
+)
+
+// IsValidAddress check the bind address (receiver) of the swaps,
+// on sei the evm address is also valid as it is resolved to the linked sei address (see resolveReceiver).
+// the accounts and contracts on chain (eg. router mpc, cw20 token) are checked by isBech32Address.
 func (b *Bridge) IsValidAddress(address string) bool {
+	if b.IsSeiChain() && isEVMAddress(address) {
+		return true
+	}
 	return IsValidAddress(b.Prefix, address)
 }
 
 // PublicKeyToAddress public key hex string (may be uncompressed) to address
+// (the address derivation is of the chain's public key type)
 func (b *Bridge) PublicKeyToAddress(pubKeyHex string) (string, error) {
-	return PublicKeyToAddress(b.Prefix, pubKeyHex)
+	pk, err := b.PubKeyFromStr(pubKeyHex)
+	if err != nil {
+		return "", err
+	}
+	return PubKeyToAddress(b.Prefix, pk)
 }
 
 func (b *Bridge) VerifyPubKey(address, pubkey string) error {
-	return VerifyPubKey(address, b.Prefix, pubkey)
+	if addr, err := b.PublicKeyToAddress(pubkey); err != nil {
+		log.Warn("public key to address error", "pubkey", pubkey, "prefix", b.Prefix, "err", err)
+		return err
+	} else if address != addr {
+		return tokens.ErrValidPublicKey
+	}
+	return nil
 }
 
 func IsValidAddress(prefix, address string) bool {
@@ -47,14 +72,19 @@ func PublicKeyToAddress(prefix, pubKeyHex string) (string, error) {
 	if pk, err := PubKeyFromStr(pubKeyHex); err != nil {
 		return "", err
 	} else {
-		if accAddress, err := sdk.AccAddressFromHex(pk.Address().String()); err != nil {
-			return "", err
+		return PubKeyToAddress(prefix, pk)
+	}
+}
+
+// PubKeyToAddress public key to bech32 address
+func PubKeyToAddress(prefix string, pk cryptoTypes.PubKey) (string, error) {
+	if accAddress, err := sdk.AccAddressFromHex(pk.Address().String()); err != nil {
+		return "", err
+	} else {
+		if bech32Addr, err := bech32.ConvertAndEncode(prefix, accAddress); err == nil {
+			return bech32Addr, nil
 		} else {
-			if bech32Addr, err := bech32.ConvertAndEncode(prefix, accAddress); err == nil {
-				return bech32Addr, nil
-			} else {
-				return "", err
-			}
+			return "", err
 		}
 	}
 }
@@ -92,3 +122,37 @@ func VerifyPubKey(address, prefix, pubkey string) error {
 		return nil
 	}
 }
+
+// GetSignerPubKey get the public key of signer to put in SignerInfo.
+// A never used account has no public key on chain, so it must be supplied,
+// and a used account's public key is checked to match with the mpc public key.
+func (b *Bridge) GetSignerPubKey(signer, pubkey string) (cryptoTypes.PubKey, error) {
+	pubKey, err := b.PubKeyFromStr(pubkey)
+	if err != nil {
+		return nil, err
+	}
+	res, err := b.GetBaseAccount(signer)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.Account == nil || res.Account.PubKey == nil || len(res.Account.PubKey.Key) == 0 {
+		log.Info("signer has no public key on chain, supply it in tx", "signer", signer)
+		return pubKey, nil
+	}
+	accPubKey := res.Account.PubKey
+	if wantType := PubKeyTypeURL(pubKey); accPubKey.Type != wantType {
+		return nil, fmt.Errorf("%w, signer: %v, type: %v, want: %v", ErrAccountPubKeyMismatch, signer, accPubKey.Type, wantType)
+	}
+	if !bytes.Equal(accPubKey.Key, pubKey.Bytes()) {
+		return nil, fmt.Errorf("%w, signer: %v, key: %X, want: %X", ErrAccountPubKeyMismatch, signer, accPubKey.Key, pubKey.Bytes())
+	}
+	return pubKey, nil
+}
+
+// PubKeyTypeURL get the type url of public key, eg. `/cosmos.crypto.secp256k1.PubKey`
+func PubKeyTypeURL(pubKey cryptoTypes.PubKey) string {
+	if anyPubKey, err := codecTypes.NewAnyWithValue(pubKey); err == nil {
+		return anyPubKey.TypeUrl
+	}
+	return ""
+}
@@ -0,0 +1,107 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	tMPCPubkey  = "0x0468438a94627b0de2b6a7c9af99136ef7e607f7944b749c3534bb27a89e742d583b1c8b3aecfae45dea2ac58730aa6ba654c73c435d44755e5cd1500c8f4d036b"
+	tMPCAddress = "cosmos10yyn2er9k5cs9qn55l7t23yxxk7egecpw9lw90"
+)
+
+func TestPublicKeyToAddress(t *testing.T) {
+	_, addrBytes, err := bech32.DecodeAndConvert(tMPCAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chainPrefixes := map[string][]string{
+		"COSMOSHUB": {"cosmos"},
+		"OSMOSIS":   {"osmo"},
+		"COREUM":    {"core", "testcore", "devcore"},
+		"SEI":       {"sei"},
+	}
+	for _, chainName := range ChainsList {
+		prefixes, exist := chainPrefixes[chainName]
+		if !exist {
+			t.Errorf("missing address prefix of chain %v", chainName)
+			continue
+		}
+		for _, prefix := range prefixes {
+			want, err := bech32.ConvertAndEncode(prefix, addrBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+			b := NewCrossChainBridge()
+			b.Prefix = prefix
+			have, err := b.PublicKeyToAddress(tMPCPubkey)
+			if err != nil {
+				t.Errorf("%v public key to address failed: %v", chainName, err)
+				continue
+			}
+			if have != want {
+				t.Errorf("%v public key to address mismatch, have %v want %v", chainName, have, want)
+			}
+			if err = b.VerifyPubKey(want, tMPCPubkey); err != nil {
+				t.Errorf("%v verify public key failed: %v", chainName, err)
+			}
+		}
+	}
+}
+
+func TestVerifyMismatchedPubKey(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.Prefix = "osmo"
+	// right address bytes but wrong prefix
+	if err := b.VerifyPubKey(tMPCAddress, tMPCPubkey); !errors.Is(err, tokens.ErrValidPublicKey) {
+		t.Errorf("verify public key should fail with %v, but have %v", tokens.ErrValidPublicKey, err)
+	}
+}
+
+func TestGetSignerPubKey(t *testing.T) {
+	mpcPubKey, err := PubKeyFromStr(tMPCPubkey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPubKey := secp256k1.GenPrivKey().PubKey()
+	encodeKey := func(key []byte) string { return base64.StdEncoding.EncodeToString(key) }
+
+	var accountPubKey string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != AccountInfo+tMPCAddress {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"account":{"address":"` + tMPCAddress + `","account_number":"12","sequence":"7"` + accountPubKey + `}}`))
+	})
+
+	tests := []struct {
+		name     string
+		pubKey   string
+		mismatch bool
+	}{
+		{"fresh account", "", false},
+		{"used account", `,"pub_key":{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"` + encodeKey(mpcPubKey.Bytes()) + `"}`, false},
+		{"used account with other key", `,"pub_key":{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"` + encodeKey(otherPubKey.Bytes()) + `"}`, true},
+		{"used account with other key type", `,"pub_key":{"@type":"/cosmos.crypto.ed25519.PubKey","key":"` + encodeKey(make([]byte, 32)) + `"}`, true},
+	}
+	for _, test := range tests {
+		accountPubKey = test.pubKey
+		pubKey, err := b.GetSignerPubKey(tMPCAddress, tMPCPubkey)
+		if test.mismatch {
+			if !errors.Is(err, ErrAccountPubKeyMismatch) {
+				t.Errorf("%v: get signer public key should fail with %v, but have %v", test.name, ErrAccountPubKeyMismatch, err)
+			}
+			continue
+		}
+		if err != nil || !pubKey.Equals(mpcPubKey) {
+			t.Errorf("%v: get signer public key failed, have %v %v", test.name, pubKey, err)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	cmath "github.com/anyswap/CrossChain-Router/v3/common/math"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// maxAmountBitLen sdk.Int panics if its bit length exceeds 256
+const maxAmountBitLen = 256
+
+var (
+	// ErrAmountOverflow amount is negative or exceeds the range of sdk.Int
+	ErrAmountOverflow = errors.New("amount overflow")
+	// ErrAmountPrecisionLoss scaled amount deviates from the source value more than rounding
+	ErrAmountPrecisionLoss = errors.New("amount precision loss")
+)
+
+// ToSdkInt convert amount to sdk.Int, errors instead of panicking if it's out of range,
+// and checks the conversion through the string representation is lossless.
+func ToSdkInt(amount *big.Int) (sdk.Int, error) {
+	if amount == nil || amount.Sign() < 0 || amount.BitLen() > maxAmountBitLen {
+		return sdk.Int{}, fmt.Errorf("%w, amount: %v, max bit length: %v", ErrAmountOverflow, amount, maxAmountBitLen)
+	}
+	res, ok := sdk.NewIntFromString(amount.String())
+	if !ok || res.BigInt().Cmp(amount) != 0 {
+		return sdk.Int{}, fmt.Errorf("%w, convert amount %v to sdk.Int failed", ErrAmountPrecisionLoss, amount)
+	}
+	return res, nil
+}
+
+// CheckScaledAmount check the scaled amount is the value converted between decimals,
+// scaling to more decimals must be exact, and scaling to less decimals
+// must deviate less than one unit of the target decimals (the rounding).
+func CheckScaledAmount(fromValue, scaled *big.Int, fromDecimals, toDecimals uint8) error {
+	if fromValue == nil || scaled == nil {
+		return fmt.Errorf("%w, nil amount", ErrAmountPrecisionLoss)
+	}
+	if fromDecimals <= toDecimals {
+		expected := new(big.Int).Mul(fromValue, cmath.BigPow(10, int64(toDecimals-fromDecimals)))
+		if scaled.Cmp(expected) != 0 {
+			return fmt.Errorf("%w, value: %v, scaled: %v, expected: %v", ErrAmountPrecisionLoss, fromValue, scaled, expected)
+		}
+		return nil
+	}
+	unit := cmath.BigPow(10, int64(fromDecimals-toDecimals))
+	deviation := new(big.Int).Mul(scaled, unit)
+	deviation.Sub(deviation, fromValue).Abs(deviation)
+	if deviation.Cmp(unit) >= 0 {
+		return fmt.Errorf("%w, value: %v (decimals %v), scaled: %v (decimals %v)", ErrAmountPrecisionLoss, fromValue, fromDecimals, scaled, toDecimals)
+	}
+	return nil
+}
+
+// checkSwapAmount check the scaled swap amount is correct and fits sdk.Int
+func checkSwapAmount(fromValue, scaled *big.Int, fromDecimals, toDecimals uint8) error {
+	if err := CheckScaledAmount(fromValue, scaled, fromDecimals, toDecimals); err != nil {
+		return err
+	}
+	_, err := ToSdkInt(scaled)
+	return err
+}
@@ -0,0 +1,57 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+func TestToSdkInt(t *testing.T) {
+	maxAmount := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), maxAmountBitLen), big.NewInt(1))
+	for _, amount := range []*big.Int{big.NewInt(0), big.NewInt(1), maxAmount} {
+		res, err := ToSdkInt(amount)
+		if err != nil {
+			t.Errorf("convert amount %v failed: %v", amount, err)
+			continue
+		}
+		if res.BigInt().Cmp(amount) != 0 {
+			t.Errorf("convert amount mismatch, have %v want %v", res, amount)
+		}
+	}
+
+	overflow := new(big.Int).Add(maxAmount, big.NewInt(1))
+	for _, amount := range []*big.Int{nil, big.NewInt(-1), overflow} {
+		if _, err := ToSdkInt(amount); !errors.Is(err, ErrAmountOverflow) {
+			t.Errorf("convert amount %v should fail with %v, but have %v", amount, ErrAmountOverflow, err)
+		}
+	}
+}
+
+func TestCheckScaledAmount(t *testing.T) {
+	large, _ := new(big.Int).SetString("123456789012345678901234567890123456789012345678901234567890", 10)
+	for _, mode := range []string{RoundingDown, RoundingHalfUp, RoundingUp} {
+		for _, decimals := range [][2]uint8{{18, 6}, {6, 18}, {18, 18}, {24, 0}} {
+			scaled := ConvertTokenValueWithRounding(large, decimals[0], decimals[1], mode)
+			if err := checkSwapAmount(large, scaled, decimals[0], decimals[1]); err != nil {
+				t.Errorf("check scaled amount from %v to %v decimals with %v rounding failed: %v", decimals[0], decimals[1], mode, err)
+			}
+		}
+	}
+
+	// one unit deviation of the target decimals is a precision loss
+	scaled := ConvertTokenValueWithRounding(large, 18, 6, RoundingDown)
+	if err := CheckScaledAmount(large, new(big.Int).Sub(scaled, big.NewInt(1)), 18, 6); !errors.Is(err, ErrAmountPrecisionLoss) {
+		t.Errorf("wrong scaled amount should fail with %v, but have %v", ErrAmountPrecisionLoss, err)
+	}
+	scaled = ConvertTokenValueWithRounding(large, 6, 18, RoundingDown)
+	if err := CheckScaledAmount(large, new(big.Int).Add(scaled, big.NewInt(1)), 6, 18); !errors.Is(err, ErrAmountPrecisionLoss) {
+		t.Errorf("inexact scaled amount to more decimals should fail with %v, but have %v", ErrAmountPrecisionLoss, err)
+	}
+
+	// scaling to more decimals may overflow sdk.Int
+	huge := new(big.Int).Lsh(big.NewInt(1), 250)
+	scaled = ConvertTokenValueWithRounding(huge, 0, 18, RoundingDown)
+	if err := checkSwapAmount(huge, scaled, 0, 18); !errors.Is(err, ErrAmountOverflow) {
+		t.Errorf("overflowed scaled amount should fail with %v, but have %v", ErrAmountOverflow, err)
+	}
+}
@@ -0,0 +1,120 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+)
+
+const (
+	AuthzGrants = "/cosmos/authz/v1beta1/grants"
+
+	MsgSendTypeURL              = "/cosmos.bank.v1beta1.MsgSend"
+	SendAuthorizationTypeURL    = "/cosmos.bank.v1beta1.SendAuthorization"
+	GenericAuthorizationTypeURL = "/cosmos.authz.v1beta1.GenericAuthorization"
+)
+
+var (
+	// ErrNoSendAuthorization the granter has no unexpired send authorization covering the amount to the grantee
+	ErrNoSendAuthorization = errors.New("no active authz send authorization")
+)
+
+// QueryGrantsResponse authz grants of granter and grantee
+type QueryGrantsResponse struct {
+	Grants []*AuthzGrant `json:"grants"`
+}
+
+// AuthzGrant authz grant
+type AuthzGrant struct {
+	Authorization *AuthzAuthorization `json:"authorization"`
+	Expiration    *time.Time          `json:"expiration"`
+}
+
+// AuthzAuthorization send or generic authorization
+type AuthzAuthorization struct {
+	Type       string    `json:"@type"`
+	Msg        string    `json:"msg,omitempty"`
+	SpendLimit sdk.Coins `json:"spend_limit,omitempty"`
+}
+
+// GetAuthzGranter get the treasury account which grants the mpc to send on behalf of it.
+// It is configured by the `authzGranter` custom, and payouts are not delegated if it is empty.
+func (b *Bridge) GetAuthzGranter() string {
+	return params.GetCustom(b.ChainConfig.ChainID, "authzGranter")
+}
+
+// BuildExecMsg wrap msgs in a MsgExec executed by grantee
+func BuildExecMsg(grantee string, msgs ...sdk.Msg) (*authz.MsgExec, error) {
+	msgsAny := make([]*codecTypes.Any, len(msgs))
+	for i, msg := range msgs {
+		any, err := codecTypes.NewAnyWithValue(msg)
+		if err != nil {
+			return nil, err
+		}
+		msgsAny[i] = any
+	}
+	return &authz.MsgExec{
+		Grantee: grantee,
+		Msgs:    msgsAny,
+	}, nil
+}
+
+// GetAuthzGrants get the authz grants of MsgSend from granter to grantee
+func (b *Bridge) GetAuthzGrants(granter, grantee string) ([]*AuthzGrant, error) {
+	query := url.Values{}
+	query.Set("granter", granter)
+	query.Set("grantee", grantee)
+	query.Set("msg_type_url", MsgSendTypeURL)
+
+	path := AuthzGrants + "?" + query.Encode()
+
+	var result *QueryGrantsResponse
+	var err error
+	for _, gatewayURL := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(gatewayURL, path)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return result.Grants, nil
+		}
+		log.Warn("GetAuthzGrants failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "GetAuthzGrants", granter, grantee)
+}
+
+// checkSendAuthorization check there is an active grant allowing grantee to send amount of denom
+func (b *Bridge) checkSendAuthorization(granter, grantee, denom string, amount *big.Int) error {
+	grants, err := b.GetAuthzGrants(granter, grantee)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for _, grant := range grants {
+		if grant == nil || grant.Authorization == nil {
+			continue
+		}
+		if grant.Expiration != nil && !grant.Expiration.After(now) {
+			continue
+		}
+		auth := grant.Authorization
+		switch auth.Type {
+		case GenericAuthorizationTypeURL:
+			if auth.Msg == MsgSendTypeURL {
+				return nil
+			}
+		case SendAuthorizationTypeURL:
+			// spend limit must be set in SendAuthorization
+			if auth.SpendLimit.AmountOf(denom).BigInt().Cmp(amount) >= 0 {
+				return nil
+			}
+		}
+	}
+	return fmt.Errorf("%w, granter: %v, grantee: %v, denom: %v, amount: %v", ErrNoSendAuthorization, granter, grantee, denom, amount)
+}
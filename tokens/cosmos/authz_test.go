@@ -0,0 +1,91 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+	tGranter = "sei1granter"
+	tGrantee = "sei1grantee"
+)
+
+func TestBuildExecMsg(t *testing.T) {
+	b := NewCrossChainBridge()
+
+	sendMsg := BuildSendMsg(tGranter, "sei1receiver", "usei", big.NewInt(1000))
+	execMsg, err := BuildExecMsg(tGrantee, sendMsg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err = txBuilder.SetMsgs(execMsg); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := b.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode tx with MsgExec failed: %v", err)
+	}
+
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		t.Fatalf("tx should have one msg, but have %v", len(msgs))
+	}
+	decodedExec, ok := msgs[0].(*authz.MsgExec)
+	if !ok {
+		t.Fatalf("tx msg should be MsgExec, but have %T", msgs[0])
+	}
+	if decodedExec.Grantee != tGrantee {
+		t.Errorf("MsgExec grantee mismatch, have %v want %v", decodedExec.Grantee, tGrantee)
+	}
+	innerMsgs, err := decodedExec.GetMessages()
+	if err != nil || len(innerMsgs) != 1 {
+		t.Fatalf("MsgExec should wrap one msg, have %v %v", len(innerMsgs), err)
+	}
+	innerSend, ok := innerMsgs[0].(*bankTypes.MsgSend)
+	if !ok {
+		t.Fatalf("MsgExec inner msg should be MsgSend, but have %T", innerMsgs[0])
+	}
+	if innerSend.FromAddress != tGranter || !innerSend.Amount.IsEqual(sendMsg.Amount) {
+		t.Errorf("MsgExec inner msg mismatch, have %v", innerSend)
+	}
+}
+
+func TestCheckSendAuthorization(t *testing.T) {
+	var grants string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("granter") != tGranter || query.Get("grantee") != tGrantee || query.Get("msg_type_url") != MsgSendTypeURL {
+			t.Errorf("unexpected query %v", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"grants":[` + grants + `]}`))
+	})
+
+	grants = `{"authorization":{"@type":"/cosmos.bank.v1beta1.SendAuthorization","spend_limit":[{"denom":"usei","amount":"5000"}]},"expiration":"2099-01-01T00:00:00Z"}`
+	if err := b.checkSendAuthorization(tGranter, tGrantee, "usei", big.NewInt(5000)); err != nil {
+		t.Errorf("check send authorization failed: %v", err)
+	}
+	if err := b.checkSendAuthorization(tGranter, tGrantee, "usei", big.NewInt(5001)); !errors.Is(err, ErrNoSendAuthorization) {
+		t.Errorf("exceed spend limit should fail with %v, but have %v", ErrNoSendAuthorization, err)
+	}
+
+	grants = `{"authorization":{"@type":"/cosmos.authz.v1beta1.GenericAuthorization","msg":"/cosmos.bank.v1beta1.MsgSend"},"expiration":"2000-01-01T00:00:00Z"}`
+	if err := b.checkSendAuthorization(tGranter, tGrantee, "usei", big.NewInt(1)); !errors.Is(err, ErrNoSendAuthorization) {
+		t.Errorf("expired grant should fail with %v, but have %v", ErrNoSendAuthorization, err)
+	}
+
+	grants = `{"authorization":{"@type":"/cosmos.authz.v1beta1.GenericAuthorization","msg":"/cosmos.bank.v1beta1.MsgSend"},"expiration":null}`
+	if err := b.checkSendAuthorization(tGranter, tGrantee, "usei", big.NewInt(1)); err != nil {
+		t.Errorf("check generic authorization failed: %v", err)
+	}
+}
@@ -31,16 +31,40 @@ type Bridge struct {
 
 	Prefix string
 	Denom  string
+
+	heightCache      *blockHeightCache
+	ibcTransfers     *ibcTransferTracker
+	seqCache         *accountSeqCache
+	denomTraces      *denomTraceCache
+	nodeMinGasPrices *minGasPriceCache
 }
 
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
 	clientCtx := NewClientContext()
-	return &Bridge{
+	b := &Bridge{
 		NonceSetterBase: base.NewNonceSetterBase(),
 		TxConfig:        clientCtx.TxConfig,
 		ClientContext:   grpc.NewClientContext(clientCtx),
 	}
+	b.heightCache = newBlockHeightCache(b.GetLatestBlockNumber)
+	b.ibcTransfers = newIBCTransferTracker()
+	b.seqCache = newAccountSeqCache()
+	b.denomTraces = newDenomTraceCache()
+	b.nodeMinGasPrices = newMinGasPriceCache(b.queryNodeMinGasPrices)
+	return b
+}
+
+// SetChainConfig set chain config, and rebuild the tx config
+// if the chain has registered chain specific proto types
+func (b *Bridge) SetChainConfig(chainCfg *tokens.ChainConfig) {
+	b.CrossChainBridgeBase.SetChainConfig(chainCfg)
+	if registrars := GetChainInterfaceRegistrars(chainCfg.ChainID); len(registrars) > 0 {
+		clientCtx := NewClientContext(registrars...)
+		b.TxConfig = clientCtx.TxConfig
+		b.ClientContext = grpc.NewClientContext(clientCtx)
+		log.Info("register chain specific interfaces finished", "chainID", chainCfg.ChainID, "count", len(registrars))
+	}
 }
 
 func (b *Bridge) SetPrefixAndDenom(prefix, denom string) {
@@ -85,7 +109,7 @@ func (b *Bridge) InitRouterInfo(routerContract, routerVersion string) (err error
 	}
 
 	routerMPC := routerContract
-	if !b.IsValidAddress(routerMPC) {
+	if !b.isBech32Address(routerMPC) {
 		log.Warn("wrong router mpc address (in cosmos routerMPC is routerContract)", "routerMPC", routerMPC)
 		return fmt.Errorf("wrong router mpc address: %v", routerMPC)
 	}
@@ -108,6 +132,7 @@ func (b *Bridge) InitRouterInfo(routerContract, routerVersion string) (err error
 		},
 	)
 	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+	b.startSeqPrefetch(routerMPC)
 
 	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
 		"routerContract", routerContract, "routerMPC", routerMPC)
@@ -126,20 +151,34 @@ func (b *Bridge) SetTokenConfig(tokenAddr string, tokenCfg *tokens.TokenConfig)
 	isReload := router.IsReloading
 	logErrFunc := log.GetLogFuncOr(isReload, log.Errorf, log.Fatalf)
 
-	err := sdk.ValidateDenom(tokenCfg.ContractAddress)
+	kind, err := GetTokenKind(tokenCfg)
 	if err != nil {
-		logErrFunc("wrong meta coin denom: %v %w", tokenCfg.ContractAddress, err)
+		logErrFunc("wrong token kind of %v: %v", tokenCfg.ContractAddress, err)
+		return
+	}
+
+	if err = b.checkTokenKindFormat(kind, tokenCfg.ContractAddress); err != nil {
+		logErrFunc("wrong token address: %v", err)
 		if isReload {
 			return
 		}
 	}
 
-	if tokenCfg.Decimals != 6 {
+	// the decimals override is authoritative for the bank denoms of other decimals
+	if kind == TokenKindBank && tokenCfg.DecimalsOverride == nil && tokenCfg.Decimals != 6 {
 		logErrFunc("meta coin %v decimals mismatch, have %v want 6", tokenCfg.ContractAddress, tokenCfg.Decimals)
 		if isReload {
 			return
 		}
 	}
+
+	if err = b.verifyTokenKindOnChain(kind, tokenCfg.ContractAddress, tokenCfg.Decimals); err != nil {
+		if tokens.IsRPCQueryOrNotFoundError(err) {
+			log.Warn("can not verify token kind on chain", "token", tokenCfg.ContractAddress, "kind", kind, "err", err)
+		} else {
+			logErrFunc("verify token kind on chain failed: %v", err)
+		}
+	}
 }
 
 // GetTransaction impl
@@ -162,11 +201,21 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		} else {
 			status.BlockHeight = txHeight
 		}
+		status.BlockTime = ParseBlockTime(res.TxResponse.Timestamp)
 		if blockNumber, err := b.GetLatestBlockNumber(); err == nil {
-			if blockNumber > status.BlockHeight {
-				status.Confirmations = blockNumber - status.BlockHeight
-			}
+			status.Confirmations = b.countConfirmations(status.BlockHeight, blockNumber)
 		}
 	}
 	return status, nil
 }
+
+// GetTxBlockInfo impl NonceSetter interface
+func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
+	res, err := b.GetTransactionByHash(txHash)
+	if err != nil || res == nil || res.TxResponse == nil {
+		return 0, 0
+	}
+	blockHeight, _ = strconv.ParseUint(res.TxResponse.Height, 10, 64)
+	blockTime = ParseBlockTime(res.TxResponse.Timestamp)
+	return blockHeight, blockTime
+}
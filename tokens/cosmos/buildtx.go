@@ -6,6 +6,7 @@ import (
 	"math/big"
 	"regexp"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
@@ -13,6 +14,7 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/params"
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
 )
 
 var (
@@ -21,102 +23,184 @@ var (
 	DefaultGasLimit  uint64 = 150000
 	DefaultFee              = "500"
 
-	cachedAccountNumberMap = make(map[string]uint64)
+	// DefaultGasAdjustment multiplier of the simulated gas used if `gasAdjustment` is not configed,
+	// the simulated gas used is usually a little lower than the gas used on chain
+	DefaultGasAdjustment = 1.3
+
+	cachedAccountNumberMap  = make(map[string]uint64)
+	cachedAccountNumberLock sync.RWMutex
+
+	// ErrAccountNumberMismatch the explicit account number mismatches the one of the account
+	ErrAccountNumberMismatch = errors.New("account number mismatch")
 )
 
 // BuildRawTransaction build raw tx
+func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	txBuilder, receiver, err := b.buildTxBuilder(args, false)
+	if err != nil {
+		return nil, err
+	}
+	extra := args.Extra
+	var accountNumber uint64
+	if extra.AccountNumber != nil {
+		accountNumber = *extra.AccountNumber
+		if err = b.checkAccountNumberOverride(args.From, accountNumber); err != nil {
+			return nil, err
+		}
+	} else if accountNumber, err = b.GetAccountNum(args.From); err != nil {
+		return nil, err
+	}
+	log.Info(fmt.Sprintf("build %s raw tx", args.SwapType.String()),
+		"identifier", args.Identifier, "swapID", args.SwapID,
+		"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
+		"from", args.From, "receiver", receiver,
+		"accountNumber", accountNumber, "sequence", *extra.Sequence,
+		"gasLimit", *extra.Gas, "replaceNum", args.GetReplaceNum(),
+		"originValue", args.OriginValue, "swapValue", args.SwapValue,
+		"gasFee", *extra.Fee, "bridgeFee", extra.BridgeFee,
+	)
+	return &BuildRawTx{
+		TxBuilder:     txBuilder,
+		AccountNumber: accountNumber,
+		Sequence:      *extra.Sequence,
+	}, nil
+}
+
+// buildTxBuilder build the unsigned tx of swap.
+// In preview mode, the sequence is queried from chain instead of being allocated.
 //
 //nolint:gocyclo // ok
-func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+func (b *Bridge) buildTxBuilder(args *tokens.BuildTxArgs, preview bool) (txBuilder cosmosClient.TxBuilder, receiver string, err error) {
 	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
-		return nil, tokens.ErrToChainIDMismatch
+		return nil, "", tokens.ErrToChainIDMismatch
 	}
 	if args.Input != nil {
-		return nil, fmt.Errorf("forbid build raw swap tx with input data")
+		return nil, "", fmt.Errorf("forbid build raw swap tx with input data")
 	}
 	if args.From == "" {
-		return nil, fmt.Errorf("forbid empty sender")
+		return nil, "", fmt.Errorf("forbid empty sender")
 	}
 
 	routerMPC, err := router.GetRouterMPC(args.GetTokenID(), b.ChainConfig.ChainID)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 	if !common.IsEqualIgnoreCase(args.From, routerMPC) {
 		log.Error("build tx mpc mismatch", "have", args.From, "want", routerMPC)
-		return nil, tokens.ErrSenderMismatch
+		return nil, "", tokens.ErrSenderMismatch
 	}
 
 	mpcPubkey := router.GetMPCPublicKey(args.From)
 	if mpcPubkey == "" {
-		return nil, tokens.ErrMissMPCPublicKey
+		return nil, "", tokens.ErrMissMPCPublicKey
+	}
+	if err := b.VerifyPubKey(args.From, mpcPubkey); err != nil {
+		log.Error("build tx mpc public key mismatch", "mpc", args.From, "pubkey", mpcPubkey, "prefix", b.Prefix, "err", err)
+		return nil, "", tokens.ErrValidPublicKey
 	}
 
 	erc20SwapInfo := args.ERC20SwapInfo
 	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, args.ToChainID.String())
 	if multichainToken == "" {
 		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", args.ToChainID)
-		return nil, tokens.ErrMissTokenConfig
+		return nil, "", tokens.ErrMissTokenConfig
 	}
 
 	tokenCfg := b.GetTokenConfig(multichainToken)
 	if tokenCfg == nil {
-		return nil, tokens.ErrMissTokenConfig
+		return nil, "", tokens.ErrMissTokenConfig
 	}
 
-	if receiver, amount, err := b.getReceiverAndAmount(args, multichainToken); err != nil {
-		return nil, err
-	} else {
-		args.SwapValue = amount // SwapValue
-		if extra, err := b.initExtra(args); err != nil {
-			return nil, err
-		} else {
-			memo := args.GetUniqueSwapIdentifier()
-			mpcPubkey := router.GetMPCPublicKey(args.From)
-			if txBuilder, err := b.BuildTx(args, receiver, multichainToken, memo, mpcPubkey, amount); err != nil {
-				return nil, err
-			} else {
-				accountNumber, err := b.GetAccountNum(args.From)
-				if err != nil {
-					return nil, err
-				}
-				log.Info(fmt.Sprintf("build %s raw tx", args.SwapType.String()),
-					"identifier", args.Identifier, "swapID", args.SwapID,
-					"fromChainID", args.FromChainID, "toChainID", args.ToChainID,
-					"from", args.From, "receiver", receiver,
-					"accountNumber", accountNumber, "sequence", *extra.Sequence,
-					"gasLimit", *extra.Gas, "replaceNum", args.GetReplaceNum(),
-					"originValue", args.OriginValue, "swapValue", args.SwapValue,
-					"gasFee", *extra.Fee, "bridgeFee", extra.BridgeFee,
-				)
-				return &BuildRawTx{
-					TxBuilder:     txBuilder,
-					AccountNumber: accountNumber,
-					Sequence:      *extra.Sequence,
-				}, nil
+	receiver, amount, err := b.getReceiverAndAmount(args, multichainToken)
+	if err != nil {
+		return nil, receiver, err
+	}
+	memo, err := b.buildPayoutMemo(args, receiver)
+	if err != nil {
+		return nil, receiver, err
+	}
+	args.SwapValue = amount // SwapValue
+	estimateGas := args.Extra == nil || args.Extra.Gas == nil
+	if preview && (args.Extra == nil || args.Extra.Sequence == nil) {
+		// do not allocate sequence in preview mode
+		sequence, errf := b.GetPoolNonce(args.From, "pending")
+		if errf != nil {
+			return nil, receiver, errf
+		}
+		if args.Extra == nil {
+			args.Extra = &tokens.AllExtras{}
+		}
+		args.Extra.Sequence = &sequence
+	}
+	extra, err := b.initExtra(args, multichainToken)
+	if err != nil {
+		return nil, receiver, err
+	}
+	txBuilder, err = b.BuildTx(args, receiver, multichainToken, memo, mpcPubkey, amount)
+	if err != nil {
+		return nil, receiver, err
+	}
+	if estimateGas {
+		gasLimit := b.EstimateGasLimit(txBuilder, args.GetTokenID())
+		extra.Gas = &gasLimit
+		fee, errf := b.enforceMinGasPrice(*extra.Fee, gasLimit)
+		if errf != nil {
+			return nil, receiver, errf
+		}
+		if fee != *extra.Fee {
+			// rebuild with the bumped fee, so that its payer (or the fee granter) is decided by it
+			extra.Fee = &fee
+			extra.FeeGranter = nil
+			if txBuilder, err = b.BuildTx(args, receiver, multichainToken, memo, mpcPubkey, amount); err != nil {
+				return nil, receiver, err
 			}
+		} else {
+			txBuilder.SetGasLimit(gasLimit)
 		}
 	}
+	if err = b.checkFeeCap(*extra.Fee, *extra.Gas); err != nil {
+		return nil, receiver, err
+	}
+	return txBuilder, receiver, nil
 }
 
-func (b *Bridge) initExtra(args *tokens.BuildTxArgs) (extra *tokens.AllExtras, err error) {
+func (b *Bridge) initExtra(args *tokens.BuildTxArgs, denom string) (extra *tokens.AllExtras, err error) {
 	extra = args.Extra
 	if extra == nil {
 		extra = &tokens.AllExtras{}
 		args.Extra = extra
 	}
-	if extra.Sequence == nil {
-		if extra.Sequence, err = b.GetSeq(args); err != nil {
-			return nil, err
-		}
-	}
 	if extra.Gas == nil {
 		extra.Gas = &DefaultGasLimit
 	}
+	// select fee (bumped to the min gas price) before allocating sequence,
+	// the fee in the args (eg. rebuilt by the accept nodes) is final
 	if extra.Fee == nil {
-		fee := b.getDefaultFee()
+		payout := args.SwapValue
+		if b.GetAuthzGranter() != "" {
+			payout = nil // paid by the granter
+		}
+		fee, errf := b.selectFee(args.From, denom, payout, *extra.Gas)
+		if errf != nil {
+			return nil, errf
+		}
 		extra.Fee = &fee
 	}
+	if extra.Sequence == nil {
+		if extra.Sequence, err = b.GetSeq(args); err != nil {
+			return nil, err
+		}
+	}
+	// the timeout height is passed in the args so that the accept nodes rebuild the same tx
+	if extra.ExpiryHeight == nil {
+		timeoutHeight, errf := b.GetTimeoutHeight()
+		if errf != nil {
+			return nil, errf
+		}
+		if timeoutHeight > 0 {
+			extra.ExpiryHeight = &timeoutHeight
+		}
+	}
 	return extra, nil
 }
 
@@ -168,6 +252,11 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 		return &nonce, nil
 	}
 
+	if seq, ok := b.getPrefetchedSeq(args.From); ok {
+		nonce = b.AdjustNonce(args.From, seq)
+		return &nonce, nil
+	}
+
 	for i := 0; i < retryRPCCount; i++ {
 		nonce, err = b.GetPoolNonce(args.From, "pending")
 		if err == nil {
@@ -184,7 +273,10 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 
 // GetAccountNum get account number
 func (b *Bridge) GetAccountNum(account string) (uint64, error) {
-	if accNo := cachedAccountNumberMap[account]; accNo > 0 {
+	if accNo, ok := b.getPrefetchedAccountNumber(account); ok {
+		return accNo, nil
+	}
+	if accNo := getCachedAccountNumber(account); accNo > 0 {
 		return accNo, nil
 	}
 	if acc, err := b.GetBaseAccount(account); err != nil {
@@ -192,7 +284,7 @@ func (b *Bridge) GetAccountNum(account string) (uint64, error) {
 	} else {
 		if acc != nil {
 			if accountNumber, err := strconv.ParseUint(acc.Account.AccountNumber, 10, 64); err == nil {
-				cachedAccountNumberMap[account] = accountNumber
+				setCachedAccountNumber(account, accountNumber)
 				return accountNumber, nil
 			} else {
 				return 0, err
@@ -202,6 +294,36 @@ func (b *Bridge) GetAccountNum(account string) (uint64, error) {
 	}
 }
 
+// checkAccountNumberOverride the explicit account number is authoritative (bypass `GetAccountNum`),
+// which is used for recovery when the node is desynced or for testing across environments.
+// As the account number never changes once the account is created,
+// it is refused if it mismatches the one on chain (cached or queried if not cached).
+// It is not refused if the query fails, as the node may be the desynced one.
+func (b *Bridge) checkAccountNumberOverride(account string, accountNumber uint64) error {
+	onChain, err := b.GetAccountNum(account)
+	if err != nil {
+		log.Warn("can not verify explicit account number", "chainID", b.ChainConfig.ChainID,
+			"account", account, "accountNumber", accountNumber, "err", err)
+	} else if onChain != accountNumber {
+		return fmt.Errorf("%w, account: %v, explicit: %v, on chain: %v", ErrAccountNumberMismatch, account, accountNumber, onChain)
+	}
+	log.Warn("build tx with explicit account number", "chainID", b.ChainConfig.ChainID,
+		"account", account, "accountNumber", accountNumber, "onChainAccountNumber", onChain)
+	return nil
+}
+
+func getCachedAccountNumber(account string) uint64 {
+	cachedAccountNumberLock.RLock()
+	defer cachedAccountNumberLock.RUnlock()
+	return cachedAccountNumberMap[account]
+}
+
+func setCachedAccountNumber(account string, accountNumber uint64) {
+	cachedAccountNumberLock.Lock()
+	defer cachedAccountNumberLock.Unlock()
+	cachedAccountNumberMap[account] = accountNumber
+}
+
 func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken string) (receiver string, amount *big.Int, err error) {
 	erc20SwapInfo := args.ERC20SwapInfo
 	receiver = args.Bind
@@ -209,6 +331,10 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
 		return receiver, amount, errors.New("swapout to invalid receiver")
 	}
+	if receiver, err = b.resolveReceiver(args.Bind); err != nil {
+		log.Warn("resolve receiver failed", "bind", args.Bind, "err", err)
+		return receiver, amount, err
+	}
 	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
 	if fromBridge == nil {
 		return receiver, amount, tokens.ErrNoBridgeForChainID
@@ -222,8 +348,20 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, amount, tokens.ErrMissTokenConfig
 	}
-	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
-	totalAmount := tokens.ConvertTokenValue(args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals)
+	toDecimals := b.GetTokenDecimals(toTokenCfg)
+	// deduct fees in source decimals, then scale with the configed rounding mode
+	valueLeft := tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, fromTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	rounding := b.GetSwapValueRounding()
+	amount = ConvertTokenValueWithRounding(valueLeft, fromTokenCfg.Decimals, toDecimals, rounding)
+	totalAmount := ConvertTokenValueWithRounding(args.OriginValue, fromTokenCfg.Decimals, toDecimals, rounding)
+	if err = checkSwapAmount(valueLeft, amount, fromTokenCfg.Decimals, toDecimals); err != nil {
+		log.Warn("check swap amount failed", "swapID", args.SwapID, "err", err)
+		return receiver, amount, err
+	}
+	if err = checkSwapAmount(args.OriginValue, totalAmount, fromTokenCfg.Decimals, toDecimals); err != nil {
+		log.Warn("check swap total amount failed", "swapID", args.SwapID, "err", err)
+		return receiver, amount, err
+	}
 	args.Extra.BridgeFee = new(big.Int).Sub(totalAmount, amount)
 	return receiver, amount, err
 }
@@ -0,0 +1,270 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
+)
+
+// newTestBuildTxBridge new a cosmos hub testnet bridge routed with `tMPCAddress` as mpc,
+// and return it with a builder of swapin args paying to the mpc.
+func newTestBuildTxBridge(t *testing.T, handler http.HandlerFunc) (*Bridge, func() *tokens.BuildTxArgs) {
+	mpc := tMPCAddress
+	b := newTestSeiBridge(t, handler)
+	// use the default bech32 prefix of sdk to validate msgs
+	b.Prefix, b.Denom = "cosmos", "uatom"
+	b.ChainConfig.ChainID = GetStubChainID("COSMOSHUB", testnetNetWork).String()
+
+	swapType := tokens.GetRouterSwapType()
+	tokens.InitRouterSwapType("erc20swap")
+	tokenID, fromChainID, toChainID := "ATOM", big.NewInt(1), b.ChainConfig.ChainID
+	fromBridge := NewCrossChainBridge()
+	fromBridge.CrossChainBridgeBase.SetTokenConfig("0xtoken", &tokens.TokenConfig{TokenID: tokenID, Decimals: 6})
+	b.CrossChainBridgeBase.SetTokenConfig("uatom", &tokens.TokenConfig{TokenID: tokenID, Decimals: 6, RouterContract: "router"})
+	router.SetBridge(fromChainID.String(), fromBridge)
+	router.SetBridge(toChainID, b)
+	router.SetMultichainToken(tokenID, toChainID, "uatom")
+	router.SetRouterInfo("router", toChainID, &router.SwapRouterInfo{RouterMPC: mpc})
+	router.SetMPCPublicKey(mpc, tMPCPubkey)
+	t.Cleanup(func() {
+		tokens.SetRouterSwapType(swapType)
+		router.SetBridge(fromChainID.String(), nil)
+		router.SetBridge(toChainID, nil)
+		router.SetMultichainTokens(tokenID, nil)
+	})
+
+	newArgs := func() *tokens.BuildTxArgs {
+		return &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: "0xtoken", TokenID: tokenID}},
+				SwapID:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+				SwapType:    tokens.ERC20SwapType,
+				Bind:        mpc,
+				FromChainID: fromChainID,
+				ToChainID:   GetStubChainID("COSMOSHUB", testnetNetWork),
+			},
+			From:        mpc,
+			OriginValue: big.NewInt(1000000),
+			Extra:       &tokens.AllExtras{},
+		}
+	}
+	return b, newArgs
+}
+
+func TestBuildTxWithAccountNumberOverride(t *testing.T) {
+	mpc := tMPCAddress
+	accountInfoDown := true
+	b, newArgs := newTestBuildTxBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			if accountInfoDown {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"100000000"}]}`))
+		case SimulateTx:
+			_, _ = w.Write([]byte(`{"gas_info":{"gas_used":"80000"}}`))
+		case LatestBlock:
+			_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"theta-testnet-001","height":"100"}}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setCachedAccountNumber(mpc, 0)
+	t.Cleanup(func() { setCachedAccountNumber(mpc, 0) })
+
+	overrideArgs := func(accountNumber uint64) *tokens.BuildTxArgs {
+		args := newArgs()
+		sequence, fee, gas := uint64(7), "5000uatom", uint64(100000)
+		args.Extra = &tokens.AllExtras{Sequence: &sequence, Fee: &fee, Gas: &gas, AccountNumber: &accountNumber}
+		return args
+	}
+
+	// the explicit account number is not refused if it can not be verified (eg. the node is desynced)
+	if err := b.checkAccountNumberOverride(mpc, 34); err != nil {
+		t.Errorf("unverifiable account number should not be refused, but have %v", err)
+	}
+	if cached := getCachedAccountNumber(mpc); cached != 0 {
+		t.Errorf("explicit account number should not be cached, have %v", cached)
+	}
+
+	// the account number never changes, a mismatch with the one on chain is refused even if it is not cached
+	accountInfoDown = false
+	if _, err := b.BuildRawTransaction(overrideArgs(34)); !errors.Is(err, ErrAccountNumberMismatch) {
+		t.Errorf("mismatched account number should fail with %v, but have %v", ErrAccountNumberMismatch, err)
+	}
+	if cached := getCachedAccountNumber(mpc); cached != 12 {
+		t.Errorf("queried account number should be cached, have %v", cached)
+	}
+
+	// the explicit account number is used in the sign bytes
+	rawTx, err := b.BuildRawTransaction(overrideArgs(12))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx := rawTx.(*BuildRawTx)
+	signBytes, err := b.GetSignBytes(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var signDoc sdktx.SignDoc
+	if err = signDoc.Unmarshal(signBytes); err != nil {
+		t.Fatal(err)
+	}
+	if tx.AccountNumber != 12 || signDoc.AccountNumber != 12 || signDoc.ChainId != "theta-testnet-001" {
+		t.Errorf("sign doc mismatch, have account number %v chain id %v", signDoc.AccountNumber, signDoc.ChainId)
+	}
+}
+
+func TestBuildTxFeeOfMinGasPrice(t *testing.T) {
+	mpc := tMPCAddress
+	b, newArgs := newTestBuildTxBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"100000000"}]}`))
+		case SimulateTx:
+			_, _ = w.Write([]byte(`{"gas_info":{"gas_used":"200000"}}`))
+		case LatestBlock:
+			_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"theta-testnet-001","height":"100"}}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"minGasPrice": "0.025uatom"})
+
+	// the fee is selected by the min gas price of the default gas limit,
+	// and is bumped again by the estimated gas limit (200000 * 1.3)
+	args := newArgs()
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txFee := rawTx.(*BuildRawTx).TxBuilder.GetTx().GetFee().String()
+	if *args.Extra.Gas != 260000 || *args.Extra.Fee != "6500uatom" || txFee != "6500uatom" {
+		t.Errorf("fee of min gas price mismatch, have gas %v fee %v tx fee %v", *args.Extra.Gas, *args.Extra.Fee, txFee)
+	}
+
+	// the fee in the args is final, so that the accept nodes rebuild the same tx
+	args = newArgs()
+	fee, gas := "500uatom", uint64(100000)
+	args.Extra = &tokens.AllExtras{Fee: &fee, Gas: &gas}
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	txFee = rawTx.(*BuildRawTx).TxBuilder.GetTx().GetFee().String()
+	if *args.Extra.Fee != "500uatom" || txFee != "500uatom" {
+		t.Errorf("explicit fee should not be bumped, have fee %v tx fee %v", *args.Extra.Fee, txFee)
+	}
+}
+
+func TestBuildTxWithTimeoutHeight(t *testing.T) {
+	mpc := tMPCAddress
+	height := "500"
+	b, newArgs := newTestBuildTxBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"100000000"}]}`))
+		case SimulateTx:
+			_, _ = w.Write([]byte(`{"gas_info":{"gas_used":"80000"}}`))
+		case LatestBlock:
+			_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"theta-testnet-001","height":"` + height + `"}}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"txTimeoutBlocks": "100"})
+
+	// the timeout height is recorded in the args on the first build
+	args := newArgs()
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	timeoutHeight := rawTx.(*BuildRawTx).TxBuilder.GetTx().GetTimeoutHeight()
+	if args.Extra.ExpiryHeight == nil || *args.Extra.ExpiryHeight != 600 || timeoutHeight != 600 {
+		t.Fatalf("timeout height mismatch, have %v in tx %v", args.Extra.ExpiryHeight, timeoutHeight)
+	}
+
+	// and reused on rebuild (eg. by the accept nodes) even if the chain has moved on
+	height = "520"
+	b.heightCache.mu.Lock()
+	b.heightCache.updatedAt = time.Now().Add(-latestHeightCacheTTL)
+	b.heightCache.mu.Unlock()
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	if timeoutHeight = rawTx.(*BuildRawTx).TxBuilder.GetTx().GetTimeoutHeight(); timeoutHeight != 600 {
+		t.Errorf("rebuilt timeout height mismatch, have %v want 600", timeoutHeight)
+	}
+}
+
+func TestBuildTxWithFeeGranterInArgs(t *testing.T) {
+	mpc := tMPCAddress
+	feeGranter, err := bech32.ConvertAndEncode("cosmos", make([]byte, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	mpcBalance := "400" // less than the fee
+	b, newArgs := newTestBuildTxBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"` + mpcBalance + `"}]}`))
+		case Balances + feeGranter:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"100000000"}]}`))
+		case LatestBlock:
+			_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"theta-testnet-001","height":"100"}}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"feeGranter": feeGranter})
+
+	// the fee granter is recorded in the args on the first build
+	args := newArgs()
+	fee, gas := "500uatom", uint64(100000)
+	args.Extra = &tokens.AllExtras{Fee: &fee, Gas: &gas}
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if granter := rawTx.(*BuildRawTx).TxBuilder.GetTx().FeeGranter().String(); granter != feeGranter {
+		t.Fatalf("fee granter mismatch, have %v want %v", granter, feeGranter)
+	}
+	if args.Extra.FeeGranter == nil || *args.Extra.FeeGranter != feeGranter {
+		t.Fatalf("fee granter in args mismatch, have %v", args.Extra.FeeGranter)
+	}
+
+	// and honored on rebuild (eg. by the accept nodes) even if the mpc can afford the fee now
+	mpcBalance = "100000000"
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	if granter := rawTx.(*BuildRawTx).TxBuilder.GetTx().FeeGranter().String(); granter != feeGranter {
+		t.Errorf("rebuilt fee granter mismatch, have %v want %v", granter, feeGranter)
+	}
+
+	// the mpc pays the fee if it is decided so
+	noGranter := ""
+	args.Extra.FeeGranter = &noGranter
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	if granter := rawTx.(*BuildRawTx).TxBuilder.GetTx().FeeGranter(); !granter.Empty() {
+		t.Errorf("rebuilt tx should have no fee granter, have %v", granter)
+	}
+}
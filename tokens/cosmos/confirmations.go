@@ -0,0 +1,38 @@
+package cosmos
+
+import (
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// GetConfirmationsRequired get the confirmation depth required before a tx is complete,
+// configed by `confirmationsRequired` custom, default to `Confirmations` of the chain config.
+// Most cosmos chains have instant finality, but some operators want a buffer.
+func (b *Bridge) GetConfirmationsRequired() uint64 {
+	required := b.ChainConfig.Confirmations
+	if requiredStr := params.GetCustom(b.ChainConfig.ChainID, "confirmationsRequired"); requiredStr != "" {
+		if value, err := strconv.ParseUint(requiredStr, 10, 64); err == nil {
+			required = value
+		} else {
+			log.Warn("wrong confirmationsRequired custom", "chainID", b.ChainConfig.ChainID, "value", requiredStr)
+		}
+	}
+	return required
+}
+
+// countConfirmations count the confirmations of tx by comparing its block height against the latest height.
+// It returns zero until the required depth is reached, so the tx is not marked complete before that.
+func (b *Bridge) countConfirmations(txHeight, latest uint64) uint64 {
+	if latest <= txHeight {
+		return 0
+	}
+	confirmations := latest - txHeight
+	if required := b.GetConfirmationsRequired(); confirmations < required {
+		log.Debug("tx has not reached the required confirmations", "chainID", b.ChainConfig.ChainID,
+			"txHeight", txHeight, "latest", latest, "confirmations", confirmations, "required", required)
+		return 0
+	}
+	return confirmations
+}
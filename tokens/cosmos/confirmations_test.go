@@ -0,0 +1,53 @@
+package cosmos
+
+import (
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+func TestConfirmationsRequired(t *testing.T) {
+	const txHash = "ABCDEF"
+	var latest uint64 = 100
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TxByHash + txHash:
+			_, _ = w.Write([]byte(`{"tx_response":{"height":"100","txhash":"` + txHash + `","code":0,"timestamp":"2022-01-01T00:00:00Z"}}`))
+		case LatestBlock:
+			height := strconv.FormatUint(atomic.LoadUint64(&latest), 10)
+			_, _ = w.Write([]byte(`{"block":{"header":{"height":"` + height + `"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	b.ChainConfig.Confirmations = 1
+
+	checkConfirmations := func(height, want uint64) {
+		t.Helper()
+		atomic.StoreUint64(&latest, height)
+		status, err := b.GetTransactionStatus(txHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status.BlockHeight != 100 || status.Confirmations != want {
+			t.Errorf("tx status at latest height %v mismatch, have height %v confirmations %v, want confirmations %v",
+				height, status.BlockHeight, status.Confirmations, want)
+		}
+	}
+
+	// default to the chain config
+	checkConfirmations(100, 0)
+	checkConfirmations(101, 1)
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"confirmationsRequired": "3"})
+
+	if required := b.GetConfirmationsRequired(); required != 3 {
+		t.Fatalf("confirmations required mismatch, have %v want 3", required)
+	}
+	// not complete until the depth is reached
+	checkConfirmations(101, 0)
+	checkConfirmations(102, 0)
+	checkConfirmations(103, 3)
+	checkConfirmations(110, 10)
+}
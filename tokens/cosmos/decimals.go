@@ -0,0 +1,92 @@
+package cosmos
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// DenomMetadata rest api path of the bank denom metadata
+const DenomMetadata = "/cosmos/bank/v1beta1/denoms_metadata/"
+
+// denomDecimalsCache chainID:denom -> *denomDecimals, the decimals registered in the bank denom metadata
+var denomDecimalsCache sync.Map
+
+func denomDecimalsCacheKey(chainID, denom string) string {
+	return chainID + ":" + denom
+}
+
+type denomDecimals struct {
+	decimals uint8
+	exist    bool
+}
+
+// DenomUnit denom unit of the bank denom metadata
+type DenomUnit struct {
+	Denom    string `json:"denom"`
+	Exponent uint32 `json:"exponent"`
+}
+
+// QueryDenomMetadataResponse bank denom metadata response
+type QueryDenomMetadataResponse struct {
+	Metadata *struct {
+		Base       string       `json:"base"`
+		Display    string       `json:"display"`
+		DenomUnits []*DenomUnit `json:"denom_units"`
+	} `json:"metadata"`
+}
+
+// GetDenomMetadataDecimals get the decimals of the denom registered in the bank denom metadata,
+// which is the exponent of the display unit. exist is false if the chain does not register it.
+func (b *Bridge) GetDenomMetadataDecimals(denom string) (decimals uint8, exist bool, err error) {
+	cacheKey := denomDecimalsCacheKey(b.ChainConfig.ChainID, denom)
+	if cached, ok := denomDecimalsCache.Load(cacheKey); ok {
+		result := cached.(*denomDecimals)
+		return result.decimals, result.exist, nil
+	}
+	var result *QueryDenomMetadataResponse
+	for _, gateway := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(gateway, DenomMetadata+denom)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			break
+		}
+		log.Warn("get denom metadata failed", "url", restApi, "err", err)
+	}
+	if err != nil || result == nil {
+		return 0, false, wrapRPCQueryError(err, "get denom metadata", denom)
+	}
+	cached := &denomDecimals{}
+	if metadata := result.Metadata; metadata != nil {
+		for _, unit := range metadata.DenomUnits {
+			if unit != nil && unit.Denom == metadata.Display && unit.Exponent <= 255 {
+				cached.decimals, cached.exist = uint8(unit.Exponent), true
+				break
+			}
+		}
+	}
+	denomDecimalsCache.Store(cacheKey, cached)
+	return cached.decimals, cached.exist, nil
+}
+
+// GetTokenDecimals get the decimals of the token used to calc the swap value.
+// The decimals override of the token config takes precedence over its decimals, so that operators
+// do not rely on chains that do not register the denom metadata. It is logged if it disagrees with the metadata.
+func (b *Bridge) GetTokenDecimals(tokenCfg *tokens.TokenConfig) uint8 {
+	if tokenCfg.DecimalsOverride == nil {
+		return tokenCfg.Decimals
+	}
+	decimals := *tokenCfg.DecimalsOverride
+	if kind, errf := GetTokenKind(tokenCfg); errf == nil && kind == TokenKindBank && tokenCfg.ContractAddress != "" {
+		metadataDecimals, registered, errf := b.GetDenomMetadataDecimals(tokenCfg.ContractAddress)
+		switch {
+		case errf != nil:
+			log.Debug("can not compare decimals with denom metadata", "chainID", b.ChainConfig.ChainID, "denom", tokenCfg.ContractAddress, "err", errf)
+		case registered && metadataDecimals != decimals:
+			log.Warn("decimals override disagrees with denom metadata", "chainID", b.ChainConfig.ChainID,
+				"tokenID", tokenCfg.TokenID, "denom", tokenCfg.ContractAddress, "override", decimals, "metadata", metadataDecimals)
+		}
+	}
+	return decimals
+}
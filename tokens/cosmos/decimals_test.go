@@ -0,0 +1,92 @@
+package cosmos
+
+import (
+	"math/big"
+	"net/http"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestDecimalsOverride(t *testing.T) {
+	var metadataQueries int
+	b, newArgs := newTestBuildTxBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != DenomMetadata+"uatom" {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		metadataQueries++
+		_, _ = w.Write([]byte(`{"metadata":{"base":"uatom","display":"atom",` +
+			`"denom_units":[{"denom":"uatom","exponent":0},{"denom":"matom","exponent":3},{"denom":"atom","exponent":6}]}}`))
+	})
+	cacheKey := denomDecimalsCacheKey(b.ChainConfig.ChainID, "uatom")
+	t.Cleanup(func() { denomDecimalsCache.Delete(cacheKey) })
+	tokenCfg := &tokens.TokenConfig{TokenID: "ATOM", ContractAddress: "uatom", Decimals: 6, RouterContract: "router"}
+	b.CrossChainBridgeBase.SetTokenConfig("uatom", tokenCfg)
+
+	// no swap fee
+	args := newArgs()
+	toMap := new(sync.Map)
+	toMap.Store(b.ChainConfig.ChainID, &tokens.FeeConfig{MaximumSwapFee: big.NewInt(0), MinimumSwapFee: big.NewInt(0)})
+	fromMap := new(sync.Map)
+	fromMap.Store(args.FromChainID.String(), toMap)
+	feeCfgs := new(sync.Map)
+	feeCfgs.Store(args.GetTokenID(), fromMap)
+	tokens.SetFeeConfigs(feeCfgs)
+	t.Cleanup(func() { tokens.SetFeeConfigs(new(sync.Map)) })
+
+	// the decimals of the token config is used by default
+	if _, amount, err := b.getReceiverAndAmount(args, "uatom"); err != nil || amount.Int64() != 1000000 {
+		t.Errorf("amount without override mismatch, have %v %v", amount, err)
+	}
+	if metadataQueries != 0 {
+		t.Errorf("denom metadata should not be queried without override, have %v queries", metadataQueries)
+	}
+
+	hook := logtest.NewGlobal()
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+	override := uint8(8)
+	tokenCfg.DecimalsOverride = &override
+	for i := 0; i < 2; i++ {
+		if _, amount, err := b.getReceiverAndAmount(newArgs(), "uatom"); err != nil || amount.Int64() != 100000000 {
+			t.Errorf("override should win, have amount %v %v", amount, err)
+		}
+	}
+	if metadataQueries != 1 {
+		t.Errorf("denom metadata should be cached, have %v queries", metadataQueries)
+	}
+	var disagreed *logrus.Entry
+	for _, e := range hook.AllEntries() {
+		if e.Message == "decimals override disagrees with denom metadata" {
+			disagreed = e
+		}
+	}
+	if disagreed == nil || disagreed.Data["override"] != uint8(8) || disagreed.Data["metadata"] != uint8(6) {
+		t.Errorf("disagreement with denom metadata should be logged, have %v", disagreed)
+	}
+
+	if _, ok := denomDecimalsCache.Load(cacheKey); !ok {
+		t.Error("denom metadata decimals should be cached by chainID and denom")
+	}
+}
+
+func TestGetTokenDecimalsOverride(t *testing.T) {
+	chainID := "cosmoshub-4"
+	t.Cleanup(func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) })
+	_ = params.SetExtraConfig(&params.ExtraConfig{
+		LocalChainConfig: map[string]*params.LocalChainConfig{chainID: {TokenDecimalsOverride: map[string]uint8{"atom": 8}}},
+	})
+	if decimals, exist := params.GetTokenDecimalsOverride(chainID, "ATOM"); !exist || decimals != 8 {
+		t.Errorf("decimals override mismatch, have %v %v", decimals, exist)
+	}
+	if _, exist := params.GetTokenDecimalsOverride(chainID, "OSMO"); exist {
+		t.Error("token without decimals override should not exist")
+	}
+	if _, exist := params.GetTokenDecimalsOverride("osmosis-1", "ATOM"); exist {
+		t.Error("decimals override of other chain should not exist")
+	}
+}
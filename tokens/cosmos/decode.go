@@ -0,0 +1,39 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ErrUnregisteredTypeURL the proto type of the type URL is not registered to the interface registry
+var ErrUnregisteredTypeURL = errors.New("unregistered proto type")
+
+var unresolvedTypeURLRegexp = regexp.MustCompile(`unable to resolve type URL ([^\s:]+)`)
+
+// DecodeTx decode the tx bytes with the chain's tx config.
+// Decoding a tx with a msg (or public key) type which is not registered fails with
+// ErrUnregisteredTypeURL naming the type URL, as the codec error is hard to act on.
+func (b *Bridge) DecodeTx(txBytes []byte) (tx sdk.Tx, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = wrapDecodeError(fmt.Errorf("decode tx panic: %v", r))
+		}
+	}()
+	tx, err = b.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		return nil, wrapDecodeError(err)
+	}
+	return tx, nil
+}
+
+// wrapDecodeError wrap the codec error of unregistered type URL with a hint
+func wrapDecodeError(err error) error {
+	match := unresolvedTypeURLRegexp.FindStringSubmatch(err.Error())
+	if match == nil {
+		return err
+	}
+	return fmt.Errorf("%w %v, register it by RegisterChainInterfaces for the chain: %v", ErrUnregisteredTypeURL, match[1], err)
+}
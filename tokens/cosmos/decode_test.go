@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestDecodeTxWithUnregisteredType(t *testing.T) {
+	// encoding does not need the type registered
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID("COSMOSHUB", testnetNetWork).String()})
+	txBuilder := b.TxConfig.NewTxBuilder()
+	err := txBuilder.SetMsgs(&stakingTypes.MsgDelegate{
+		DelegatorAddress: "cosmos1delegator",
+		ValidatorAddress: "cosmosvaloper1validator",
+		Amount:           sdk.NewCoin("uatom", sdk.NewIntFromBigInt(big.NewInt(1000))),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = b.DecodeTx(txBytes)
+	if !errors.Is(err, ErrUnregisteredTypeURL) {
+		t.Fatalf("decode tx with unregistered msg should fail with %v, but have %v", ErrUnregisteredTypeURL, err)
+	}
+	if !strings.Contains(err.Error(), "/cosmos.staking.v1beta1.MsgDelegate") || !strings.Contains(err.Error(), "RegisterChainInterfaces") {
+		t.Errorf("decode error should name the type URL and the register hook, have %v", err)
+	}
+
+	// other errors are kept
+	_, err = b.DecodeTx([]byte{0xff, 0xff})
+	if err == nil || errors.Is(err, ErrUnregisteredTypeURL) {
+		t.Errorf("decode malformed tx should fail with codec error, have %v", err)
+	}
+
+	// decode the tx of registered types
+	txBuilder = b.TxConfig.NewTxBuilder()
+	if err = txBuilder.SetMsgs(BuildSendMsg("cosmos1sender", "cosmos1receiver", "uatom", big.NewInt(1000))); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, _ = b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if tx, err := b.DecodeTx(txBytes); err != nil || len(tx.GetMsgs()) != 1 {
+		t.Errorf("decode tx failed: %v", err)
+	}
+}
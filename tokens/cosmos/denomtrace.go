@@ -0,0 +1,113 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+const (
+	// DenomTraces query of the denom trace of ibc denom hash (transfer module)
+	DenomTraces = "/ibc/apps/transfer/v1/denom_traces/"
+
+	// IBCDenomPrefix prefix of ibc received denoms, followed by the denom trace hash
+	IBCDenomPrefix = "ibc/"
+)
+
+var (
+	// ErrDenomTraceMismatch the queried denom trace does not hash to the ibc denom
+	ErrDenomTraceMismatch = errors.New("denom trace mismatch with the ibc denom hash")
+)
+
+// DenomTrace the origin of ibc received tokens
+type DenomTrace struct {
+	// Path the chain of port/channel identifiers the token is transferred through (eg. `transfer/channel-0`)
+	Path string `json:"path"`
+	// BaseDenom the denom on the origin chain (eg. `uatom`)
+	BaseDenom string `json:"base_denom"`
+}
+
+// QueryDenomTraceResponse denom trace query result
+type QueryDenomTraceResponse struct {
+	DenomTrace *DenomTrace `json:"denom_trace"`
+}
+
+// GetFullDenomPath the full denom path (`{path}/{base_denom}`), which is the base denom if the path is empty
+func (t *DenomTrace) GetFullDenomPath() string {
+	if t.Path == "" {
+		return t.BaseDenom
+	}
+	return t.Path + "/" + t.BaseDenom
+}
+
+// Hash the sha256 hash of the full denom path (upper case hex)
+func (t *DenomTrace) Hash() string {
+	return fmt.Sprintf("%X", sha256.Sum256([]byte(t.GetFullDenomPath())))
+}
+
+// IBCDenom the ibc denom (`ibc/{hash}`) of the trace, which is the base denom if the path is empty
+func (t *DenomTrace) IBCDenom() string {
+	if t.Path == "" {
+		return t.BaseDenom
+	}
+	return IBCDenomPrefix + t.Hash()
+}
+
+// IsIBCDenom is the denom an ibc received denom
+func IsIBCDenom(denom string) bool {
+	return strings.HasPrefix(denom, IBCDenomPrefix)
+}
+
+// denomTraceCache caches the resolved denom traces by hash, which never change
+type denomTraceCache struct {
+	traces sync.Map // hash -> *DenomTrace
+}
+
+func newDenomTraceCache() *denomTraceCache {
+	return &denomTraceCache{}
+}
+
+// DenomTrace get the denom trace of the ibc denom hash (`ibc/{hash}` or `{hash}`).
+// The trace is verified to hash to the ibc denom, and cached once it is resolved.
+func (b *Bridge) DenomTrace(hash string) (*DenomTrace, error) {
+	hash = strings.ToUpper(strings.TrimPrefix(hash, IBCDenomPrefix))
+	if cached, exist := b.denomTraces.traces.Load(hash); exist {
+		return cached.(*DenomTrace), nil
+	}
+	var result *QueryDenomTraceResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, DenomTraces+hash)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil && result.DenomTrace != nil {
+			break
+		}
+		log.Warn("query denom trace failed", "url", restApi, "err", err)
+	}
+	if err != nil || result == nil || result.DenomTrace == nil {
+		return nil, wrapRPCQueryError(err, "DenomTrace", hash)
+	}
+	trace := result.DenomTrace
+	if trace.Path == "" || trace.Hash() != hash {
+		return nil, fmt.Errorf("%w, hash: %v, path: %v, base denom: %v", ErrDenomTraceMismatch, hash, trace.Path, trace.BaseDenom)
+	}
+	b.denomTraces.traces.Store(hash, trace)
+	log.Info("resolve ibc denom trace success", "chainID", b.ChainConfig.ChainID, "hash", hash, "path", trace.Path, "baseDenom", trace.BaseDenom)
+	return trace, nil
+}
+
+// GetBaseDenom get the base denom of the origin chain of the ibc denom, or the denom itself if it is not an ibc denom
+func (b *Bridge) GetBaseDenom(denom string) (string, error) {
+	if !IsIBCDenom(denom) {
+		return denom, nil
+	}
+	trace, err := b.DenomTrace(denom)
+	if err != nil {
+		return "", err
+	}
+	return trace.BaseDenom, nil
+}
@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// ATOM transferred to osmosis through `transfer/channel-0`
+const tAtomIBCHash = "27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+
+func TestDenomTrace(t *testing.T) {
+	var queries int
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		switch strings.TrimPrefix(r.URL.Path, DenomTraces) {
+		case tAtomIBCHash:
+			_, _ = w.Write([]byte(`{"denom_trace":{"path":"transfer/channel-0","base_denom":"uatom"}}`))
+		case strings.Repeat("AB", 32):
+			_, _ = w.Write([]byte(`{"denom_trace":{"path":"transfer/channel-1","base_denom":"uatom"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	trace, err := b.DenomTrace("ibc/" + tAtomIBCHash)
+	if err != nil {
+		t.Fatalf("resolve denom trace failed: %v", err)
+	}
+	if trace.Path != "transfer/channel-0" || trace.BaseDenom != "uatom" {
+		t.Errorf("denom trace mismatch, have %+v", trace)
+	}
+	if trace.GetFullDenomPath() != "transfer/channel-0/uatom" || trace.IBCDenom() != "ibc/"+tAtomIBCHash {
+		t.Errorf("denom trace path or ibc denom mismatch, have %v %v", trace.GetFullDenomPath(), trace.IBCDenom())
+	}
+
+	// resolved trace is cached (the hash is case insensitive)
+	if trace, err = b.DenomTrace(strings.ToLower(tAtomIBCHash)); err != nil || trace.BaseDenom != "uatom" {
+		t.Errorf("get cached denom trace failed, have %v %v", trace, err)
+	}
+	if queries != 1 {
+		t.Errorf("resolved denom trace should be cached, but queried %v times", queries)
+	}
+	if baseDenom, err := b.GetBaseDenom("ibc/" + tAtomIBCHash); err != nil || baseDenom != "uatom" {
+		t.Errorf("get base denom failed, have %v %v", baseDenom, err)
+	}
+	if baseDenom, err := b.GetBaseDenom("usei"); err != nil || baseDenom != "usei" || queries != 1 {
+		t.Errorf("base denom of non ibc denom should be itself, have %v %v", baseDenom, err)
+	}
+
+	if _, err = b.DenomTrace(strings.Repeat("AB", 32)); !errors.Is(err, ErrDenomTraceMismatch) {
+		t.Errorf("trace not hashing to the denom should fail with %v, but have %v", ErrDenomTraceMismatch, err)
+	}
+	if _, err = b.DenomTrace(strings.Repeat("CD", 32)); err == nil {
+		t.Error("unknown denom trace should fail")
+	}
+}
@@ -0,0 +1,138 @@
+package cosmos
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	"github.com/gogo/protobuf/proto"
+)
+
+// public key types, configed by `pubKeyType` custom
+const (
+	// PubKeyTypeSecp256k1 the standard cosmos secp256k1 key (default)
+	PubKeyTypeSecp256k1 = "secp256k1"
+	// PubKeyTypeEthSecp256k1 the ethereum secp256k1 key of EVM compatible chains (eg. Evmos, Injective),
+	// whose address is the ethereum address (Keccak256 based) bech32 encoded.
+	PubKeyTypeEthSecp256k1 = "eth_secp256k1"
+	// PubKeyTypeInjectiveEthSecp256k1 the eth_secp256k1 key with the injective proto type
+	PubKeyTypeInjectiveEthSecp256k1 = "injective_eth_secp256k1"
+)
+
+var (
+	_ cryptoTypes.PubKey = &EthSecp256k1PubKey{}
+	_ cryptoTypes.PubKey = &InjectiveEthSecp256k1PubKey{}
+)
+
+func init() {
+	proto.RegisterType((*EthSecp256k1PubKey)(nil), "ethermint.crypto.v1.ethsecp256k1.PubKey")
+	proto.RegisterType((*InjectiveEthSecp256k1PubKey)(nil), "injective.crypto.v1beta1.ethsecp256k1.PubKey")
+}
+
+// PublicKeyRegisterInterfaces register the supported public key types
+func PublicKeyRegisterInterfaces(registry codecTypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*cryptoTypes.PubKey)(nil),
+		&secp256k1.PubKey{},
+		&EthSecp256k1PubKey{},
+		&InjectiveEthSecp256k1PubKey{},
+	)
+}
+
+// EthSecp256k1PubKey the ethermint eth_secp256k1 public key (33 bytes compressed).
+// It has the same proto encoding as the cosmos secp256k1 public key,
+// but the address derivation and signature verification are of ethereum.
+type EthSecp256k1PubKey struct {
+	secp256k1.PubKey
+}
+
+// InjectiveEthSecp256k1PubKey the eth_secp256k1 public key with injective proto type name
+type InjectiveEthSecp256k1PubKey struct {
+	EthSecp256k1PubKey
+}
+
+// Address the ethereum address of public key (last 20 bytes of Keccak256 of uncompressed key)
+func (pubKey *EthSecp256k1PubKey) Address() cryptoTypes.Address {
+	pub, err := crypto.DecompressPubkey(pubKey.Key)
+	if err != nil {
+		log.Warn("decompress eth_secp256k1 public key failed", "key", fmt.Sprintf("%X", pubKey.Key), "err", err)
+		return nil
+	}
+	return crypto.PubkeyToAddress(*pub).Bytes()
+}
+
+// Type the key type
+func (pubKey *EthSecp256k1PubKey) Type() string {
+	return PubKeyTypeEthSecp256k1
+}
+
+// Equals compare public keys of the same type
+func (pubKey *EthSecp256k1PubKey) Equals(other cryptoTypes.PubKey) bool {
+	return pubKey.Type() == other.Type() && bytes.Equal(pubKey.Bytes(), other.Bytes())
+}
+
+// VerifySignature verify the signature (R || S, and an optional V is ignored) of the Keccak256 hash of msg
+func (pubKey *EthSecp256k1PubKey) VerifySignature(msg, sig []byte) bool {
+	if len(sig) == crypto.SignatureLength {
+		sig = sig[:crypto.SignatureLength-1]
+	}
+	return crypto.VerifySignature(pubKey.Key, crypto.Keccak256(msg), sig)
+}
+
+// String the key string
+func (pubKey *EthSecp256k1PubKey) String() string {
+	return fmt.Sprintf("EthPubKeySecp256k1{%X}", pubKey.Key)
+}
+
+// GetPubKeyType get the public key type of chain, configed by `pubKeyType` custom
+func (b *Bridge) GetPubKeyType() string {
+	if b.ChainConfig == nil {
+		return PubKeyTypeSecp256k1
+	}
+	keyType := params.GetCustom(b.ChainConfig.ChainID, "pubKeyType")
+	switch keyType {
+	case "":
+		return PubKeyTypeSecp256k1
+	case PubKeyTypeSecp256k1, PubKeyTypeEthSecp256k1, PubKeyTypeInjectiveEthSecp256k1:
+		return keyType
+	default:
+		log.Warn("unknown public key type, use default", "chainID", b.ChainConfig.ChainID, "pubKeyType", keyType)
+		return PubKeyTypeSecp256k1
+	}
+}
+
+// PubKeyFromStr get public key of the chain's public key type from hex string
+func (b *Bridge) PubKeyFromStr(pubKeyHex string) (cryptoTypes.PubKey, error) {
+	pubKey, err := PubKeyFromStr(pubKeyHex)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertPubKeyType(pubKey, b.GetPubKeyType())
+}
+
+// ConvertPubKeyType convert secp256k1 public key to the public key type
+func ConvertPubKeyType(pubKey cryptoTypes.PubKey, keyType string) (cryptoTypes.PubKey, error) {
+	ethPubKey := EthSecp256k1PubKey{PubKey: secp256k1.PubKey{Key: pubKey.Bytes()}}
+	switch keyType {
+	case PubKeyTypeSecp256k1:
+		return pubKey, nil
+	case PubKeyTypeEthSecp256k1:
+		return &ethPubKey, nil
+	case PubKeyTypeInjectiveEthSecp256k1:
+		return &InjectiveEthSecp256k1PubKey{EthSecp256k1PubKey: ethPubKey}, nil
+	default:
+		return nil, fmt.Errorf("unknown public key type %v", keyType)
+	}
+}
+
+// getSignHash get the hash of sign bytes to be signed by mpc
+func getSignHash(pubKey cryptoTypes.PubKey, signBytes []byte) []byte {
+	if pubKey.Type() == PubKeyTypeEthSecp256k1 {
+		return crypto.Keccak256(signBytes)
+	}
+	return Sha256Sum(signBytes)
+}
@@ -0,0 +1,143 @@
+package cosmos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func newTestEthSecp256k1Bridge(t *testing.T, keyType string) *Bridge {
+	b := NewCrossChainBridge()
+	b.Prefix = "inj"
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1234567"}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"pubKeyType": keyType})
+	return b
+}
+
+func TestEthSecp256k1Address(t *testing.T) {
+	b := newTestEthSecp256k1Bridge(t, PubKeyTypeEthSecp256k1)
+
+	priv, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	ethAddress := crypto.PubkeyToAddress(priv.PublicKey)
+
+	for _, pubKeyHex := range []string{
+		fmt.Sprintf("%X", crypto.FromECDSAPub(&priv.PublicKey)),
+		fmt.Sprintf("%X", crypto.CompressPubkey(&priv.PublicKey)),
+	} {
+		address, err := b.PublicKeyToAddress(pubKeyHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		bz, err := sdk.GetFromBech32(address, b.Prefix)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(bz, ethAddress.Bytes()) {
+			t.Errorf("eth_secp256k1 address mismatch, have %v (0x%x) want %v", address, bz, ethAddress.String())
+		}
+		if err = b.VerifyPubKey(address, pubKeyHex); err != nil {
+			t.Errorf("verify eth_secp256k1 public key failed: %v", err)
+		}
+
+		// the standard secp256k1 address is different
+		cosmosAddress, err := PublicKeyToAddress(b.Prefix, pubKeyHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if cosmosAddress == address {
+			t.Errorf("eth_secp256k1 address should differ from the secp256k1 one")
+		}
+	}
+}
+
+func TestEthSecp256k1PubKeyEncoding(t *testing.T) {
+	priv, _ := crypto.GenerateKey()
+	pubKeyHex := fmt.Sprintf("%X", crypto.CompressPubkey(&priv.PublicKey))
+
+	for keyType, typeURL := range map[string]string{
+		PubKeyTypeEthSecp256k1:          "/ethermint.crypto.v1.ethsecp256k1.PubKey",
+		PubKeyTypeInjectiveEthSecp256k1: "/injective.crypto.v1beta1.ethsecp256k1.PubKey",
+	} {
+		b := newTestEthSecp256k1Bridge(t, keyType)
+		pubKey, err := b.PubKeyFromStr(pubKeyHex)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if pubKey.Type() != PubKeyTypeEthSecp256k1 || PubKeyTypeURL(pubKey) != typeURL {
+			t.Errorf("%v public key type mismatch, have %v %v", keyType, pubKey.Type(), PubKeyTypeURL(pubKey))
+		}
+
+		anyPubKey, err := codecTypes.NewAnyWithValue(pubKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var decoded cryptoTypes.PubKey
+		if err = b.InterfaceRegistry().UnpackAny(anyPubKey, &decoded); err != nil {
+			t.Fatalf("unpack %v public key failed: %v", keyType, err)
+		}
+		if !decoded.Equals(pubKey) || !bytes.Equal(decoded.Address(), pubKey.Address()) {
+			t.Errorf("decoded %v public key mismatch, have %v want %v", keyType, decoded, pubKey)
+		}
+
+		// signatures are of the Keccak256 hash, with or without the recovery byte
+		msg := []byte("sign bytes")
+		sig, err := crypto.Sign(getSignHash(pubKey, msg), priv)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !pubKey.VerifySignature(msg, sig) || !pubKey.VerifySignature(msg, sig[:64]) {
+			t.Errorf("verify %v signature failed", keyType)
+		}
+		if pubKey.VerifySignature([]byte("other bytes"), sig) {
+			t.Errorf("verify %v signature of wrong message should fail", keyType)
+		}
+	}
+}
+
+func TestVerifyMsgHashEthSecp256k1(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"` + tSeiChainID + `"}}}`))
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"pubKeyType": PubKeyTypeEthSecp256k1})
+
+	ecPrikey, err := crypto.HexToECDSA(tSignerPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey, err := b.PubKeyFromStr(fmt.Sprintf("%X", crypto.FromECDSAPub(&ecPrikey.PublicKey)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err = txBuilder.SetMsgs(BuildSendMsg(tMPCAddress, tMPCAddress, "usei", big.NewInt(1000))); err != nil {
+		t.Fatal(err)
+	}
+	if err = txBuilder.SetSignatures(BuildSignatures(pubKey, 3, nil)); err != nil {
+		t.Fatal(err)
+	}
+	rawTx := &BuildRawTx{TxBuilder: txBuilder, AccountNumber: 9, Sequence: 3}
+	signBytes, err := b.GetSignBytes(rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the msg hash signed by mpc is keccak256 of the sign bytes
+	if err = b.VerifyMsgHash(rawTx, []string{fmt.Sprintf("%X", crypto.Keccak256(signBytes))}); err != nil {
+		t.Errorf("verify keccak256 msg hash failed: %v", err)
+	}
+	if err = b.VerifyMsgHash(rawTx, []string{fmt.Sprintf("%X", Sha256Sum(signBytes))}); !errors.Is(err, tokens.ErrMsgHashMismatch) {
+		t.Errorf("sha256 msg hash should fail with %v, but have %v", tokens.ErrMsgHashMismatch, err)
+	}
+}
@@ -0,0 +1,113 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// CoinReceivedType the bank event emitted for each receiver of coins (no sender)
+	CoinReceivedType = "coin_received"
+
+	// attribute keys of the transfer and coin_received events
+	TransferRecipientKey = "recipient"
+	TransferSenderKey    = "sender"
+	TransferAmountKey    = "amount"
+	CoinReceiverKey      = "receiver"
+)
+
+var (
+	// ErrTransferNotFound the message log has neither transfer nor coin_received event
+	ErrTransferNotFound = errors.New("transfer event not found")
+)
+
+// TransferEvent transfer of coins emitted in the transfer or coin_received event
+type TransferEvent struct {
+	Sender    string    `json:"sender,omitempty"`
+	Recipient string    `json:"recipient"`
+	Amount    sdk.Coins `json:"amount"`
+}
+
+// ParseTransferEvents parse transfers of message log.
+// The same type events of a message are merged into one event in the message log,
+// so attributes are grouped into transfers, each of which starts with the recipient.
+// The coin_received events are used if there is no transfer event (no sender then).
+func ParseTransferEvents(messageLog sdk.ABCIMessageLog) ([]*TransferEvent, error) {
+	transfers, err := parseTransferEvents(messageLog, TransferType, TransferRecipientKey)
+	if err != nil || len(transfers) > 0 {
+		return transfers, err
+	}
+	transfers, err = parseTransferEvents(messageLog, CoinReceivedType, CoinReceiverKey)
+	if err != nil || len(transfers) > 0 {
+		return transfers, err
+	}
+	return nil, ErrTransferNotFound
+}
+
+func parseTransferEvents(messageLog sdk.ABCIMessageLog, eventType, recipientKey string) (transfers []*TransferEvent, err error) {
+	var current *TransferEvent
+	for _, event := range messageLog.Events {
+		if event.Type != eventType {
+			continue
+		}
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case recipientKey:
+				current = &TransferEvent{Recipient: attr.Value}
+				transfers = append(transfers, current)
+			case TransferSenderKey:
+				if current != nil {
+					current.Sender = attr.Value
+				}
+			case TransferAmountKey:
+				if current == nil {
+					return nil, fmt.Errorf("%v event has amount without %v", eventType, recipientKey)
+				}
+				if current.Amount, err = ParseCoinsNormalized(attr.Value); err != nil {
+					return nil, fmt.Errorf("parse %v amount '%v' failed: %w", eventType, attr.Value, err)
+				}
+			}
+		}
+	}
+	return transfers, nil
+}
+
+// GetReceivedAmount get the total amount of denom received by the recipient
+func GetReceivedAmount(transfers []*TransferEvent, recipient, denom string) *big.Int {
+	total := big.NewInt(0)
+	for _, transfer := range transfers {
+		if !common.IsEqualIgnoreCase(transfer.Recipient, recipient) {
+			continue
+		}
+		amount := transfer.Amount.AmountOfNoDenomValidation(denom)
+		if !amount.IsNil() {
+			total.Add(total, amount.BigInt())
+		}
+	}
+	return total
+}
+
+// VerifyTransferEvents verify the router mpc received the expected value of denom
+// according to the transfer events of message log (like verifying evm deposits by logs)
+func (b *Bridge) VerifyTransferEvents(messageLog sdk.ABCIMessageLog, denom string, expected *big.Int) error {
+	transfers, err := ParseTransferEvents(messageLog)
+	if err != nil {
+		return err
+	}
+	mpc := b.GetRouterContract(denom)
+	received := GetReceivedAmount(transfers, mpc, denom)
+	if received.Sign() <= 0 {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	if expected == nil || received.Cmp(expected) != 0 {
+		log.Warn("transfer events value mismatch", "denom", denom, "mpc", mpc, "received", received, "expected", expected)
+		return tokens.ErrTxWithWrongValue
+	}
+	return nil
+}
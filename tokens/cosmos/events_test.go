@@ -0,0 +1,69 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const tTransferLog = `{"msg_index":0,"events":[
+{"type":"coin_received","attributes":[{"key":"receiver","value":"cosmos1mpc"},{"key":"amount","value":"1500000uatom"},{"key":"receiver","value":"cosmos1other"},{"key":"amount","value":"10uatom"}]},
+{"type":"coin_spent","attributes":[{"key":"spender","value":"cosmos1sender"},{"key":"amount","value":"1500000uatom"},{"key":"spender","value":"cosmos1sender"},{"key":"amount","value":"10uatom"}]},
+{"type":"message","attributes":[{"key":"action","value":"/cosmos.bank.v1beta1.MsgSend"},{"key":"sender","value":"cosmos1sender"},{"key":"module","value":"bank"}]},
+{"type":"transfer","attributes":[{"key":"recipient","value":"cosmos1mpc"},{"key":"sender","value":"cosmos1sender"},{"key":"amount","value":"1500000uatom"},{"key":"recipient","value":"cosmos1other"},{"key":"sender","value":"cosmos1sender"},{"key":"amount","value":"10uatom"}]}]}`
+
+func TestParseTransferEvents(t *testing.T) {
+	var messageLog sdk.ABCIMessageLog
+	if err := json.Unmarshal([]byte(tTransferLog), &messageLog); err != nil {
+		t.Fatal(err)
+	}
+
+	transfers, err := ParseTransferEvents(messageLog)
+	if err != nil {
+		t.Fatalf("parse transfer events failed: %v", err)
+	}
+	if len(transfers) != 2 {
+		t.Fatalf("parse transfer events count mismatch, have %v want 2", len(transfers))
+	}
+	if transfers[0].Sender != "cosmos1sender" || transfers[0].Recipient != "cosmos1mpc" ||
+		!transfers[0].Amount.IsEqual(sdk.NewCoins(sdk.NewInt64Coin("uatom", 1500000))) {
+		t.Errorf("parse transfer event mismatch, have %+v", transfers[0])
+	}
+	if have := GetReceivedAmount(transfers, "cosmos1other", "uatom"); have.Int64() != 10 {
+		t.Errorf("received amount mismatch, have %v want 10", have)
+	}
+
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1", RouterContract: "cosmos1mpc"}
+	b.SetTokenConfig("uatom", &tokens.TokenConfig{TokenID: "ATOM", ContractAddress: "uatom"})
+	if err = b.VerifyTransferEvents(messageLog, "uatom", big.NewInt(1500000)); err != nil {
+		t.Errorf("verify transfer events failed: %v", err)
+	}
+	if err = b.VerifyTransferEvents(messageLog, "uatom", big.NewInt(1500001)); !errors.Is(err, tokens.ErrTxWithWrongValue) {
+		t.Errorf("verify transfer events with wrong value should fail with %v, but have %v", tokens.ErrTxWithWrongValue, err)
+	}
+
+	// without transfer events, fallback to coin_received events
+	messageLog.Events = messageLog.Events[:3]
+	transfers, err = ParseTransferEvents(messageLog)
+	if err != nil || len(transfers) != 2 || transfers[0].Sender != "" {
+		t.Fatalf("parse coin_received events mismatch, have %v %v", transfers, err)
+	}
+	if have := GetReceivedAmount(transfers, "cosmos1mpc", "uatom"); have.Int64() != 1500000 {
+		t.Errorf("received amount mismatch, have %v want 1500000", have)
+	}
+
+	b.ChainConfig.RouterContract = "cosmos1another"
+	if err = b.VerifyTransferEvents(messageLog, "uatom", big.NewInt(1500000)); !errors.Is(err, tokens.ErrTxWithWrongReceiver) {
+		t.Errorf("verify transfer events to other receiver should fail with %v, but have %v", tokens.ErrTxWithWrongReceiver, err)
+	}
+
+	messageLog.Events = messageLog.Events[1:3]
+	if _, err = ParseTransferEvents(messageLog); !errors.Is(err, ErrTransferNotFound) {
+		t.Errorf("parse log without transfers should fail with %v, but have %v", ErrTransferNotFound, err)
+	}
+}
@@ -0,0 +1,82 @@
+package cosmos
+
+import (
+	"errors"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+	signingTypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+// ErrMissSignerPubKey the raw tx has no signer public key, which is set by BuildTx
+var ErrMissSignerPubKey = errors.New("raw tx has no signer public key")
+
+// SigningBytes the sign bytes of a tx for external signers (eg. HSM, external MPC)
+type SigningBytes struct {
+	SignMode      string `json:"signMode"`
+	ChainID       string `json:"chainID"`
+	AccountNumber uint64 `json:"accountNumber"`
+	Sequence      uint64 `json:"sequence"`
+	PubKeyType    string `json:"pubKeyType"`
+	SignBytes     []byte `json:"signBytes"`
+	SignHash      []byte `json:"signHash"` // sha256 (keccak256 for eth_secp256k1) of the sign bytes, which is signed by mpc
+}
+
+// GetSigningBytes returns the sign bytes of the unsigned tx (in the direct sign mode) and the signer data used,
+// which is exactly what MPCSignTransaction signs. The external signer signs the sign hash with the key of the
+// signer public key of the tx, and the signed tx is made by MakeSignedTransaction with the signature.
+func (b *Bridge) GetSigningBytes(rawTx interface{}) (*SigningBytes, error) {
+	buildRawTx, ok := rawTx.(*BuildRawTx)
+	if !ok {
+		return nil, tokens.ErrWrongRawTx
+	}
+	pubKey, err := getSignerPubKey(buildRawTx)
+	if err != nil {
+		return nil, err
+	}
+	signerData, signBytes, err := b.getSignBytes(buildRawTx)
+	if err != nil {
+		return nil, err
+	}
+	return &SigningBytes{
+		SignMode:      signingTypes.SignMode_SIGN_MODE_DIRECT.String(),
+		ChainID:       signerData.ChainID,
+		AccountNumber: signerData.AccountNumber,
+		Sequence:      signerData.Sequence,
+		PubKeyType:    pubKey.Type(),
+		SignBytes:     signBytes,
+		SignHash:      getSignHash(pubKey, signBytes),
+	}, nil
+}
+
+// MakeSignedTransaction make the signed tx with the signature (R || S || V or R || S) of the sign hash
+// returned by GetSigningBytes. The signature is verified against the signer public key of the tx.
+func (b *Bridge) MakeSignedTransaction(rawTx interface{}, signature []byte) (signedTx interface{}, txHash string, err error) {
+	buildRawTx, ok := rawTx.(*BuildRawTx)
+	if !ok {
+		return nil, "", tokens.ErrWrongRawTx
+	}
+	pubKey, err := getSignerPubKey(buildRawTx)
+	if err != nil {
+		return nil, "", err
+	}
+	signBytes, err := b.GetSignBytes(buildRawTx)
+	if err != nil {
+		return nil, "", err
+	}
+	return b.assembleSignedTx(buildRawTx, pubKey, signBytes, signature, "")
+}
+
+func getSignerPubKey(rawTx *BuildRawTx) (cryptoTypes.PubKey, error) {
+	if rawTx.TxBuilder == nil {
+		return nil, tokens.ErrWrongRawTx
+	}
+	sigs, err := rawTx.TxBuilder.GetTx().GetSignaturesV2()
+	if err != nil {
+		return nil, err
+	}
+	if len(sigs) != 1 || sigs[0].PubKey == nil {
+		return nil, ErrMissSignerPubKey
+	}
+	return sigs[0].PubKey, nil
+}
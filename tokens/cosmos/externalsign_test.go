@@ -0,0 +1,83 @@
+package cosmos
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+)
+
+func TestGetSigningBytes(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LatestBlock {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"` + tSeiChainID + `"}}}`))
+	})
+	ecPrikey, err := crypto.HexToECDSA(tSignerPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pubKey := (&secp256k1.PrivKey{Key: crypto.FromECDSA(ecPrikey)}).PubKey()
+
+	newRawTx := func(withPubKey bool) *BuildRawTx {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(BuildSendMsg(tMPCAddress, tMPCAddress, "usei", big.NewInt(1000))); err != nil {
+			t.Fatal(err)
+		}
+		txBuilder.SetGasLimit(DefaultGasLimit)
+		if withPubKey {
+			if err := txBuilder.SetSignatures(BuildSignatures(pubKey, 3, nil)); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return &BuildRawTx{TxBuilder: txBuilder, AccountNumber: 9, Sequence: 3}
+	}
+
+	signingBytes, err := b.GetSigningBytes(newRawTx(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signingBytes.SignMode != "SIGN_MODE_DIRECT" || signingBytes.ChainID != tSeiChainID ||
+		signingBytes.AccountNumber != 9 || signingBytes.Sequence != 3 || signingBytes.PubKeyType != PubKeyTypeSecp256k1 {
+		t.Errorf("signer data mismatch, have %+v", signingBytes)
+	}
+
+	// the same as the internal sign path
+	signBytes, err := b.GetSignBytes(newRawTx(true))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signingBytes.SignBytes, signBytes) || !bytes.Equal(signingBytes.SignHash, Sha256Sum(signBytes)) {
+		t.Errorf("sign bytes mismatch, have %X %X", signingBytes.SignBytes, signingBytes.SignHash)
+	}
+
+	// sign externally and feed the signature back
+	signature, err := crypto.Sign(signingBytes.SignHash, ecPrikey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedTx, txHash, err := b.MakeSignedTransaction(newRawTx(true), signature)
+	if err != nil {
+		t.Fatalf("make signed tx failed: %v", err)
+	}
+	wantSignedTx, wantTxHash, err := b.SignTransactionWithPrivateKey(newRawTx(true), tSignerPrivKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(signedTx.([]byte), wantSignedTx.([]byte)) || txHash != wantTxHash {
+		t.Errorf("externally signed tx mismatch, have %v want %v", txHash, wantTxHash)
+	}
+
+	if _, _, err = b.MakeSignedTransaction(newRawTx(true), make([]byte, 65)); err == nil {
+		t.Error("make signed tx with wrong signature should fail")
+	}
+	if _, err = b.GetSigningBytes(newRawTx(false)); !errors.Is(err, ErrMissSignerPubKey) {
+		t.Errorf("raw tx without signer public key should fail with %v, but have %v", ErrMissSignerPubKey, err)
+	}
+}
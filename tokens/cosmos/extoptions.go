@@ -0,0 +1,157 @@
+package cosmos
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	txExtensionOptionName           = "cosmos.tx.v1beta1.TxExtensionOptionI"
+	extensionOptionDynamicFeeTxName = "ethermint.types.v1.ExtensionOptionDynamicFeeTx"
+)
+
+// TxExtensionOptionI extension option of the tx body (`extension_options` and `non_critical_extension_options`)
+type TxExtensionOptionI interface {
+	proto.Message
+}
+
+// ExtensionOptionDynamicFeeTx ethermint dynamic fee extension option (`ethermint.types.v1.ExtensionOptionDynamicFeeTx`),
+// which specifies the max priority price (tip) of the cosmos tx on ethermint based chains.
+type ExtensionOptionDynamicFeeTx struct {
+	MaxPriorityPrice sdk.Int
+}
+
+var _ TxExtensionOptionI = &ExtensionOptionDynamicFeeTx{}
+
+// ethermintFileDescriptor gzipped file descriptor of ExtensionOptionDynamicFeeTx
+var ethermintFileDescriptor = newProtoFileDescriptor("ethermint/types/v1/dynamic_fee.proto", "ethermint.types.v1", nil,
+	&descriptor.DescriptorProto{
+		Name: proto.String("ExtensionOptionDynamicFeeTx"),
+		Field: []*descriptor.FieldDescriptorProto{
+			newProtoField("max_priority_price", 1, descriptor.FieldDescriptorProto_TYPE_STRING),
+		},
+	})
+
+// RegisterExtensionOptionInterfaces register the extension options,
+// the tx decoder refuses extension options of unregistered types.
+func RegisterExtensionOptionInterfaces(registry codecTypes.InterfaceRegistry) {
+	registry.RegisterInterface(txExtensionOptionName, (*TxExtensionOptionI)(nil),
+		&ExtensionOptionDynamicFeeTx{},
+	)
+}
+
+// Reset impl proto.Message
+func (m *ExtensionOptionDynamicFeeTx) Reset() { *m = ExtensionOptionDynamicFeeTx{} }
+
+// String impl proto.Message
+func (m *ExtensionOptionDynamicFeeTx) String() string {
+	return fmt.Sprintf("max_priority_price:%q", m.MaxPriorityPrice)
+}
+
+// ProtoMessage impl proto.Message
+func (*ExtensionOptionDynamicFeeTx) ProtoMessage() {}
+
+// XXX_MessageName the full proto name (used as the type url)
+func (*ExtensionOptionDynamicFeeTx) XXX_MessageName() string { //nolint:revive,stylecheck // proto convention
+	return extensionOptionDynamicFeeTxName
+}
+
+// Descriptor gzipped file descriptor and the message index
+func (*ExtensionOptionDynamicFeeTx) Descriptor() ([]byte, []int) {
+	return ethermintFileDescriptor, []int{0}
+}
+
+// Marshal proto encoding
+func (m *ExtensionOptionDynamicFeeTx) Marshal() ([]byte, error) {
+	var bz []byte
+	if !m.MaxPriorityPrice.IsNil() {
+		bz = protowire.AppendTag(bz, 1, protowire.BytesType)
+		bz = protowire.AppendString(bz, m.MaxPriorityPrice.String())
+	}
+	return bz, nil
+}
+
+// Unmarshal proto decoding
+func (m *ExtensionOptionDynamicFeeTx) Unmarshal(bz []byte) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if num != 1 || typ != protowire.BytesType {
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		var value string
+		value, n = protowire.ConsumeString(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		price, ok := sdk.NewIntFromString(value)
+		if !ok {
+			return fmt.Errorf("invalid max priority price %v", value)
+		}
+		m.MaxPriorityPrice = price
+	}
+	return nil
+}
+
+// Size proto encoding size
+func (m *ExtensionOptionDynamicFeeTx) Size() int {
+	bz, _ := m.Marshal()
+	return len(bz)
+}
+
+// getExtensionOptions get the extension options of the chain.
+// `dynamicFeeExtension` custom (max priority price, eg. `0`) adds the ethermint dynamic fee extension option,
+// it is put into the non critical extension options if `nonCriticalExtensionOptions` custom is `true`.
+func (b *Bridge) getExtensionOptions() (extOpts, nonCriticalExtOpts []*codecTypes.Any, err error) {
+	chainID := b.ChainConfig.ChainID
+	var opts []*codecTypes.Any
+	if priceStr := params.GetCustom(chainID, "dynamicFeeExtension"); priceStr != "" {
+		price, ok := sdk.NewIntFromString(priceStr)
+		if !ok || price.IsNegative() {
+			return nil, nil, fmt.Errorf("wrong dynamicFeeExtension custom %v", priceStr)
+		}
+		opt, errf := codecTypes.NewAnyWithValue(&ExtensionOptionDynamicFeeTx{MaxPriorityPrice: price})
+		if errf != nil {
+			return nil, nil, errf
+		}
+		opts = append(opts, opt)
+	}
+	if params.GetCustom(chainID, "nonCriticalExtensionOptions") == "true" {
+		return nil, opts, nil
+	}
+	return opts, nil, nil
+}
+
+// setExtensionOptions set the extension options of the chain to the tx body
+func (b *Bridge) setExtensionOptions(txBuilder cosmosClient.TxBuilder) error {
+	extOpts, nonCriticalExtOpts, err := b.getExtensionOptions()
+	if err != nil || (len(extOpts) == 0 && len(nonCriticalExtOpts) == 0) {
+		return err
+	}
+	extBuilder, ok := txBuilder.(authTx.ExtensionOptionsTxBuilder)
+	if !ok {
+		return fmt.Errorf("tx builder %T can not set extension options", txBuilder)
+	}
+	extBuilder.SetExtensionOptions(extOpts...)
+	extBuilder.SetNonCriticalExtensionOptions(nonCriticalExtOpts...)
+	log.Debug("set tx extension options", "chainID", b.ChainConfig.ChainID, "extOpts", len(extOpts), "nonCriticalExtOpts", len(nonCriticalExtOpts))
+	return nil
+}
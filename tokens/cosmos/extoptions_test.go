@@ -0,0 +1,65 @@
+package cosmos
+
+import (
+	"net/http"
+	"testing"
+
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/ante"
+)
+
+func TestSetExtensionOptions(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v", r.URL)
+	})
+
+	// round trip the tx body and read back the extension options
+	roundTrip := func() ante.HasExtensionOptionsTx {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := b.setExtensionOptions(txBuilder); err != nil {
+			t.Fatalf("set extension options failed: %v", err)
+		}
+		txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+		if err != nil {
+			t.Fatal(err)
+		}
+		tx, err := b.TxConfig.TxDecoder()(txBytes)
+		if err != nil {
+			t.Fatalf("decode tx with extension options failed: %v", err)
+		}
+		return tx.(ante.HasExtensionOptionsTx)
+	}
+	checkOption := func(opts []*codecTypes.Any) {
+		if len(opts) != 1 || opts[0].TypeUrl != "/"+extensionOptionDynamicFeeTxName {
+			t.Fatalf("extension options mismatch, have %v", opts)
+		}
+		var opt ExtensionOptionDynamicFeeTx
+		if err := opt.Unmarshal(opts[0].Value); err != nil || !opt.MaxPriorityPrice.Equal(sdk.NewInt(100)) {
+			t.Errorf("dynamic fee extension option mismatch, have %v %v", opt.MaxPriorityPrice, err)
+		}
+	}
+
+	if tx := roundTrip(); len(tx.GetExtensionOptions()) != 0 || len(tx.GetNonCriticalExtensionOptions()) != 0 {
+		t.Errorf("no extension options should be set if not configed")
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"dynamicFeeExtension": "100"})
+	tx := roundTrip()
+	checkOption(tx.GetExtensionOptions())
+	if len(tx.GetNonCriticalExtensionOptions()) != 0 {
+		t.Errorf("non critical extension options should be empty")
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"dynamicFeeExtension": "100", "nonCriticalExtensionOptions": "true"})
+	tx = roundTrip()
+	checkOption(tx.GetNonCriticalExtensionOptions())
+	if len(tx.GetExtensionOptions()) != 0 {
+		t.Errorf("extension options should be empty")
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"dynamicFeeExtension": "-1"})
+	if err := b.setExtensionOptions(b.TxConfig.NewTxBuilder()); err == nil {
+		t.Errorf("negative max priority price should fail")
+	}
+}
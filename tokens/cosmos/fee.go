@@ -0,0 +1,212 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+var (
+	// ErrNoFeePayer neither the payer nor the fee granter can pay any fee candidate
+	ErrNoFeePayer = errors.New("no account can pay the fee")
+)
+
+// GetFeeGranter get the account which grants the mpc a fee allowance (feegrant).
+// It is configured by the `feeGranter` custom, and it pays the fee only if the mpc can not.
+func (b *Bridge) GetFeeGranter() string {
+	return params.GetCustom(b.ChainConfig.ChainID, "feeGranter")
+}
+
+// getMultiDenomFee get the fee of multiple coins for chains requiring fees in multiple denoms,
+// configed by `multiDenomFee` custom (comma separated coins, eg. `100usei,5000uatom`).
+// It is normalized to the canonical coins (sorted by denom), empty if not configed.
+func (b *Bridge) getMultiDenomFee() (string, error) {
+	fee := params.GetCustom(b.ChainConfig.ChainID, "multiDenomFee")
+	if fee == "" {
+		return "", nil
+	}
+	feeCoins, err := ParseCoinsFee(fee)
+	if err != nil {
+		return "", fmt.Errorf("wrong multiDenomFee custom %v, %w", fee, err)
+	}
+	if feeCoins.Empty() {
+		return "", fmt.Errorf("wrong multiDenomFee custom %v, no positive coin", fee)
+	}
+	return feeCoins.String(), nil
+}
+
+// getFeeCandidates returns the default fee and the alternative fees
+// configed by `feeAlternatives` custom (comma separated, eg. `5000uosmo,6000ibc/ABC`),
+// or only the multi denom fee if it is configed.
+func (b *Bridge) getFeeCandidates() []string {
+	if fee, err := b.getMultiDenomFee(); err != nil {
+		log.Warn("wrong multi denom fee", "chainID", b.ChainConfig.ChainID, "err", err)
+	} else if fee != "" {
+		return []string{fee}
+	}
+	candidates := []string{b.getDefaultFee()}
+	alternatives := params.GetCustom(b.ChainConfig.ChainID, "feeAlternatives")
+	for _, fee := range strings.Split(alternatives, ",") {
+		if fee = strings.TrimSpace(fee); fee != "" {
+			candidates = append(candidates, fee)
+		}
+	}
+	return candidates
+}
+
+// getFlooredFeeCandidates get the fee candidates bumped to the min gas price of the gas (see enforceMinGasPrice),
+// the candidates whose bumped fee exceeds the cap are skipped, it fails if all of them are skipped.
+func (b *Bridge) getFlooredFeeCandidates(gas uint64) ([]string, error) {
+	var candidates []string
+	var err error
+	for _, fee := range b.getFeeCandidates() {
+		floored, errf := b.enforceMinGasPrice(fee, gas)
+		if errf != nil {
+			log.Warn("skip fee candidate", "chainID", b.ChainConfig.ChainID, "fee", fee, "gas", gas, "err", errf)
+			if err == nil {
+				err = errf
+			}
+			continue
+		}
+		candidates = append(candidates, floored)
+	}
+	if len(candidates) == 0 {
+		return nil, err
+	}
+	return candidates, nil
+}
+
+// selectFee select fee by the ordered fallback chain:
+// 1. the first fee candidate the payer has enough balance to pay,
+// 2. the first txfees fee token (osmosis) the payer has enough balance to pay the converted default fee,
+// a failed txfees query is logged and falls back to the fee granter,
+// 3. the first fee candidate the fee granter has enough balance to pay,
+// 4. fails if the fee granter is set, otherwise the default fee is used.
+// payout is also taken into account if it is paid by the payer with the same denom.
+// the multi denom fee has no fallback, see selectMultiDenomFee.
+// the fee candidates are bumped to the min gas price of the gas before checking the balances.
+func (b *Bridge) selectFee(payer, payoutDenom string, payout *big.Int, gas uint64) (string, error) {
+	multiDenomFee, err := b.getMultiDenomFee()
+	if err != nil {
+		return "", err
+	}
+	if multiDenomFee != "" {
+		return b.selectMultiDenomFee(payer, multiDenomFee, payoutDenom, payout)
+	}
+	candidates, err := b.getFlooredFeeCandidates(gas)
+	if err != nil {
+		return "", err
+	}
+	feeGranter := b.GetFeeGranter()
+	if len(candidates) == 1 && feeGranter == "" && len(b.getTxFeesTokens()) == 0 {
+		return candidates[0], nil
+	}
+	fee, err := b.selectAffordableFee(payer, candidates, payoutDenom, payout)
+	if err != nil || fee != "" {
+		return fee, err
+	}
+	fee, err = b.selectTxFeesToken(payer, candidates[0], payoutDenom, payout)
+	if err != nil {
+		log.Warn("select txfees fee token failed", "chainID", b.ChainConfig.ChainID, "payer", payer, "err", err)
+	} else if fee != "" {
+		return fee, nil
+	}
+	if feeGranter != "" {
+		fee, err = b.selectAffordableFee(feeGranter, candidates, "", nil)
+		if err != nil {
+			return "", err
+		}
+		if fee != "" {
+			log.Info("fee will be paid by the fee granter", "payer", payer, "feeGranter", feeGranter, "fee", fee)
+			return fee, nil
+		}
+		return "", fmt.Errorf("%w, payer: %v, fee granter: %v, candidates: %v", ErrNoFeePayer, payer, feeGranter, candidates)
+	}
+	log.Warn("no fee candidate is affordable, use the default fee", "payer", payer, "candidates", candidates)
+	return candidates[0], nil
+}
+
+// selectMultiDenomFee use the multi denom fee if the payer (or else the fee granter) holds enough balance of each denom,
+// otherwise it fails instead of building a tx which can not pay the fee.
+func (b *Bridge) selectMultiDenomFee(payer, fee, payoutDenom string, payout *big.Int) (string, error) {
+	enough, err := b.canAffordFee(payer, fee, payoutDenom, payout, make(map[string]sdk.Int))
+	if err != nil {
+		return "", err
+	}
+	if enough {
+		return fee, nil
+	}
+	feeGranter := b.GetFeeGranter()
+	if feeGranter != "" {
+		if enough, err = b.canAffordFee(feeGranter, fee, "", nil, make(map[string]sdk.Int)); err != nil {
+			return "", err
+		}
+		if enough {
+			log.Info("fee will be paid by the fee granter", "payer", payer, "feeGranter", feeGranter, "fee", fee)
+			return fee, nil
+		}
+	}
+	return "", fmt.Errorf("%w, payer: %v, fee granter: %v, fee: %v", ErrNoFeePayer, payer, feeGranter, fee)
+}
+
+// selectAffordableFee select the first fee candidate the account has enough balance to pay,
+// returns empty string if no candidate is affordable.
+func (b *Bridge) selectAffordableFee(account string, candidates []string, payoutDenom string, payout *big.Int) (string, error) {
+	balances := make(map[string]sdk.Int)
+	for _, fee := range candidates {
+		enough, err := b.canAffordFee(account, fee, payoutDenom, payout, balances)
+		if err != nil {
+			return "", err
+		}
+		if enough {
+			return fee, nil
+		}
+		log.Info("not enough balance to pay fee", "account", account, "fee", fee)
+	}
+	return "", nil
+}
+
+// canAffordFee whether the account has enough balance to pay the fee,
+// the queried balances are cached in balances.
+func (b *Bridge) canAffordFee(account, fee, payoutDenom string, payout *big.Int, balances map[string]sdk.Int) (bool, error) {
+	feeCoins, err := ParseCoinsFee(fee)
+	if err != nil {
+		log.Warn("wrong fee config", "chainID", b.ChainConfig.ChainID, "fee", fee, "err", err)
+		return false, nil
+	}
+	for _, coin := range feeCoins {
+		balance, exist := balances[coin.Denom]
+		if !exist {
+			if balance, err = b.GetDenomBalance(account, coin.Denom); err != nil {
+				return false, err
+			}
+			balances[coin.Denom] = balance
+		}
+		need := coin.Amount
+		if coin.Denom == payoutDenom && payout != nil {
+			need = need.Add(sdk.NewIntFromBigInt(payout))
+		}
+		if balance.LT(need) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// getFeeGranterOf returns the fee granter if the payer can not afford the selected fee
+func (b *Bridge) getFeeGranterOf(payer, fee, payoutDenom string, payout *big.Int) (string, error) {
+	feeGranter := b.GetFeeGranter()
+	if feeGranter == "" {
+		return "", nil
+	}
+	enough, err := b.canAffordFee(payer, fee, payoutDenom, payout, make(map[string]sdk.Int))
+	if err != nil || enough {
+		return "", err
+	}
+	return feeGranter, nil
+}
@@ -0,0 +1,156 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestSelectFee(t *testing.T) {
+	var balances string
+	var queried bool
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != Balances+"sei1payer" {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		queried = true
+		_, _ = w.Write([]byte(`{"balances":[` + balances + `]}`))
+	})
+
+	// no alternatives, use the default fee without querying balances
+	if fee, err := b.selectFee("sei1payer", "usei", big.NewInt(1000), DefaultGasLimit); err != nil || fee != "500usei" || queried {
+		t.Errorf("select fee without alternatives failed, have %v %v, queried %v", fee, err, queried)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"feeAlternatives": "3000ufoo, 100ubar"})
+	tests := []struct {
+		name        string
+		balances    string
+		payoutDenom string
+		want        string
+	}{
+		{"hold default fee denom", `{"denom":"usei","amount":"600"}`, "ufoo", "500usei"},
+		{"hold alternative fee denom only", `{"denom":"ufoo","amount":"5000"}`, "ufoo", "3000ufoo"},
+		{"default fee denom is used by payout", `{"denom":"usei","amount":"1200"},{"denom":"ufoo","amount":"5000"}`, "usei", "3000ufoo"},
+		{"alternative fee denom is used by payout", `{"denom":"ufoo","amount":"3500"},{"denom":"ubar","amount":"100"}`, "ufoo", "100ubar"},
+		{"no affordable fee", `{"denom":"ubaz","amount":"5000"}`, "ubaz", "500usei"},
+	}
+	for _, test := range tests {
+		balances = test.balances
+		fee, err := b.selectFee("sei1payer", test.payoutDenom, big.NewInt(1000), DefaultGasLimit)
+		if err != nil || fee != test.want {
+			t.Errorf("%v: select fee mismatch, have %v %v want %v", test.name, fee, err, test.want)
+		}
+	}
+
+	// the fee candidates are bumped to the min gas price before checking the balances
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"feeAlternatives": "3000ufoo", "minGasPrice": "0.01usei"})
+	for have, want := range map[string]string{"600": "3000ufoo", "1500": "1500usei"} {
+		balances = `{"denom":"usei","amount":"` + have + `"},{"denom":"ufoo","amount":"5000"}`
+		if fee, err := b.selectFee("sei1payer", "ubaz", big.NewInt(1000), DefaultGasLimit); err != nil || fee != want {
+			t.Errorf("select fee of min gas price with balance %vusei mismatch, have %v %v want %v", have, fee, err, want)
+		}
+	}
+}
+
+func TestSelectFeeWithFeeGranter(t *testing.T) {
+	const feeGranter = "sei1feegranter"
+	balances := make(map[string]string)
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == TxFeesFeeTokens {
+			http.Error(w, "txfees module is not available", http.StatusNotImplemented)
+			return
+		}
+		account := strings.TrimPrefix(r.URL.Path, Balances)
+		_, _ = w.Write([]byte(`{"balances":[` + balances[account] + `]}`))
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"feeAlternatives": "3000ufoo",
+		"feeGranter":      feeGranter,
+		// the failed txfees query does not prevent the fee granter from paying
+		"txFeesTokens": "ibc/FOO",
+	})
+
+	tests := []struct {
+		name           string
+		payerBalances  string
+		granterBalance string
+		wantFee        string
+		wantGranter    string
+		wantErr        error
+	}{
+		{"payer pays default fee", `{"denom":"usei","amount":"1500"}`, "", "500usei", "", nil},
+		{"payer pays alternative fee", `{"denom":"usei","amount":"1000"},{"denom":"ufoo","amount":"3000"}`, "", "3000ufoo", "", nil},
+		{"fee granter pays default fee", `{"denom":"usei","amount":"1000"}`, `{"denom":"usei","amount":"500"}`, "500usei", feeGranter, nil},
+		{"fee granter pays alternative fee", "", `{"denom":"ufoo","amount":"3000"}`, "3000ufoo", feeGranter, nil},
+		{"no fee payer", `{"denom":"usei","amount":"1000"}`, `{"denom":"usei","amount":"499"}`, "", "", ErrNoFeePayer},
+	}
+	for _, test := range tests {
+		balances["sei1payer"] = test.payerBalances
+		balances[feeGranter] = test.granterBalance
+		fee, err := b.selectFee("sei1payer", "usei", big.NewInt(1000), DefaultGasLimit)
+		if !errors.Is(err, test.wantErr) || fee != test.wantFee {
+			t.Errorf("%v: select fee mismatch, have %v %v want %v %v", test.name, fee, err, test.wantFee, test.wantErr)
+			continue
+		}
+		if err != nil {
+			continue
+		}
+		granter, err := b.getFeeGranterOf("sei1payer", fee, "usei", big.NewInt(1000))
+		if err != nil || granter != test.wantGranter {
+			t.Errorf("%v: fee granter mismatch, have %v %v want %v", test.name, granter, err, test.wantGranter)
+		}
+	}
+}
+
+func TestSelectMultiDenomFee(t *testing.T) {
+	var balances string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"balances":[` + balances + `]}`))
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"multiDenomFee":   "100usei,5000uatom",
+		"feeAlternatives": "3000ufoo",
+	})
+
+	// the fee is normalized to the canonical coins sorted by denom
+	const want = "5000uatom,100usei"
+	if candidates := b.getFeeCandidates(); len(candidates) != 1 || candidates[0] != want {
+		t.Errorf("fee candidates mismatch, have %v want %v", candidates, want)
+	}
+
+	balances = `{"denom":"uatom","amount":"5000"},{"denom":"usei","amount":"1100"},{"denom":"ufoo","amount":"3000"}`
+	fee, err := b.selectFee("sei1payer", "usei", big.NewInt(1000), DefaultGasLimit)
+	if err != nil || fee != want {
+		t.Fatalf("select multi denom fee mismatch, have %v %v want %v", fee, err, want)
+	}
+	feeCoins, err := ParseCoinsFee(fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBuilder := b.TxConfig.NewTxBuilder()
+	txBuilder.SetFeeAmount(feeCoins)
+	txFee := txBuilder.GetTx().GetFee()
+	if !txFee.IsValid() || len(txFee) != 2 || txFee[0].Denom != "uatom" || txFee[1].Denom != "usei" ||
+		txFee.AmountOf("uatom").Int64() != 5000 || txFee.AmountOf("usei").Int64() != 100 {
+		t.Errorf("tx fee mismatch, have %v", txFee)
+	}
+
+	// each denom must be held, the alternatives are not fallbacks of the multi denom fee
+	for _, held := range []string{
+		`{"denom":"usei","amount":"1100"},{"denom":"ufoo","amount":"3000"}`,
+		`{"denom":"uatom","amount":"5000"},{"denom":"usei","amount":"1099"},{"denom":"ufoo","amount":"3000"}`,
+	} {
+		balances = held
+		if fee, err = b.selectFee("sei1payer", "usei", big.NewInt(1000), DefaultGasLimit); !errors.Is(err, ErrNoFeePayer) {
+			t.Errorf("select unaffordable multi denom fee should fail with %v, but have %v %v", ErrNoFeePayer, fee, err)
+		}
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"multiDenomFee": "100usei,5000usei"})
+	if _, err = b.selectFee("sei1payer", "usei", nil, DefaultGasLimit); err == nil {
+		t.Error("select wrong multi denom fee should fail")
+	}
+}
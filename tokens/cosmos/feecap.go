@@ -0,0 +1,47 @@
+package cosmos
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// getFeeCap get the absolute max fee of the fee denom, configed by `maxFee` custom (eg. `50000uatom`),
+// exist is false if it is not configed for the denom.
+func (b *Bridge) getFeeCap(denom string) (feeCap sdk.Coin, exist bool, err error) {
+	maxFee := params.GetCustom(b.ChainConfig.ChainID, "maxFee")
+	if maxFee == "" {
+		return sdk.Coin{}, false, nil
+	}
+	caps, err := ParseCoinsFee(maxFee)
+	if err != nil {
+		return sdk.Coin{}, false, fmt.Errorf("wrong maxFee custom %v, %w", maxFee, err)
+	}
+	if amount := caps.AmountOf(denom); amount.IsPositive() {
+		return sdk.NewCoin(denom, amount), true, nil
+	}
+	return sdk.Coin{}, false, nil
+}
+
+// checkFeeCap check the fee of the built tx does not exceed the absolute max fee,
+// so that a runaway gas estimate (simulate * gas price) can not drain the fee account.
+func (b *Bridge) checkFeeCap(fee string, gas uint64) error {
+	feeCoins, err := ParseCoinsFee(fee)
+	if err != nil {
+		return err
+	}
+	for _, coin := range feeCoins {
+		feeCap, exist, err := b.getFeeCap(coin.Denom)
+		if err != nil {
+			return err
+		}
+		if !exist || coin.Amount.LTE(feeCap.Amount) {
+			continue
+		}
+		log.Warn("fee exceeds the cap", "chainID", b.ChainConfig.ChainID, "gas", gas, "estimated", coin, "cap", feeCap)
+		return fmt.Errorf("%w, fee: %v, gas: %v, cap: %v", ErrFeeExceedsCap, fee, gas, feeCap)
+	}
+	return nil
+}
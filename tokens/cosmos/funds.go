@@ -0,0 +1,137 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+const (
+	// CodeInsufficientFunds sdk error code of insufficient funds
+	CodeInsufficientFunds = 5
+)
+
+var (
+	ErrInsufficientFeeFunds    = errors.New("insufficient funds to pay fee")
+	ErrInsufficientPayoutFunds = errors.New("insufficient funds to pay out")
+)
+
+type fundsNeed struct {
+	account string
+	denom   string
+	fee     sdk.Int
+	payout  sdk.Int
+}
+
+// DiagnoseInsufficientFunds distinguish whether the insufficient funds rejection
+// of the signed tx is caused by lacking the fee denom or the payout denom.
+func (b *Bridge) DiagnoseInsufficientFunds(signedTx []byte) error {
+	needs, err := b.getFundsNeeds(signedTx)
+	if err != nil {
+		return err
+	}
+	for _, need := range needs {
+		balance, err := b.GetDenomBalance(need.account, need.denom)
+		if err != nil {
+			return err
+		}
+		total := need.fee.Add(need.payout)
+		if balance.GTE(total) {
+			continue
+		}
+		log.Warn("insufficient funds", "account", need.account, "denom", need.denom,
+			"balance", balance, "fee", need.fee, "payout", need.payout)
+		if balance.LT(need.fee) || need.payout.IsZero() {
+			return fmt.Errorf("%w, account: %v, denom: %v, balance: %v, fee: %v", ErrInsufficientFeeFunds, need.account, need.denom, balance, need.fee)
+		}
+		return fmt.Errorf("%w, account: %v, denom: %v, balance: %v, payout: %v, fee: %v", ErrInsufficientPayoutFunds, need.account, need.denom, balance, need.payout, need.fee)
+	}
+	return nil
+}
+
+// getFundsNeeds get the funds needed by the signed tx (per account and denom)
+func (b *Bridge) getFundsNeeds(signedTx []byte) ([]*fundsNeed, error) {
+	txBytes, err := base64.StdEncoding.DecodeString(string(signedTx))
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.DecodeTx(txBytes)
+	if err != nil {
+		return nil, err
+	}
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return nil, errors.New("tx is not a fee tx")
+	}
+
+	needMap := make(map[string]*fundsNeed)
+	getNeed := func(account, denom string) *fundsNeed {
+		key := account + "/" + denom
+		need, exist := needMap[key]
+		if !exist {
+			need = &fundsNeed{account: account, denom: denom, fee: sdk.ZeroInt(), payout: sdk.ZeroInt()}
+			needMap[key] = need
+		}
+		return need
+	}
+
+	var feePayer string
+	for _, msg := range tx.GetMsgs() {
+		switch m := msg.(type) {
+		case *bankTypes.MsgSend:
+			if feePayer == "" {
+				feePayer = m.FromAddress
+			}
+			addPayout(getNeed, m)
+		case *authz.MsgExec:
+			if feePayer == "" {
+				feePayer = m.Grantee
+			}
+			innerMsgs, err := m.GetMessages()
+			if err != nil {
+				return nil, err
+			}
+			for _, innerMsg := range innerMsgs {
+				if sendMsg, ok := innerMsg.(*bankTypes.MsgSend); ok {
+					addPayout(getNeed, sendMsg)
+				}
+			}
+		}
+	}
+	if feePayer == "" {
+		return nil, errors.New("can not get fee payer of tx")
+	}
+	for _, coin := range feeTx.GetFee() {
+		need := getNeed(feePayer, coin.Denom)
+		need.fee = need.fee.Add(coin.Amount)
+	}
+
+	needs := make([]*fundsNeed, 0, len(needMap))
+	for _, need := range needMap {
+		needs = append(needs, need)
+	}
+	// check fee denoms first
+	sort.Slice(needs, func(i, j int) bool {
+		if needs[i].fee.IsZero() != needs[j].fee.IsZero() {
+			return !needs[i].fee.IsZero()
+		}
+		if needs[i].account != needs[j].account {
+			return needs[i].account < needs[j].account
+		}
+		return needs[i].denom < needs[j].denom
+	})
+	return needs, nil
+}
+
+func addPayout(getNeed func(account, denom string) *fundsNeed, msg *bankTypes.MsgSend) {
+	for _, coin := range msg.Amount {
+		need := getNeed(msg.FromAddress, coin.Denom)
+		need.payout = need.payout.Add(coin.Amount)
+	}
+}
@@ -0,0 +1,75 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func buildTestSignedTxWithFee(t *testing.T, b *Bridge, payoutDenom string, payout int64, fee string) []byte {
+	txBuilder := b.TxConfig.NewTxBuilder()
+	msg := BuildSendMsg("sei1sender", "sei1receiver", payoutDenom, big.NewInt(payout))
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		t.Fatal(err)
+	}
+	feeAmount, err := ParseCoinsFee(fee)
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBuilder.SetFeeAmount(feeAmount)
+	txBuilder.SetGasLimit(DefaultGasLimit)
+	signedTx, _, err := b.GetSignTx(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedTx
+}
+
+func TestDiagnoseInsufficientFunds(t *testing.T) {
+	var balances string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == Balances+"sei1sender":
+			_, _ = w.Write([]byte(`{"balances":[` + balances + `]}`))
+		case r.URL.Path == BroadTx:
+			_, _ = w.Write([]byte(`{"tx_response":{"height":"0","txhash":"","code":5}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+
+	tests := []struct {
+		name        string
+		payoutDenom string
+		balances    string
+		want        error
+	}{
+		{"lack fee denom", "ufoo", `{"denom":"ufoo","amount":"5000"},{"denom":"usei","amount":"100"}`, ErrInsufficientFeeFunds},
+		{"lack payout denom", "ufoo", `{"denom":"ufoo","amount":"10"},{"denom":"usei","amount":"1000"}`, ErrInsufficientPayoutFunds},
+		{"no payout denom", "ufoo", `{"denom":"usei","amount":"1000"}`, ErrInsufficientPayoutFunds},
+		{"same denom lack payout", "usei", `{"denom":"usei","amount":"1200"}`, ErrInsufficientPayoutFunds},
+		{"same denom lack fee", "usei", `{"denom":"usei","amount":"300"}`, ErrInsufficientFeeFunds},
+		{"enough funds", "ufoo", `{"denom":"ufoo","amount":"1000"},{"denom":"usei","amount":"500"}`, nil},
+	}
+	for _, test := range tests {
+		balances = test.balances
+		signedTx := buildTestSignedTxWithFee(t, b, test.payoutDenom, 1000, "500usei")
+		err := b.DiagnoseInsufficientFunds(signedTx)
+		if !errors.Is(err, test.want) || (test.want == nil && err != nil) {
+			t.Errorf("%v: diagnose should return %v, but have %v", test.name, test.want, err)
+		}
+	}
+
+	balances = `{"denom":"ufoo","amount":"5000"}`
+	signedTx := buildTestSignedTxWithFee(t, b, "ufoo", 1000, "500usei")
+	if _, err := b.SendTransaction(signedTx); !errors.Is(err, ErrInsufficientFeeFunds) {
+		t.Errorf("send transaction should fail with %v, but have %v", ErrInsufficientFeeFunds, err)
+	}
+
+	balances = `{"denom":"ufoo","amount":"5000"},{"denom":"usei","amount":"5000"}`
+	if _, err := b.SendTransaction(signedTx); err == nil || !strings.Contains(err.Error(), "code: 5") {
+		t.Errorf("undiagnosed insufficient funds should fail with code 5, but have %v", err)
+	}
+}
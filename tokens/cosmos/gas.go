@@ -0,0 +1,117 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	cosmosClient "github.com/cosmos/cosmos-sdk/client"
+)
+
+// GetGasAdjustment get gas adjustment of token (fallback to the chain's one)
+func (b *Bridge) GetGasAdjustment(tokenID string) float64 {
+	for _, key := range []string{"gasAdjustment:" + tokenID, "gasAdjustment"} {
+		adjustmentStr := params.GetCustom(b.ChainConfig.ChainID, key)
+		if adjustmentStr == "" {
+			continue
+		}
+		adjustment, err := strconv.ParseFloat(adjustmentStr, 64)
+		if err != nil || adjustment <= 0 {
+			log.Warn("wrong gas adjustment config", "chainID", b.ChainConfig.ChainID, "key", key, "value", adjustmentStr)
+			continue
+		}
+		return adjustment
+	}
+	return DefaultGasAdjustment
+}
+
+// GetGasLimitOverride get fixed gas limit of token (0 means not set)
+func (b *Bridge) GetGasLimitOverride(tokenID string) uint64 {
+	key := "gasLimit:" + tokenID
+	gasLimitStr := params.GetCustom(b.ChainConfig.ChainID, key)
+	if gasLimitStr == "" {
+		return 0
+	}
+	gasLimit, err := strconv.ParseUint(gasLimitStr, 10, 64)
+	if err != nil {
+		log.Warn("wrong gas limit config", "chainID", b.ChainConfig.ChainID, "key", key, "value", gasLimitStr)
+		return 0
+	}
+	return gasLimit
+}
+
+var errSimulateDisabled = errors.New("simulate is disabled")
+
+// GetFallbackGasLimit get the fixed gas limit used when simulate is unavailable,
+// configed by `fallbackGasLimit` custom (default to DefaultGasLimit)
+func (b *Bridge) GetFallbackGasLimit() uint64 {
+	gasLimitStr := params.GetCustom(b.ChainConfig.ChainID, "fallbackGasLimit")
+	if gasLimitStr == "" {
+		return DefaultGasLimit
+	}
+	gasLimit, err := strconv.ParseUint(gasLimitStr, 10, 64)
+	if err != nil || gasLimit == 0 {
+		log.Warn("wrong fallback gas limit config", "chainID", b.ChainConfig.ChainID, "value", gasLimitStr)
+		return DefaultGasLimit
+	}
+	return gasLimit
+}
+
+// IsSimulateDisabled is simulate disabled by `disableSimulate` custom,
+// for nodes which disable the simulate endpoint
+func (b *Bridge) IsSimulateDisabled() bool {
+	return params.GetCustom(b.ChainConfig.ChainID, "disableSimulate") == "true"
+}
+
+// SimulateGasUsed simulate the tx and returns the gas used
+func (b *Bridge) SimulateGasUsed(txBuilder cosmosClient.TxBuilder) (uint64, error) {
+	txBytes, _, err := b.GetSignTx(txBuilder.GetTx())
+	if err != nil {
+		return 0, err
+	}
+	res, err := b.SimulateTx(&SimulateRequest{TxBytes: string(txBytes)})
+	if err != nil {
+		return 0, err
+	}
+	var simRes *SimulateResponse
+	if err := json.Unmarshal([]byte(res), &simRes); err != nil {
+		return 0, err
+	}
+	if simRes == nil || simRes.GasInfo == nil {
+		return 0, fmt.Errorf("%w, %v", tokens.ErrSimulateTx, res)
+	}
+	return strconv.ParseUint(simRes.GasInfo.GasUsed, 10, 64)
+}
+
+// EstimateGasLimit estimate gas limit of the tx for the token.
+// The simulated gas used is multiplied by the gas adjustment,
+// and clamped by the fixed gas limit of the token if it is set.
+func (b *Bridge) EstimateGasLimit(txBuilder cosmosClient.TxBuilder, tokenID string) uint64 {
+	gasOverride := b.GetGasLimitOverride(tokenID)
+	var gasUsed uint64
+	var err error
+	if b.IsSimulateDisabled() {
+		err = errSimulateDisabled
+	} else {
+		gasUsed, err = b.SimulateGasUsed(txBuilder)
+	}
+	if err != nil {
+		if gasOverride > 0 {
+			log.Warn("simulate tx failed, use the token gas limit", "chainID", b.ChainConfig.ChainID, "tokenID", tokenID, "gasLimit", gasOverride, "err", err)
+			return gasOverride
+		}
+		gasLimit := b.GetFallbackGasLimit()
+		log.Warn("simulate tx failed, skip estimation and use the fallback gas limit", "chainID", b.ChainConfig.ChainID, "tokenID", tokenID, "gasLimit", gasLimit, "err", err)
+		return gasLimit
+	}
+	gasLimit := uint64(b.GetGasAdjustment(tokenID) * float64(gasUsed))
+	if gasOverride > 0 && gasLimit > gasOverride {
+		log.Info("estimated gas limit is clamped", "tokenID", tokenID, "gasUsed", gasUsed, "estimated", gasLimit, "override", gasOverride)
+		gasLimit = gasOverride
+	}
+	return gasLimit
+}
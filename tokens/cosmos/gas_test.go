@@ -0,0 +1,69 @@
+package cosmos
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestEstimateGasLimit(t *testing.T) {
+	var simulateFail bool
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SimulateTx {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		if simulateFail {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"gas_info":{"gas_wanted":"0","gas_used":"100000"}}`))
+	})
+
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(BuildSendMsg("sei1sender", "sei1receiver", "usei", big.NewInt(1000))); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name         string
+		customs      map[string]string
+		simulateFail bool
+		want         uint64
+	}{
+		{"no config", nil, false, 130000},
+		{"chain adjustment", map[string]string{"gasAdjustment": "1.5"}, false, 150000},
+		{"token adjustment first", map[string]string{"gasAdjustment": "1.5", "gasAdjustment:CW20": "2"}, false, 200000},
+		{"other token adjustment", map[string]string{"gasAdjustment": "1.5", "gasAdjustment:IBC": "2"}, false, 150000},
+		{"wrong token adjustment", map[string]string{"gasAdjustment": "1.5", "gasAdjustment:CW20": "-1"}, false, 150000},
+		{"wrong chain adjustment", map[string]string{"gasAdjustment": "abc"}, false, 130000},
+		{"clamped by override", map[string]string{"gasAdjustment:CW20": "2", "gasLimit:CW20": "180000"}, false, 180000},
+		{"below override", map[string]string{"gasAdjustment:CW20": "1.2", "gasLimit:CW20": "180000"}, false, 120000},
+		{"simulate fail with override", map[string]string{"gasLimit:CW20": "180000"}, true, 180000},
+		{"simulate fail without override", nil, true, DefaultGasLimit},
+		{"simulate fail with fallback", map[string]string{"fallbackGasLimit": "300000"}, true, 300000},
+		{"simulate fail with override and fallback", map[string]string{"gasLimit:CW20": "180000", "fallbackGasLimit": "300000"}, true, 180000},
+		{"wrong fallback", map[string]string{"fallbackGasLimit": "abc"}, true, DefaultGasLimit},
+		{"fallback not used if simulate ok", map[string]string{"fallbackGasLimit": "300000"}, false, 130000},
+		{"simulate disabled", map[string]string{"disableSimulate": "true", "fallbackGasLimit": "300000"}, false, 300000},
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, test.customs)
+		simulateFail = test.simulateFail
+		if have := b.EstimateGasLimit(txBuilder, "CW20"); have != test.want {
+			t.Errorf("%v: estimated gas limit mismatch, have %v want %v", test.name, have, test.want)
+		}
+	}
+}
+
+func TestGetGasAdjustmentDefault(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: "test-gas-adjustment"})
+	_ = params.SetExtraConfig(&params.ExtraConfig{})
+	if have := b.GetGasAdjustment("CW20"); have != DefaultGasAdjustment || have <= 1 {
+		t.Errorf("unconfiged gas adjustment should be the default above 1, have %v want %v", have, DefaultGasAdjustment)
+	}
+}
@@ -111,10 +111,11 @@ func (b *Bridge) GRPCGetTransactionByHash(txHash string) (res *GetTxResponse, er
 					},
 				},
 				TxResponse: &TxResponse{
-					Height: fmt.Sprintf("%v", txres.Height),
-					TxHash: txres.TxHash,
-					Code:   txres.Code,
-					Logs:   txres.Logs,
+					Height:    fmt.Sprintf("%v", txres.Height),
+					TxHash:    txres.TxHash,
+					Code:      txres.Code,
+					Logs:      txres.Logs,
+					Timestamp: txres.Timestamp,
 				},
 			}, nil
 		}
@@ -131,13 +132,7 @@ func (b *Bridge) GRPCGetBaseAccount(address string) (res *QueryAccountResponse,
 		clientCtx := b.ClientContext.WithClient(rpcClient)
 		ret, err = grpc.GetAccountInfo(ctx, clientCtx, address)
 		if err == nil {
-			return &QueryAccountResponse{
-				Account: &BaseAccount{
-					Address:       ret.GetAddress().String(),
-					AccountNumber: fmt.Sprintf("%v", ret.GetAccountNumber()),
-					Sequence:      fmt.Sprintf("%v", ret.GetSequence()),
-				},
-			}, nil
+			return newQueryAccountResponse(ret), nil
 		}
 	}
 	if err != nil {
@@ -161,9 +156,13 @@ func (b *Bridge) GRPCGetDenomBalance(address, denom string) (res sdk.Int, err er
 }
 
 func (b *Bridge) GRPCSimulateTx(simulateReq *SimulateRequest) (res *sdktx.SimulateResponse, err error) {
+	txBytes, err := base64.StdEncoding.DecodeString(simulateReq.TxBytes)
+	if err != nil {
+		return nil, wrapRPCQueryError(err, "GRPCSimulateTx")
+	}
 	for _, rpcClient := range rpcClients {
 		clientCtx := b.ClientContext.WithClient(rpcClient)
-		res, err = grpc.SimulateTx(ctx, clientCtx, []byte(simulateReq.TxBytes))
+		res, err = grpc.SimulateTx(ctx, clientCtx, txBytes)
 		if err == nil {
 			return res, nil
 		}
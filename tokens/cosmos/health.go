@@ -0,0 +1,137 @@
+package cosmos
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// HealthSnapshot the health snapshot of the mpc accounts of the chain,
+// a failed query is reported in the `Error` of the account or balance
+// so that the other fields are still available to dashboards.
+type HealthSnapshot struct {
+	ChainID  string           `json:"chainID"`
+	Accounts []*AccountHealth `json:"accounts"`
+}
+
+// AccountHealth the health of a mpc account
+type AccountHealth struct {
+	Account           string          `json:"account"`
+	AccountNumber     uint64          `json:"accountNumber"`
+	Sequence          uint64          `json:"sequence"`          // on chain sequence
+	AllocatedSequence uint64          `json:"allocatedSequence"` // cached high-water of the allocated sequence
+	SequenceGap       uint64          `json:"sequenceGap"`
+	PubKeyOnChain     bool            `json:"pubKeyOnChain"` // the account has sent a tx and its public key is recorded
+	Balances          []*DenomBalance `json:"balances,omitempty"`
+	Error             string          `json:"error,omitempty"`
+}
+
+// DenomBalance the balance of a payout token or a fee denom
+type DenomBalance struct {
+	TokenID string `json:"tokenID,omitempty"` // empty for the fee denom which is not a payout token
+	Denom   string `json:"denom"`
+	Kind    string `json:"kind"`
+	IsFee   bool   `json:"isFee"`
+	Balance string `json:"balance,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// GetHealthSnapshot get the health snapshot of the mpc accounts (the router contract
+// of the chain and of the tokens), including the balances of the payout tokens and the fee denoms,
+// the on chain sequence vs the allocated one, and whether the public key is recorded on chain.
+func (b *Bridge) GetHealthSnapshot() (*HealthSnapshot, error) {
+	mpcs, tokenCfgs, err := b.GetMPCTokenConfigs(router.GetRouterMPC)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &HealthSnapshot{ChainID: b.ChainConfig.ChainID}
+	for _, mpc := range mpcs {
+		snapshot.Accounts = append(snapshot.Accounts, b.getAccountHealth(mpc, tokenCfgs[mpc]))
+	}
+	return snapshot, nil
+}
+
+func (b *Bridge) getAccountHealth(account string, tokenCfgs []*tokens.TokenConfig) *AccountHealth {
+	health := &AccountHealth{
+		Account:           account,
+		AllocatedSequence: b.GetSwapNonce(account),
+	}
+
+	if err := b.fillAccountHealth(health); err != nil {
+		log.Warn("get account health failed", "chainID", b.ChainConfig.ChainID, "account", account, "err", err)
+		health.Error = err.Error()
+	}
+
+	sort.Slice(tokenCfgs, func(i, j int) bool { return tokenCfgs[i].TokenID < tokenCfgs[j].TokenID })
+	denoms := make(map[string]*DenomBalance)
+	for _, tokenCfg := range tokenCfgs {
+		balance := &DenomBalance{TokenID: tokenCfg.TokenID, Denom: tokenCfg.ContractAddress}
+		health.Balances = append(health.Balances, balance)
+		kind, err := GetTokenKind(tokenCfg)
+		if err != nil {
+			balance.Error = err.Error()
+			continue
+		}
+		balance.Kind = kind
+		if kind != TokenKindCW20 {
+			denoms[balance.Denom] = balance
+		}
+	}
+	for _, fee := range b.getFeeCandidates() {
+		feeCoins, err := ParseCoinsFee(fee)
+		if err != nil {
+			log.Warn("wrong fee candidate", "chainID", b.ChainConfig.ChainID, "fee", fee, "err", err)
+			continue
+		}
+		for _, coin := range feeCoins {
+			if balance, exist := denoms[coin.Denom]; exist {
+				balance.IsFee = true
+				continue
+			}
+			balance := &DenomBalance{Denom: coin.Denom, Kind: TokenKindBank, IsFee: true}
+			health.Balances = append(health.Balances, balance)
+			denoms[coin.Denom] = balance
+		}
+	}
+
+	for _, balance := range health.Balances {
+		if balance.Error != "" {
+			continue
+		}
+		amount, err := b.GetTokenBalance(balance.Kind, account, balance.Denom)
+		if err != nil {
+			log.Warn("get balance health failed", "chainID", b.ChainConfig.ChainID, "account", account,
+				"denom", balance.Denom, "err", err)
+			balance.Error = err.Error()
+			continue
+		}
+		balance.Balance = amount.String()
+	}
+	return health
+}
+
+func (b *Bridge) fillAccountHealth(health *AccountHealth) (err error) {
+	res, err := b.GetBaseAccount(health.Account)
+	if err != nil {
+		return err
+	}
+	if res == nil || res.Account == nil {
+		return tokens.ErrRPCQueryError
+	}
+	acc := res.Account
+	if health.AccountNumber, err = strconv.ParseUint(acc.AccountNumber, 10, 64); err != nil {
+		return err
+	}
+	if health.Sequence, err = strconv.ParseUint(acc.Sequence, 10, 64); err != nil {
+		return err
+	}
+	if health.AllocatedSequence > health.Sequence {
+		health.SequenceGap = health.AllocatedSequence - health.Sequence
+	}
+	health.PubKeyOnChain = acc.PubKey != nil && len(acc.PubKey.Key) > 0
+	return nil
+}
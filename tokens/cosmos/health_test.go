@@ -0,0 +1,67 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestGetHealthSnapshot(t *testing.T) {
+	mpc := tMPCAddress
+	pubKey := base64.StdEncoding.EncodeToString(make([]byte, 33))
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7",` +
+				`"pub_key":{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"` + pubKey + `"}}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"factory/sei1creator/usdc","amount":"300"},` +
+				`{"denom":"uatom","amount":"200"},{"denom":"usei","amount":"100"}]}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"feeAlternatives": "5000uatom"})
+
+	if _, err := b.GetHealthSnapshot(); !errors.Is(err, tokens.ErrNoRouterMPC) {
+		t.Errorf("snapshot without mpc should fail with %v, but have %v", tokens.ErrNoRouterMPC, err)
+	}
+
+	b.ChainConfig.RouterContract = mpc
+	b.CrossChainBridgeBase.SetTokenConfig("usei", &tokens.TokenConfig{TokenID: "SEI", ContractAddress: "usei", Decimals: 6})
+	b.CrossChainBridgeBase.SetTokenConfig("factory/sei1creator/usdc", &tokens.TokenConfig{TokenID: "USDC", ContractAddress: "factory/sei1creator/usdc", Decimals: 6})
+	b.SetNonce(mpc, 10)
+
+	snapshot, err := b.GetHealthSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.ChainID != b.ChainConfig.ChainID || len(snapshot.Accounts) != 1 {
+		t.Fatalf("snapshot mismatch, have %+v", snapshot)
+	}
+	health := snapshot.Accounts[0]
+	if health.Account != mpc || health.Error != "" || health.AccountNumber != 12 || !health.PubKeyOnChain {
+		t.Errorf("account health mismatch, have %+v", health)
+	}
+	if health.Sequence != 7 || health.AllocatedSequence != 10 || health.SequenceGap != 3 {
+		t.Errorf("account sequence health mismatch, have %+v", health)
+	}
+
+	// the payout tokens, then the fee denoms which are not payout tokens
+	want := []DenomBalance{
+		{TokenID: "SEI", Denom: "usei", Kind: TokenKindBank, IsFee: true, Balance: "100"},
+		{TokenID: "USDC", Denom: "factory/sei1creator/usdc", Kind: TokenKindBank, Balance: "300"},
+		{Denom: "uatom", Kind: TokenKindBank, IsFee: true, Balance: "200"},
+	}
+	if len(health.Balances) != len(want) {
+		t.Fatalf("balances count mismatch, have %v want %v", len(health.Balances), len(want))
+	}
+	for i, balance := range health.Balances {
+		if *balance != want[i] {
+			t.Errorf("balance %v mismatch, have %+v want %+v", i, balance, want[i])
+		}
+	}
+}
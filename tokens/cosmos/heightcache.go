@@ -0,0 +1,75 @@
+package cosmos
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// latestHeightCacheTTL blocks are produced every several seconds
+var latestHeightCacheTTL = 2 * time.Second
+
+// blockHeightCache caches the latest block height.
+// It is shared by the goroutines using the same bridge,
+// and concurrent callers of a stale cache share one fetch.
+type blockHeightCache struct {
+	mu        sync.Mutex
+	height    uint64
+	updatedAt time.Time
+
+	fetch func() (uint64, error)
+}
+
+func newBlockHeightCache(fetch func() (uint64, error)) *blockHeightCache {
+	return &blockHeightCache{fetch: fetch}
+}
+
+// Get returns the cached height if it's fresh, otherwise refreshes it
+func (c *blockHeightCache) Get() (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.height > 0 && time.Since(c.updatedAt) < latestHeightCacheTTL {
+		return c.height, nil
+	}
+	height, err := c.fetch()
+	if err != nil {
+		return 0, err
+	}
+	// block height never goes back
+	if height > c.height {
+		c.height = height
+	}
+	c.updatedAt = time.Now()
+	return c.height, nil
+}
+
+// GetLatestBlockHeight gets latest block height from cache
+func (b *Bridge) GetLatestBlockHeight() (uint64, error) {
+	return b.heightCache.Get()
+}
+
+// GetTimeoutHeight get the timeout height of the built tx,
+// which is the latest height plus `txTimeoutBlocks` custom.
+// It returns 0 (no timeout) if `txTimeoutBlocks` is not set.
+func (b *Bridge) GetTimeoutHeight() (uint64, error) {
+	blocksStr := params.GetCustom(b.ChainConfig.ChainID, "txTimeoutBlocks")
+	if blocksStr == "" {
+		return 0, nil
+	}
+	blocks, err := strconv.ParseUint(blocksStr, 10, 64)
+	if err != nil {
+		log.Warn("wrong txTimeoutBlocks custom", "chainID", b.ChainConfig.ChainID, "value", blocksStr, "err", err)
+		return 0, nil
+	}
+	if blocks == 0 {
+		return 0, nil
+	}
+	height, err := b.GetLatestBlockHeight()
+	if err != nil {
+		return 0, err
+	}
+	return height + blocks, nil
+}
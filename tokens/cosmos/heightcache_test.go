@@ -0,0 +1,81 @@
+package cosmos
+
+import (
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCountingHeightCache(calls *int64) *blockHeightCache {
+	return newBlockHeightCache(func() (uint64, error) {
+		return uint64(1000 + atomic.AddInt64(calls, 1)), nil
+	})
+}
+
+func TestBlockHeightCacheConcurrent(t *testing.T) {
+	var calls int64
+	cache := newCountingHeightCache(&calls)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if height, err := cache.Get(); err != nil || height != 1001 {
+					t.Errorf("get cached block height failed, have %v %v", height, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if have := atomic.LoadInt64(&calls); have != 1 {
+		t.Errorf("fresh block height should be read from cache, but fetched %v times", have)
+	}
+
+	// stale, refresh it
+	cache.mu.Lock()
+	cache.updatedAt = time.Now().Add(-latestHeightCacheTTL)
+	cache.mu.Unlock()
+	if height, _ := cache.Get(); height != 1002 {
+		t.Errorf("stale block height should be refreshed, have %v", height)
+	}
+}
+
+func TestGetTimeoutHeight(t *testing.T) {
+	var queries int32
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&queries, 1)
+		_, _ = w.Write([]byte(`{"block":{"header":{"height":"500"}}}`))
+	})
+
+	if height, err := b.GetTimeoutHeight(); err != nil || height != 0 {
+		t.Errorf("timeout height should be 0 if not configed, have %v %v", height, err)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"txTimeoutBlocks": "100"})
+	for i := 0; i < 3; i++ {
+		if height, err := b.GetTimeoutHeight(); err != nil || height != 600 {
+			t.Errorf("timeout height mismatch, have %v %v", height, err)
+		}
+	}
+	if have := atomic.LoadInt32(&queries); have != 1 {
+		t.Errorf("latest block height should be cached, but queried %v times", have)
+	}
+}
+
+func BenchmarkGetLatestBlockHeight(b *testing.B) {
+	var calls int64
+	cache := newCountingHeightCache(&calls)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := cache.Get(); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
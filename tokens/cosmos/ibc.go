@@ -0,0 +1,90 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	SendPacketType = "send_packet"
+
+	PacketSequenceKey         = "packet_sequence"
+	PacketSrcPortKey          = "packet_src_port"
+	PacketSrcChannelKey       = "packet_src_channel"
+	PacketDstPortKey          = "packet_dst_port"
+	PacketDstChannelKey       = "packet_dst_channel"
+	PacketTimeoutHeightKey    = "packet_timeout_height"
+	PacketTimeoutTimestampKey = "packet_timeout_timestamp"
+)
+
+var (
+	// ErrSendPacketNotFound the ibc transfer tx has no send_packet event of the transfer
+	ErrSendPacketNotFound = errors.New("send_packet event not found")
+)
+
+// IBCPacketInfo ibc packet info emitted in the send_packet event
+type IBCPacketInfo struct {
+	Sequence         uint64 `json:"sequence"`
+	SourcePort       string `json:"sourcePort"`
+	SourceChannel    string `json:"sourceChannel"`
+	DestPort         string `json:"destPort"`
+	DestChannel      string `json:"destChannel"`
+	TimeoutHeight    string `json:"timeoutHeight"`
+	TimeoutTimestamp uint64 `json:"timeoutTimestamp"`
+}
+
+// GetIBCPacketInfo get ibc packet info of the message at logIndex (starts from 1)
+func (b *Bridge) GetIBCPacketInfo(txHash string, logIndex int) (*IBCPacketInfo, error) {
+	txr, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if txr.TxResponse == nil {
+		return nil, ErrSendPacketNotFound
+	}
+	logs := txr.TxResponse.Logs
+	if logIndex < 1 || logIndex > len(logs) {
+		return nil, fmt.Errorf("log index %v out of range [1, %v]", logIndex, len(logs))
+	}
+	return ParseIBCPacketInfo(logs[logIndex-1])
+}
+
+// ParseIBCPacketInfo parse ibc packet info from the send_packet event of message log
+func ParseIBCPacketInfo(messageLog sdk.ABCIMessageLog) (*IBCPacketInfo, error) {
+	for _, event := range messageLog.Events {
+		if event.Type != SendPacketType {
+			continue
+		}
+		info := &IBCPacketInfo{}
+		var err error
+		for _, attr := range event.Attributes {
+			switch attr.Key {
+			case PacketSequenceKey:
+				info.Sequence, err = strconv.ParseUint(attr.Value, 10, 64)
+			case PacketSrcPortKey:
+				info.SourcePort = attr.Value
+			case PacketSrcChannelKey:
+				info.SourceChannel = attr.Value
+			case PacketDstPortKey:
+				info.DestPort = attr.Value
+			case PacketDstChannelKey:
+				info.DestChannel = attr.Value
+			case PacketTimeoutHeightKey:
+				info.TimeoutHeight = attr.Value
+			case PacketTimeoutTimestampKey:
+				info.TimeoutTimestamp, err = strconv.ParseUint(attr.Value, 10, 64)
+			}
+			if err != nil {
+				return nil, fmt.Errorf("parse %v attribute failed: %w", attr.Key, err)
+			}
+		}
+		if info.Sequence == 0 || info.SourceChannel == "" {
+			return nil, fmt.Errorf("%w: missing packet sequence or source channel", ErrSendPacketNotFound)
+		}
+		return info, nil
+	}
+	return nil, ErrSendPacketNotFound
+}
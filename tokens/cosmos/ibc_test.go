@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const tIBCTransferLog = `{"msg_index":0,"events":[
+{"type":"ibc_transfer","attributes":[{"key":"sender","value":"osmo1sender"},{"key":"receiver","value":"cosmos1receiver"}]},
+{"type":"message","attributes":[{"key":"action","value":"/ibc.applications.transfer.v1.MsgTransfer"},{"key":"sender","value":"osmo1sender"},{"key":"module","value":"ibc_channel"},{"key":"module","value":"transfer"}]},
+{"type":"send_packet","attributes":[
+{"key":"packet_data","value":"{\"amount\":\"1000\",\"denom\":\"uosmo\",\"receiver\":\"cosmos1receiver\",\"sender\":\"osmo1sender\"}"},
+{"key":"packet_data_hex","value":"7b7d"},
+{"key":"packet_timeout_height","value":"4-12345678"},
+{"key":"packet_timeout_timestamp","value":"1690000000000000000"},
+{"key":"packet_sequence","value":"1823456"},
+{"key":"packet_src_port","value":"transfer"},
+{"key":"packet_src_channel","value":"channel-0"},
+{"key":"packet_dst_port","value":"transfer"},
+{"key":"packet_dst_channel","value":"channel-141"},
+{"key":"packet_channel_ordering","value":"ORDER_UNORDERED"},
+{"key":"packet_connection","value":"connection-1"}]},
+{"type":"transfer","attributes":[{"key":"recipient","value":"osmo1escrow"},{"key":"sender","value":"osmo1sender"},{"key":"amount","value":"1000uosmo"}]}]}`
+
+func TestParseIBCPacketInfo(t *testing.T) {
+	var messageLog sdk.ABCIMessageLog
+	if err := json.Unmarshal([]byte(tIBCTransferLog), &messageLog); err != nil {
+		t.Fatal(err)
+	}
+
+	info, err := ParseIBCPacketInfo(messageLog)
+	if err != nil {
+		t.Fatalf("parse ibc packet info failed: %v", err)
+	}
+	want := IBCPacketInfo{
+		Sequence:         1823456,
+		SourcePort:       "transfer",
+		SourceChannel:    "channel-0",
+		DestPort:         "transfer",
+		DestChannel:      "channel-141",
+		TimeoutHeight:    "4-12345678",
+		TimeoutTimestamp: 1690000000000000000,
+	}
+	if *info != want {
+		t.Errorf("parse ibc packet info mismatch, have %+v want %+v", *info, want)
+	}
+
+	messageLog.Events = messageLog.Events[:2]
+	if _, err = ParseIBCPacketInfo(messageLog); !errors.Is(err, ErrSendPacketNotFound) {
+		t.Errorf("parse log without send_packet should fail with %v, but have %v", ErrSendPacketNotFound, err)
+	}
+}
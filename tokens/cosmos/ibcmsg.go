@@ -0,0 +1,325 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/cosmos/cosmos-sdk/codec"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/cosmos/cosmos-sdk/x/auth/legacy/legacytx"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	msgTransferName = "ibc.applications.transfer.v1.MsgTransfer"
+	heightName      = "ibc.core.client.v1.Height"
+
+	// msgTransferAminoName the amino name of MsgTransfer, required by SIGN_MODE_LEGACY_AMINO_JSON
+	msgTransferAminoName = "cosmos-sdk/MsgTransfer"
+
+	// IBCTransferPort the port of the ibc transfer module
+	IBCTransferPort = "transfer"
+)
+
+// Height ibc client height (`ibc.core.client.v1.Height`)
+type Height struct {
+	RevisionNumber uint64 `json:"revision_number,omitempty"`
+	RevisionHeight uint64 `json:"revision_height,omitempty"`
+}
+
+// MsgTransfer ibc transfer msg (`ibc.applications.transfer.v1.MsgTransfer`).
+// It is defined here with the same proto and amino encoding, as ibc-go is not a dependency.
+type MsgTransfer struct {
+	SourcePort       string   `json:"source_port"`
+	SourceChannel    string   `json:"source_channel"`
+	Token            sdk.Coin `json:"token"`
+	Sender           string   `json:"sender"`
+	Receiver         string   `json:"receiver"`
+	TimeoutHeight    Height   `json:"timeout_height"`
+	TimeoutTimestamp uint64   `json:"timeout_timestamp,omitempty"`
+	Memo             string   `json:"memo,omitempty"`
+}
+
+var (
+	_ sdk.Msg            = &MsgTransfer{}
+	_ legacytx.LegacyMsg = &MsgTransfer{}
+
+	// ibcAmino amino codec of the ibc msgs, the amino json of the msg is its sign bytes
+	ibcAmino = newIBCAmino()
+
+	// ibcFileDescriptor gzipped file descriptor of MsgTransfer
+	ibcFileDescriptor = newProtoFileDescriptor("ibc/applications/transfer/v1/tx.proto", "ibc.applications.transfer.v1",
+		[]string{"cosmos/base/v1beta1/coin.proto", "ibc/core/client/v1/client.proto"},
+		&descriptor.DescriptorProto{
+			Name: proto.String("MsgTransfer"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newProtoField("source_port", 1, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoField("source_channel", 2, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoMessageField("token", 3, ".cosmos.base.v1beta1.Coin"),
+				newProtoField("sender", 4, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoField("receiver", 5, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoMessageField("timeout_height", 6, "."+heightName),
+				newProtoField("timeout_timestamp", 7, descriptor.FieldDescriptorProto_TYPE_UINT64),
+				newProtoField("memo", 8, descriptor.FieldDescriptorProto_TYPE_STRING),
+			},
+		})
+
+	// ibcClientFileDescriptor gzipped file descriptor of Height
+	ibcClientFileDescriptor = newProtoFileDescriptor("ibc/core/client/v1/client.proto", "ibc.core.client.v1", nil,
+		&descriptor.DescriptorProto{
+			Name: proto.String("Height"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newProtoField("revision_number", 1, descriptor.FieldDescriptorProto_TYPE_UINT64),
+				newProtoField("revision_height", 2, descriptor.FieldDescriptorProto_TYPE_UINT64),
+			},
+		})
+)
+
+func init() {
+	// the tx decoder resolves the nested message type by name to reject unknown fields
+	proto.RegisterType((*Height)(nil), heightName)
+}
+
+func newIBCAmino() *codec.LegacyAmino {
+	amino := codec.NewLegacyAmino()
+	RegisterIBCLegacyAminoCodec(amino)
+	amino.Seal()
+	return amino
+}
+
+// RegisterIBCInterfaces register the ibc msgs
+func RegisterIBCInterfaces(registry codecTypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil), &MsgTransfer{})
+}
+
+// RegisterIBCLegacyAminoCodec register the amino names of the ibc msgs,
+// so that they can be signed by SIGN_MODE_LEGACY_AMINO_JSON
+func RegisterIBCLegacyAminoCodec(cdc *codec.LegacyAmino) {
+	cdc.RegisterConcrete(&MsgTransfer{}, msgTransferAminoName, nil)
+}
+
+// Reset impl proto.Message
+func (m *Height) Reset() { *m = Height{} }
+
+// String impl proto.Message
+func (m *Height) String() string {
+	return fmt.Sprintf("%d-%d", m.RevisionNumber, m.RevisionHeight)
+}
+
+// ProtoMessage impl proto.Message
+func (*Height) ProtoMessage() {}
+
+// Descriptor gzipped file descriptor and the message index
+func (*Height) Descriptor() ([]byte, []int) {
+	return ibcClientFileDescriptor, []int{0}
+}
+
+// IsZero is zero height
+func (m Height) IsZero() bool {
+	return m.RevisionNumber == 0 && m.RevisionHeight == 0
+}
+
+// Marshal proto encoding
+func (m *Height) Marshal() ([]byte, error) {
+	var bz []byte
+	if m.RevisionNumber != 0 {
+		bz = protowire.AppendTag(bz, 1, protowire.VarintType)
+		bz = protowire.AppendVarint(bz, m.RevisionNumber)
+	}
+	if m.RevisionHeight != 0 {
+		bz = protowire.AppendTag(bz, 2, protowire.VarintType)
+		bz = protowire.AppendVarint(bz, m.RevisionHeight)
+	}
+	return bz, nil
+}
+
+// Unmarshal proto decoding
+func (m *Height) Unmarshal(bz []byte) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if typ != protowire.VarintType || num < 1 || num > 2 {
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		value, n := protowire.ConsumeVarint(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if num == 1 {
+			m.RevisionNumber = value
+		} else {
+			m.RevisionHeight = value
+		}
+	}
+	return nil
+}
+
+// Size proto encoding size
+func (m *Height) Size() int {
+	bz, _ := m.Marshal()
+	return len(bz)
+}
+
+// Reset impl proto.Message
+func (m *MsgTransfer) Reset() { *m = MsgTransfer{} }
+
+// String impl proto.Message
+func (m *MsgTransfer) String() string {
+	return fmt.Sprintf("source_port:%q source_channel:%q token:%v sender:%q receiver:%q timeout_height:%v timeout_timestamp:%v memo:%q",
+		m.SourcePort, m.SourceChannel, m.Token, m.Sender, m.Receiver, m.TimeoutHeight.String(), m.TimeoutTimestamp, m.Memo)
+}
+
+// ProtoMessage impl proto.Message
+func (*MsgTransfer) ProtoMessage() {}
+
+// XXX_MessageName the full proto name (used as the type url)
+func (*MsgTransfer) XXX_MessageName() string { //nolint:revive,stylecheck // proto convention
+	return msgTransferName
+}
+
+// Descriptor gzipped file descriptor and the message index
+func (*MsgTransfer) Descriptor() ([]byte, []int) {
+	return ibcFileDescriptor, []int{0}
+}
+
+// ValidateBasic impl sdk.Msg
+func (m *MsgTransfer) ValidateBasic() error {
+	if m.SourcePort == "" || m.SourceChannel == "" {
+		return fmt.Errorf("invalid source port %q or channel %q", m.SourcePort, m.SourceChannel)
+	}
+	if !m.Token.IsValid() || !m.Token.IsPositive() {
+		return fmt.Errorf("invalid token %v", m.Token)
+	}
+	if _, err := sdk.AccAddressFromBech32(m.Sender); err != nil {
+		return fmt.Errorf("invalid sender %v, %w", m.Sender, err)
+	}
+	if m.Receiver == "" {
+		return errors.New("empty receiver")
+	}
+	if m.TimeoutHeight.IsZero() && m.TimeoutTimestamp == 0 {
+		return errors.New("timeout height and timeout timestamp can not be both zero")
+	}
+	return nil
+}
+
+// GetSigners impl sdk.Msg
+func (m *MsgTransfer) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(m.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+// Route impl legacytx.LegacyMsg
+func (*MsgTransfer) Route() string { return IBCTransferPort }
+
+// Type impl legacytx.LegacyMsg
+func (*MsgTransfer) Type() string { return "transfer" }
+
+// GetSignBytes impl legacytx.LegacyMsg, the sorted amino json
+func (m *MsgTransfer) GetSignBytes() []byte {
+	return sdk.MustSortJSON(ibcAmino.MustMarshalJSON(m))
+}
+
+// Marshal proto encoding
+func (m *MsgTransfer) Marshal() ([]byte, error) {
+	var bz []byte
+	appendString := func(num protowire.Number, value string) {
+		if value != "" {
+			bz = protowire.AppendTag(bz, num, protowire.BytesType)
+			bz = protowire.AppendString(bz, value)
+		}
+	}
+	appendString(1, m.SourcePort)
+	appendString(2, m.SourceChannel)
+	token, err := m.Token.Marshal()
+	if err != nil {
+		return nil, err
+	}
+	bz = protowire.AppendTag(bz, 3, protowire.BytesType)
+	bz = protowire.AppendBytes(bz, token)
+	appendString(4, m.Sender)
+	appendString(5, m.Receiver)
+	height, _ := m.TimeoutHeight.Marshal()
+	bz = protowire.AppendTag(bz, 6, protowire.BytesType)
+	bz = protowire.AppendBytes(bz, height)
+	if m.TimeoutTimestamp != 0 {
+		bz = protowire.AppendTag(bz, 7, protowire.VarintType)
+		bz = protowire.AppendVarint(bz, m.TimeoutTimestamp)
+	}
+	appendString(8, m.Memo)
+	return bz, nil
+}
+
+// Unmarshal proto decoding
+func (m *MsgTransfer) Unmarshal(bz []byte) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if num == 7 && typ == protowire.VarintType {
+			m.TimeoutTimestamp, n = protowire.ConsumeVarint(bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		if typ != protowire.BytesType || num < 1 || num > 8 || num == 7 {
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		var value []byte
+		value, n = protowire.ConsumeBytes(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		switch num {
+		case 1:
+			m.SourcePort = string(value)
+		case 2:
+			m.SourceChannel = string(value)
+		case 3:
+			if err := m.Token.Unmarshal(value); err != nil {
+				return err
+			}
+		case 4:
+			m.Sender = string(value)
+		case 5:
+			m.Receiver = string(value)
+		case 6:
+			if err := m.TimeoutHeight.Unmarshal(value); err != nil {
+				return err
+			}
+		case 8:
+			m.Memo = string(value)
+		}
+	}
+	return nil
+}
+
+// Size proto encoding size
+func (m *MsgTransfer) Size() int {
+	bz, _ := m.Marshal()
+	return len(bz)
+}
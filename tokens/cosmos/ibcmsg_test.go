@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	signingTypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+)
+
+func TestMsgTransferLegacyAminoSignBytes(t *testing.T) {
+	b := NewCrossChainBridge()
+	msg := &MsgTransfer{
+		SourcePort:       IBCTransferPort,
+		SourceChannel:    "channel-141",
+		Token:            sdk.NewInt64Coin("uatom", 1000),
+		Sender:           tMPCAddress,
+		Receiver:         "osmo1receiver",
+		TimeoutHeight:    Height{RevisionNumber: 1, RevisionHeight: 100},
+		TimeoutTimestamp: 1700000000000000000,
+	}
+	if err := msg.ValidateBasic(); err != nil {
+		t.Fatal(err)
+	}
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		t.Fatal(err)
+	}
+	txBuilder.SetGasLimit(200000)
+	txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("uatom", 5000)))
+	txBuilder.SetMemo("swap")
+
+	handler := b.TxConfig.SignModeHandler()
+	signerData := BuildSignerData("cosmoshub-4", 12, 7)
+	signBytes, err := handler.GetSignBytes(signingTypes.SignMode_SIGN_MODE_LEGACY_AMINO_JSON, signerData, txBuilder.GetTx())
+	if err != nil {
+		t.Fatalf("get amino sign bytes of ibc transfer failed: %v", err)
+	}
+	want := `{"account_number":"12","chain_id":"cosmoshub-4","fee":{"amount":[{"amount":"5000","denom":"uatom"}],"gas":"200000"},` +
+		`"memo":"swap","msgs":[{"type":"cosmos-sdk/MsgTransfer","value":{"receiver":"osmo1receiver","sender":"` + tMPCAddress + `",` +
+		`"source_channel":"channel-141","source_port":"transfer","timeout_height":{"revision_height":"100","revision_number":"1"},` +
+		`"timeout_timestamp":"1700000000000000000","token":{"amount":"1000","denom":"uatom"}}}],"sequence":"7"}`
+	if string(signBytes) != want {
+		t.Errorf("amino sign bytes mismatch\nhave %s\nwant %s", signBytes, want)
+	}
+
+	// the proto encoding round trips through the tx decoder which rejects unknown fields
+	txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := b.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode ibc transfer tx failed: %v", err)
+	}
+	if msgs := tx.GetMsgs(); len(msgs) != 1 || msgs[0].(*MsgTransfer).String() != msg.String() {
+		t.Errorf("decoded msg mismatch, have %v want %v", msgs, msg)
+	}
+}
@@ -0,0 +1,250 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+const (
+	// AcknowledgePacketType the ibc event emitted on the source chain when the packet is acknowledged
+	AcknowledgePacketType = "acknowledge_packet"
+	// TimeoutPacketType the ibc event emitted on the source chain when the packet is timed out
+	TimeoutPacketType = "timeout_packet"
+
+	// FungibleTokenPacketType the ics20 event emitted with the ack result on the source chain
+	FungibleTokenPacketType = "fungible_token_packet"
+	AckSuccessKey           = "success"
+	AckErrorKey             = "error"
+
+	// TxsByEvents query txs by events
+	TxsByEvents = "/cosmos/tx/v1beta1/txs"
+)
+
+var (
+	// ErrWrongIBCTransferTransition the ibc transfer state can not transit to the state
+	ErrWrongIBCTransferTransition = errors.New("wrong ibc transfer state transition")
+)
+
+// IBCTransferState state of an ibc transfer on the source chain
+type IBCTransferState int
+
+// IBCTransferState values
+const (
+	// IBCTransferSent the packet is sent, and the ack or timeout is not relayed yet
+	IBCTransferSent IBCTransferState = iota
+	// IBCTransferAcknowledged the packet is acknowledged by the destination chain
+	IBCTransferAcknowledged
+	// IBCTransferTimedOut the timeout is relayed and the funds are refunded to the sender
+	IBCTransferTimedOut
+	// IBCTransferAckError the error ack is relayed and the funds are refunded to the sender
+	IBCTransferAckError
+)
+
+func (s IBCTransferState) String() string {
+	switch s {
+	case IBCTransferSent:
+		return "Sent"
+	case IBCTransferAcknowledged:
+		return "Acknowledged"
+	case IBCTransferTimedOut:
+		return "TimedOut"
+	case IBCTransferAckError:
+		return "AckError"
+	default:
+		return fmt.Sprintf("unknown ibc transfer state %d", int(s))
+	}
+}
+
+// IsFinal is terminal state
+func (s IBCTransferState) IsFinal() bool {
+	return s == IBCTransferAcknowledged || s.CanRetry()
+}
+
+// CanRetry is it safe to send the transfer again (the funds are refunded)
+func (s IBCTransferState) CanRetry() bool {
+	return s == IBCTransferTimedOut || s == IBCTransferAckError
+}
+
+// canTransitTo only `Sent` can transit to the terminal states
+func (s IBCTransferState) canTransitTo(next IBCTransferState) bool {
+	return s == next || (s == IBCTransferSent && next.IsFinal())
+}
+
+// QueryTxsByEventsResponse txs by events
+type QueryTxsByEventsResponse struct {
+	Txs         []*Tx               `json:"txs"`
+	TxResponses []*TxResponse       `json:"tx_responses"`
+	Pagination  *TxSearchPagination `json:"pagination"`
+}
+
+// ibcTransferTracker tracks the states of ibc transfers
+type ibcTransferTracker struct {
+	lock   sync.Mutex
+	states map[string]IBCTransferState
+}
+
+func newIBCTransferTracker() *ibcTransferTracker {
+	return &ibcTransferTracker{states: make(map[string]IBCTransferState)}
+}
+
+func (t *ibcTransferTracker) get(key string) (IBCTransferState, bool) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	state, exist := t.states[key]
+	return state, exist
+}
+
+func (t *ibcTransferTracker) transit(key string, next IBCTransferState) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if state, exist := t.states[key]; exist && !state.canTransitTo(next) {
+		return fmt.Errorf("%w, key: %v, from %v to %v", ErrWrongIBCTransferTransition, key, state, next)
+	}
+	t.states[key] = next
+	return nil
+}
+
+// GetIBCTransferState get the state of the ibc transfer message at logIndex (starts from 1).
+// A sent transfer must not be regarded as failed and sent again before the timeout
+// is relayed (which refunds the funds), otherwise the funds may be sent twice.
+func (b *Bridge) GetIBCTransferState(txHash string, logIndex int) (IBCTransferState, error) {
+	key := fmt.Sprintf("%v:%v", txHash, logIndex)
+	if state, exist := b.ibcTransfers.get(key); exist && state.IsFinal() {
+		return state, nil
+	}
+	info, err := b.GetIBCPacketInfo(txHash, logIndex)
+	if err != nil {
+		return IBCTransferSent, err
+	}
+	state, err := b.queryIBCTransferState(info)
+	if err != nil {
+		return IBCTransferSent, err
+	}
+	if err = b.ibcTransfers.transit(key, state); err != nil {
+		return IBCTransferSent, err
+	}
+	if state.IsFinal() {
+		log.Info("ibc transfer is finished", "txHash", txHash, "logIndex", logIndex, "state", state,
+			"channel", info.SourceChannel, "sequence", info.Sequence)
+	}
+	return state, nil
+}
+
+// CanRetryIBCTransfer is it safe to send the ibc transfer again,
+// ie. the timeout is relayed and the funds are refunded.
+func (b *Bridge) CanRetryIBCTransfer(txHash string, logIndex int) (bool, error) {
+	state, err := b.GetIBCTransferState(txHash, logIndex)
+	if err != nil {
+		return false, err
+	}
+	return state.CanRetry(), nil
+}
+
+// CheckIBCTransfers check the ibc transfers sent by the swap tx, pending is true if any of them is not relayed back yet,
+// and refunded is true if any of them is refunded by timeout or error ack, so that the swap is safe to retry.
+// Both are false if the tx sends no ibc transfer.
+func (b *Bridge) CheckIBCTransfers(txHash string) (pending, refunded bool, err error) {
+	txr, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return false, false, err
+	}
+	if txr.TxResponse == nil {
+		return false, false, nil
+	}
+	for i, messageLog := range txr.TxResponse.Logs {
+		if _, errf := ParseIBCPacketInfo(messageLog); errf != nil {
+			continue
+		}
+		state, errf := b.GetIBCTransferState(txHash, i+1)
+		if errf != nil {
+			return false, false, errf
+		}
+		pending = pending || !state.IsFinal()
+		refunded = refunded || state.CanRetry()
+	}
+	return pending, refunded, nil
+}
+
+// queryIBCTransferState query the ack or timeout of the packet on the source chain
+func (b *Bridge) queryIBCTransferState(info *IBCPacketInfo) (IBCTransferState, error) {
+	ackTxs, err := b.queryIBCPacketEventTxs(AcknowledgePacketType, info)
+	if err != nil {
+		return IBCTransferSent, err
+	}
+	if len(ackTxs) > 0 {
+		for _, txres := range ackTxs {
+			if isIBCPacketAckError(txres, info) {
+				return IBCTransferAckError, nil
+			}
+		}
+		return IBCTransferAcknowledged, nil
+	}
+	timeoutTxs, err := b.queryIBCPacketEventTxs(TimeoutPacketType, info)
+	if err != nil {
+		return IBCTransferSent, err
+	}
+	if len(timeoutTxs) > 0 {
+		return IBCTransferTimedOut, nil
+	}
+	return IBCTransferSent, nil
+}
+
+// isIBCPacketAckError is the ack of the packet an error ack, which is the `error` attribute of
+// the `fungible_token_packet` event in the message log acknowledging the packet (not `success`).
+func isIBCPacketAckError(txres *TxResponse, info *IBCPacketInfo) bool {
+	if txres == nil || txres.Code != 0 {
+		return false
+	}
+	sequence := fmt.Sprintf("%v", info.Sequence)
+	for _, messageLog := range txres.Logs {
+		var isPacket, isError bool
+		for _, event := range messageLog.Events {
+			switch event.Type {
+			case AcknowledgePacketType:
+				var matchSequence, matchChannel bool
+				for _, attr := range event.Attributes {
+					switch attr.Key {
+					case PacketSequenceKey:
+						matchSequence = attr.Value == sequence
+					case PacketSrcChannelKey:
+						matchChannel = attr.Value == info.SourceChannel
+					}
+				}
+				isPacket = isPacket || (matchSequence && matchChannel)
+			case FungibleTokenPacketType:
+				for _, attr := range event.Attributes {
+					if attr.Key == AckErrorKey {
+						isError = true
+					}
+				}
+			}
+		}
+		if isPacket {
+			return isError
+		}
+	}
+	return false
+}
+
+// queryIBCPacketEventTxs query the txs emitting the event of the packet
+func (b *Bridge) queryIBCPacketEventTxs(eventType string, info *IBCPacketInfo) ([]*TxResponse, error) {
+	query := url.Values{}
+	query.Add("events", fmt.Sprintf("%v.%v='%v'", eventType, PacketSequenceKey, info.Sequence))
+	query.Add("events", fmt.Sprintf("%v.%v='%v'", eventType, PacketSrcChannelKey, info.SourceChannel))
+	query.Add("events", fmt.Sprintf("%v.%v='%v'", eventType, PacketSrcPortKey, info.SourcePort))
+	var result *QueryTxsByEventsResponse
+	var err error
+	for _, gateway := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(gateway, TxsByEvents) + "?" + query.Encode()
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return result.TxResponses, nil
+		}
+		log.Warn("query ibc packet event failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "query ibc packet event", eventType)
+}
@@ -0,0 +1,132 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestIBCTransferState(t *testing.T) {
+	const txHash = "IBCTRANSFER"
+	var outcome string
+	var searches int
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TxByHash + txHash:
+			_, _ = w.Write([]byte(`{"tx_response":{"height":"100","txhash":"` + txHash + `","code":0,"logs":[` + tIBCTransferLog + `]}}`))
+		case TxsByEvents:
+			searches++
+			events := r.URL.Query()["events"]
+			for _, event := range []string{"packet_sequence='1823456'", "packet_src_channel='channel-0'", "packet_src_port='transfer'"} {
+				if !strings.Contains(strings.Join(events, ","), event) {
+					t.Errorf("query packet events without %v, have %v", event, events)
+				}
+			}
+			if outcome != "" && strings.HasPrefix(events[0], strings.TrimSuffix(outcome, ":error")+".") {
+				_, _ = w.Write([]byte(`{"tx_responses":[{"height":"120","txhash":"RELAYED","logs":[` + tIBCAckLog(outcome) + `]}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"tx_responses":[]}`))
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	tests := []struct {
+		name     string
+		outcome  string
+		want     IBCTransferState
+		canRetry bool
+	}{
+		{"acknowledged", AcknowledgePacketType, IBCTransferAcknowledged, false},
+		{"error ack and refunded", AcknowledgePacketType + ":error", IBCTransferAckError, true},
+		{"timed out and refunded", TimeoutPacketType, IBCTransferTimedOut, true},
+	}
+	for _, test := range tests {
+		b.ibcTransfers = newIBCTransferTracker()
+
+		// not relayed yet, must not retry
+		outcome = ""
+		state, err := b.GetIBCTransferState(txHash, 1)
+		if err != nil || state != IBCTransferSent {
+			t.Fatalf("%v: pending transfer state mismatch, have %v %v", test.name, state, err)
+		}
+		if canRetry, err := b.CanRetryIBCTransfer(txHash, 1); err != nil || canRetry {
+			t.Errorf("%v: pending transfer should not be retried, have %v %v", test.name, canRetry, err)
+		}
+
+		if pending, refunded, err := b.CheckIBCTransfers(txHash); err != nil || !pending || refunded {
+			t.Errorf("%v: pending transfer check mismatch, have %v %v %v", test.name, pending, refunded, err)
+		}
+
+		outcome = test.outcome
+		if state, err = b.GetIBCTransferState(txHash, 1); err != nil || state != test.want {
+			t.Errorf("%v: transfer state mismatch, have %v %v want %v", test.name, state, err, test.want)
+		}
+		// terminal state is not queried again
+		searches = 0
+		canRetry, err := b.CanRetryIBCTransfer(txHash, 1)
+		if err != nil || canRetry != test.canRetry || searches != 0 {
+			t.Errorf("%v: can retry mismatch, have %v %v want %v, searches %v", test.name, canRetry, err, test.canRetry, searches)
+		}
+		if pending, refunded, err := b.CheckIBCTransfers(txHash); err != nil || pending || refunded != test.canRetry {
+			t.Errorf("%v: finished transfer check mismatch, have %v %v %v", test.name, pending, refunded, err)
+		}
+	}
+}
+
+// tIBCAckLog the message log relaying the ack or timeout of the packet of tIBCTransferLog
+func tIBCAckLog(outcome string) string {
+	eventType, ackResult := outcome, `{"key":"success","value":"\u0001"}`
+	if strings.HasSuffix(outcome, ":error") {
+		eventType, ackResult = strings.TrimSuffix(outcome, ":error"), `{"key":"error","value":"ABCI code: 5: error handling packet"}`
+	}
+	return `{"msg_index":1,"events":[
+{"type":"` + eventType + `","attributes":[{"key":"packet_sequence","value":"1823456"},{"key":"packet_src_port","value":"transfer"},{"key":"packet_src_channel","value":"channel-0"}]},
+{"type":"fungible_token_packet","attributes":[{"key":"module","value":"transfer"},{"key":"receiver","value":"cosmos1receiver"}]},
+{"type":"fungible_token_packet","attributes":[` + ackResult + `]}]}`
+}
+
+func TestIsIBCPacketAckError(t *testing.T) {
+	info := &IBCPacketInfo{Sequence: 1823456, SourcePort: IBCTransferPort, SourceChannel: "channel-0"}
+	parse := func(logs ...string) *TxResponse {
+		var txres *TxResponse
+		if err := json.Unmarshal([]byte(`{"logs":[`+strings.Join(logs, ",")+`]}`), &txres); err != nil {
+			t.Fatal(err)
+		}
+		return txres
+	}
+	otherPacket := strings.Replace(tIBCAckLog(AcknowledgePacketType+":error"), "1823456", "1823457", 1)
+	tests := []struct {
+		name  string
+		txres *TxResponse
+		want  bool
+	}{
+		{"success ack", parse(tIBCAckLog(AcknowledgePacketType)), false},
+		{"error ack", parse(tIBCAckLog(AcknowledgePacketType + ":error")), true},
+		{"error ack of other packet", parse(otherPacket, tIBCAckLog(AcknowledgePacketType)), false},
+		{"no logs", parse(), false},
+	}
+	for _, test := range tests {
+		if have := isIBCPacketAckError(test.txres, info); have != test.want {
+			t.Errorf("%v: ack error mismatch, have %v want %v", test.name, have, test.want)
+		}
+	}
+}
+
+func TestIBCTransferTransition(t *testing.T) {
+	tracker := newIBCTransferTracker()
+	if err := tracker.transit("tx:1", IBCTransferSent); err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.transit("tx:1", IBCTransferTimedOut); err != nil {
+		t.Errorf("transit from sent to timed out failed: %v", err)
+	}
+	for _, next := range []IBCTransferState{IBCTransferSent, IBCTransferAcknowledged} {
+		if err := tracker.transit("tx:1", next); !errors.Is(err, ErrWrongIBCTransferTransition) {
+			t.Errorf("transit from timed out to %v should fail with %v, but have %v", next, ErrWrongIBCTransferTransition, err)
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// broadcast modes
+const (
+	BroadcastModeSync  = "BROADCAST_MODE_SYNC"
+	BroadcastModeAsync = "BROADCAST_MODE_ASYNC"
+)
+
+var (
+	// ErrTxInclusionTimeout tx is not found on chain before polling ends
+	ErrTxInclusionTimeout = errors.New("wait tx inclusion timeout")
+	// ErrTxIncludedWithFailure tx is included on chain with non zero code
+	ErrTxIncludedWithFailure = errors.New("tx included with non zero code")
+)
+
+// InclusionPollConfig config of polling tx inclusion with exponential backoff
+type InclusionPollConfig struct {
+	InitialInterval time.Duration // interval before the first query
+	MaxInterval     time.Duration // the doubled interval is capped by it
+	MaxAttempts     int           // max count of queries
+	Timeout         time.Duration // max duration of polling
+}
+
+// DefaultInclusionPollConfig default inclusion poll config
+var DefaultInclusionPollConfig = &InclusionPollConfig{
+	InitialInterval: 1 * time.Second,
+	MaxInterval:     16 * time.Second,
+	MaxAttempts:     10,
+	Timeout:         2 * time.Minute,
+}
+
+// WaitTxInclusion wait until the tx is included on chain.
+// If `websocketEndpoints` custom is configed, the tx event is subscribed for near-instant confirmation,
+// and it falls back to polling if the websocket is unavailable.
+// The tx is polled by hash otherwise. Query errors are treated as not found yet, and the tx is queried again
+// after an exponential backoff until max attempts or timeout is reached.
+// It returns ErrTxIncludedWithFailure along with the tx response if the tx
+// is included with non zero code, as the tx is final and must not be retried.
+func (b *Bridge) WaitTxInclusion(txHash string, config *InclusionPollConfig) (*TxResponse, error) {
+	if config == nil {
+		config = DefaultInclusionPollConfig
+	}
+	if endpoints := b.getWebsocketEndpoints(); len(endpoints) > 0 {
+		txRes, err := b.waitTxEvent(endpoints, txHash, config.Timeout)
+		if !errors.Is(err, ErrWebsocketUnavailable) {
+			return txRes, err
+		}
+		log.Warn("websocket is unavailable, fallback to polling tx inclusion", "txHash", txHash, "err", err)
+	}
+	return b.pollTxInclusion(txHash, config)
+}
+
+func (b *Bridge) pollTxInclusion(txHash string, config *InclusionPollConfig) (*TxResponse, error) {
+	deadline := time.Now().Add(config.Timeout)
+	interval := config.InitialInterval
+	var err error
+	for i := 0; i < config.MaxAttempts; i++ {
+		if i > 0 && time.Now().Add(interval).After(deadline) {
+			break
+		}
+		time.Sleep(interval)
+
+		var res *GetTxResponse
+		res, err = b.GetTransactionByHash(txHash)
+		if err == nil && res != nil && res.TxResponse != nil && strings.EqualFold(res.TxResponse.TxHash, txHash) {
+			return checkIncludedTx(txHash, res.TxResponse, fmt.Sprintf("polling %v", i+1))
+		}
+		log.Debug("tx not found yet", "txHash", txHash, "attempts", i+1, "err", err)
+
+		interval *= 2
+		if interval > config.MaxInterval {
+			interval = config.MaxInterval
+		}
+	}
+	return nil, fmt.Errorf("%w, txHash: %v, lastErr: %v", ErrTxInclusionTimeout, txHash, err)
+}
+
+// checkIncludedTx check the code of the included tx, `by` is logged to tell how the tx is found
+func checkIncludedTx(txHash string, txRes *TxResponse, by string) (*TxResponse, error) {
+	if txRes.Code != 0 {
+		log.Warn("tx included with non zero code", "txHash", txHash, "height", txRes.Height, "code", txRes.Code, "by", by)
+		return txRes, fmt.Errorf("%w, txHash: %v, code: %v", ErrTxIncludedWithFailure, txHash, txRes.Code)
+	}
+	log.Info("tx included", "txHash", txHash, "height", txRes.Height, "by", by)
+	return txRes, nil
+}
+
+// WaitTransactionStatus wait until the sent swap tx is included on chain (see WaitTxInclusion)
+// with DefaultInclusionPollConfig, and get its status. It is called by the worker after sending the swap tx.
+func (b *Bridge) WaitTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	if _, err := b.WaitTxInclusion(txHash, DefaultInclusionPollConfig); err != nil {
+		return nil, err
+	}
+	return b.GetTransactionStatus(txHash)
+}
+
+// BroadcastSignedTxAndWait broadcast the signed tx asynchronously and wait for its inclusion,
+// as async broadcast returns before the tx is checked and included.
+func (b *Bridge) BroadcastSignedTxAndWait(signedTx []byte, config *InclusionPollConfig) (*TxResponse, error) {
+	txHash, err := b.BroadcastSignedTx(signedTx, BroadcastModeAsync)
+	if err != nil {
+		return nil, err
+	}
+	return b.WaitTxInclusion(txHash, config)
+}
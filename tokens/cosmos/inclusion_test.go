@@ -0,0 +1,72 @@
+package cosmos
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const tInclusionTxHash = "4E0C9C1F1C4BB3F8D4A2A8AF2F3B0F4C5D6E7F8091A2B3C4D5E6F708192A3B4C"
+
+var tInclusionPollConfig = &InclusionPollConfig{
+	InitialInterval: time.Millisecond,
+	MaxInterval:     4 * time.Millisecond,
+	MaxAttempts:     6,
+	Timeout:         time.Second,
+}
+
+func newTestInclusionBridge(t *testing.T, foundAfter int32, code string) (*Bridge, *int32) {
+	var queries int32
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != TxByHash+tInclusionTxHash {
+			http.NotFound(w, r)
+			return
+		}
+		if atomic.AddInt32(&queries, 1) <= foundAfter {
+			http.Error(w, `{"code":5,"message":"tx not found"}`, http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tx_response":{"height":"100","txhash":"` + tInclusionTxHash + `","code":` + code + `,"logs":[]}}`))
+	})
+	return b, &queries
+}
+
+func TestWaitTxInclusion(t *testing.T) {
+	b, queries := newTestInclusionBridge(t, 3, "0")
+	txRes, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txRes.Height != "100" || txRes.Code != 0 {
+		t.Errorf("tx response mismatch, have height %v code %v", txRes.Height, txRes.Code)
+	}
+	if *queries != 4 {
+		t.Errorf("tx should be found at the 4th query, have %v queries", *queries)
+	}
+}
+
+func TestWaitTxInclusionWithFailure(t *testing.T) {
+	b, queries := newTestInclusionBridge(t, 1, "5")
+	txRes, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig)
+	if !errors.Is(err, ErrTxIncludedWithFailure) {
+		t.Fatalf("included failed tx should return %v, but have %v", ErrTxIncludedWithFailure, err)
+	}
+	if txRes == nil || txRes.Code != 5 {
+		t.Errorf("failed tx response should be returned, have %v", txRes)
+	}
+	if *queries != 2 {
+		t.Errorf("failed tx should not be polled again, have %v queries", *queries)
+	}
+}
+
+func TestWaitTxInclusionTimeout(t *testing.T) {
+	b, queries := newTestInclusionBridge(t, 100, "0")
+	if _, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig); !errors.Is(err, ErrTxInclusionTimeout) {
+		t.Fatalf("not found tx should return %v, but have %v", ErrTxInclusionTimeout, err)
+	}
+	if *queries != int32(tInclusionPollConfig.MaxAttempts) {
+		t.Errorf("polling should stop at max attempts, have %v queries", *queries)
+	}
+}
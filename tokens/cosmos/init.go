@@ -1,6 +1,8 @@
 package cosmos
 
 import (
+	"errors"
+	"fmt"
 	"math/big"
 	"strings"
 	"sync"
@@ -10,33 +12,78 @@ import (
 	cosmosClient "github.com/cosmos/cosmos-sdk/client"
 	"github.com/cosmos/cosmos-sdk/codec"
 	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
-	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
-	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 	sdktx "github.com/cosmos/cosmos-sdk/types/tx"
 	authTx "github.com/cosmos/cosmos-sdk/x/auth/tx"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/cosmos/cosmos-sdk/x/authz"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
 )
 
 var (
 	supportedChainIDs     = make(map[string]bool)
 	supportedChainIDsInit sync.Once
+	stubChainIDs          = make(map[StubChain]string) // stub chain -> stub chainID
+	stubChains            = make(map[string]StubChain) // stub chainID -> stub chain
 	ChainsList            = []string{"COSMOSHUB", "OSMOSIS", "COREUM", "SEI"}
 )
 
+// ErrInvalidStubChainName the chain name derives a stub chainID equal to the base or out of range
+var ErrInvalidStubChainName = errors.New("invalid stub chain name")
+
 const (
 	mainnetNetWork = "mainnet"
 	testnetNetWork = "testnet"
 	devnetNetWork  = "devnet"
 )
 
-func NewClientContext() cosmosClient.Context {
+// InterfaceRegistrar registers proto types to the interface registry
+type InterfaceRegistrar func(codecTypes.InterfaceRegistry)
+
+var (
+	chainInterfaceRegistrars     = make(map[string][]InterfaceRegistrar)
+	chainInterfaceRegistrarsLock sync.RWMutex
+)
+
+// RegisterChainInterfaces register chain specific proto types which are not in the standard sdk
+// (eg. coreum assetft, sei evm module). It should be called before setting the chain config.
+func RegisterChainInterfaces(chainName string, registrar InterfaceRegistrar) {
+	chainInterfaceRegistrarsLock.Lock()
+	defer chainInterfaceRegistrarsLock.Unlock()
+	chainName = strings.ToUpper(chainName)
+	chainInterfaceRegistrars[chainName] = append(chainInterfaceRegistrars[chainName], registrar)
+}
+
+// GetChainInterfaceRegistrars get the registered chain specific registrars of chainID
+func GetChainInterfaceRegistrars(chainID string) (registrars []InterfaceRegistrar) {
+	chainInterfaceRegistrarsLock.RLock()
+	defer chainInterfaceRegistrarsLock.RUnlock()
+	for chainName, chainRegistrars := range chainInterfaceRegistrars {
+		if IsSubChainOf(chainName, chainID) {
+			registrars = append(registrars, chainRegistrars...)
+		}
+	}
+	return registrars
+}
+
+// NewClientContext new client context with the standard proto types
+// and the additional chain specific proto types of registrars
+func NewClientContext(registrars ...InterfaceRegistrar) cosmosClient.Context {
 	amino := codec.NewLegacyAmino()
+	RegisterIBCLegacyAminoCodec(amino)
 
 	interfaceRegistry := codecTypes.NewInterfaceRegistry()
-	interfaceRegistry.RegisterImplementations((*cryptoTypes.PubKey)(nil), &secp256k1.PubKey{})
+	PublicKeyRegisterInterfaces(interfaceRegistry)
 	interfaceRegistry.RegisterImplementations((*authtypes.AccountI)(nil), &authtypes.BaseAccount{})
 	interfaceRegistry.RegisterImplementations((*sdk.Tx)(nil), &sdktx.Tx{})
+	bankTypes.RegisterInterfaces(interfaceRegistry)
+	authz.RegisterInterfaces(interfaceRegistry)
+	RegisterWasmInterfaces(interfaceRegistry)
+	RegisterIBCInterfaces(interfaceRegistry)
+	RegisterExtensionOptionInterfaces(interfaceRegistry)
+	for _, registrar := range registrars {
+		registrar(interfaceRegistry)
+	}
 
 	protoCodec := codec.NewProtoCodec(interfaceRegistry)
 	txConfig := authTx.NewTxConfig(protoCodec, authTx.DefaultSignModes)
@@ -48,18 +95,53 @@ func NewClientContext() cosmosClient.Context {
 		WithLegacyAmino(amino)
 }
 
-// SupportsChainID supports chainID
-func SupportsChainID(chainID *big.Int) bool {
+// StubChain chain name and network of a stub chainID
+type StubChain struct {
+	Name    string
+	Network string
+}
+
+func initSupportedChainIDs() {
 	supportedChainIDsInit.Do(func() {
 		for _, chainName := range ChainsList {
-			supportedChainIDs[GetStubChainID(chainName, mainnetNetWork).String()] = true
-			supportedChainIDs[GetStubChainID(chainName, testnetNetWork).String()] = true
-			supportedChainIDs[GetStubChainID(chainName, devnetNetWork).String()] = true
+			if err := CheckStubChainName(chainName); err != nil {
+				log.Fatalf("%v", err)
+			}
+			for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+				chainID := GetStubChainID(chainName, network).String()
+				stubChain := StubChain{Name: chainName, Network: network}
+				if exist, ok := stubChains[chainID]; ok {
+					log.Fatalf("stub chainID %v of %v conflicts with %v", chainID, stubChain, exist)
+				}
+				supportedChainIDs[chainID] = true
+				stubChainIDs[stubChain] = chainID
+				stubChains[chainID] = stubChain
+			}
 		}
 	})
+}
+
+// SupportsChainID supports chainID
+func SupportsChainID(chainID *big.Int) bool {
+	initSupportedChainIDs()
 	return supportedChainIDs[chainID.String()]
 }
 
+// LookupStubChain get the chain name and network of the supported stub chainID,
+// as the stub chainID is modded by StubChainIDBase and can not be decoded to the name.
+func LookupStubChain(chainID string) (stubChain StubChain, exist bool) {
+	initSupportedChainIDs()
+	stubChain, exist = stubChains[chainID]
+	return stubChain, exist
+}
+
+// LookupStubChainID get the stub chainID of the supported chain name and network
+func LookupStubChainID(chainName, network string) (chainID string, exist bool) {
+	initSupportedChainIDs()
+	chainID, exist = stubChainIDs[StubChain{Name: strings.ToUpper(chainName), Network: network}]
+	return chainID, exist
+}
+
 // IsSupportedCosmosSubChain is supported
 func IsSupportedCosmosSubChain(chainName string) bool {
 	var match bool
@@ -90,3 +172,31 @@ func GetStubChainID(chainName, network string) *big.Int {
 	stubChainID.Add(stubChainID, tokens.StubChainIDBase)
 	return stubChainID
 }
+
+// CheckStubChainName check the stub chainIDs of the chain name in every network
+// are in range (StubChainIDBase, 2*StubChainIDBase). As the name value (plus the network offset)
+// is modded by StubChainIDBase, an unusual name whose value is a multiple of the base derives the base itself.
+func CheckStubChainName(chainName string) error {
+	if strings.TrimSpace(chainName) == "" {
+		return fmt.Errorf("%w, empty chain name", ErrInvalidStubChainName)
+	}
+	upper := new(big.Int).Mul(tokens.StubChainIDBase, big.NewInt(2))
+	for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+		stubChainID := GetStubChainID(chainName, network)
+		if stubChainID.Cmp(tokens.StubChainIDBase) <= 0 || stubChainID.Cmp(upper) >= 0 {
+			return fmt.Errorf("%w, chain name %q derives stub chainID %v in %v out of range (%v, %v)",
+				ErrInvalidStubChainName, chainName, stubChainID, network, tokens.StubChainIDBase, upper)
+		}
+	}
+	return nil
+}
+
+// IsSubChainOf is chainID one of the stub chainIDs of the chain name
+func IsSubChainOf(chainName, chainID string) bool {
+	for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+		if GetStubChainID(chainName, network).String() == chainID {
+			return true
+		}
+	}
+	return false
+}
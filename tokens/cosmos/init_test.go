@@ -0,0 +1,113 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	stakingTypes "github.com/cosmos/cosmos-sdk/x/staking/types"
+)
+
+func TestRegisterChainInterfaces(t *testing.T) {
+	const fakeChain = "FAKECHAIN"
+	RegisterChainInterfaces(fakeChain, func(registry codecTypes.InterfaceRegistry) {
+		registry.RegisterImplementations((*sdk.Msg)(nil), &stakingTypes.MsgDelegate{})
+	})
+	t.Cleanup(func() {
+		chainInterfaceRegistrarsLock.Lock()
+		delete(chainInterfaceRegistrars, fakeChain)
+		chainInterfaceRegistrarsLock.Unlock()
+	})
+
+	msg := &stakingTypes.MsgDelegate{
+		DelegatorAddress: "fake1delegator",
+		ValidatorAddress: "fakevaloper1validator",
+		Amount:           sdk.NewCoin("ufake", sdk.NewIntFromBigInt(big.NewInt(1000))),
+	}
+	roundTrip := func(b *Bridge) (sdk.Tx, error) {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(msg); err != nil {
+			return nil, err
+		}
+		txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+		if err != nil {
+			return nil, err
+		}
+		return b.TxConfig.TxDecoder()(txBytes)
+	}
+
+	other := NewCrossChainBridge()
+	other.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID("COSMOSHUB", testnetNetWork).String()})
+	if _, err := roundTrip(other); err == nil {
+		t.Error("decode chain specific msg should fail on other chains")
+	}
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(fakeChain, testnetNetWork).String()})
+	tx, err := roundTrip(b)
+	if err != nil {
+		t.Fatalf("round trip chain specific msg failed: %v", err)
+	}
+	msgs := tx.GetMsgs()
+	if len(msgs) != 1 {
+		t.Fatalf("tx should have one msg, but have %v", len(msgs))
+	}
+	decoded, ok := msgs[0].(*stakingTypes.MsgDelegate)
+	if !ok {
+		t.Fatalf("tx msg should be MsgDelegate, but have %T", msgs[0])
+	}
+	if decoded.DelegatorAddress != msg.DelegatorAddress || !decoded.Amount.IsEqual(msg.Amount) {
+		t.Errorf("chain specific msg mismatch, have %v want %v", decoded, msg)
+	}
+}
+
+func TestLookupStubChain(t *testing.T) {
+	if len(ChainsList) != 4 {
+		t.Errorf("supported chains changed, have %v", ChainsList)
+	}
+	for _, chainName := range ChainsList {
+		for _, network := range []string{mainnetNetWork, testnetNetWork, devnetNetWork} {
+			chainID := GetStubChainID(chainName, network)
+			if !SupportsChainID(chainID) {
+				t.Errorf("stub chainID %v of %v %v is not supported", chainID, chainName, network)
+			}
+			stubChain, exist := LookupStubChain(chainID.String())
+			if !exist || stubChain.Name != chainName || stubChain.Network != network {
+				t.Errorf("lookup stub chain of %v mismatch, have %v %v want %v %v", chainID, stubChain, exist, chainName, network)
+			}
+			lookupID, exist := LookupStubChainID(strings.ToLower(chainName), network)
+			if !exist || lookupID != chainID.String() {
+				t.Errorf("lookup stub chainID of %v %v mismatch, have %v %v want %v", chainName, network, lookupID, exist, chainID)
+			}
+		}
+	}
+	if _, exist := LookupStubChain(GetStubChainID("UNKNOWNCHAIN", mainnetNetWork).String()); exist {
+		t.Error("lookup unsupported stub chainID should fail")
+	}
+	if _, exist := LookupStubChainID("SEI", "unknown"); exist {
+		t.Error("lookup stub chainID of unknown network should fail")
+	}
+}
+
+func TestCheckStubChainName(t *testing.T) {
+	for _, chainName := range append(ChainsList, "UNKNOWNCHAIN") {
+		if err := CheckStubChainName(chainName); err != nil {
+			t.Errorf("check stub chain name %v failed: %v", chainName, err)
+		}
+	}
+
+	// the value of the name is a multiple of StubChainIDBase, so its mainnet stub chainID is the base
+	const pathological = "FWDLER0\x00"
+	if stubChainID := GetStubChainID(pathological, mainnetNetWork); stubChainID.Cmp(tokens.StubChainIDBase) != 0 {
+		t.Fatalf("stub chainID of the pathological name should be the base, have %v", stubChainID)
+	}
+	for _, chainName := range []string{pathological, "", " "} {
+		if err := CheckStubChainName(chainName); !errors.Is(err, ErrInvalidStubChainName) {
+			t.Errorf("check stub chain name %q should fail with %v, but have %v", chainName, ErrInvalidStubChainName, err)
+		}
+	}
+}
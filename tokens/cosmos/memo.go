@@ -0,0 +1,75 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const (
+	// defaultMaxMemoLength the default `MaxMemoCharacters` of the auth module
+	defaultMaxMemoLength = 256
+	// complianceTagSeparator separates the swap identifier and the compliance tag in memo
+	complianceTagSeparator = ";"
+)
+
+var (
+	// ErrInvalidComplianceTag compliance tag has control characters or the separator
+	ErrInvalidComplianceTag = errors.New("invalid compliance tag")
+	// ErrMemoTooLong memo exceeds the max memo length
+	ErrMemoTooLong = errors.New("memo too long")
+)
+
+// getMaxMemoLength get max memo length, configed by `maxMemoLength` custom
+func (b *Bridge) getMaxMemoLength() int {
+	if lenStr := params.GetCustom(b.ChainConfig.ChainID, "maxMemoLength"); lenStr != "" {
+		if maxLen, err := strconv.Atoi(lenStr); err == nil && maxLen > 0 {
+			return maxLen
+		}
+		log.Warn("wrong maxMemoLength custom", "chainID", b.ChainConfig.ChainID, "value", lenStr)
+	}
+	return defaultMaxMemoLength
+}
+
+// getComplianceTag expand the `complianceMemo` custom template with the swap,
+// the placeholders are `{originator}`, `{beneficiary}`, `{fromChainID}` and `{swapID}`.
+// It returns empty string if the template is not set (off by default).
+func (b *Bridge) getComplianceTag(args *tokens.BuildTxArgs, receiver string) (string, error) {
+	template := params.GetCustom(b.ChainConfig.ChainID, "complianceMemo")
+	if template == "" {
+		return "", nil
+	}
+	tag := strings.NewReplacer(
+		"{originator}", args.OriginFrom,
+		"{beneficiary}", receiver,
+		"{fromChainID}", args.FromChainID.String(),
+		"{swapID}", args.SwapID,
+	).Replace(template)
+	if strings.Contains(tag, complianceTagSeparator) || strings.IndexFunc(tag, unicode.IsControl) >= 0 {
+		return "", fmt.Errorf("%w, tag: %q", ErrInvalidComplianceTag, tag)
+	}
+	return tag, nil
+}
+
+// buildPayoutMemo build memo of payout, which is the unique swap identifier,
+// followed by the compliance tag if `complianceMemo` custom is set.
+func (b *Bridge) buildPayoutMemo(args *tokens.BuildTxArgs, receiver string) (string, error) {
+	memo := args.GetUniqueSwapIdentifier()
+	tag, err := b.getComplianceTag(args, receiver)
+	if err != nil {
+		return "", err
+	}
+	if tag != "" {
+		memo += complianceTagSeparator + tag
+	}
+	if maxLen := b.getMaxMemoLength(); len(memo) > maxLen {
+		return "", fmt.Errorf("%w, length: %v, max: %v", ErrMemoTooLong, len(memo), maxLen)
+	}
+	return memo, nil
+}
@@ -0,0 +1,60 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestBuildPayoutMemo(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1234567"}
+
+	args := &tokens.BuildTxArgs{
+		SwapArgs: tokens.SwapArgs{
+			SwapID:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+			LogIndex:    2,
+			FromChainID: big.NewInt(1),
+		},
+		OriginFrom: "0x2222222222222222222222222222222222222222",
+	}
+	receiver := "cosmos1receiver"
+	identifier := args.GetUniqueSwapIdentifier()
+
+	// disabled by default
+	memo, err := b.buildPayoutMemo(args, receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if memo != identifier {
+		t.Errorf("memo should have no compliance tag if disabled, have %q", memo)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"complianceMemo": "tr:orig={originator},bene={beneficiary},chain={fromChainID}"})
+	memo, err = b.buildPayoutMemo(args, receiver)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := identifier + ";tr:orig=" + args.OriginFrom + ",bene=" + receiver + ",chain=1"
+	if memo != want {
+		t.Errorf("compliance memo mismatch, have %q want %q", memo, want)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"complianceMemo": "tr\n{originator}"})
+	if _, err = b.buildPayoutMemo(args, receiver); !errors.Is(err, ErrInvalidComplianceTag) {
+		t.Errorf("compliance tag with control character should fail with %v, but have %v", ErrInvalidComplianceTag, err)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"complianceMemo": strings.Repeat("x", defaultMaxMemoLength)})
+	if _, err = b.buildPayoutMemo(args, receiver); !errors.Is(err, ErrMemoTooLong) {
+		t.Errorf("too long memo should fail with %v, but have %v", ErrMemoTooLong, err)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"complianceMemo": strings.Repeat("x", defaultMaxMemoLength), "maxMemoLength": "512"})
+	if _, err = b.buildPayoutMemo(args, receiver); err != nil {
+		t.Errorf("memo within configed max length should pass, but have %v", err)
+	}
+}
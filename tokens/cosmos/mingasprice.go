@@ -0,0 +1,206 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// NodeConfig query of the node config (minimum gas price)
+	NodeConfig = "/cosmos/base/node/v1beta1/config"
+
+	// minGasPriceFromNode `minGasPrice` custom value to use the min gas price advertised by the node
+	minGasPriceFromNode = "node"
+
+	// defaultMaxFeeBumpMultiplier the fee can be bumped to this multiple at most if `maxFee` is not configed
+	defaultMaxFeeBumpMultiplier = 10
+)
+
+var (
+	// ErrFeeExceedsCap the fee (or the one required by the min gas price) exceeds the cap
+	ErrFeeExceedsCap = errors.New("fee exceeds the cap")
+	// ErrGasPriceTooLow the gas price of the signed tx is below the min gas price
+	ErrGasPriceTooLow = errors.New("gas price is below the min gas price")
+)
+
+// QueryNodeConfigResponse node config
+type QueryNodeConfigResponse struct {
+	MinimumGasPrice string `json:"minimum_gas_price"`
+}
+
+// nodeMinGasPriceCacheTTL the node min gas price is changed rarely (by restarting the node with a new config)
+var nodeMinGasPriceCacheTTL = time.Minute
+
+// minGasPriceCache caches the min gas prices advertised by the node,
+// as they are queried when building every tx and again before broadcasting it.
+type minGasPriceCache struct {
+	mu        sync.Mutex
+	prices    sdk.DecCoins
+	updatedAt time.Time
+
+	fetch func() (sdk.DecCoins, error)
+}
+
+func newMinGasPriceCache(fetch func() (sdk.DecCoins, error)) *minGasPriceCache {
+	return &minGasPriceCache{fetch: fetch}
+}
+
+// Get returns the cached min gas prices if they are fresh, otherwise refreshes them
+func (c *minGasPriceCache) Get() (sdk.DecCoins, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.updatedAt.IsZero() && time.Since(c.updatedAt) < nodeMinGasPriceCacheTTL {
+		return c.prices, nil
+	}
+	prices, err := c.fetch()
+	if err != nil {
+		return nil, err
+	}
+	c.prices, c.updatedAt = prices, time.Now()
+	return c.prices, nil
+}
+
+// GetNodeMinGasPrices get the min gas prices advertised by the node (cached for nodeMinGasPriceCacheTTL)
+func (b *Bridge) GetNodeMinGasPrices() (sdk.DecCoins, error) {
+	return b.nodeMinGasPrices.Get()
+}
+
+// queryNodeMinGasPrices query the min gas prices advertised by the node
+func (b *Bridge) queryNodeMinGasPrices() (sdk.DecCoins, error) {
+	var result *QueryNodeConfigResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, NodeConfig)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return sdk.ParseDecCoins(result.MinimumGasPrice)
+		}
+		log.Warn("GetNodeMinGasPrices failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "GetNodeMinGasPrices")
+}
+
+// GetMinGasPrices get the min gas prices configed by `minGasPrice` custom,
+// which is dec coins (eg. `0.025uatom`), or `node` to use the one advertised by the node.
+// No min gas price is enforced if it is not configed.
+func (b *Bridge) GetMinGasPrices() (sdk.DecCoins, error) {
+	minGasPrice := params.GetCustom(b.ChainConfig.ChainID, "minGasPrice")
+	switch minGasPrice {
+	case "":
+		return nil, nil
+	case minGasPriceFromNode:
+		return b.GetNodeMinGasPrices()
+	default:
+		prices, err := sdk.ParseDecCoins(minGasPrice)
+		if err != nil {
+			return nil, fmt.Errorf("wrong minGasPrice custom %v, %w", minGasPrice, err)
+		}
+		return prices, nil
+	}
+}
+
+// getMaxFee get the cap of bumping the fee, which is the absolute fee cap (see getFeeCap),
+// default to defaultMaxFeeBumpMultiplier times of the fee.
+func (b *Bridge) getMaxFee(fee sdk.Coin) (sdk.Coin, error) {
+	feeCap, exist, err := b.getFeeCap(fee.Denom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	if exist {
+		return feeCap, nil
+	}
+	return sdk.NewCoin(fee.Denom, fee.Amount.MulRaw(defaultMaxFeeBumpMultiplier)), nil
+}
+
+// getRequiredFee get the fee of the min gas price of the fee denom (rounded up),
+// exist is false if the min gas price of the denom is not set.
+func getRequiredFee(minGasPrices sdk.DecCoins, denom string, gas uint64) (required sdk.Coin, exist bool) {
+	price := minGasPrices.AmountOf(denom)
+	if !price.IsPositive() {
+		return sdk.Coin{}, false
+	}
+	amount := price.MulInt64(int64(gas)).Ceil().TruncateInt()
+	return sdk.NewCoin(denom, amount), true
+}
+
+// enforceMinGasPrice returns the fee bumped to the min gas price of the gas if it is below it,
+// so that the tx is not rejected by `insufficient fees`. It fails if the bumped fee exceeds the cap.
+// It is applied to the fee candidates before selecting the affordable one (see selectFee).
+func (b *Bridge) enforceMinGasPrice(fee string, gas uint64) (string, error) {
+	minGasPrices, err := b.GetMinGasPrices()
+	if err != nil || minGasPrices.Empty() {
+		return fee, err
+	}
+	feeCoins, err := ParseCoinsFee(fee)
+	if err != nil {
+		return "", err
+	}
+	if len(feeCoins) != 1 {
+		log.Warn("skip enforcing min gas price of multiple fee coins", "chainID", b.ChainConfig.ChainID, "fee", fee)
+		return fee, nil
+	}
+	feeCoin := feeCoins[0]
+	required, exist := getRequiredFee(minGasPrices, feeCoin.Denom, gas)
+	if !exist {
+		log.Warn("no min gas price of the fee denom", "chainID", b.ChainConfig.ChainID, "fee", fee, "minGasPrices", minGasPrices)
+		return fee, nil
+	}
+	if feeCoin.Amount.GTE(required.Amount) {
+		return fee, nil
+	}
+	maxFee, err := b.getMaxFee(feeCoin)
+	if err != nil {
+		return "", err
+	}
+	if required.Amount.GT(maxFee.Amount) {
+		log.Warn("fee required by min gas price exceeds the cap", "chainID", b.ChainConfig.ChainID, "gas", gas, "estimated", required, "cap", maxFee)
+		return "", fmt.Errorf("%w, fee: %v, gas: %v, required: %v, cap: %v", ErrFeeExceedsCap, fee, gas, required, maxFee)
+	}
+	log.Info("bump fee to the min gas price", "chainID", b.ChainConfig.ChainID, "fee", fee, "gas", gas, "bumped", required, "minGasPrices", minGasPrices)
+	return required.String(), nil
+}
+
+// checkMinGasPrice check the gas price of the signed tx is not below the min gas price before broadcasting,
+// the signed tx can not be bumped, it is refused to avoid the predictable `insufficient fees` rejection.
+func (b *Bridge) checkMinGasPrice(signedTx []byte) error {
+	minGasPrices, err := b.GetMinGasPrices()
+	if err != nil || minGasPrices.Empty() {
+		return err
+	}
+	txBytes, err := base64.StdEncoding.DecodeString(string(signedTx))
+	if err != nil {
+		return err
+	}
+	tx, err := b.DecodeTx(txBytes)
+	if err != nil {
+		return err
+	}
+	feeTx, ok := tx.(sdk.FeeTx)
+	if !ok {
+		return errors.New("tx is not a fee tx")
+	}
+	// the node accepts the tx if any fee coin satisfies the min gas price of its denom
+	gas := feeTx.GetGas()
+	checked := false
+	for _, coin := range feeTx.GetFee() {
+		required, exist := getRequiredFee(minGasPrices, coin.Denom, gas)
+		if !exist {
+			continue
+		}
+		if coin.Amount.GTE(required.Amount) {
+			return nil
+		}
+		checked = true
+	}
+	if checked {
+		return fmt.Errorf("%w, fee: %v, gas: %v, minGasPrices: %v", ErrGasPriceTooLow, feeTx.GetFee(), gas, minGasPrices)
+	}
+	return nil
+}
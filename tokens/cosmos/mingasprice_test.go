@@ -0,0 +1,151 @@
+package cosmos
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestEnforceMinGasPrice(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != NodeConfig {
+			t.Errorf("unexpected request %v", r.URL)
+		}
+		_, _ = w.Write([]byte(`{"minimum_gas_price":"0.02usei"}`))
+	})
+
+	tests := []struct {
+		name    string
+		customs map[string]string
+		fee     string
+		want    string
+		wantErr error
+	}{
+		{"not configed", nil, "1000usei", "1000usei", nil},
+		{"above min gas price", map[string]string{"minGasPrice": "0.01usei"}, "3000usei", "3000usei", nil},
+		{"below min gas price is bumped", map[string]string{"minGasPrice": "0.025usei"}, "3000usei", "5000usei", nil},
+		{"rounded up", map[string]string{"minGasPrice": "0.0250001usei"}, "3000usei", "5001usei", nil},
+		{"node advertised", map[string]string{"minGasPrice": "node"}, "3000usei", "4000usei", nil},
+		{"within cap", map[string]string{"minGasPrice": "0.025usei", "maxFee": "5000usei"}, "3000usei", "5000usei", nil},
+		{"exceeds cap", map[string]string{"minGasPrice": "0.025usei", "maxFee": "4999usei"}, "3000usei", "", ErrFeeExceedsCap},
+		{"exceeds default cap", map[string]string{"minGasPrice": "1usei"}, "3000usei", "", ErrFeeExceedsCap},
+		{"other fee denom", map[string]string{"minGasPrice": "0.025usei"}, "10ibc/ABC", "10ibc/ABC", nil},
+	}
+	gas := uint64(200000)
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, test.customs)
+		fee, err := b.enforceMinGasPrice(test.fee, gas)
+		if test.wantErr != nil {
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("%v: should fail with %v, but have %v", test.name, test.wantErr, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: enforce min gas price failed: %v", test.name, err)
+			continue
+		}
+		if fee != test.want {
+			t.Errorf("%v: fee mismatch, have %v want %v", test.name, fee, test.want)
+		}
+	}
+}
+
+func TestNodeMinGasPriceCache(t *testing.T) {
+	var queries int
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		queries++
+		_, _ = w.Write([]byte(`{"minimum_gas_price":"0.02usei"}`))
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"minGasPrice": "node"})
+
+	for i := 0; i < 3; i++ {
+		if prices, err := b.GetMinGasPrices(); err != nil || prices.String() != "0.020000000000000000usei" {
+			t.Fatalf("get node min gas prices mismatch, have %v %v", prices, err)
+		}
+	}
+	if queries != 1 {
+		t.Errorf("node min gas prices should be cached, but queried %v times", queries)
+	}
+
+	ttl := nodeMinGasPriceCacheTTL
+	nodeMinGasPriceCacheTTL = 0
+	t.Cleanup(func() { nodeMinGasPriceCacheTTL = ttl })
+	if _, err := b.GetMinGasPrices(); err != nil || queries != 2 {
+		t.Errorf("expired node min gas prices should be queried again, have %v queries %v", queries, err)
+	}
+}
+
+func TestCheckMinGasPriceBeforeBroadcast(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected request %v", r.URL)
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"minGasPrice": "0.025usei"})
+
+	signedTx := func(fee int64) []byte {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(BuildSendMsg("sei1sender", "sei1receiver", "usei", sdk.NewInt(1000).BigInt())); err != nil {
+			t.Fatal(err)
+		}
+		txBuilder.SetGasLimit(200000)
+		txBuilder.SetFeeAmount(sdk.NewCoins(sdk.NewInt64Coin("usei", fee)))
+		tx, _, err := b.GetSignTx(txBuilder.GetTx())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return tx
+	}
+	if err := b.checkMinGasPrice(signedTx(5000)); err != nil {
+		t.Errorf("check min gas price failed: %v", err)
+	}
+	// refused before broadcasting
+	if _, err := b.SendTransaction(signedTx(4999)); !errors.Is(err, ErrGasPriceTooLow) {
+		t.Errorf("send tx below min gas price should fail with %v, but have %v", ErrGasPriceTooLow, err)
+	}
+}
+
+func TestFeeCapOfRunawayGasEstimate(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != SimulateTx {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		_, _ = w.Write([]byte(`{"gas_info":{"gas_wanted":"0","gas_used":"10000000000"}}`))
+	})
+
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(BuildSendMsg("sei1sender", "sei1receiver", "usei", sdk.NewInt(1000).BigInt())); err != nil {
+		t.Fatal(err)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"minGasPrice": "0.025usei", "maxFee": "50000usei"})
+	gas := b.EstimateGasLimit(txBuilder, "")
+	if gas != 13000000000 {
+		t.Fatalf("estimated gas mismatch, have %v", gas)
+	}
+	if _, err := b.enforceMinGasPrice("5000usei", gas); !errors.Is(err, ErrFeeExceedsCap) {
+		t.Errorf("fee of runaway gas estimate should fail with %v, but have %v", ErrFeeExceedsCap, err)
+	}
+
+	tests := []struct {
+		name    string
+		customs map[string]string
+		fee     string
+		wantErr bool
+	}{
+		{"not configed", nil, "1000000000usei", false},
+		{"within cap", map[string]string{"maxFee": "50000usei"}, "50000usei", false},
+		{"exceeds cap", map[string]string{"maxFee": "50000usei"}, "50001usei", true},
+		{"other denom not capped", map[string]string{"maxFee": "50000usei"}, "50001uatom", false},
+		{"one of the coins exceeds cap", map[string]string{"maxFee": "50000usei,10uatom"}, "11uatom,50000usei", true},
+		{"wrong cap", map[string]string{"maxFee": "abc"}, "1usei", true},
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, test.customs)
+		err := b.checkFeeCap(test.fee, gas)
+		if (err != nil) != test.wantErr {
+			t.Errorf("%v: check fee cap mismatch, want error %v, have %v", test.name, test.wantErr, err)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+const (
+	// TxFeesFeeTokens osmosis txfees module query of the whitelisted fee tokens
+	TxFeesFeeTokens = "/osmosis/txfees/v1beta1/fee_tokens"
+	// TxFeesSpotPrice osmosis txfees module query of the fee token spot price (in base denom)
+	TxFeesSpotPrice = "/osmosis/txfees/v1beta1/spot_price_by_denom?denom="
+)
+
+var (
+	// ErrFeeTokenNotWhitelisted the fee token is not whitelisted by the txfees module
+	ErrFeeTokenNotWhitelisted = errors.New("fee token is not whitelisted by txfees module")
+	// ErrWrongFeeTokenPrice the spot price of the fee token is not positive
+	ErrWrongFeeTokenPrice = errors.New("wrong fee token spot price")
+)
+
+// FeeToken osmosis txfees fee token
+type FeeToken struct {
+	Denom  string `json:"denom"`
+	PoolID string `json:"poolID"`
+}
+
+// QueryFeeTokensResponse osmosis txfees fee tokens
+type QueryFeeTokensResponse struct {
+	FeeTokens []FeeToken `json:"fee_tokens"`
+}
+
+// QuerySpotPriceByDenomResponse osmosis txfees spot price of fee token
+type QuerySpotPriceByDenomResponse struct {
+	PoolID    string `json:"poolID"`
+	SpotPrice string `json:"spot_price"`
+}
+
+// getTxFeesTokens get the fee tokens to pay fee with if `mpc` lacks the base denom,
+// which is configed by `txFeesTokens` custom (comma separated denoms, eg. `ibc/ABC,ibc/DEF`)
+func (b *Bridge) getTxFeesTokens() (denoms []string) {
+	for _, denom := range strings.Split(params.GetCustom(b.ChainConfig.ChainID, "txFeesTokens"), ",") {
+		if denom = strings.TrimSpace(denom); denom != "" {
+			denoms = append(denoms, denom)
+		}
+	}
+	return denoms
+}
+
+// GetTxFeesFeeTokens get the fee tokens whitelisted by the txfees module
+func (b *Bridge) GetTxFeesFeeTokens() ([]FeeToken, error) {
+	var result *QueryFeeTokensResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, TxFeesFeeTokens)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return result.FeeTokens, nil
+		}
+		log.Warn("GetTxFeesFeeTokens failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "GetTxFeesFeeTokens")
+}
+
+// GetTxFeesSpotPrice get the spot price of the fee token, ie. base denom amount of one fee token
+func (b *Bridge) GetTxFeesSpotPrice(denom string) (sdk.Dec, error) {
+	var result *QuerySpotPriceByDenomResponse
+	var err error
+	for _, gateway := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(gateway, TxFeesSpotPrice+url.QueryEscape(denom))
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			price, errf := sdk.NewDecFromStr(result.SpotPrice)
+			if errf != nil || !price.IsPositive() {
+				return sdk.Dec{}, fmt.Errorf("%w, denom: %v, price: %v", ErrWrongFeeTokenPrice, denom, result.SpotPrice)
+			}
+			return price, nil
+		}
+		log.Warn("GetTxFeesSpotPrice failed", "url", restApi, "err", err)
+	}
+	return sdk.Dec{}, wrapRPCQueryError(err, "GetTxFeesSpotPrice", denom)
+}
+
+// convertFeeToFeeToken convert the base denom fee to the amount of the whitelisted fee token,
+// the amount is rounded up so that the converted value is not less than the base fee.
+func (b *Bridge) convertFeeToFeeToken(baseFee sdk.Coin, denom string, whitelist []FeeToken) (sdk.Coin, error) {
+	whitelisted := false
+	for _, feeToken := range whitelist {
+		if feeToken.Denom == denom {
+			whitelisted = true
+			break
+		}
+	}
+	if !whitelisted {
+		return sdk.Coin{}, fmt.Errorf("%w: %v", ErrFeeTokenNotWhitelisted, denom)
+	}
+	price, err := b.GetTxFeesSpotPrice(denom)
+	if err != nil {
+		return sdk.Coin{}, err
+	}
+	amount := sdk.NewDecFromInt(baseFee.Amount).Quo(price).Ceil().TruncateInt()
+	return sdk.NewCoin(denom, amount), nil
+}
+
+// selectTxFeesToken select the first fee token (configed by `txFeesTokens` custom)
+// the payer has enough balance to pay the base fee converted by the txfees spot price,
+// returns empty string if no fee token is configed or affordable.
+func (b *Bridge) selectTxFeesToken(payer, baseFee, payoutDenom string, payout *big.Int) (string, error) {
+	denoms := b.getTxFeesTokens()
+	if len(denoms) == 0 {
+		return "", nil
+	}
+	baseCoins, err := ParseCoinsFee(baseFee)
+	if err != nil || len(baseCoins) != 1 {
+		log.Warn("wrong base fee to convert", "chainID", b.ChainConfig.ChainID, "fee", baseFee, "err", err)
+		return "", nil
+	}
+	whitelist, err := b.GetTxFeesFeeTokens()
+	if err != nil {
+		return "", err
+	}
+	balances := make(map[string]sdk.Int)
+	for _, denom := range denoms {
+		feeCoin, errf := b.convertFeeToFeeToken(baseCoins[0], denom, whitelist)
+		if errf != nil {
+			log.Warn("convert fee to fee token failed", "chainID", b.ChainConfig.ChainID, "fee", baseFee, "denom", denom, "err", errf)
+			continue
+		}
+		fee := feeCoin.String()
+		enough, errf := b.canAffordFee(payer, fee, payoutDenom, payout, balances)
+		if errf != nil {
+			return "", errf
+		}
+		if enough {
+			log.Info("fee will be paid with txfees fee token", "payer", payer, "baseFee", baseFee, "fee", fee)
+			return fee, nil
+		}
+		log.Info("not enough balance to pay fee", "account", payer, "fee", fee)
+	}
+	return "", nil
+}
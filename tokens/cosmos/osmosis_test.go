@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"errors"
+	"math/big"
+	"net/http"
+	"testing"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestSelectTxFeesToken(t *testing.T) {
+	const ibcFoo, ibcBar = "ibc/FOO", "ibc/BAR"
+	var balances string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TxFeesFeeTokens:
+			_, _ = w.Write([]byte(`{"fee_tokens":[{"denom":"` + ibcFoo + `","poolID":"1"}]}`))
+		case "/osmosis/txfees/v1beta1/spot_price_by_denom":
+			if denom := r.URL.Query().Get("denom"); denom != ibcFoo {
+				t.Errorf("query spot price of non whitelisted fee token %v", denom)
+			}
+			_, _ = w.Write([]byte(`{"poolID":"1","spot_price":"0.300000000000000000"}`))
+		case Balances + "sei1payer":
+			_, _ = w.Write([]byte(`{"balances":[` + balances + `]}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"txFeesTokens": ibcBar + "," + ibcFoo})
+
+	// 500usei / 0.3 = 1666.67, rounded up
+	price, err := b.GetTxFeesSpotPrice(ibcFoo)
+	if err != nil || !price.Equal(sdk.NewDecWithPrec(3, 1)) {
+		t.Errorf("get fee token spot price failed, have %v %v", price, err)
+	}
+	tests := []struct {
+		name     string
+		balances string
+		want     string
+	}{
+		{"hold base denom", `{"denom":"usei","amount":"600"}`, "500usei"},
+		{"pay with whitelisted fee token", `{"denom":"usei","amount":"100"},{"denom":"ibc/FOO","amount":"1667"}`, "1667ibc/FOO"},
+		{"not enough fee token", `{"denom":"ibc/FOO","amount":"1666"}`, "500usei"},
+		{"non whitelisted fee token is skipped", `{"denom":"ibc/BAR","amount":"1000000"}`, "500usei"},
+	}
+	for _, test := range tests {
+		balances = test.balances
+		fee, err := b.selectFee("sei1payer", "uother", big.NewInt(1000), DefaultGasLimit)
+		if err != nil || fee != test.want {
+			t.Errorf("%v: select fee mismatch, have %v %v want %v", test.name, fee, err, test.want)
+		}
+	}
+
+	if _, err = b.convertFeeToFeeToken(sdk.NewInt64Coin("usei", 500), ibcBar, []FeeToken{{Denom: ibcFoo}}); !errors.Is(err, ErrFeeTokenNotWhitelisted) {
+		t.Errorf("convert to non whitelisted fee token should fail with %v, but have %v", ErrFeeTokenNotWhitelisted, err)
+	}
+}
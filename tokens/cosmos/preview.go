@@ -0,0 +1,55 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// TxPreview summary of the tx which would be built for a swap
+type TxPreview struct {
+	Receiver  string            `json:"receiver"`
+	Amount    *big.Int          `json:"amount"`
+	Denom     string            `json:"denom"`
+	BridgeFee *big.Int          `json:"bridgeFee,omitempty"`
+	Messages  []json.RawMessage `json:"messages"`
+	Memo      string            `json:"memo"`
+	Gas       uint64            `json:"gas"`
+	Fee       string            `json:"fee"`
+	Sequence  uint64            `json:"sequence"`
+}
+
+// PreviewTransaction dry run building the swap tx without allocating sequence or signing
+func (b *Bridge) PreviewTransaction(args *tokens.BuildTxArgs) (*TxPreview, error) {
+	txBuilder, receiver, err := b.buildTxBuilder(args, true)
+	if err != nil {
+		return nil, err
+	}
+	tx := txBuilder.GetTx()
+	txJSON, err := b.TxConfig.TxJSONEncoder()(tx)
+	if err != nil {
+		return nil, err
+	}
+	var txBody struct {
+		Body struct {
+			Messages []json.RawMessage `json:"messages"`
+		} `json:"body"`
+	}
+	if err = json.Unmarshal(txJSON, &txBody); err != nil {
+		return nil, err
+	}
+	extra := args.Extra
+	return &TxPreview{
+		Receiver:  receiver,
+		Amount:    args.SwapValue,
+		Denom:     router.GetCachedMultichainToken(args.GetTokenID(), args.ToChainID.String()),
+		BridgeFee: extra.BridgeFee,
+		Messages:  txBody.Body.Messages,
+		Memo:      tx.GetMemo(),
+		Gas:       tx.GetGas(),
+		Fee:       tx.GetFee().String(),
+		Sequence:  *extra.Sequence,
+	}, nil
+}
@@ -0,0 +1,81 @@
+package cosmos
+
+import (
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestPreviewTransaction(t *testing.T) {
+	mpc := tMPCAddress
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case Balances + mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"100000000"}]}`))
+		case SimulateTx:
+			_, _ = w.Write([]byte(`{"gas_info":{"gas_used":"80000"}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	// use the default bech32 prefix of sdk to validate msgs
+	b.Prefix, b.Denom = "cosmos", "uatom"
+	b.ChainConfig.ChainID = GetStubChainID("COSMOSHUB", testnetNetWork).String()
+
+	tokens.InitRouterSwapType("erc20swap")
+	tokenID, fromChainID, toChainID := "ATOM", big.NewInt(1), b.ChainConfig.ChainID
+	fromBridge := NewCrossChainBridge()
+	fromBridge.CrossChainBridgeBase.SetTokenConfig("0xtoken", &tokens.TokenConfig{TokenID: tokenID, Decimals: 6})
+	b.CrossChainBridgeBase.SetTokenConfig("uatom", &tokens.TokenConfig{TokenID: tokenID, Decimals: 6, RouterContract: "router"})
+	router.SetBridge(fromChainID.String(), fromBridge)
+	router.SetBridge(toChainID, b)
+	router.SetMultichainToken(tokenID, toChainID, "uatom")
+	router.SetRouterInfo("router", toChainID, &router.SwapRouterInfo{RouterMPC: mpc})
+	router.SetMPCPublicKey(mpc, tMPCPubkey)
+	t.Cleanup(func() {
+		router.SetBridge(fromChainID.String(), nil)
+		router.SetBridge(toChainID, nil)
+		router.SetMultichainTokens(tokenID, nil)
+	})
+
+	newArgs := func() *tokens.BuildTxArgs {
+		return &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: "0xtoken", TokenID: tokenID}},
+				SwapID:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+				SwapType:    tokens.ERC20SwapType,
+				Bind:        mpc,
+				FromChainID: fromChainID,
+				ToChainID:   GetStubChainID("COSMOSHUB", testnetNetWork),
+			},
+			From:        mpc,
+			OriginValue: big.NewInt(1000000),
+			Extra:       &tokens.AllExtras{},
+		}
+	}
+
+	b.SetNonce(mpc, 5)
+	preview, err := b.PreviewTransaction(newArgs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Sequence != 7 || preview.Gas != 104000 || preview.Denom != "uatom" || preview.Receiver != mpc || len(preview.Messages) != 1 {
+		t.Errorf("preview mismatch, have %+v", preview)
+	}
+	if nonce := b.GetSwapNonce(mpc); nonce != 5 {
+		t.Errorf("preview should not consume sequence, but swap nonce is changed to %v", nonce)
+	}
+
+	rawTx, err := b.BuildRawTransaction(newArgs())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tx := rawTx.(*BuildRawTx); tx.Sequence != preview.Sequence || tx.TxBuilder.GetTx().GetGas() != preview.Gas {
+		t.Errorf("built tx mismatch with preview, have sequence %v gas %v", tx.Sequence, tx.TxBuilder.GetTx().GetGas())
+	}
+}
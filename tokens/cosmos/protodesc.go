@@ -0,0 +1,48 @@
+package cosmos
+
+import (
+	"bytes"
+	"compress/gzip"
+
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+)
+
+// newProtoField new optional field descriptor
+func newProtoField(name string, number int32, typ descriptor.FieldDescriptorProto_Type) *descriptor.FieldDescriptorProto {
+	return &descriptor.FieldDescriptorProto{
+		Name:     proto.String(name),
+		Number:   proto.Int32(number),
+		Label:    descriptor.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		Type:     typ.Enum(),
+		JsonName: proto.String(name),
+	}
+}
+
+// newProtoMessageField new optional message field descriptor
+func newProtoMessageField(name string, number int32, typeName string) *descriptor.FieldDescriptorProto {
+	field := newProtoField(name, number, descriptor.FieldDescriptorProto_TYPE_MESSAGE)
+	field.TypeName = proto.String(typeName)
+	return field
+}
+
+// newProtoFileDescriptor gzipped file descriptor of the hand written proto messages
+// (which are not dependencies), the tx decoder requires it to reject unknown fields
+func newProtoFileDescriptor(name, pkg string, deps []string, msgs ...*descriptor.DescriptorProto) []byte {
+	fd := &descriptor.FileDescriptorProto{
+		Name:        proto.String(name),
+		Package:     proto.String(pkg),
+		Dependency:  deps,
+		MessageType: msgs,
+		Syntax:      proto.String("proto3"),
+	}
+	bz, err := proto.Marshal(fd)
+	if err != nil {
+		panic(err)
+	}
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, _ = zw.Write(bz)
+	_ = zw.Close()
+	return buf.Bytes()
+}
@@ -1,12 +1,9 @@
 package cosmos
 
 import (
-	"encoding/json"
-	"fmt"
 	"strconv"
 	"strings"
 
-	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
@@ -102,23 +99,13 @@ func (b *Bridge) GetTransactionByHash(txHash string) (*GetTxResponse, error) {
 	return nil, wrapRPCQueryError(err, "GetTransactionByHash")
 }
 
-func (b *Bridge) GetBaseAccount(address string) (*QueryAccountResponse, error) {
-	if result, err := b.GRPCGetBaseAccount(address); err == nil {
-		return result, nil
-	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
-		return nil, err
-	}
-	var result *QueryAccountResponse
-	var err error
-	for _, url := range b.GatewayConfig.AllGatewayURLs {
-		restApi := joinURLPath(url, AccountInfo+address)
-		if err = client.RPCGet(&result, restApi); err == nil {
-			return result, nil
-		} else {
-			log.Warn("GetBaseAccount failed", "url", restApi, "err", err)
+func (b *Bridge) GetBaseAccount(address string) (res *QueryAccountResponse, err error) {
+	for _, transport := range b.getQueryTransports() {
+		if res, err = transport.GetBaseAccount(address); err == nil {
+			return res, nil
 		}
 	}
-	return nil, wrapRPCQueryError(err, "GetBaseAccount")
+	return nil, err
 }
 
 func (b *Bridge) GetDenomBalance(address, denom string) (sdk.Int, error) {
@@ -145,53 +132,20 @@ func (b *Bridge) GetDenomBalance(address, denom string) (sdk.Int, error) {
 	return sdk.ZeroInt(), wrapRPCQueryError(err, "GetDenomBalance")
 }
 
-func (b *Bridge) SimulateTx(simulateReq *SimulateRequest) (string, error) {
-	if result, err := b.GRPCSimulateTx(simulateReq); err == nil {
-		return common.ToJSONString(result.GasInfo, false), nil
-	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
-		return "", err
-	}
-	if data, err := json.Marshal(simulateReq); err != nil {
-		return "", err
-	} else {
-		for _, url := range b.GatewayConfig.AllGatewayURLs {
-			restApi := joinURLPath(url, SimulateTx)
-			if res, err := client.RPCRawPostWithTimeout(restApi, string(data), 120); err == nil && res != "" && res != "\n" {
-				return res, nil
-			}
+func (b *Bridge) SimulateTx(simulateReq *SimulateRequest) (res string, err error) {
+	for _, transport := range b.getQueryTransports() {
+		if res, err = transport.SimulateTx(simulateReq); err == nil {
+			return res, nil
 		}
-		return "", wrapRPCQueryError(err, "SimulateTx")
 	}
+	return "", err
 }
 
-func (b *Bridge) BroadcastTx(req *BroadcastTxRequest) (string, error) {
-	if result, err := b.GRPCBroadcastTx(req); err == nil {
-		data, _ := json.Marshal(BroadcastTxResponse{
-			TxResponse: &TxResponse{
-				Height: fmt.Sprintf("%d", result.Height),
-				TxHash: result.TxHash,
-				Code:   result.Code,
-				Logs:   result.Logs,
-			},
-		})
-		return string(data), nil
-	} else if len(b.GatewayConfig.AllGatewayURLs) == 0 {
-		return "", err
-	}
-	if data, err := json.Marshal(req); err != nil {
-		return "", err
-	} else {
-		var res string
-		var success bool
-		for _, url := range b.GatewayConfig.AllGatewayURLs {
-			restApi := joinURLPath(url, BroadTx)
-			if res, err = client.RPCJsonPostWithTimeout(restApi, string(data), 120); err == nil {
-				success = true
-			}
-		}
-		if success {
+func (b *Bridge) BroadcastTx(req *BroadcastTxRequest) (res string, err error) {
+	for _, transport := range b.getBroadcastTransports() {
+		if res, err = transport.BroadcastTx(req); err == nil {
 			return res, nil
 		}
-		return "", wrapRPCQueryError(err, "BroadcastTx")
 	}
+	return "", err
 }
@@ -0,0 +1,57 @@
+package cosmos
+
+import (
+	"math/big"
+
+	cmath "github.com/anyswap/CrossChain-Router/v3/common/math"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// swap value rounding modes, configed by `swapValueRounding` custom
+const (
+	// RoundingDown drop the remainder (default), it never over-deliver
+	RoundingDown = "down"
+	// RoundingHalfUp round half up the remainder
+	RoundingHalfUp = "halfUp"
+	// RoundingUp round up any non zero remainder
+	RoundingUp = "up"
+)
+
+// GetSwapValueRounding get the rounding mode used when scaling swap value to less decimals
+func (b *Bridge) GetSwapValueRounding() string {
+	mode := params.GetCustom(b.ChainConfig.ChainID, "swapValueRounding")
+	switch mode {
+	case RoundingDown, RoundingHalfUp, RoundingUp:
+		return mode
+	case "":
+	default:
+		log.Warn("unknown swap value rounding mode, use default", "chainID", b.ChainConfig.ChainID, "mode", mode, "default", RoundingDown)
+	}
+	return RoundingDown
+}
+
+// ConvertTokenValueWithRounding convert token value between decimals,
+// the remainder is rounded by the rounding mode when scaling to less decimals.
+func ConvertTokenValueWithRounding(fromValue *big.Int, fromDecimals, toDecimals uint8, mode string) *big.Int {
+	if fromDecimals == toDecimals || fromValue == nil {
+		return fromValue
+	}
+	if fromDecimals < toDecimals {
+		return new(big.Int).Mul(fromValue, cmath.BigPow(10, int64(toDecimals-fromDecimals)))
+	}
+	divisor := cmath.BigPow(10, int64(fromDecimals-toDecimals))
+	quotient, remainder := new(big.Int).QuoRem(fromValue, divisor, new(big.Int))
+	if remainder.Sign() <= 0 {
+		return quotient
+	}
+	switch mode {
+	case RoundingUp:
+		quotient.Add(quotient, big.NewInt(1))
+	case RoundingHalfUp:
+		if new(big.Int).Lsh(remainder, 1).Cmp(divisor) >= 0 {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+	return quotient
+}
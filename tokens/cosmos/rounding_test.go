@@ -0,0 +1,58 @@
+package cosmos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestConvertTokenValueWithRounding(t *testing.T) {
+	tests := []struct {
+		value        string
+		fromDecimals uint8
+		toDecimals   uint8
+		mode         string
+		want         string
+	}{
+		{"1000000500000000000", 18, 6, RoundingDown, "1000000"},
+		{"1000000500000000000", 18, 6, RoundingHalfUp, "1000001"},
+		{"1000000500000000000", 18, 6, RoundingUp, "1000001"},
+		{"1000000499999999999", 18, 6, RoundingDown, "1000000"},
+		{"1000000499999999999", 18, 6, RoundingHalfUp, "1000000"},
+		{"1000000499999999999", 18, 6, RoundingUp, "1000001"},
+		{"1000000000000000001", 18, 6, RoundingUp, "1000001"},
+		{"1000000000000000000", 18, 6, RoundingUp, "1000000"},
+		{"1000000000000000000", 18, 6, RoundingHalfUp, "1000000"},
+		{"999999999999", 18, 6, RoundingDown, "0"},
+		{"999999999999", 18, 6, RoundingHalfUp, "1"},
+		{"1000001", 6, 18, RoundingUp, "1000001000000000000"},
+		{"1000001", 6, 6, RoundingUp, "1000001"},
+	}
+	for _, test := range tests {
+		value, _ := new(big.Int).SetString(test.value, 10)
+		have := ConvertTokenValueWithRounding(value, test.fromDecimals, test.toDecimals, test.mode)
+		if have.String() != test.want {
+			t.Errorf("convert %v from %v to %v decimals with rounding %v mismatch, have %v want %v",
+				test.value, test.fromDecimals, test.toDecimals, test.mode, have, test.want)
+		}
+	}
+}
+
+func TestGetSwapValueRounding(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1"}
+
+	for custom, want := range map[string]string{
+		"":       RoundingDown,
+		"down":   RoundingDown,
+		"halfUp": RoundingHalfUp,
+		"up":     RoundingUp,
+		"ceil":   RoundingDown,
+	} {
+		setTestCustoms(t, "1", map[string]string{"swapValueRounding": custom})
+		if have := b.GetSwapValueRounding(); have != want {
+			t.Errorf("rounding mode of custom '%v' mismatch, have %v want %v", custom, have, want)
+		}
+	}
+}
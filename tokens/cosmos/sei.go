@@ -0,0 +1,71 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+const (
+	// SeiAddressByEVM sei evm module query of the linked sei address
+	SeiAddressByEVM = "/sei-protocol/seichain/evm/sei_address?evm_address="
+)
+
+var (
+	// ErrSeiEVMAddressNotLinked the sei evm address has no associated sei address on chain
+	ErrSeiEVMAddressNotLinked = errors.New("sei evm address is not linked to a sei address")
+)
+
+// QuerySeiAddressByEVMAddressResponse sei address of evm address
+type QuerySeiAddressByEVMAddressResponse struct {
+	SeiAddress string `json:"sei_address"`
+	Associated bool   `json:"associated"`
+}
+
+// IsSeiChain is sei chain
+func (b *Bridge) IsSeiChain() bool {
+	return b.ChainConfig != nil && IsSubChainOf("SEI", b.ChainConfig.ChainID)
+}
+
+// GetSeiAddressByEVMAddress get the sei address linked to the evm address
+func (b *Bridge) GetSeiAddressByEVMAddress(evmAddress string) (string, error) {
+	var result *QuerySeiAddressByEVMAddressResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, SeiAddressByEVM+evmAddress)
+		if err = client.RPCGet(&result, restApi); err == nil {
+			if result == nil || !result.Associated || result.SeiAddress == "" {
+				return "", fmt.Errorf("%w: %v", ErrSeiEVMAddressNotLinked, evmAddress)
+			}
+			return result.SeiAddress, nil
+		}
+		log.Warn("GetSeiAddressByEVMAddress failed", "url", restApi, "err", err)
+	}
+	return "", wrapRPCQueryError(err, "GetSeiAddressByEVMAddress", evmAddress)
+}
+
+// resolveReceiver resolve the bech32 receiver of the bind address.
+// on sei the bind address may be an evm address which should be
+// resolved to its linked sei address before paying out native denoms.
+func (b *Bridge) resolveReceiver(bind string) (string, error) {
+	if !b.IsSeiChain() || !isEVMAddress(bind) {
+		return bind, nil
+	}
+	receiver, err := b.GetSeiAddressByEVMAddress(bind)
+	if err != nil {
+		return "", err
+	}
+	if !IsValidAddress(b.Prefix, receiver) {
+		return "", fmt.Errorf("linked sei address %v of %v is invalid", receiver, bind)
+	}
+	log.Info("resolve sei evm address success", "evmAddress", bind, "seiAddress", receiver)
+	return receiver, nil
+}
+
+func isEVMAddress(address string) bool {
+	return strings.HasPrefix(address, "0x") && common.IsHexAddress(address)
+}
@@ -0,0 +1,108 @@
+package cosmos
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+const (
+	tLinkedEVMAddress   = "0x1111111111111111111111111111111111111111"
+	tUnlinkedEVMAddress = "0x2222222222222222222222222222222222222222"
+)
+
+func newTestSeiBridge(t *testing.T, handler http.HandlerFunc) *Bridge {
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	b := NewCrossChainBridge()
+	b.Prefix = "sei"
+	b.Denom = "usei"
+	b.ChainConfig = &tokens.ChainConfig{ChainID: GetStubChainID("SEI", testnetNetWork).String()}
+	b.GatewayConfig = &tokens.GatewayConfig{AllGatewayURLs: []string{srv.URL}}
+	return b
+}
+
+// setTestCustoms set the customs of the chain, they are reset when the test finishes
+func setTestCustoms(t *testing.T, chainID string, customs map[string]string) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		Customs: map[string]map[string]string{chainID: customs},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) })
+}
+
+func TestResolveSeiReceiver(t *testing.T) {
+	seiAddress, err := bech32.ConvertAndEncode("sei", make([]byte, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("evm_address") {
+		case tLinkedEVMAddress:
+			_, _ = w.Write([]byte(`{"sei_address":"` + seiAddress + `","associated":true}`))
+		default:
+			_, _ = w.Write([]byte(`{"sei_address":"","associated":false}`))
+		}
+	})
+
+	if !b.IsSeiChain() {
+		t.Fatal("bridge should be sei chain")
+	}
+	if !b.IsValidAddress(tLinkedEVMAddress) {
+		t.Fatal("evm address should be valid bind address on sei")
+	}
+	if b.isBech32Address(tLinkedEVMAddress) {
+		t.Error("evm address should not be valid bech32 address on sei")
+	}
+	if err = b.checkTokenKindFormat(TokenKindCW20, tLinkedEVMAddress); !errors.Is(err, ErrWrongTokenKind) {
+		t.Errorf("evm address should not be valid cw20 contract on sei, have %v", err)
+	}
+	b.ChainConfig.Extra = "sei:usei"
+	if err = b.InitRouterInfo(tLinkedEVMAddress, ""); err == nil || !strings.Contains(err.Error(), "wrong router mpc address") {
+		t.Errorf("evm address should not be valid router mpc on sei, have %v", err)
+	}
+
+	receiver, err := b.resolveReceiver(tLinkedEVMAddress)
+	if err != nil {
+		t.Fatalf("resolve linked address failed: %v", err)
+	}
+	if receiver != seiAddress {
+		t.Errorf("resolve linked address mismatch, have %v want %v", receiver, seiAddress)
+	}
+
+	_, err = b.resolveReceiver(tUnlinkedEVMAddress)
+	if !errors.Is(err, ErrSeiEVMAddressNotLinked) {
+		t.Errorf("resolve unlinked address should fail with %v, but have %v", ErrSeiEVMAddressNotLinked, err)
+	}
+
+	receiver, err = b.resolveReceiver(seiAddress)
+	if err != nil || receiver != seiAddress {
+		t.Errorf("bech32 receiver should be kept as is, have %v %v", receiver, err)
+	}
+}
+
+func TestNonSeiChainRejectsEVMAddress(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Errorf("unexpected query %v", r.URL)
+	})
+	b.Prefix = "osmo"
+	b.ChainConfig.ChainID = GetStubChainID("OSMOSIS", testnetNetWork).String()
+
+	if b.IsValidAddress(tLinkedEVMAddress) {
+		t.Error("evm address should be invalid on non sei chain")
+	}
+	receiver, err := b.resolveReceiver(tLinkedEVMAddress)
+	if err != nil || !strings.EqualFold(receiver, tLinkedEVMAddress) {
+		t.Errorf("non sei chain should not resolve receiver, have %v %v", receiver, err)
+	}
+}
@@ -1,36 +1,121 @@
 package cosmos
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
+	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 )
 
+const (
+	// CodeWrongSequence sdk error code of incorrect account sequence
+	CodeWrongSequence = 32
+)
+
+var (
+	// ErrTxHashMismatch the tx hash returned by the node differs from the locally computed one
+	ErrTxHashMismatch = errors.New("broadcast tx hash mismatch")
+)
+
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (string, error) {
 	if txBytes, ok := signedTx.([]byte); !ok {
 		return "", errors.New("wrong signed transaction type")
 	} else {
-		req := &BroadcastTxRequest{
-			TxBytes: string(txBytes),
-			Mode:    "BROADCAST_MODE_SYNC",
+		if err := b.checkMinGasPrice(txBytes); err != nil {
+			return "", err
+		}
+		return b.BroadcastSignedTx(txBytes, BroadcastModeSync)
+	}
+}
+
+// GetTxHash returns the tx hash (sha256 of the encoded tx bytes) of the base64 encoded signed tx
+func GetTxHash(signedTx []byte) (string, error) {
+	if txBytes, err := base64.StdEncoding.DecodeString(string(signedTx)); err != nil {
+		return "", err
+	} else {
+		return fmt.Sprintf("%X", Sha256Sum(txBytes)), nil
+	}
+}
+
+// BroadcastSignedTx broadcast the base64 encoded signed tx.
+// It returns the locally computed tx hash, and errors if the node returns a different one.
+func (b *Bridge) BroadcastSignedTx(signedTx []byte, mode string) (string, error) {
+	txHash, err := GetTxHash(signedTx)
+	if err != nil {
+		return "", err
+	}
+	req := &BroadcastTxRequest{
+		TxBytes: string(signedTx),
+		Mode:    mode,
+	}
+	// the prefetched sequence of the signer is consumed (or uncertain) once the tx is broadcasted
+	if signer, _, errf := b.getTxSigner(signedTx); errf == nil {
+		defer b.seqCache.invalidate(signer)
+	} else {
+		defer b.seqCache.invalidateAll()
+	}
+	var txRes string
+	for i := 0; i < retryRPCCount; i++ {
+		if txRes, err = b.BroadcastTx(req); err == nil {
+			break
+		}
+		// the tx may have landed even if broadcasting is timeout,
+		// check it before rebroadcasting to prevent double spending.
+		if b.isTxLanded(txHash) {
+			log.Info("broadcast tx failed but it has landed", "txHash", txHash, "err", err)
+			return txHash, nil
+		}
+		log.Warn("broadcast tx failed, retry later", "txHash", txHash, "times", i+1, "err", err)
+		time.Sleep(retryRPCInterval)
+	}
+	if err != nil {
+		return "", err
+	} else {
+		if txRes == "" {
+			return "", tokens.ErrBroadcastTx
 		}
-		if txRes, err := b.BroadcastTx(req); err != nil {
+		var txResponse *BroadcastTxResponse
+		if err := json.Unmarshal([]byte(txRes), &txResponse); err != nil {
 			return "", err
-		} else {
-			if txRes == "" {
-				return "", tokens.ErrBroadcastTx
-			}
-			var txResponse *BroadcastTxResponse
-			if err := json.Unmarshal([]byte(txRes), &txResponse); err != nil {
+		}
+		if txResponse == nil || txResponse.TxResponse == nil {
+			return "", tokens.ErrBroadcastTx
+		}
+		// a landed tx is reported as incorrect account sequence when it's broadcasted again
+		if txResponse.TxResponse.Code == CodeWrongSequence && b.isTxLanded(txHash) {
+			log.Info("broadcast tx with incorrect sequence but it has landed", "txHash", txHash)
+			return txHash, nil
+		}
+		if txResponse.TxResponse.Code == CodeInsufficientFunds {
+			if err := b.DiagnoseInsufficientFunds(signedTx); err != nil {
 				return "", err
 			}
-			if txResponse.TxResponse.Code != 0 && txResponse.TxResponse.Code != 19 {
-				return "", fmt.Errorf("SendTransaction error, code: %v", txResponse.TxResponse.Code)
-			}
-			return txResponse.TxResponse.TxHash, nil
 		}
+		if txResponse.TxResponse.Code == CodeUnauthorized {
+			return "", b.DiagnoseSignatureVerifyFailed(signedTx)
+		}
+		if txResponse.TxResponse.Code != 0 && txResponse.TxResponse.Code != 19 {
+			return "", fmt.Errorf("SendTransaction error, code: %v", txResponse.TxResponse.Code)
+		}
+		if nodeTxHash := txResponse.TxResponse.TxHash; nodeTxHash != "" && !strings.EqualFold(nodeTxHash, txHash) {
+			log.Error("broadcast tx hash mismatch", "local", txHash, "node", nodeTxHash)
+			return "", fmt.Errorf("%w, local: %v, node: %v", ErrTxHashMismatch, txHash, nodeTxHash)
+		}
+		return txHash, nil
+	}
+}
+
+// isTxLanded check whether the tx of txHash is already on chain
+func (b *Bridge) isTxLanded(txHash string) bool {
+	txr, err := b.GetTransactionByHash(txHash)
+	if err != nil || txr == nil || txr.TxResponse == nil {
+		return false
 	}
+	return strings.EqualFold(txr.TxResponse.TxHash, txHash)
 }
@@ -0,0 +1,146 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	tmtypes "github.com/tendermint/tendermint/types"
+)
+
+func buildTestSignedTx(t *testing.T, b *Bridge) (signedTx []byte, txBytes []byte) {
+	txBuilder := b.TxConfig.NewTxBuilder()
+	msg := BuildSendMsg("sei1sender", "sei1receiver", "usei", big.NewInt(1000))
+	if err := txBuilder.SetMsgs(msg); err != nil {
+		t.Fatal(err)
+	}
+	txBuilder.SetMemo("0x1111111111111111111111111111111111111111:1")
+	txBuilder.SetGasLimit(DefaultGasLimit)
+
+	txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	signedTx, _, err = b.GetSignTx(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return signedTx, txBytes
+}
+
+func TestGetTxHash(t *testing.T) {
+	b := NewCrossChainBridge()
+	signedTx, txBytes := buildTestSignedTx(t, b)
+
+	txHash, err := GetTxHash(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+	if txHash != want {
+		t.Errorf("local tx hash mismatch, have %v want %v", txHash, want)
+	}
+}
+
+func TestBroadcastSignedTx(t *testing.T) {
+	var nodeTxHash string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"tx_response":{"height":"0","txhash":"` + nodeTxHash + `","code":0}}`))
+	})
+	signedTx, txBytes := buildTestSignedTx(t, b)
+	want := fmt.Sprintf("%X", tmtypes.Tx(txBytes).Hash())
+
+	nodeTxHash = strings.ToLower(want)
+	txHash, err := b.SendTransaction(signedTx)
+	if err != nil {
+		t.Fatalf("send transaction failed: %v", err)
+	}
+	if txHash != want {
+		t.Errorf("send transaction hash mismatch, have %v want %v", txHash, want)
+	}
+
+	nodeTxHash = strings.Repeat("AB", 32)
+	if _, err = b.BroadcastSignedTx(signedTx, "BROADCAST_MODE_ASYNC"); !errors.Is(err, ErrTxHashMismatch) {
+		t.Errorf("broadcast should fail with %v, but have %v", ErrTxHashMismatch, err)
+	}
+}
+
+func TestBroadcastTimeoutThenFound(t *testing.T) {
+	defer func(interval time.Duration) { retryRPCInterval = interval }(retryRPCInterval)
+	retryRPCInterval = time.Millisecond
+
+	var landedTxHash string
+	var broadcastCount int
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == BroadTx:
+			broadcastCount++
+			w.WriteHeader(http.StatusGatewayTimeout)
+		case r.Method == http.MethodGet && strings.HasPrefix(r.URL.Path, TxByHash):
+			txHash := strings.TrimPrefix(r.URL.Path, TxByHash)
+			if landedTxHash == "" || txHash != landedTxHash {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = w.Write([]byte(`{"tx":{"body":{"memo":""}},"tx_response":{"height":"100","txhash":"` + txHash + `","code":0}}`))
+		default:
+			t.Errorf("unexpected request %v %v", r.Method, r.URL)
+		}
+	})
+	signedTx, _ := buildTestSignedTx(t, b)
+	want, err := GetTxHash(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// not landed, retry and fail finally
+	if _, err = b.SendTransaction(signedTx); err == nil {
+		t.Error("broadcast timeout of not landed tx should fail")
+	}
+	if broadcastCount != retryRPCCount {
+		t.Errorf("broadcast should retry %v times, but have %v", retryRPCCount, broadcastCount)
+	}
+
+	// landed, do not rebroadcast
+	broadcastCount = 0
+	landedTxHash = want
+	txHash, err := b.SendTransaction(signedTx)
+	if err != nil {
+		t.Fatalf("broadcast timeout of landed tx should succeed, but have %v", err)
+	}
+	if txHash != want {
+		t.Errorf("send transaction hash mismatch, have %v want %v", txHash, want)
+	}
+	if broadcastCount != 1 {
+		t.Errorf("landed tx should not be rebroadcasted, but broadcast %v times", broadcastCount)
+	}
+}
+
+func TestBroadcastWrongSequenceOfLandedTx(t *testing.T) {
+	var landed bool
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			_, _ = w.Write([]byte(`{"tx_response":{"height":"0","txhash":"","code":32}}`))
+			return
+		}
+		if !landed {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		txHash := strings.TrimPrefix(r.URL.Path, TxByHash)
+		_, _ = w.Write([]byte(`{"tx_response":{"height":"100","txhash":"` + txHash + `","code":0}}`))
+	})
+	signedTx, _ := buildTestSignedTx(t, b)
+
+	if _, err := b.SendTransaction(signedTx); err == nil {
+		t.Error("incorrect sequence of not landed tx should fail")
+	}
+	landed = true
+	if _, err := b.SendTransaction(signedTx); err != nil {
+		t.Errorf("incorrect sequence of landed tx should succeed, but have %v", err)
+	}
+}
@@ -0,0 +1,167 @@
+package cosmos
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// accountSeqEntry the prefetched account data
+type accountSeqEntry struct {
+	accountNumber uint64
+	sequence      uint64
+	seqConsumed   bool // the sequence is consumed by a broadcasted tx
+	fetchedAt     time.Time
+}
+
+// accountSeqCache caches the sequences and account numbers of the mpc accounts prefetched in batch,
+// so that the first payout does not pay the latency of a cold account query.
+// The sequence of the account is invalidated once its tx is broadcasted, as the sequence is consumed,
+// while the account number is kept as it never changes once the account is created.
+type accountSeqCache struct {
+	mu       sync.Mutex
+	accounts []string
+	entries  map[string]*accountSeqEntry
+	ttl      time.Duration
+
+	startOnce sync.Once
+}
+
+func newAccountSeqCache() *accountSeqCache {
+	return &accountSeqCache{entries: make(map[string]*accountSeqEntry)}
+}
+
+// track add the account to the prefetch batch
+func (c *accountSeqCache) track(account string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, acc := range c.accounts {
+		if acc == account {
+			return
+		}
+	}
+	c.accounts = append(c.accounts, account)
+}
+
+func (c *accountSeqCache) tracked() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.accounts...)
+}
+
+func (c *accountSeqCache) set(account string, accountNumber, sequence uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[account] = &accountSeqEntry{accountNumber: accountNumber, sequence: sequence, fetchedAt: time.Now()}
+}
+
+func (c *accountSeqCache) setTTL(ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttl = ttl
+}
+
+// get returns the prefetched sequence if it is not invalidated or expired
+func (c *accountSeqCache) get(account string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, exist := c.entries[account]
+	if !exist || entry.seqConsumed || (c.ttl > 0 && time.Since(entry.fetchedAt) > c.ttl) {
+		return 0, false
+	}
+	return entry.sequence, true
+}
+
+// getAccountNumber returns the prefetched account number
+func (c *accountSeqCache) getAccountNumber(account string) (uint64, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, exist := c.entries[account]
+	if !exist {
+		return 0, false
+	}
+	return entry.accountNumber, true
+}
+
+// invalidate drops the prefetched sequence of the account
+func (c *accountSeqCache) invalidate(account string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, exist := c.entries[account]; exist {
+		entry.seqConsumed = true
+	}
+}
+
+// invalidateAll drops the prefetched sequences of all the accounts
+func (c *accountSeqCache) invalidateAll() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, entry := range c.entries {
+		entry.seqConsumed = true
+	}
+}
+
+// getSeqPrefetchInterval get the interval of prefetching the mpc accounts,
+// which is configed by `seqPrefetchInterval` custom (in seconds, prefetch is disabled if not set)
+func (b *Bridge) getSeqPrefetchInterval() time.Duration {
+	intervalStr := params.GetCustom(b.ChainConfig.ChainID, "seqPrefetchInterval")
+	if intervalStr == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseUint(intervalStr, 10, 64)
+	if err != nil {
+		log.Warn("wrong seqPrefetchInterval custom", "chainID", b.ChainConfig.ChainID, "value", intervalStr)
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// PrefetchAccounts query and cache the sequences and account numbers of the mpc accounts in batch
+func (b *Bridge) PrefetchAccounts() {
+	accounts := b.seqCache.tracked()
+	var fetched int
+	for _, account := range accounts {
+		acc, err := b.getFreshAccount(account)
+		if err != nil {
+			log.Warn("prefetch account failed", "chainID", b.ChainConfig.ChainID, "account", account, "err", err)
+			continue
+		}
+		b.seqCache.set(account, acc.accountNumber, acc.sequence)
+		fetched++
+	}
+	log.Debug("prefetch accounts finished", "chainID", b.ChainConfig.ChainID, "accounts", len(accounts), "fetched", fetched)
+}
+
+// startSeqPrefetch track the mpc account, and start prefetching the tracked accounts
+// on startup and periodically if `seqPrefetchInterval` custom is set
+func (b *Bridge) startSeqPrefetch(mpc string) {
+	interval := b.getSeqPrefetchInterval()
+	if interval == 0 {
+		return
+	}
+	b.seqCache.track(mpc)
+	b.seqCache.startOnce.Do(func() {
+		// a missed refresh should not leave a stale sequence
+		b.seqCache.setTTL(2 * interval)
+		log.Info("start prefetching accounts", "chainID", b.ChainConfig.ChainID, "interval", interval)
+		go func() {
+			for {
+				b.PrefetchAccounts()
+				time.Sleep(interval)
+			}
+		}()
+	})
+}
+
+// getPrefetchedSeq get the prefetched sequence of the account
+func (b *Bridge) getPrefetchedSeq(account string) (uint64, bool) {
+	return b.seqCache.get(account)
+}
+
+// getPrefetchedAccountNumber get the prefetched account number of the account
+func (b *Bridge) getPrefetchedAccountNumber(account string) (uint64, bool) {
+	return b.seqCache.getAccountNumber(account)
+}
@@ -0,0 +1,102 @@
+package cosmos
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"net/http"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func TestPrefetchAccounts(t *testing.T) {
+	privKeyBytes, _ := hex.DecodeString(tSignerPrivKey)
+	pubKey := (&secp256k1.PrivKey{Key: privKeyBytes}).PubKey()
+	mpc, err := bech32.ConvertAndEncode("sei", pubKey.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	other := "sei1other"
+	var accountQueries int
+	var sequence uint64 = 7
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + mpc:
+			accountQueries++
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"account":{"address":"%v","account_number":"12","sequence":"%v"}}`, mpc, sequence)))
+		case AccountInfo + other:
+			_, _ = w.Write([]byte(fmt.Sprintf(`{"account":{"address":"%v","account_number":"13","sequence":"3"}}`, other)))
+		case BroadTx:
+			_, _ = w.Write([]byte(`{"tx_response":{"height":"0","txhash":"","code":0}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"seqPrefetchInterval": "60"})
+
+	if interval := b.getSeqPrefetchInterval(); interval.Seconds() != 60 {
+		t.Fatalf("prefetch interval mismatch, have %v", interval)
+	}
+	if _, ok := b.getPrefetchedSeq(mpc); ok {
+		t.Fatal("sequence should not be cached before prefetching")
+	}
+
+	b.seqCache.track(mpc)
+	b.seqCache.track(mpc)
+	b.seqCache.track(other)
+	b.PrefetchAccounts()
+	if accountQueries != 1 {
+		t.Errorf("tracked account should be queried once, but queried %v times", accountQueries)
+	}
+	if seq, ok := b.getPrefetchedSeq(mpc); !ok || seq != 7 {
+		t.Errorf("prefetched sequence mismatch, have %v %v", seq, ok)
+	}
+	if accountNumber, ok := b.getPrefetchedAccountNumber(mpc); !ok || accountNumber != 12 {
+		t.Errorf("prefetched account number mismatch, have %v %v", accountNumber, ok)
+	}
+
+	// GetSeq uses the prefetched sequence without querying
+	sequence = 8
+	args := &tokens.BuildTxArgs{From: mpc}
+	nonce, err := b.GetSeq(args)
+	if err != nil || *nonce != 7 {
+		t.Errorf("get seq should use the prefetched sequence, have %v %v", nonce, err)
+	}
+	if accountQueries != 1 {
+		t.Errorf("get seq should not query the account, but queried %v times", accountQueries)
+	}
+
+	// broadcasting invalidates the prefetched sequence of the signer
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err = txBuilder.SetMsgs(BuildSendMsg(mpc, "sei1receiver", "usei", big.NewInt(1000))); err != nil {
+		t.Fatal(err)
+	}
+	if err = txBuilder.SetSignatures(BuildSignatures(pubKey, 7, []byte{1})); err != nil {
+		t.Fatal(err)
+	}
+	signedTx, _, err := b.GetSignTx(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = b.BroadcastSignedTx(signedTx, BroadcastModeSync); err != nil {
+		t.Fatalf("broadcast tx failed: %v", err)
+	}
+	if _, ok := b.getPrefetchedSeq(mpc); ok {
+		t.Error("prefetched sequence should be invalidated after broadcasting")
+	}
+	if seq, ok := b.getPrefetchedSeq(other); !ok || seq != 3 {
+		t.Errorf("prefetched sequence of other account should be kept, have %v %v", seq, ok)
+	}
+	if accountNumber, err := b.GetAccountNum(mpc); err != nil || accountNumber != 12 {
+		t.Errorf("prefetched account number should be kept after broadcasting, have %v %v", accountNumber, err)
+	}
+	if nonce, err = b.GetSeq(args); err != nil || *nonce != 8 {
+		t.Errorf("get seq should query the account after invalidation, have %v %v", nonce, err)
+	}
+	if accountQueries != 2 {
+		t.Errorf("get seq should query the account after invalidation, but queried %v times", accountQueries)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -12,7 +13,19 @@ import (
 	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tools/crypto"
+	"github.com/btcsuite/btcd/btcec"
 	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	cryptoTypes "github.com/cosmos/cosmos-sdk/crypto/types"
+)
+
+var (
+	// ErrWrongSignatureLength signature is neither rsv (65 bytes) nor rs (64 bytes)
+	ErrWrongSignatureLength = errors.New("wrong signature length")
+	// ErrWrongSignatureValue r or s of signature is out of range
+	ErrWrongSignatureValue = errors.New("wrong signature value")
+
+	secp256k1N     = btcec.S256().N
+	secp256k1HalfN = new(big.Int).Rsh(secp256k1N, 1)
 )
 
 // MPCSignTransaction mpc sign raw tx
@@ -23,14 +36,14 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		mpcParams := params.GetMPCConfig(b.UseFastMPC)
 		if mpcParams.SignWithPrivateKey {
 			priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
-			return b.SignTransactionWithPrivateKey(buildRawTx, priKey)
+			return b.signTransactionWithPrivateKey(buildRawTx, priKey, args.SwapID)
 		}
 
 		mpcPubkey := router.GetMPCPublicKey(args.From)
 		if mpcPubkey == "" {
 			return nil, "", tokens.ErrMissMPCPublicKey
 		}
-		pubKey, err := PubKeyFromStr(mpcPubkey)
+		pubKey, err := b.PubKeyFromStr(mpcPubkey)
 		if err != nil {
 			return nil, txHash, err
 		}
@@ -42,10 +55,9 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 
 			txid := args.SwapID
 			logPrefix := b.ChainConfig.BlockChain + " MPCSignTransaction "
-			log.Info(logPrefix+"start", "txid", txid)
-
 			mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
-			msgHash := fmt.Sprintf("%X", Sha256Sum(signBytes))
+			msgHash := fmt.Sprintf("%X", getSignHash(pubKey, signBytes))
+			log.Info(logPrefix+"start", "txid", txid, "signHash", msgHash)
 			if keyID, rsvs, err := mpcConfig.DoSignOneEC(mpcPubkey, msgHash, msgContext); err != nil {
 				return nil, "", err
 			} else {
@@ -57,73 +69,103 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 
 				rsv := rsvs[0]
 				log.Trace(logPrefix+"get rsv signature success", "keyID", keyID, "txid", txid, "rsv", rsv)
-				signature := common.FromHex(rsv)
-
-				if len(signature) == crypto.SignatureLength {
-					signature = signature[:crypto.SignatureLength-1]
-				}
-
-				if len(signature) != crypto.SignatureLength-1 {
-					log.Error("wrong signature length", "keyID", keyID, "txid", txid, "have", len(signature), "want", crypto.SignatureLength)
-					return nil, "", errors.New("wrong signature length")
-				}
-
-				if !pubKey.VerifySignature(signBytes, signature) {
-					log.Error("verify signature failed", "signBytes", common.ToHex(signBytes), "signature", signature)
-					return nil, "", errors.New("wrong signature")
-				}
-				sequence := buildRawTx.Sequence
-				sig := BuildSignatures(pubKey, sequence, signature)
-				txBuilder := buildRawTx.TxBuilder
-				if err := txBuilder.SetSignatures(sig); err != nil {
-					return nil, "", err
-				}
-
-				return b.GetSignTx(txBuilder.GetTx())
+				return b.assembleSignedTx(buildRawTx, pubKey, signBytes, common.FromHex(rsv), txid)
 			}
 		}
 	}
 }
 
-// SignTransactionWithPrivateKey sign tx with ECDSA private key
+// SignTransactionWithPrivateKey sign tx with ECDSA private key (for testing).
+// The signed tx has the same envelope as the mpc signed one.
 func (b *Bridge) SignTransactionWithPrivateKey(buildRawTx *BuildRawTx, privKey string) (signedTx interface{}, txHash string, err error) {
-	if ecPrikey, err := crypto.HexToECDSA(privKey); err != nil {
+	return b.signTransactionWithPrivateKey(buildRawTx, privKey, "")
+}
+
+func (b *Bridge) signTransactionWithPrivateKey(buildRawTx *BuildRawTx, privKey, swapID string) (signedTx interface{}, txHash string, err error) {
+	ecPrikey, err := crypto.HexToECDSA(privKey)
+	if err != nil {
+		return nil, "", err
+	}
+	// the private key must be 32 bytes even if it has leading zeros
+	ecPriv := &secp256k1.PrivKey{Key: crypto.FromECDSA(ecPrikey)}
+
+	signBytes, err := b.GetSignBytes(buildRawTx)
+	if err != nil {
+		return nil, "", err
+	}
+	pubKey, err := ConvertPubKeyType(ecPriv.PubKey(), b.GetPubKeyType())
+	if err != nil {
 		return nil, "", err
+	}
+	var signature []byte
+	if pubKey.Type() == PubKeyTypeEthSecp256k1 {
+		signature, err = crypto.Sign(getSignHash(pubKey, signBytes), ecPrikey)
 	} else {
-		ecPriv := &secp256k1.PrivKey{Key: ecPrikey.D.Bytes()}
+		signature, err = ecPriv.Sign(signBytes)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	return b.assembleSignedTx(buildRawTx, pubKey, signBytes, signature, swapID)
+}
 
-		if signBytes, err := b.GetSignBytes(buildRawTx); err != nil {
-			return nil, "", err
-		} else {
-			if signature, err := ecPriv.Sign(signBytes); err != nil {
-				return nil, "", err
-			} else {
-				if len(signature) == crypto.SignatureLength {
-					signature = signature[:crypto.SignatureLength-1]
-				}
+// assembleSignedTx verify the signature (see RsvToCosmosSignature)
+// and set it with the public key into the tx.
+// The sign hash and the tx hash are logged with the swapID to debug signature mismatches.
+func (b *Bridge) assembleSignedTx(buildRawTx *BuildRawTx, pubKey cryptoTypes.PubKey, signBytes, signature []byte, swapID string) (signedTx interface{}, txHash string, err error) {
+	rs, err := RsvToCosmosSignature(signature)
+	if err != nil {
+		log.Error("convert signature failed", "signature", common.ToHex(signature), "err", err)
+		return nil, "", err
+	}
+	signature = rs
 
-				if len(signature) != crypto.SignatureLength-1 {
-					log.Error("wrong length of signature", "length", len(signature))
-					return nil, "", errors.New("wrong signature length")
-				}
+	signHash := fmt.Sprintf("%X", getSignHash(pubKey, signBytes))
+	if !pubKey.VerifySignature(signBytes, signature) {
+		log.Error("verify signature failed", "txid", swapID, "signHash", signHash,
+			"signBytes", common.ToHex(signBytes), "signature", common.ToHex(signature))
+		return nil, "", errors.New("wrong signature")
+	}
+	sequence := buildRawTx.Sequence
+	sig := BuildSignatures(pubKey, sequence, signature)
+	txBuilder := buildRawTx.TxBuilder
+	if err := txBuilder.SetSignatures(sig); err != nil {
+		return nil, "", err
+	}
+	if err := txBuilder.GetTx().ValidateBasic(); err != nil {
+		return nil, "", err
+	}
 
-				pubKey := ecPriv.PubKey()
-				if !pubKey.VerifySignature(signBytes, signature) {
-					log.Error("verify signature failed", "signBytes", common.ToHex(signBytes), "signature", signature)
-					return nil, "", errors.New("wrong signature")
-				}
-				sequence := buildRawTx.Sequence
-				sig := BuildSignatures(pubKey, sequence, signature)
-				txBuilder := buildRawTx.TxBuilder
-				if err := txBuilder.SetSignatures(sig); err != nil {
-					return nil, "", err
-				}
-				if err := txBuilder.GetTx().ValidateBasic(); err != nil {
-					return nil, "", err
-				}
+	signedTx, txHash, err = b.GetSignTx(txBuilder.GetTx())
+	if err != nil {
+		return nil, "", err
+	}
+	log.Info(b.ChainConfig.BlockChain+" sign tx success", "txid", swapID, "signHash", signHash,
+		"txHash", txHash, "sequence", sequence, "pubKey", common.ToHex(pubKey.Bytes()))
+	log.Debug(b.ChainConfig.BlockChain+" sign tx sign bytes", "txid", swapID, "signBytes", common.ToHex(signBytes))
+	return signedTx, txHash, nil
+}
 
-				return b.GetSignTx(txBuilder.GetTx())
-			}
-		}
+// RsvToCosmosSignature convert the signature returned by mpc (R || S || V)
+// into the 64 bytes cosmos secp256k1 signature (R || S).
+// The recovery byte V is stripped as cosmos does not use it, and a high S
+// is normalized to N - S, as cosmos only accepts the low S canonical form.
+func RsvToCosmosSignature(rsv []byte) ([]byte, error) {
+	switch len(rsv) {
+	case crypto.SignatureLength, crypto.SignatureLength - 1:
+	default:
+		return nil, fmt.Errorf("%w, have %v, want %v or %v", ErrWrongSignatureLength, len(rsv), crypto.SignatureLength, crypto.SignatureLength-1)
+	}
+	r := new(big.Int).SetBytes(rsv[:32])
+	s := new(big.Int).SetBytes(rsv[32:64])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, ErrWrongSignatureValue
+	}
+	if s.Cmp(secp256k1HalfN) > 0 {
+		s.Sub(secp256k1N, s)
 	}
+	sig := make([]byte, 64)
+	r.FillBytes(sig[:32])
+	s.FillBytes(sig[32:])
+	return sig, nil
 }
@@ -0,0 +1,157 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	signingTypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/signing"
+	"github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
+)
+
+func TestSignTransactionWithPrivateKey(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != LatestBlock {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"` + tSeiChainID + `"}}}`))
+	})
+
+	// private key with leading zero byte
+	privKeyHex := "00" + tSignerPrivKey[2:]
+	privKeyBytes, _ := hex.DecodeString(privKeyHex)
+	privKey := &secp256k1.PrivKey{Key: privKeyBytes}
+
+	newRawTx := func() *BuildRawTx {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(BuildSendMsg(tMPCAddress, tMPCAddress, "usei", big.NewInt(1000))); err != nil {
+			t.Fatal(err)
+		}
+		txBuilder.SetGasLimit(DefaultGasLimit)
+		return &BuildRawTx{TxBuilder: txBuilder, AccountNumber: 9, Sequence: 3}
+	}
+
+	rawTx := newRawTx()
+	signedTx, txHash, err := b.SignTransactionWithPrivateKey(rawTx, privKeyHex)
+	if err != nil {
+		t.Fatalf("sign tx with private key failed: %v", err)
+	}
+	txBytes, _ := base64.StdEncoding.DecodeString(string(signedTx.([]byte)))
+	tx, err := b.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatalf("decode signed tx failed: %v", err)
+	}
+	sigs, _ := tx.(signing.SigVerifiableTx).GetSignaturesV2()
+	if len(sigs) != 1 || !sigs[0].PubKey.Equals(privKey.PubKey()) || sigs[0].Sequence != 3 {
+		t.Fatalf("signature of signed tx mismatch, have %+v", sigs)
+	}
+	signBytes, _ := b.GetSignBytes(newRawTx())
+	signature := sigs[0].Data.(*signingTypes.SingleSignatureData).Signature
+	if !sigs[0].PubKey.VerifySignature(signBytes, signature) {
+		t.Errorf("verify signature of signed tx failed")
+	}
+
+	// mpc returns the signature with a recovery id appended
+	mpcSignature := append(append([]byte{}, signature...), 1)
+	mpcSignedTx, mpcTxHash, err := b.assembleSignedTx(newRawTx(), privKey.PubKey(), signBytes, mpcSignature, "")
+	if err != nil {
+		t.Fatalf("assemble mpc signed tx failed: %v", err)
+	}
+	if !bytes.Equal(mpcSignedTx.([]byte), signedTx.([]byte)) || mpcTxHash != txHash {
+		t.Errorf("tx signed with private key should be the same as the mpc signed one")
+	}
+}
+
+func TestRsvToCosmosSignature(t *testing.T) {
+	privKey := secp256k1.GenPrivKey()
+	signBytes := []byte("cosmos sign bytes")
+	signature, err := privKey.Sign(signBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// low S signature with or without recovery byte
+	for _, rsv := range [][]byte{signature, append(append([]byte{}, signature...), 1)} {
+		sig, err := RsvToCosmosSignature(rsv)
+		if err != nil {
+			t.Fatalf("convert rsv of length %v failed: %v", len(rsv), err)
+		}
+		if !bytes.Equal(sig, signature) {
+			t.Errorf("low S signature should be kept, have %x want %x", sig, signature)
+		}
+	}
+
+	// high S signature is normalized to low S
+	s := new(big.Int).SetBytes(signature[32:])
+	highS := new(big.Int).Sub(secp256k1N, s)
+	highSig := append(append([]byte{}, signature[:32]...), highS.FillBytes(make([]byte, 32))...)
+	if privKey.PubKey().VerifySignature(signBytes, highSig) {
+		t.Fatalf("high S signature should be rejected by cosmos")
+	}
+	sig, err := RsvToCosmosSignature(append(highSig, 0))
+	if err != nil {
+		t.Fatalf("convert high S rsv failed: %v", err)
+	}
+	if !bytes.Equal(sig, signature) || !privKey.PubKey().VerifySignature(signBytes, sig) {
+		t.Errorf("high S signature should be normalized, have %x want %x", sig, signature)
+	}
+
+	// malformed signatures
+	for _, rsv := range [][]byte{nil, signature[:63], append(append([]byte{}, signature...), 1, 2)} {
+		if _, err := RsvToCosmosSignature(rsv); !errors.Is(err, ErrWrongSignatureLength) {
+			t.Errorf("rsv of length %v should fail with %v, but have %v", len(rsv), ErrWrongSignatureLength, err)
+		}
+	}
+	zeroR := append(make([]byte, 32), signature[32:]...)
+	overflowS := append(append([]byte{}, signature[:32]...), secp256k1N.Bytes()...)
+	for _, rsv := range [][]byte{zeroR, overflowS} {
+		if _, err := RsvToCosmosSignature(rsv); !errors.Is(err, ErrWrongSignatureValue) {
+			t.Errorf("rsv %x should fail with %v, but have %v", rsv, ErrWrongSignatureValue, err)
+		}
+	}
+}
+
+func TestSignTransactionLogFields(t *testing.T) {
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"` + tSeiChainID + `"}}}`))
+	})
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err := txBuilder.SetMsgs(BuildSendMsg(tMPCAddress, tMPCAddress, "usei", big.NewInt(1000))); err != nil {
+		t.Fatal(err)
+	}
+	rawTx := &BuildRawTx{TxBuilder: txBuilder, AccountNumber: 9, Sequence: 3}
+	signBytes, err := b.GetSignBytes(rawTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hook := logtest.NewGlobal()
+	t.Cleanup(func() { logrus.StandardLogger().ReplaceHooks(make(logrus.LevelHooks)) })
+
+	_, txHash, err := b.signTransactionWithPrivateKey(rawTx, tSignerPrivKey, "swap-1")
+	if err != nil {
+		t.Fatalf("sign tx failed: %v", err)
+	}
+	var entry *logrus.Entry
+	for _, e := range hook.AllEntries() {
+		if strings.HasSuffix(e.Message, "sign tx success") {
+			entry = e
+		}
+	}
+	if entry == nil {
+		t.Fatal("sign tx success is not logged")
+	}
+	wantSignHash := strings.ToUpper(hex.EncodeToString(Sha256Sum(signBytes)))
+	if entry.Data["txid"] != "swap-1" || entry.Data["signHash"] != wantSignHash || entry.Data["txHash"] != txHash {
+		t.Errorf("sign tx log fields mismatch, have %v, want signHash %v txHash %v", entry.Data, wantSignHash, txHash)
+	}
+}
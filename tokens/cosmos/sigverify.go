@@ -0,0 +1,153 @@
+package cosmos
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	signingTypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+const (
+	// CodeUnauthorized sdk error code of unauthorized (eg. signature verification failed)
+	CodeUnauthorized = 4
+)
+
+var (
+	ErrSignatureVerifyFailed = errors.New("signature verification failed")
+)
+
+// freshAccount the account data queried from chain
+type freshAccount struct {
+	accountNumber uint64
+	sequence      uint64
+	pubKey        *AccountPubKey
+}
+
+// getFreshAccount query account data from chain and refresh the cached account number
+func (b *Bridge) getFreshAccount(account string) (*freshAccount, error) {
+	res, err := b.GetBaseAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if res == nil || res.Account == nil {
+		return nil, fmt.Errorf("account %v not found", account)
+	}
+	accountNumber, err := strconv.ParseUint(res.Account.AccountNumber, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	sequence, err := strconv.ParseUint(res.Account.Sequence, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	setCachedAccountNumber(account, accountNumber)
+	return &freshAccount{
+		accountNumber: accountNumber,
+		sequence:      sequence,
+		pubKey:        res.Account.PubKey,
+	}, nil
+}
+
+// DiagnoseSignatureVerifyFailed refresh the account data of the signer whose tx is
+// rejected for signature verification failure, and distinguish whether the rejection
+// is caused by stale account data (which can be fixed by rebuilding the tx)
+// or by a sign mode or public key mismatch (which needs an operator to check the config).
+func (b *Bridge) DiagnoseSignatureVerifyFailed(signedTx []byte) error {
+	signer, sig, err := b.getTxSigner(signedTx)
+	if err != nil {
+		return err
+	}
+
+	cachedNumber := getCachedAccountNumber(signer)
+	acc, err := b.getFreshAccount(signer)
+	if err != nil {
+		return err
+	}
+	log.Warn("signature verification failed", "signer", signer,
+		"cachedAccountNumber", cachedNumber, "accountNumber", acc.accountNumber,
+		"txSequence", sig.Sequence, "sequence", acc.sequence)
+
+	switch {
+	case cachedNumber != 0 && cachedNumber != acc.accountNumber:
+		return fmt.Errorf("%w, signature verification failed, signer: %v, account number: %v, on chain: %v", tokens.ErrStaleAccountData, signer, cachedNumber, acc.accountNumber)
+	case sig.Sequence < acc.sequence:
+		return fmt.Errorf("%w, signature verification failed, signer: %v, sequence: %v, on chain: %v", tokens.ErrStaleAccountData, signer, sig.Sequence, acc.sequence)
+	case acc.pubKey != nil && len(acc.pubKey.Key) > 0 && !bytes.Equal(acc.pubKey.Key, sig.PubKey.Bytes()):
+		return fmt.Errorf("%w, likely public key mismatch, signer: %v, key: %X, on chain: %X", ErrSignatureVerifyFailed, signer, sig.PubKey.Bytes(), acc.pubKey.Key)
+	default:
+		signMode := "unknown"
+		if data, ok := sig.Data.(*signingTypes.SingleSignatureData); ok {
+			signMode = data.SignMode.String()
+		}
+		return fmt.Errorf("%w, account data is up to date, likely sign mode or chain id mismatch, signer: %v, sign mode: %v", ErrSignatureVerifyFailed, signer, signMode)
+	}
+}
+
+// RefreshRawTxAccount refresh the account number and sequence of the raw tx from chain,
+// so that the tx rejected with stale account data (see DiagnoseSignatureVerifyFailed) can be signed again.
+// The fresh account data is written into the build args, so that the accept nodes rebuild the same tx.
+func (b *Bridge) RefreshRawTxAccount(rawTx interface{}, args *tokens.BuildTxArgs) error {
+	buildRawTx, ok := rawTx.(*BuildRawTx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	acc, err := b.getFreshAccount(args.From)
+	if err != nil {
+		return err
+	}
+	buildRawTx.AccountNumber = acc.accountNumber
+	if acc.sequence > buildRawTx.Sequence {
+		buildRawTx.Sequence = acc.sequence
+	}
+	// the signer info of the auth info is signed too (SIGN_MODE_DIRECT)
+	pubKey, err := getSignerPubKey(buildRawTx)
+	if err != nil {
+		return err
+	}
+	if err = buildRawTx.TxBuilder.SetSignatures(BuildSignatures(pubKey, buildRawTx.Sequence, nil)); err != nil {
+		return err
+	}
+
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	args.Extra.EthExtra = nil // clear this which may be set in replace job
+	sequence := buildRawTx.Sequence
+	accountNumber := buildRawTx.AccountNumber
+	args.Extra.Sequence = &sequence
+	args.Extra.AccountNumber = &accountNumber
+	return nil
+}
+
+// getTxSigner get the signer address and signature of the base64 encoded signed tx of one signer
+func (b *Bridge) getTxSigner(signedTx []byte) (signer string, sig signingTypes.SignatureV2, err error) {
+	txBytes, err := base64.StdEncoding.DecodeString(string(signedTx))
+	if err != nil {
+		return "", sig, err
+	}
+	tx, err := b.DecodeTx(txBytes)
+	if err != nil {
+		return "", sig, err
+	}
+	sigTx, ok := tx.(signing.SigVerifiableTx)
+	if !ok {
+		return "", sig, errors.New("tx is not a sig verifiable tx")
+	}
+	sigs, err := sigTx.GetSignaturesV2()
+	if err != nil {
+		return "", sig, err
+	}
+	if len(sigs) != 1 || sigs[0].PubKey == nil {
+		return "", sig, fmt.Errorf("%w, tx should have one signer with public key", ErrSignatureVerifyFailed)
+	}
+	sig = sigs[0]
+	signer, err = bech32.ConvertAndEncode(b.Prefix, sig.PubKey.Address())
+	return signer, sig, err
+}
@@ -0,0 +1,135 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	signingTypes "github.com/cosmos/cosmos-sdk/types/tx/signing"
+	"github.com/cosmos/cosmos-sdk/x/auth/signing"
+)
+
+const (
+	tSignerPrivKey = "1f2e3d4c5b6a79881f2e3d4c5b6a79881f2e3d4c5b6a79881f2e3d4c5b6a7988"
+	tSeiChainID    = "atlantic-2"
+)
+
+func TestRefreshRawTxAccount(t *testing.T) {
+	privKeyBytes, _ := hex.DecodeString(tSignerPrivKey)
+	pubKey := (&secp256k1.PrivKey{Key: privKeyBytes}).PubKey()
+	signer, err := bech32.ConvertAndEncode("cosmos", pubKey.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		b               *Bridge
+		accountNumber   uint64 = 9
+		sequence        uint64 = 3
+		broadcastCount  int
+		alwaysRejecting bool
+	)
+	b = newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == LatestBlock:
+			_, _ = w.Write([]byte(`{"block":{"header":{"chain_id":"` + tSeiChainID + `"}}}`))
+		case r.URL.Path == AccountInfo+signer:
+			_, _ = fmt.Fprintf(w, `{"account":{"address":"%v","account_number":"%v","sequence":"%v","pub_key":{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"%v"}}}`,
+				signer, accountNumber, sequence, base64.StdEncoding.EncodeToString(pubKey.Bytes()))
+		case r.Method == http.MethodPost && r.URL.Path == BroadTx:
+			broadcastCount++
+			var req BroadcastTxRequest
+			_ = json.NewDecoder(r.Body).Decode(&req)
+			txBytes, _ := base64.StdEncoding.DecodeString(req.TxBytes)
+			tx, err := b.TxConfig.TxDecoder()(txBytes)
+			if err != nil {
+				t.Errorf("decode broadcasted tx failed: %v", err)
+				return
+			}
+			sigs, _ := tx.(signing.SigVerifiableTx).GetSignaturesV2()
+			signerData := BuildSignerData(tSeiChainID, accountNumber, sequence)
+			signBytes, _ := b.TxConfig.SignModeHandler().GetSignBytes(signingTypes.SignMode_SIGN_MODE_DIRECT, signerData, tx)
+			signature := sigs[0].Data.(*signingTypes.SingleSignatureData).Signature
+			code := 0
+			if alwaysRejecting || !sigs[0].PubKey.VerifySignature(signBytes, signature) {
+				code = CodeUnauthorized
+			}
+			_, _ = fmt.Fprintf(w, `{"tx_response":{"height":"0","txhash":"%X","code":%v}}`, Sha256Sum(txBytes), code)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+	// the sdk config verifies addresses with the default cosmos prefix
+	b.Prefix = "cosmos"
+
+	newRawTx := func(seq uint64) *BuildRawTx {
+		txBuilder := b.TxConfig.NewTxBuilder()
+		if err := txBuilder.SetMsgs(BuildSendMsg(signer, signer, "usei", big.NewInt(1000))); err != nil {
+			t.Fatal(err)
+		}
+		txBuilder.SetGasLimit(DefaultGasLimit)
+		accNo, err := b.GetAccountNum(signer)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := txBuilder.SetSignatures(BuildSignatures(pubKey, seq, nil)); err != nil {
+			t.Fatal(err)
+		}
+		return &BuildRawTx{TxBuilder: txBuilder, AccountNumber: accNo, Sequence: seq}
+	}
+	signAndSend := func(rawTx *BuildRawTx) (string, error) {
+		signedTx, _, err := b.SignTransactionWithPrivateKey(rawTx, tSignerPrivKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return b.SendTransaction(signedTx)
+	}
+
+	// the account number is changed after it is cached, and the sequence is behind
+	setCachedAccountNumber(signer, 5)
+	t.Cleanup(func() { setCachedAccountNumber(signer, 0) })
+	rawTx := newRawTx(sequence - 1)
+	if _, err = signAndSend(rawTx); !errors.Is(err, tokens.ErrStaleAccountData) {
+		t.Fatalf("tx with stale account number should be diagnosed as stale account data, but have %v", err)
+	}
+	args := &tokens.BuildTxArgs{From: signer, Extra: &tokens.AllExtras{EthExtra: &tokens.EthExtraArgs{}}}
+	if err = b.RefreshRawTxAccount(rawTx, args); err != nil {
+		t.Fatal(err)
+	}
+	if rawTx.AccountNumber != accountNumber || getCachedAccountNumber(signer) != accountNumber || rawTx.Sequence != sequence {
+		t.Errorf("account data should be refreshed, have %v %v cached %v", rawTx.AccountNumber, rawTx.Sequence, getCachedAccountNumber(signer))
+	}
+	if sigs, _ := rawTx.TxBuilder.GetTx().GetSignaturesV2(); len(sigs) != 1 || sigs[0].Sequence != sequence {
+		t.Errorf("signer info sequence should be refreshed, have %v", sigs)
+	}
+	// the accept nodes rebuild the tx from the build args
+	extra := args.Extra
+	if extra.EthExtra != nil || extra.AccountNumber == nil || *extra.AccountNumber != accountNumber ||
+		extra.Sequence == nil || *extra.Sequence != sequence || args.GetTxNonce() != sequence {
+		t.Errorf("fresh account data should be written into the build args, have %+v", extra)
+	}
+	txHash, err := signAndSend(rawTx)
+	if err != nil || txHash == "" || broadcastCount != 2 {
+		t.Errorf("rebuilt tx should succeed on the second attempt, have hash %v %v with %v broadcasts", txHash, err, broadcastCount)
+	}
+
+	// rejected with up to date account data, it is not stale
+	alwaysRejecting = true
+	_, err = signAndSend(newRawTx(sequence))
+	if errors.Is(err, tokens.ErrStaleAccountData) || !errors.Is(err, ErrSignatureVerifyFailed) || !strings.Contains(err.Error(), "sign mode") {
+		t.Errorf("persistent rejection should be diagnosed as sign mode mismatch, but have %v", err)
+	}
+
+	if err = b.RefreshRawTxAccount("wrong raw tx", args); !errors.Is(err, tokens.ErrWrongRawTx) {
+		t.Errorf("refresh wrong raw tx should fail with %v, but have %v", tokens.ErrWrongRawTx, err)
+	}
+}
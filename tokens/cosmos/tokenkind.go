@@ -0,0 +1,213 @@
+package cosmos
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// token kinds, configed by `extra` of the token config
+const (
+	// TokenKindBank native bank denom (default), eg. `usei`, `ibc/...`, `factory/...`
+	TokenKindBank = "bank"
+	// TokenKindAssetFT coreum assetft token, the denom is `{subunit}-{issuer}`
+	TokenKindAssetFT = "assetft"
+	// TokenKindCW20 cosmwasm cw20 token, the token address is the contract address
+	TokenKindCW20 = "cw20"
+
+	// AssetFTToken coreum assetft query of token
+	AssetFTToken = "/coreum/asset/ft/v1/tokens/"
+	// AssetFTBalance coreum assetft query of account balance, followed by `{account}/balances/{denom}`
+	AssetFTBalance = "/coreum/asset/ft/v1/accounts/"
+)
+
+var (
+	// ErrUnknownTokenKind the token kind is not one of bank, assetft and cw20
+	ErrUnknownTokenKind = errors.New("unknown token kind")
+	// ErrWrongTokenKind the token address does not match the token kind
+	ErrWrongTokenKind = errors.New("token address does not match token kind")
+)
+
+// AssetFTTokenInfo coreum assetft token
+type AssetFTTokenInfo struct {
+	Denom          string `json:"denom"`
+	Issuer         string `json:"issuer"`
+	Symbol         string `json:"symbol"`
+	Subunit        string `json:"subunit"`
+	Precision      uint32 `json:"precision"`
+	GloballyFrozen bool   `json:"globally_frozen"`
+}
+
+// QueryAssetFTTokenResponse coreum assetft token
+type QueryAssetFTTokenResponse struct {
+	Token AssetFTTokenInfo `json:"token"`
+}
+
+// QueryAssetFTBalanceResponse coreum assetft account balance
+type QueryAssetFTBalanceResponse struct {
+	Balance     sdk.Int `json:"balance"`
+	Whitelisted sdk.Int `json:"whitelisted"`
+	Frozen      sdk.Int `json:"frozen"`
+	Locked      sdk.Int `json:"locked"`
+}
+
+// GetTokenKind get the token kind configed by `extra` of the token config (default to bank)
+func GetTokenKind(tokenCfg *tokens.TokenConfig) (string, error) {
+	if tokenCfg == nil {
+		return TokenKindBank, nil
+	}
+	switch kind := strings.ToLower(strings.TrimSpace(tokenCfg.Extra)); kind {
+	case "", TokenKindBank:
+		return TokenKindBank, nil
+	case TokenKindAssetFT, TokenKindCW20:
+		return kind, nil
+	default:
+		return "", fmt.Errorf("%w: %v", ErrUnknownTokenKind, tokenCfg.Extra)
+	}
+}
+
+// getTokenKindOf get the token kind of the configed token address
+func (b *Bridge) getTokenKindOf(token string) (string, error) {
+	return GetTokenKind(b.GetTokenConfig(token))
+}
+
+// checkTokenKindFormat check the token address has the format of the token kind.
+// A cw20 contract address configed as bank denom is refused,
+// otherwise a bank MsgSend of the contract address would be sent.
+func (b *Bridge) checkTokenKindFormat(kind, token string) error {
+	switch kind {
+	case TokenKindBank:
+		if err := sdk.ValidateDenom(token); err != nil {
+			return fmt.Errorf("%w, kind: %v, token: %v, %v", ErrWrongTokenKind, kind, token, err)
+		}
+		if b.isBech32Address(token) {
+			return fmt.Errorf("%w, kind: %v, token: %v is an address (cw20?)", ErrWrongTokenKind, kind, token)
+		}
+	case TokenKindAssetFT:
+		if err := sdk.ValidateDenom(token); err != nil {
+			return fmt.Errorf("%w, kind: %v, token: %v, %v", ErrWrongTokenKind, kind, token, err)
+		}
+		pos := strings.LastIndex(token, "-")
+		if pos <= 0 || !b.isBech32Address(token[pos+1:]) {
+			return fmt.Errorf("%w, kind: %v, token: %v is not {subunit}-{issuer}", ErrWrongTokenKind, kind, token)
+		}
+	case TokenKindCW20:
+		if !b.isBech32Address(token) {
+			return fmt.Errorf("%w, kind: %v, token: %v is not a contract address", ErrWrongTokenKind, kind, token)
+		}
+	default:
+		return fmt.Errorf("%w: %v", ErrUnknownTokenKind, kind)
+	}
+	return nil
+}
+
+// isBech32Address is a bech32 address of the prefix (without logging as denoms are checked too)
+func (b *Bridge) isBech32Address(address string) bool {
+	bz, err := sdk.GetFromBech32(address, b.Prefix)
+	return err == nil && sdk.VerifyAddressFormat(bz) == nil
+}
+
+// verifyTokenKindOnChain verify the token kind and decimals against the chain if possible.
+// It returns the query error if the token can not be queried.
+func (b *Bridge) verifyTokenKindOnChain(kind, token string, decimals uint8) error {
+	switch kind {
+	case TokenKindBank:
+		// resolve and cache the origin of the ibc denom
+		if IsIBCDenom(token) {
+			if _, err := b.DenomTrace(token); err != nil {
+				return err
+			}
+		}
+	case TokenKindAssetFT:
+		info, err := b.GetAssetFTToken(token)
+		if err != nil {
+			return err
+		}
+		if info.Precision != uint32(decimals) {
+			return fmt.Errorf("assetft %v decimals mismatch, have %v want %v", token, decimals, info.Precision)
+		}
+	case TokenKindCW20:
+		info, err := b.GetCW20TokenInfo(token)
+		if err != nil {
+			return err
+		}
+		if info.Decimals != decimals {
+			return fmt.Errorf("cw20 %v decimals mismatch, have %v want %v", token, decimals, info.Decimals)
+		}
+	}
+	return nil
+}
+
+// GetTokenBalance get the spendable balance of the token of the token kind
+func (b *Bridge) GetTokenBalance(kind, account, token string) (sdk.Int, error) {
+	switch kind {
+	case TokenKindBank:
+		return b.GetDenomBalance(account, token)
+	case TokenKindAssetFT:
+		return b.GetAssetFTBalance(account, token)
+	case TokenKindCW20:
+		return b.GetCW20Balance(account, token)
+	default:
+		return sdk.ZeroInt(), fmt.Errorf("%w: %v", ErrUnknownTokenKind, kind)
+	}
+}
+
+// BuildTokenSendMsg build the msg sending the token of the token kind,
+// assetft tokens are bank denoms and are sent by MsgSend,
+// cw20 tokens are sent by executing `transfer` of the contract.
+func BuildTokenSendMsg(kind, from, to, token string, amount *big.Int) (sdk.Msg, error) {
+	switch kind {
+	case TokenKindBank, TokenKindAssetFT:
+		return BuildSendMsg(from, to, token, amount), nil
+	case TokenKindCW20:
+		return BuildCW20TransferMsg(from, token, to, amount)
+	default:
+		return nil, fmt.Errorf("%w: %v", ErrUnknownTokenKind, kind)
+	}
+}
+
+// GetAssetFTToken get the coreum assetft token
+func (b *Bridge) GetAssetFTToken(denom string) (*AssetFTTokenInfo, error) {
+	var result *QueryAssetFTTokenResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, AssetFTToken+denom)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return &result.Token, nil
+		}
+		log.Warn("GetAssetFTToken failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "GetAssetFTToken", denom)
+}
+
+// GetAssetFTBalance get the spendable assetft balance of the account, ie. excluding the frozen and locked amount
+func (b *Bridge) GetAssetFTBalance(account, denom string) (sdk.Int, error) {
+	var result *QueryAssetFTBalanceResponse
+	var err error
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, AssetFTBalance+account+"/balances/"+denom)
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			spendable := sdk.ZeroInt()
+			if !result.Balance.IsNil() {
+				spendable = result.Balance
+			}
+			for _, unspendable := range []sdk.Int{result.Frozen, result.Locked} {
+				if !unspendable.IsNil() {
+					spendable = spendable.Sub(unspendable)
+				}
+			}
+			if spendable.IsNegative() {
+				return sdk.ZeroInt(), nil
+			}
+			return spendable, nil
+		}
+		log.Warn("GetAssetFTBalance failed", "url", restApi, "err", err)
+	}
+	return sdk.ZeroInt(), wrapRPCQueryError(err, "GetAssetFTBalance", denom)
+}
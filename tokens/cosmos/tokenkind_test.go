@@ -0,0 +1,208 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+	bankTypes "github.com/cosmos/cosmos-sdk/x/bank/types"
+)
+
+func TestBuildTxByTokenKind(t *testing.T) {
+	mpc := tMPCAddress
+	cw20Contract, err := bech32.ConvertAndEncode("cosmos", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assetFT := "ucore-" + mpc
+	var queried []string
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch path := r.URL.Path; {
+		case path == AccountInfo+mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case path == Balances+mpc:
+			queried = append(queried, TokenKindBank)
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"3000000"},{"denom":"` + assetFT + `","amount":"5000000"}]}`))
+		case path == AssetFTBalance+mpc+"/balances/"+assetFT:
+			queried = append(queried, TokenKindAssetFT)
+			_, _ = w.Write([]byte(`{"balance":"5000000","whitelisted":"0","frozen":"2000001","locked":"0"}`))
+		case strings.HasPrefix(path, WasmSmartQuery+cw20Contract+"/smart/"):
+			queried = append(queried, TokenKindCW20)
+			_, _ = w.Write([]byte(`{"data":{"balance":"3000000"}}`))
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	b.Prefix, b.Denom = "cosmos", "uatom"
+	b.CrossChainBridgeBase.SetTokenConfig("uatom", &tokens.TokenConfig{Decimals: 6})
+	b.CrossChainBridgeBase.SetTokenConfig(assetFT, &tokens.TokenConfig{Decimals: 6, Extra: TokenKindAssetFT})
+	b.CrossChainBridgeBase.SetTokenConfig(cw20Contract, &tokens.TokenConfig{Decimals: 6, Extra: TokenKindCW20})
+
+	fee, gas, sequence := "500uatom", uint64(200000), uint64(7)
+	args := &tokens.BuildTxArgs{From: mpc, Extra: &tokens.AllExtras{Fee: &fee, Gas: &gas, Sequence: &sequence}}
+	for _, test := range []struct {
+		kind  string
+		token string
+	}{
+		{TokenKindBank, "uatom"},
+		{TokenKindAssetFT, assetFT},
+		{TokenKindCW20, cw20Contract},
+	} {
+		queried = nil
+		txBuilder, err := b.BuildTx(args, mpc, test.token, "", tMPCPubkey, big.NewInt(3000000))
+		if test.kind == TokenKindAssetFT {
+			// the frozen amount is not spendable
+			if !errors.Is(err, tokens.ErrBalanceNotEnough) {
+				t.Errorf("assetft payout exceeding the unfrozen balance should fail, but have %v", err)
+			}
+			queried = nil
+			txBuilder, err = b.BuildTx(args, mpc, test.token, "", tMPCPubkey, big.NewInt(3000000-1))
+		}
+		if err != nil {
+			t.Errorf("%v: build tx failed: %v", test.kind, err)
+			continue
+		}
+		if len(queried) != 1 || queried[0] != test.kind {
+			t.Errorf("%v: balance is queried by %v", test.kind, queried)
+		}
+		msgs := txBuilder.GetTx().GetMsgs()
+		if len(msgs) != 1 {
+			t.Fatalf("%v: msgs count mismatch, have %v", test.kind, len(msgs))
+		}
+		switch msg := msgs[0].(type) {
+		case *bankTypes.MsgSend:
+			if test.kind == TokenKindCW20 || msg.Amount[0].Denom != test.token {
+				t.Errorf("%v: wrong bank send msg %v", test.kind, msg)
+			}
+		case *MsgExecuteContract:
+			want := `{"transfer":{"recipient":"` + mpc + `","amount":"3000000"}}`
+			if test.kind != TokenKindCW20 || msg.Contract != cw20Contract || string(msg.Msg) != want {
+				t.Errorf("%v: wrong execute contract msg %v", test.kind, msg)
+			}
+		default:
+			t.Errorf("%v: unexpected msg type %T", test.kind, msg)
+		}
+	}
+}
+
+func TestMsgExecuteContractEncoding(t *testing.T) {
+	b := NewCrossChainBridge()
+	contract, err := bech32.ConvertAndEncode("cosmos", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msg, err := BuildCW20TransferMsg(tMPCAddress, contract, tMPCAddress, big.NewInt(100))
+	if err != nil {
+		t.Fatal(err)
+	}
+	txBuilder := b.TxConfig.NewTxBuilder()
+	if err = txBuilder.SetMsgs(msg); err != nil {
+		t.Fatal(err)
+	}
+	txBytes, err := b.TxConfig.TxEncoder()(txBuilder.GetTx())
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := b.TxConfig.TxDecoder()(txBytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, ok := tx.GetMsgs()[0].(*MsgExecuteContract)
+	if !ok || decoded.String() != msg.String() {
+		t.Errorf("decoded msg mismatch, have %v want %v", tx.GetMsgs()[0], msg)
+	}
+}
+
+func TestCheckTokenKindFormat(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.Prefix = "cosmos"
+	contract, err := bech32.ConvertAndEncode("cosmos", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		kind  string
+		token string
+		valid bool
+	}{
+		{TokenKindBank, "uatom", true},
+		{TokenKindBank, contract, false}, // never send bank MsgSend for cw20
+		{TokenKindAssetFT, "ucore-" + tMPCAddress, true},
+		{TokenKindAssetFT, "ucore", false},
+		{TokenKindCW20, contract, true},
+		{TokenKindCW20, "uatom", false},
+	}
+	for _, test := range tests {
+		if err := b.checkTokenKindFormat(test.kind, test.token); (err == nil) != test.valid {
+			t.Errorf("check %v token %v mismatch, have %v want valid %v", test.kind, test.token, err, test.valid)
+		}
+	}
+	if _, err := GetTokenKind(&tokens.TokenConfig{Extra: "erc20"}); !errors.Is(err, ErrUnknownTokenKind) {
+		t.Errorf("unknown token kind should fail with %v, but have %v", ErrUnknownTokenKind, err)
+	}
+}
+
+func TestBuildCW20TxFromAuthzGranter(t *testing.T) {
+	mpc := tMPCAddress
+	cw20Contract, err := bech32.ConvertAndEncode("cosmos", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	granter, err := bech32.ConvertAndEncode("cosmos", make([]byte, 20))
+	if err != nil {
+		t.Fatal(err)
+	}
+	allowance := "2999999"
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		prefix := WasmSmartQuery + cw20Contract + "/smart/"
+		switch path := r.URL.Path; {
+		case path == AccountInfo+mpc:
+			_, _ = w.Write([]byte(`{"account":{"address":"` + mpc + `","account_number":"12","sequence":"7"}}`))
+		case path == Balances+mpc:
+			_, _ = w.Write([]byte(`{"balances":[{"denom":"uatom","amount":"3000000"}]}`))
+		case strings.HasPrefix(path, prefix):
+			query, _ := base64.URLEncoding.DecodeString(strings.TrimPrefix(path, prefix))
+			switch string(query) {
+			case `{"balance":{"address":"` + granter + `"}}`:
+				_, _ = w.Write([]byte(`{"data":{"balance":"3000000"}}`))
+			case `{"allowance":{"owner":"` + granter + `","spender":"` + mpc + `"}}`:
+				_, _ = w.Write([]byte(`{"data":{"allowance":"` + allowance + `","expires":{"never":{}}}}`))
+			default:
+				t.Errorf("unexpected query %s", query)
+			}
+		default:
+			t.Errorf("unexpected request %v", r.URL)
+		}
+	})
+	b.Prefix, b.Denom = "cosmos", "uatom"
+	b.CrossChainBridgeBase.SetTokenConfig(cw20Contract, &tokens.TokenConfig{Decimals: 6, Extra: TokenKindCW20})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"authzGranter": granter})
+
+	fee, gas, sequence := "500uatom", uint64(200000), uint64(7)
+	newArgs := func() *tokens.BuildTxArgs {
+		return &tokens.BuildTxArgs{From: mpc, Extra: &tokens.AllExtras{Fee: &fee, Gas: &gas, Sequence: &sequence}}
+	}
+	if _, err = b.BuildTx(newArgs(), mpc, cw20Contract, "", tMPCPubkey, big.NewInt(3000000)); !errors.Is(err, ErrCW20AllowanceNotEnough) {
+		t.Errorf("cw20 payout exceeding the allowance should fail with %v, but have %v", ErrCW20AllowanceNotEnough, err)
+	}
+
+	allowance = "3000000"
+	txBuilder, err := b.BuildTx(newArgs(), mpc, cw20Contract, "", tMPCPubkey, big.NewInt(3000000))
+	if err != nil {
+		t.Fatal(err)
+	}
+	msgs := txBuilder.GetTx().GetMsgs()
+	if len(msgs) != 1 {
+		t.Fatalf("msgs count mismatch, have %v", len(msgs))
+	}
+	msg, ok := msgs[0].(*MsgExecuteContract)
+	want := `{"transfer_from":{"owner":"` + granter + `","recipient":"` + mpc + `","amount":"3000000"}}`
+	if !ok || msg.Sender != mpc || msg.Contract != cw20Contract || string(msg.Msg) != want {
+		t.Errorf("cw20 payout of the granter should be transfer_from executed by mpc, have %v", msgs[0])
+	}
+}
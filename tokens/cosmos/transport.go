@@ -0,0 +1,217 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	"github.com/cosmos/cosmos-sdk/codec"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// query transports, configed by `queryTransport` custom
+const (
+	// QueryTransportAuto use grpc first and fallback to rest (default)
+	QueryTransportAuto = ""
+	// QueryTransportREST use rest (LCD) only
+	QueryTransportREST = "rest"
+	// QueryTransportGRPC use grpc only
+	QueryTransportGRPC = "grpc"
+)
+
+// QueryTransport transport of account query, simulate and broadcast
+type QueryTransport interface {
+	GetBaseAccount(address string) (*QueryAccountResponse, error)
+	SimulateTx(simulateReq *SimulateRequest) (string, error)
+	BroadcastTx(req *BroadcastTxRequest) (string, error)
+}
+
+var (
+	_ QueryTransport = &restTransport{}
+	_ QueryTransport = &grpcTransport{}
+)
+
+// GetQueryTransport get the configed query transport
+func (b *Bridge) GetQueryTransport() string {
+	transport := params.GetCustom(b.ChainConfig.ChainID, "queryTransport")
+	switch transport {
+	case QueryTransportAuto, QueryTransportREST, QueryTransportGRPC:
+		return transport
+	default:
+		log.Warn("unknown query transport, use default", "chainID", b.ChainConfig.ChainID, "transport", transport)
+		return QueryTransportAuto
+	}
+}
+
+// getQueryTransports get transports to try in order
+func (b *Bridge) getQueryTransports() []QueryTransport {
+	switch b.GetQueryTransport() {
+	case QueryTransportREST:
+		return []QueryTransport{&restTransport{b: b}}
+	case QueryTransportGRPC:
+		return []QueryTransport{&grpcTransport{b}}
+	default:
+		if len(b.GatewayConfig.AllGatewayURLs) == 0 {
+			return []QueryTransport{&grpcTransport{b}}
+		}
+		return []QueryTransport{&grpcTransport{b}, &restTransport{b: b}}
+	}
+}
+
+// newQueryAccountResponse convert the decoded proto account
+func newQueryAccountResponse(acc authtypes.AccountI) *QueryAccountResponse {
+	res := &BaseAccount{
+		Address:       acc.GetAddress().String(),
+		AccountNumber: fmt.Sprintf("%v", acc.GetAccountNumber()),
+		Sequence:      fmt.Sprintf("%v", acc.GetSequence()),
+	}
+	if pubKey := acc.GetPubKey(); pubKey != nil {
+		res.PubKey = &AccountPubKey{
+			Type: PubKeyTypeURL(pubKey),
+			Key:  pubKey.Bytes(),
+		}
+	}
+	return &QueryAccountResponse{Account: res}
+}
+
+// decodeAccountResponse decode the account of rest response with the proto codec
+// (the same as grpc), and fallback to plain json if the account type is not registered
+func (b *Bridge) decodeAccountResponse(data []byte) (*QueryAccountResponse, error) {
+	var protoRes authtypes.QueryAccountResponse
+	cdc := codec.NewProtoCodec(b.InterfaceRegistry())
+	if err := cdc.UnmarshalJSON(data, &protoRes); err == nil {
+		var acc authtypes.AccountI
+		if err = b.InterfaceRegistry().UnpackAny(protoRes.Account, &acc); err == nil && acc != nil {
+			return newQueryAccountResponse(acc), nil
+		}
+	}
+	var res *QueryAccountResponse
+	if err := json.Unmarshal(data, &res); err != nil {
+		return nil, err
+	}
+	if res == nil || res.Account == nil {
+		return nil, fmt.Errorf("decode account response failed: %v", string(data))
+	}
+	return res, nil
+}
+
+// GetBroadcastAPIAddress get the rest urls dedicated to broadcasting,
+// configed by `broadcastAPIAddress` custom (comma separated)
+func (b *Bridge) GetBroadcastAPIAddress() []string {
+	var urls []string
+	for _, url := range strings.Split(params.GetCustom(b.ChainConfig.ChainID, "broadcastAPIAddress"), ",") {
+		if url = strings.TrimSpace(url); url != "" {
+			urls = append(urls, url)
+		}
+	}
+	return urls
+}
+
+// getBroadcastTransports broadcast through the dedicated broadcast endpoints if configed,
+// otherwise through the query transports
+func (b *Bridge) getBroadcastTransports() []QueryTransport {
+	if urls := b.GetBroadcastAPIAddress(); len(urls) > 0 {
+		return []QueryTransport{&restTransport{b: b, broadcastURLs: urls}}
+	}
+	return b.getQueryTransports()
+}
+
+type restTransport struct {
+	b *Bridge
+
+	broadcastURLs []string // use AllGatewayURLs if empty
+}
+
+func (t *restTransport) GetBaseAccount(address string) (*QueryAccountResponse, error) {
+	var result json.RawMessage
+	var err error
+	for _, url := range t.b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, AccountInfo+address)
+		if err = client.RPCGet(&result, restApi); err == nil {
+			var res *QueryAccountResponse
+			if res, err = t.b.decodeAccountResponse(result); err == nil {
+				return res, nil
+			}
+		}
+		log.Warn("GetBaseAccount failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "GetBaseAccount")
+}
+
+func (t *restTransport) SimulateTx(simulateReq *SimulateRequest) (string, error) {
+	data, err := json.Marshal(simulateReq)
+	if err != nil {
+		return "", err
+	}
+	for _, url := range t.b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, SimulateTx)
+		var res string
+		if res, err = client.RPCRawPostWithTimeout(restApi, string(data), 120); err == nil && res != "" && res != "\n" {
+			return res, nil
+		}
+	}
+	return "", wrapRPCQueryError(err, "SimulateTx")
+}
+
+func (t *restTransport) BroadcastTx(req *BroadcastTxRequest) (string, error) {
+	data, err := json.Marshal(req)
+	if err != nil {
+		return "", err
+	}
+	urls := t.broadcastURLs
+	if len(urls) == 0 {
+		urls = t.b.GatewayConfig.AllGatewayURLs
+	}
+	var res string
+	var success bool
+	for _, url := range urls {
+		restApi := joinURLPath(url, BroadTx)
+		if res, err = client.RPCJsonPostWithTimeout(restApi, string(data), 120); err == nil {
+			success = true
+		}
+	}
+	if success {
+		return res, nil
+	}
+	return "", wrapRPCQueryError(err, "BroadcastTx")
+}
+
+type grpcTransport struct {
+	b *Bridge
+}
+
+func (t *grpcTransport) GetBaseAccount(address string) (*QueryAccountResponse, error) {
+	return t.b.GRPCGetBaseAccount(address)
+}
+
+func (t *grpcTransport) SimulateTx(simulateReq *SimulateRequest) (string, error) {
+	result, err := t.b.GRPCSimulateTx(simulateReq)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(SimulateResponse{
+		GasInfo: &GasInfo{
+			GasUsed: fmt.Sprintf("%d", result.GasInfo.GasUsed),
+		},
+	})
+	return string(data), nil
+}
+
+func (t *grpcTransport) BroadcastTx(req *BroadcastTxRequest) (string, error) {
+	result, err := t.b.GRPCBroadcastTx(req)
+	if err != nil {
+		return "", err
+	}
+	data, _ := json.Marshal(BroadcastTxResponse{
+		TxResponse: &TxResponse{
+			Height: fmt.Sprintf("%d", result.Height),
+			TxHash: result.TxHash,
+			Code:   result.Code,
+			Logs:   result.Logs,
+		},
+	})
+	return string(data), nil
+}
@@ -0,0 +1,203 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	cosmosclient "github.com/cosmos/cosmos-sdk/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	"github.com/cosmos/cosmos-sdk/crypto/keys/secp256k1"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	rpcclient "github.com/tendermint/tendermint/rpc/client"
+)
+
+func setTestQueryTransport(t *testing.T, chainID, transport string) {
+	setTestCustoms(t, chainID, map[string]string{"queryTransport": transport})
+}
+
+func newTestAccount(t *testing.T) *authtypes.BaseAccount {
+	pubKey := secp256k1.GenPrivKey().PubKey()
+	acc := authtypes.NewBaseAccountWithAddress(sdk.AccAddress(pubKey.Address()))
+	if err := acc.SetPubKey(pubKey); err != nil {
+		t.Fatal(err)
+	}
+	acc.AccountNumber = 9
+	acc.Sequence = 3
+	return acc
+}
+
+func checkTestAccount(t *testing.T, res *QueryAccountResponse, acc *authtypes.BaseAccount) {
+	t.Helper()
+	if res == nil || res.Account == nil {
+		t.Fatal("account response is empty")
+	}
+	if res.Account.Address != acc.Address {
+		t.Errorf("address mismatch, have %v want %v", res.Account.Address, acc.Address)
+	}
+	if res.Account.AccountNumber != "9" || res.Account.Sequence != "3" {
+		t.Errorf("account number or sequence mismatch, have %v %v", res.Account.AccountNumber, res.Account.Sequence)
+	}
+	if res.Account.PubKey == nil ||
+		res.Account.PubKey.Type != "/cosmos.crypto.secp256k1.PubKey" ||
+		string(res.Account.PubKey.Key) != string(acc.GetPubKey().Bytes()) {
+		t.Errorf("public key mismatch, have %v", res.Account.PubKey)
+	}
+}
+
+func TestGetBaseAccountWithRESTTransport(t *testing.T) {
+	acc := newTestAccount(t)
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != AccountInfo+acc.Address {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"account":{"@type":"/cosmos.auth.v1beta1.BaseAccount","address":"` + acc.Address +
+			`","pub_key":{"@type":"/cosmos.crypto.secp256k1.PubKey","key":"` + base64.StdEncoding.EncodeToString(acc.GetPubKey().Bytes()) +
+			`"},"account_number":"9","sequence":"3"}}`))
+	})
+	setTestQueryTransport(t, b.ChainConfig.ChainID, QueryTransportREST)
+
+	transports := b.getQueryTransports()
+	if len(transports) != 1 {
+		t.Fatalf("rest transport should be used only, have %v", len(transports))
+	}
+	if _, ok := transports[0].(*restTransport); !ok {
+		t.Fatalf("transport should be rest, have %T", transports[0])
+	}
+
+	res, err := b.GetBaseAccount(acc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTestAccount(t, res, acc)
+}
+
+func TestGetBaseAccountWithGRPCTransport(t *testing.T) {
+	acc := newTestAccount(t)
+	anyAcc, err := codecTypes.NewAnyWithValue(acc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	value, err := (&authtypes.QueryAccountResponse{Account: anyAcc}).Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var req struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		if err := json.Unmarshal(body, &req); err != nil || req.Method != "abci_query" {
+			http.Error(w, "unexpected request", http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":` + string(req.ID) +
+			`,"result":{"response":{"code":0,"value":"` + base64.StdEncoding.EncodeToString(value) + `","height":"10"}}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	rpcClient, err := cosmosclient.NewClientFromNode(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	oldClients := rpcClients
+	rpcClients = []rpcclient.Client{rpcClient}
+	t.Cleanup(func() { rpcClients = oldClients })
+
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1234567"}
+	b.GatewayConfig = &tokens.GatewayConfig{AllGatewayURLs: []string{"http://127.0.0.1:1"}}
+	setTestQueryTransport(t, b.ChainConfig.ChainID, QueryTransportGRPC)
+
+	transports := b.getQueryTransports()
+	if len(transports) != 1 {
+		t.Fatalf("grpc transport should be used only, have %v", len(transports))
+	}
+	if _, ok := transports[0].(*grpcTransport); !ok {
+		t.Fatalf("transport should be grpc, have %T", transports[0])
+	}
+
+	res, err := b.GetBaseAccount(acc.Address)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkTestAccount(t, res, acc)
+}
+
+func TestGetQueryTransports(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: "1234567"}
+	b.GatewayConfig = &tokens.GatewayConfig{}
+
+	if transports := b.getQueryTransports(); len(transports) != 1 {
+		t.Errorf("auto transport without rest urls should use grpc only, have %v", len(transports))
+	}
+
+	b.GatewayConfig.AllGatewayURLs = []string{"http://127.0.0.1:1"}
+	transports := b.getQueryTransports()
+	if len(transports) != 2 {
+		t.Fatalf("auto transport should use grpc and rest, have %v", len(transports))
+	}
+	if _, ok := transports[0].(*grpcTransport); !ok {
+		t.Errorf("auto transport should try grpc first, have %T", transports[0])
+	}
+
+	setTestQueryTransport(t, b.ChainConfig.ChainID, "unknown")
+	if transport := b.GetQueryTransport(); transport != QueryTransportAuto {
+		t.Errorf("unknown transport should use auto, have %q", transport)
+	}
+}
+
+func TestBroadcastAPIAddress(t *testing.T) {
+	acc := newTestAccount(t)
+	var queryHits, broadcastHits, wrongHits int32
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case AccountInfo + acc.Address:
+			atomic.AddInt32(&queryHits, 1)
+			_, _ = w.Write([]byte(`{"account":{"address":"` + acc.Address + `","account_number":"9","sequence":"3"}}`))
+		case SimulateTx:
+			atomic.AddInt32(&queryHits, 1)
+			_, _ = w.Write([]byte(`{"gas_info":{"gas_used":"100000"}}`))
+		default:
+			atomic.AddInt32(&wrongHits, 1)
+			http.NotFound(w, r)
+		}
+	})
+	broadcastSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != BroadTx {
+			atomic.AddInt32(&wrongHits, 1)
+			http.NotFound(w, r)
+			return
+		}
+		atomic.AddInt32(&broadcastHits, 1)
+		_, _ = w.Write([]byte(`{"tx_response":{"txhash":"ABCD","code":0}}`))
+	}))
+	t.Cleanup(broadcastSrv.Close)
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"broadcastAPIAddress": broadcastSrv.URL})
+
+	if _, err := b.GetBaseAccount(acc.Address); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := b.SimulateTx(&SimulateRequest{TxBytes: "AA=="}); err != nil {
+		t.Fatal(err)
+	}
+	if res, err := b.BroadcastTx(&BroadcastTxRequest{TxBytes: "AA==", Mode: BroadcastModeSync}); err != nil || !strings.Contains(res, "ABCD") {
+		t.Fatalf("broadcast tx failed, have %v %v", res, err)
+	}
+
+	if queryHits != 2 || broadcastHits != 1 || wrongHits != 0 {
+		t.Errorf("endpoint usage mismatch, query %v broadcast %v wrong %v", queryHits, broadcastHits, wrongHits)
+	}
+}
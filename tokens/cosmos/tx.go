@@ -57,13 +57,35 @@ func (b *Bridge) BuildTx(
 ) (cosmosClient.TxBuilder, error) {
 	from := args.From
 	extra := args.Extra
-	log.Info("start to build tx", "swapID", args.SwapID, "from", from, "to", to, "denom", denom, "memo", memo, "amount", amount, "fee", *extra.Fee, "gas", *extra.Gas, "sequence", *extra.Sequence)
-	if balance, err := b.GetDenomBalance(from, denom); err != nil {
+	// funds are sent from the authz granter if it is set
+	payer := from
+	granter := b.GetAuthzGranter()
+	if granter != "" {
+		payer = granter
+	}
+	// the balance query and the payout msg are dispatched by the token kind
+	kind, err := b.getTokenKindOf(denom)
+	if err != nil {
+		return nil, err
+	}
+	// the cw20 tokens of the authz granter are transferred by the cw20 allowance instead of authz
+	buildSendMsg := func(receiver string, value *big.Int) (sdk.Msg, error) {
+		if granter != "" && kind == TokenKindCW20 {
+			return BuildCW20TransferFromMsg(from, denom, granter, receiver, value)
+		}
+		return BuildTokenSendMsg(kind, payer, receiver, denom, value)
+	}
+	log.Info("start to build tx", "swapID", args.SwapID, "from", from, "payer", payer, "to", to, "denom", denom, "kind", kind, "memo", memo, "amount", amount, "fee", *extra.Fee, "gas", *extra.Gas, "sequence", *extra.Sequence)
+	if balance, err := b.GetTokenBalance(kind, payer, denom); err != nil {
 		return nil, err
 	} else {
 		var msgs []sdk.Msg
+		sendAmount := new(big.Int).Set(amount)
 		if balance.BigInt().Cmp(amount) >= 0 {
-			sendMsg := BuildSendMsg(from, to, denom, amount)
+			sendMsg, err := buildSendMsg(to, amount)
+			if err != nil {
+				return nil, err
+			}
 			msgs = append(msgs, sendMsg)
 		} else {
 			log.Info("balance not enough", "denom", denom, "balance", balance, "amount", amount)
@@ -78,25 +100,71 @@ func (b *Bridge) BuildTx(
 			if extra.BridgeFee != nil && extra.BridgeFee.Sign() > 0 {
 				bridgeFeeReceiver := params.FeeReceiverOnDestChain(toChainID.String())
 				if bridgeFeeReceiver != "" {
-					sendMsg := BuildSendMsg(from, bridgeFeeReceiver, denom, extra.BridgeFee)
+					sendMsg, err := buildSendMsg(bridgeFeeReceiver, extra.BridgeFee)
+					if err != nil {
+						return nil, err
+					}
 					msgs = append(msgs, sendMsg)
+					sendAmount.Add(sendAmount, extra.BridgeFee)
 					log.Info("build charge fee on dest chain", "swapID", args.SwapID, "from", from, "receiver", bridgeFeeReceiver, "denom", denom, "amount", extra.BridgeFee)
 				}
 			}
 		}
 
+		if granter != "" && kind == TokenKindCW20 {
+			if err := b.checkCW20Allowance(granter, from, denom, sendAmount); err != nil {
+				return nil, err
+			}
+		} else if granter != "" {
+			if err := b.checkSendAuthorization(granter, from, denom, sendAmount); err != nil {
+				return nil, err
+			}
+			execMsg, err := BuildExecMsg(from, msgs...)
+			if err != nil {
+				return nil, err
+			}
+			msgs = []sdk.Msg{execMsg}
+		}
+
 		txBuilder := b.TxConfig.NewTxBuilder()
 		if err := txBuilder.SetMsgs(msgs...); err != nil {
 			return nil, err
 		}
 		txBuilder.SetMemo(memo)
+		if err := b.setExtensionOptions(txBuilder); err != nil {
+			return nil, err
+		}
 		if fee, err := ParseCoinsFee(*extra.Fee); err != nil {
 			return nil, err
 		} else {
 			txBuilder.SetFeeAmount(fee)
 		}
+		// the fee is paid by the fee granter if the signer can not afford it
+		feePayout := sendAmount
+		if granter != "" {
+			feePayout = nil // paid by the authz granter
+		}
+		// the decision is passed in the args so that the accept nodes rebuild the same tx
+		if extra.FeeGranter == nil {
+			feeGranter, err := b.getFeeGranterOf(from, *extra.Fee, denom, feePayout)
+			if err != nil {
+				return nil, err
+			}
+			extra.FeeGranter = &feeGranter
+		}
+		if feeGranter := *extra.FeeGranter; feeGranter != "" {
+			granterBytes, err := sdk.GetFromBech32(feeGranter, b.Prefix)
+			if err != nil {
+				return nil, err
+			}
+			txBuilder.SetFeeGranter(granterBytes)
+			log.Info("build tx with fee granter", "swapID", args.SwapID, "from", from, "feeGranter", feeGranter, "fee", *extra.Fee)
+		}
 		txBuilder.SetGasLimit(*extra.Gas)
-		pubKey, err := PubKeyFromStr(publicKey)
+		if extra.ExpiryHeight != nil {
+			txBuilder.SetTimeoutHeight(*extra.ExpiryHeight)
+		}
+		pubKey, err := b.GetSignerPubKey(from, publicKey)
 		if err != nil {
 			return nil, err
 		}
@@ -113,15 +181,22 @@ func (b *Bridge) BuildTx(
 }
 
 func (b *Bridge) GetSignBytes(tx *BuildRawTx) ([]byte, error) {
+	_, signBytes, err := b.getSignBytes(tx)
+	return signBytes, err
+}
+
+// getSignBytes returns the signer data (chain id, account number and sequence) and the sign bytes
+func (b *Bridge) getSignBytes(tx *BuildRawTx) (signerData signing.SignerData, signBytes []byte, err error) {
 	handler := b.TxConfig.SignModeHandler()
 	if chainName, err := b.GetChainID(); err != nil {
-		return nil, err
+		return signerData, nil, err
 	} else {
 		txBuilder := tx.TxBuilder
 		accountNumber := tx.AccountNumber
 		sequence := tx.Sequence
-		signerData := BuildSignerData(chainName, accountNumber, sequence)
-		return handler.GetSignBytes(signingTypes.SignMode_SIGN_MODE_DIRECT, signerData, txBuilder.GetTx())
+		signerData = BuildSignerData(chainName, accountNumber, sequence)
+		signBytes, err = handler.GetSignBytes(signingTypes.SignMode_SIGN_MODE_DIRECT, signerData, txBuilder.GetTx())
+		return signerData, signBytes, err
 	}
 }
 
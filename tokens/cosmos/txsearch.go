@@ -0,0 +1,86 @@
+package cosmos
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+var (
+	txSearchPageLimit = 100
+	txSearchMaxPages  = 10
+)
+
+// TxSearchPagination pagination of the txs by events response
+type TxSearchPagination struct {
+	Total string `json:"total"`
+}
+
+// getMemoEvent get the event key which indexes the memo (eg. `tx.memo` on chains emitting it),
+// which is configed by `memoEvent` custom. the memo is not indexed by the sdk if not set.
+func (b *Bridge) getMemoEvent() string {
+	return params.GetCustom(b.ChainConfig.ChainID, "memoEvent")
+}
+
+// SearchDepositTxs search the txs transferring to the recipient with the memo (eg. `bindAddress:toChainID`),
+// for detecting deposits whose tx hash is not known upfront. The txs are queried by the transfer recipient event
+// (and the memo event if `memoEvent` custom is configed) page by page, and matched by the memo of the tx body.
+// The matched txs are not verified, they should be verified by `VerifyTransaction` with their hashes.
+func (b *Bridge) SearchDepositTxs(recipient, memo string) ([]*GetTxResponse, error) {
+	events := []string{fmt.Sprintf("%v.%v='%v'", TransferType, TransferRecipientKey, recipient)}
+	if memoEvent := b.getMemoEvent(); memoEvent != "" {
+		events = append(events, fmt.Sprintf("%v='%v'", memoEvent, memo))
+	}
+
+	var matched []*GetTxResponse
+	for page, offset := 0, 0; page < txSearchMaxPages; page++ {
+		result, err := b.searchTxsByEvents(events, offset, txSearchPageLimit)
+		if err != nil {
+			return nil, err
+		}
+		if len(result.Txs) != len(result.TxResponses) {
+			return nil, fmt.Errorf("search txs by events returns %v txs and %v tx responses", len(result.Txs), len(result.TxResponses))
+		}
+		for i, tx := range result.Txs {
+			if tx != nil && result.TxResponses[i] != nil && tx.Body.Memo == memo {
+				matched = append(matched, &GetTxResponse{Tx: tx, TxResponse: result.TxResponses[i]})
+			}
+		}
+		offset += len(result.TxResponses)
+		if len(result.TxResponses) == 0 || result.Pagination == nil {
+			break
+		}
+		if total, errf := strconv.Atoi(result.Pagination.Total); errf != nil || offset >= total {
+			break
+		}
+		if page == txSearchMaxPages-1 {
+			log.Warn("search deposit txs stops at max pages", "chainID", b.ChainConfig.ChainID,
+				"recipient", recipient, "memo", memo, "searched", offset, "total", result.Pagination.Total)
+		}
+	}
+	return matched, nil
+}
+
+func (b *Bridge) searchTxsByEvents(events []string, offset, limit int) (result *QueryTxsByEventsResponse, err error) {
+	query := url.Values{}
+	for _, event := range events {
+		query.Add("events", event)
+	}
+	query.Set("pagination.offset", strconv.Itoa(offset))
+	query.Set("pagination.limit", strconv.Itoa(limit))
+	query.Set("pagination.count_total", "true")
+	query.Set("order_by", "ORDER_BY_ASC")
+	for _, gateway := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(gateway, TxsByEvents) + "?" + query.Encode()
+		if err = client.RPCGet(&result, restApi); err == nil && result != nil {
+			return result, nil
+		}
+		log.Warn("search txs by events failed", "url", restApi, "err", err)
+	}
+	return nil, wrapRPCQueryError(err, "search txs by events", strings.Join(events, " AND "))
+}
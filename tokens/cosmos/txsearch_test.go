@@ -0,0 +1,59 @@
+package cosmos
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestSearchDepositTxs(t *testing.T) {
+	const memo = "0x1111111111111111111111111111111111111111:1"
+	defer func(limit int) { txSearchPageLimit = limit }(txSearchPageLimit)
+	txSearchPageLimit = 2
+
+	pages := map[string]string{
+		"0": `{"txs":[{"body":{"memo":"other:1"}},{"body":{"memo":"` + memo + `"}}],` +
+			`"tx_responses":[{"height":"100","txhash":"OTHER"},{"height":"101","txhash":"DEPOSIT1"}],"pagination":{"total":"3"}}`,
+		"2": `{"txs":[{"body":{"memo":"` + memo + `"}}],` +
+			`"tx_responses":[{"height":"102","txhash":"DEPOSIT2"}],"pagination":{"total":"3"}}`,
+	}
+	var searches int
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != TxsByEvents {
+			http.NotFound(w, r)
+			return
+		}
+		searches++
+		query := r.URL.Query()
+		events := query["events"]
+		if len(events) != 2 || events[0] != "transfer.recipient='"+tMPCAddress+"'" || events[1] != "tx.memo='"+memo+"'" {
+			t.Errorf("search events mismatch, have %v", events)
+		}
+		if limit := query.Get("pagination.limit"); limit != "2" {
+			t.Errorf("search page limit mismatch, have %v", limit)
+		}
+		page, exist := pages[query.Get("pagination.offset")]
+		if !exist {
+			t.Errorf("unexpected search offset %v", query.Get("pagination.offset"))
+			page = `{"txs":[],"tx_responses":[],"pagination":{"total":"3"}}`
+		}
+		_, _ = w.Write([]byte(page))
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"memoEvent": "tx.memo"})
+
+	txs, err := b.SearchDepositTxs(tMPCAddress, memo)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if searches != 2 {
+		t.Errorf("search should stop at the total, have %v searches", searches)
+	}
+	want := []string{"DEPOSIT1", "DEPOSIT2"}
+	if len(txs) != len(want) {
+		t.Fatalf("matched txs count mismatch, have %v want %v", len(txs), len(want))
+	}
+	for i, tx := range txs {
+		if tx.TxResponse.TxHash != want[i] || tx.Tx.Body.Memo != memo {
+			t.Errorf("matched tx %v mismatch, have %v %v", i, tx.TxResponse.TxHash, tx.Tx.Body.Memo)
+		}
+	}
+}
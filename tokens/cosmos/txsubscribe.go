@@ -0,0 +1,183 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gorilla/websocket"
+)
+
+// ErrWebsocketUnavailable the tx event can not be subscribed on any websocket endpoint
+var ErrWebsocketUnavailable = errors.New("websocket subscription unavailable")
+
+var (
+	maxWebsocketReconnects = 3
+	websocketIOTimeout     = 10 * time.Second
+)
+
+// getWebsocketEndpoints get the CometBFT websocket endpoints (eg. `ws://host:26657/websocket`),
+// which are configed by `websocketEndpoints` custom (comma separated)
+func (b *Bridge) getWebsocketEndpoints() (endpoints []string) {
+	for _, endpoint := range strings.Split(params.GetCustom(b.ChainConfig.ChainID, "websocketEndpoints"), ",") {
+		if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+type wsRequest struct {
+	JSONRPC string            `json:"jsonrpc"`
+	ID      int               `json:"id"`
+	Method  string            `json:"method"`
+	Params  map[string]string `json:"params"`
+}
+
+type wsResponse struct {
+	ID     int              `json:"id"`
+	Result *wsEventResult   `json:"result"`
+	Error  *json.RawMessage `json:"error"`
+}
+
+type wsEventResult struct {
+	Data *struct {
+		Type  string `json:"type"`
+		Value struct {
+			TxResult *struct {
+				Height string `json:"height"`
+				Result struct {
+					Code uint32 `json:"code"`
+					Log  string `json:"log"`
+				} `json:"result"`
+			} `json:"TxResult"`
+		} `json:"value"`
+	} `json:"data"`
+}
+
+// txSubscription the subscription of the tx event on a websocket connection
+type txSubscription struct {
+	conn  *websocket.Conn
+	query string
+}
+
+func txEventQuery(txHash string) string {
+	return fmt.Sprintf("tm.event='Tx' AND tx.hash='%s'", strings.ToUpper(txHash))
+}
+
+// subscribeTxEvent dial the endpoint and subscribe the tx event
+func subscribeTxEvent(endpoint, txHash string) (*txSubscription, error) {
+	dialer := &websocket.Dialer{HandshakeTimeout: websocketIOTimeout}
+	conn, _, err := dialer.Dial(endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	sub := &txSubscription{conn: conn, query: txEventQuery(txHash)}
+	if err = sub.call("subscribe"); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	_ = conn.SetReadDeadline(time.Now().Add(websocketIOTimeout))
+	var res wsResponse
+	if err = conn.ReadJSON(&res); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	if res.Error != nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("subscribe tx event failed: %s", *res.Error)
+	}
+	return sub, nil
+}
+
+func (s *txSubscription) call(method string) error {
+	_ = s.conn.SetWriteDeadline(time.Now().Add(websocketIOTimeout))
+	return s.conn.WriteJSON(&wsRequest{
+		JSONRPC: "2.0",
+		ID:      1,
+		Method:  method,
+		Params:  map[string]string{"query": s.query},
+	})
+}
+
+// close unsubscribe the tx event and close the connection
+func (s *txSubscription) close() {
+	_ = s.call("unsubscribe")
+	_ = s.conn.WriteControl(websocket.CloseMessage,
+		websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+	_ = s.conn.Close()
+}
+
+// waitEvent wait for the tx event until the deadline,
+// it returns a nil response without error if the deadline is reached.
+func (s *txSubscription) waitEvent(txHash string, deadline time.Time) (*TxResponse, error) {
+	_ = s.conn.SetReadDeadline(deadline)
+	for {
+		var res wsResponse
+		if err := s.conn.ReadJSON(&res); err != nil {
+			if time.Now().After(deadline) {
+				return nil, nil
+			}
+			return nil, err
+		}
+		if res.Error != nil {
+			return nil, fmt.Errorf("tx event subscription error: %s", *res.Error)
+		}
+		if res.Result == nil || res.Result.Data == nil || res.Result.Data.Value.TxResult == nil {
+			continue // eg. the response of subscribe
+		}
+		txResult := res.Result.Data.Value.TxResult
+		txRes := &TxResponse{
+			Height: txResult.Height,
+			TxHash: strings.ToUpper(txHash),
+			Code:   txResult.Result.Code,
+		}
+		if txRes.Code == 0 {
+			if logs, err := sdk.ParseABCILogs(txResult.Result.Log); err == nil {
+				txRes.Logs = logs
+			}
+		}
+		return txRes, nil
+	}
+}
+
+// waitTxEvent wait the tx inclusion by subscribing the tx event on the websocket endpoints.
+// the subscription is re-established on another endpoint (or the same one) if the connection drops,
+// and the tx is queried by hash after every subscription, as it may be included before subscribing.
+// It returns ErrWebsocketUnavailable if the subscription can not be (re-)established.
+func (b *Bridge) waitTxEvent(endpoints []string, txHash string, timeout time.Duration) (*TxResponse, error) {
+	deadline := time.Now().Add(timeout)
+	var err error
+	for i := 0; i <= maxWebsocketReconnects && time.Now().Before(deadline); i++ {
+		endpoint := endpoints[i%len(endpoints)]
+		var sub *txSubscription
+		sub, err = subscribeTxEvent(endpoint, txHash)
+		if err != nil {
+			log.Debug("subscribe tx event failed", "endpoint", endpoint, "txHash", txHash, "err", err)
+			continue
+		}
+
+		var txRes *TxResponse
+		if res, errf := b.GetTransactionByHash(txHash); errf == nil && res != nil && res.TxResponse != nil &&
+			strings.EqualFold(res.TxResponse.TxHash, txHash) {
+			txRes = res.TxResponse
+		} else {
+			txRes, err = sub.waitEvent(txHash, deadline)
+		}
+		sub.close()
+
+		switch {
+		case txRes != nil:
+			return checkIncludedTx(txHash, txRes, "websocket")
+		case err == nil: // deadline reached
+			return nil, fmt.Errorf("%w, txHash: %v", ErrTxInclusionTimeout, txHash)
+		}
+		log.Warn("tx event subscription dropped, reconnecting", "endpoint", endpoint, "txHash", txHash, "err", err)
+	}
+	return nil, fmt.Errorf("%w, txHash: %v, lastErr: %v", ErrWebsocketUnavailable, txHash, err)
+}
@@ -0,0 +1,132 @@
+package cosmos
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// newTestWebsocketServer returns the url of a fake CometBFT websocket,
+// which emits the tx event with code after the subscription,
+// and drops the first `drops` connections after the subscription.
+func newTestWebsocketServer(t *testing.T, drops int32, code string) (url string, unsubscribed <-chan struct{}) {
+	var conns int32
+	unsubscribes := make(chan struct{}, 10)
+	upgrader := websocket.Upgrader{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			t.Errorf("upgrade websocket failed: %v", err)
+			return
+		}
+		defer conn.Close()
+
+		var req wsRequest
+		if err = conn.ReadJSON(&req); err != nil || req.Method != "subscribe" {
+			t.Errorf("read subscribe request failed: %v %v", req.Method, err)
+			return
+		}
+		if want := txEventQuery(tInclusionTxHash); req.Params["query"] != want {
+			t.Errorf("subscribe query mismatch, have %v want %v", req.Params["query"], want)
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":{}}`))
+		if atomic.AddInt32(&conns, 1) <= drops {
+			return
+		}
+		_ = conn.WriteMessage(websocket.TextMessage, []byte(`{"jsonrpc":"2.0","id":1,"result":{"query":"`+req.Params["query"]+`",`+
+			`"data":{"type":"tendermint/event/Tx","value":{"TxResult":{"height":"100","index":0,"tx":"",`+
+			`"result":{"code":`+code+`,"log":"[]"}}}},"events":{"tx.hash":["`+tInclusionTxHash+`"]}}}`))
+		if err = conn.ReadJSON(&req); err == nil && req.Method == "unsubscribe" {
+			unsubscribes <- struct{}{}
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return "ws" + strings.TrimPrefix(srv.URL, "http"), unsubscribes
+}
+
+func setTestWebsocketEndpoints(t *testing.T, b *Bridge, endpoints string) {
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"websocketEndpoints": endpoints})
+}
+
+func TestWaitTxInclusionByWebsocket(t *testing.T) {
+	b, queries := newTestInclusionBridge(t, 100, "0")
+	url, unsubscribed := newTestWebsocketServer(t, 0, "0")
+	setTestWebsocketEndpoints(t, b, url)
+
+	txRes, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txRes.Height != "100" || txRes.Code != 0 || txRes.TxHash != tInclusionTxHash {
+		t.Errorf("tx response mismatch, have %+v", txRes)
+	}
+	// only the query right after subscribing, no polling
+	if *queries != 1 {
+		t.Errorf("tx should not be polled, have %v queries", *queries)
+	}
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Error("tx event should be unsubscribed")
+	}
+}
+
+func TestWaitTransactionStatusByWebsocket(t *testing.T) {
+	// not found by the query right after subscribing
+	b, queries := newTestInclusionBridge(t, 1, "0")
+	url, _ := newTestWebsocketServer(t, 0, "0")
+	setTestWebsocketEndpoints(t, b, url)
+	pollConfig := DefaultInclusionPollConfig
+	DefaultInclusionPollConfig = tInclusionPollConfig
+	t.Cleanup(func() { DefaultInclusionPollConfig = pollConfig })
+
+	status, err := b.WaitTransactionStatus(tInclusionTxHash)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.BlockHeight != 100 {
+		t.Errorf("tx status mismatch, have %+v", status)
+	}
+	// the query right after subscribing, and the one of the status
+	if *queries != 2 {
+		t.Errorf("tx should not be polled, have %v queries", *queries)
+	}
+}
+
+func TestWaitTxInclusionByWebsocketReconnect(t *testing.T) {
+	b, _ := newTestInclusionBridge(t, 100, "5")
+	url, _ := newTestWebsocketServer(t, 2, "5")
+	setTestWebsocketEndpoints(t, b, url)
+
+	txRes, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig)
+	if !errors.Is(err, ErrTxIncludedWithFailure) {
+		t.Fatalf("included failed tx should return %v, but have %v", ErrTxIncludedWithFailure, err)
+	}
+	if txRes == nil || txRes.Code != 5 {
+		t.Errorf("failed tx response should be returned, have %v", txRes)
+	}
+}
+
+func TestWaitTxInclusionWebsocketFallback(t *testing.T) {
+	b, queries := newTestInclusionBridge(t, 3, "0")
+	url, _ := newTestWebsocketServer(t, 100, "0")
+	setTestWebsocketEndpoints(t, b, url+",ws://127.0.0.1:1/websocket")
+
+	txRes, err := b.WaitTxInclusion(tInclusionTxHash, tInclusionPollConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if txRes.Height != "100" {
+		t.Errorf("tx response mismatch, have %+v", txRes)
+	}
+	// not found by the queries after the 2 dropped subscriptions and the 1st polling
+	if *queries != 4 {
+		t.Errorf("tx should be found by polling, have %v queries", *queries)
+	}
+}
@@ -47,6 +47,9 @@ type TxResponse struct {
 	Code uint32 `protobuf:"varint,4,opt,name=code,proto3" json:"code,omitempty"`
 	// The output of the application's logger (typed). May be non-deterministic.
 	Logs sdk.ABCIMessageLogs `protobuf:"bytes,7,rep,name=logs,proto3,castrepeated=ABCIMessageLogs" json:"logs"`
+	// Time of the previous block. For heights > 1, it's the weighted median of
+	// the timestamps of the valid votes in the block.LastCommit.
+	Timestamp string `protobuf:"bytes,12,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
 }
 
 // Tx tx
@@ -100,6 +103,14 @@ type BaseAccount struct {
 	Address       string `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
 	AccountNumber string `protobuf:"varint,3,opt,name=account_number,json=accountNumber,proto3" json:"account_number,omitempty"`
 	Sequence      string `protobuf:"varint,4,opt,name=sequence,proto3" json:"sequence,omitempty"`
+	// PubKey is nil if the account has never sent a tx
+	PubKey *AccountPubKey `protobuf:"bytes,2,opt,name=pub_key,json=pubKey,proto3" json:"pub_key,omitempty"`
+}
+
+// AccountPubKey public key recorded in account
+type AccountPubKey struct {
+	Type string `json:"@type"`
+	Key  []byte `json:"key"`
 }
 
 // QueryAllBalancesResponse balances
@@ -1,9 +1,20 @@
 package cosmos
 
 import (
+	"strings"
+	"time"
+
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
+// blockTimeLayouts timestamp layouts returned by different cosmos sdk versions
+var blockTimeLayouts = []string{
+	time.RFC3339Nano,                          // 2023-03-01T08:12:35.123456789Z, 2023-03-01T08:12:35+08:00
+	"2006-01-02T15:04:05.999999999",           // without time zone (UTC)
+	"2006-01-02 15:04:05.999999999 -0700 MST", // go time.Time.String()
+	"2006-01-02 15:04:05.999999999 -0700",
+}
+
 func ParseCoinsNormalized(coinStr string) (sdk.Coins, error) {
 	return sdk.ParseCoinsNormalized(coinStr)
 }
@@ -15,3 +26,20 @@ func ParseCoinsFee(amount string) (sdk.Coins, error) {
 		return parsedFees, nil
 	}
 }
+
+// ParseBlockTime parse block timestamp string to unix time, returns 0 if failed
+func ParseBlockTime(timestamp string) uint64 {
+	timestamp = strings.TrimSpace(timestamp)
+	if timestamp == "" {
+		return 0
+	}
+	for _, layout := range blockTimeLayouts {
+		if t, err := time.Parse(layout, timestamp); err == nil {
+			if t.Unix() < 0 {
+				return 0
+			}
+			return uint64(t.Unix())
+		}
+	}
+	return 0
+}
@@ -0,0 +1,50 @@
+package cosmos
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestParseBlockTime(t *testing.T) {
+	const want = 1677658355 // 2023-03-01T08:12:35Z
+	tests := []struct {
+		timestamp string
+		want      uint64
+	}{
+		{"2023-03-01T08:12:35Z", want},
+		{"2023-03-01T08:12:35.5Z", want},
+		{"2023-03-01T08:12:35.123456789Z", want},
+		{"2023-03-01T16:12:35.123+08:00", want},
+		{"2023-03-01T08:12:35", want},
+		{"2023-03-01T08:12:35.123456", want},
+		{"2023-03-01 08:12:35.123456789 +0000 UTC", want},
+		{"2023-03-01 16:12:35 +0800", want},
+		{" 2023-03-01T08:12:35Z\n", want},
+		{"", 0},
+		{"1677658355", 0},
+		{"2023-03-01", 0},
+		{"0001-01-01T00:00:00Z", 0},
+	}
+	for _, test := range tests {
+		if have := ParseBlockTime(test.timestamp); have != test.want {
+			t.Errorf("parse block time %q mismatch, have %v want %v", test.timestamp, have, test.want)
+		}
+	}
+}
+
+func TestGetTxBlockInfo(t *testing.T) {
+	const txHash = "7B8E3D3AF2C6FC9CE8FDB1EA8C6E0FB0E44D3D09D1C80D2F79F8B77E9B6C6A11"
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != TxByHash+txHash {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tx_response":{"height":"1234","txhash":"` + txHash + `","code":0,"timestamp":"2023-03-01T08:12:35.123456Z"}}`))
+	})
+	if height, blockTime := b.GetTxBlockInfo(txHash); height != 1234 || blockTime != 1677658355 {
+		t.Errorf("get tx block info mismatch, have height %v time %v", height, blockTime)
+	}
+	if height, blockTime := b.GetTxBlockInfo("unknown"); height != 0 || blockTime != 0 {
+		t.Errorf("get unknown tx block info should return zero, have height %v time %v", height, blockTime)
+	}
+}
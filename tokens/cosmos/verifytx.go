@@ -1,6 +1,7 @@
 package cosmos
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/big"
 	"strconv"
@@ -25,10 +26,19 @@ func (b *Bridge) VerifyMsgHash(tx interface{}, msgHashes []string) (err error) {
 	if rawTx, ok := tx.(*BuildRawTx); !ok {
 		return tokens.ErrWrongRawTx
 	} else {
+		// hash as MPCSignTransaction does, by the public key type of the chain (eg. keccak256 for eth_secp256k1)
+		signerPubKey, err := getSignerPubKey(rawTx)
+		if err != nil {
+			return err
+		}
+		pubKey, err := b.PubKeyFromStr(hex.EncodeToString(signerPubKey.Bytes()))
+		if err != nil {
+			return err
+		}
 		if signBytes, err := b.GetSignBytes(rawTx); err != nil {
 			return err
 		} else {
-			msgHash := fmt.Sprintf("%X", Sha256Sum(signBytes))
+			msgHash := fmt.Sprintf("%X", getSignHash(pubKey, signBytes))
 			if !strings.EqualFold(msgHash, msgHashes[0]) {
 				log.Warn("message hash mismatch",
 					"want", msgHashes[0], "have", string(signBytes))
@@ -78,6 +88,10 @@ func (b *Bridge) verifySwapoutTx(txHash string, logIndex int, allowUnstable bool
 			return swapInfo, err
 		}
 
+		if err := b.VerifyTransferEvents(txr.TxResponse.Logs[logIndex-1], swapInfo.ERC20SwapInfo.Token, swapInfo.Value); err != nil {
+			return swapInfo, err
+		}
+
 		if checkErr := b.checkSwapoutInfo(swapInfo); checkErr != nil {
 			return swapInfo, checkErr
 		}
@@ -122,7 +136,8 @@ func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.SwapTxInfo) error {
 		return tokens.ErrMissTokenConfig
 	}
 
-	if !tokens.CheckTokenSwapValue(swapInfo, fromTokenCfg.Decimals, toTokenCfg.Decimals) {
+	fromDecimals := b.GetTokenDecimals(fromTokenCfg)
+	if !tokens.CheckTokenSwapValue(swapInfo, fromDecimals, toTokenCfg.Decimals) {
 		return tokens.ErrTxWithWrongValue
 	}
 
@@ -0,0 +1,323 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+	codecTypes "github.com/cosmos/cosmos-sdk/codec/types"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	"github.com/gogo/protobuf/proto"
+	"github.com/gogo/protobuf/protoc-gen-gogo/descriptor"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// WasmSmartQuery cosmwasm smart query of contract, followed by `{contract}/smart/{base64 query}`
+	WasmSmartQuery = "/cosmwasm/wasm/v1/contract/"
+
+	msgExecuteContractName = "cosmwasm.wasm.v1.MsgExecuteContract"
+)
+
+// ErrCW20AllowanceNotEnough the cw20 allowance of the spender can not cover the amount
+var ErrCW20AllowanceNotEnough = errors.New("cw20 allowance not enough")
+
+// MsgExecuteContract cosmwasm execute contract msg (`cosmwasm.wasm.v1.MsgExecuteContract`).
+// It is defined here with the same proto encoding, as wasmd is not a dependency.
+type MsgExecuteContract struct {
+	Sender   string
+	Contract string
+	Msg      []byte // json encoded message
+	Funds    sdk.Coins
+}
+
+var _ sdk.Msg = &MsgExecuteContract{}
+
+// wasmFileDescriptor gzipped file descriptor of MsgExecuteContract
+var wasmFileDescriptor = buildWasmFileDescriptor()
+
+func buildWasmFileDescriptor() []byte {
+	funds := newProtoField("funds", 5, descriptor.FieldDescriptorProto_TYPE_MESSAGE)
+	funds.Label = descriptor.FieldDescriptorProto_LABEL_REPEATED.Enum()
+	funds.TypeName = proto.String(".cosmos.base.v1beta1.Coin")
+	return newProtoFileDescriptor("cosmwasm/wasm/v1/tx.proto", "cosmwasm.wasm.v1",
+		[]string{"cosmos/base/v1beta1/coin.proto"},
+		&descriptor.DescriptorProto{
+			Name: proto.String("MsgExecuteContract"),
+			Field: []*descriptor.FieldDescriptorProto{
+				newProtoField("sender", 1, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoField("contract", 2, descriptor.FieldDescriptorProto_TYPE_STRING),
+				newProtoField("msg", 3, descriptor.FieldDescriptorProto_TYPE_BYTES),
+				funds,
+			},
+		})
+}
+
+// RegisterWasmInterfaces register the cosmwasm msgs
+func RegisterWasmInterfaces(registry codecTypes.InterfaceRegistry) {
+	registry.RegisterImplementations((*sdk.Msg)(nil), &MsgExecuteContract{})
+}
+
+// Reset impl proto.Message
+func (m *MsgExecuteContract) Reset() { *m = MsgExecuteContract{} }
+
+// String impl proto.Message
+func (m *MsgExecuteContract) String() string {
+	return fmt.Sprintf("sender:%q contract:%q msg:%q funds:%v", m.Sender, m.Contract, m.Msg, m.Funds)
+}
+
+// ProtoMessage impl proto.Message
+func (*MsgExecuteContract) ProtoMessage() {}
+
+// XXX_MessageName the full proto name (used as the type url)
+func (*MsgExecuteContract) XXX_MessageName() string { //nolint:revive,stylecheck // proto convention
+	return msgExecuteContractName
+}
+
+// Descriptor gzipped file descriptor and the message index
+func (*MsgExecuteContract) Descriptor() ([]byte, []int) {
+	return wasmFileDescriptor, []int{0}
+}
+
+// ValidateBasic impl sdk.Msg
+func (m *MsgExecuteContract) ValidateBasic() error {
+	if _, err := sdk.AccAddressFromBech32(m.Sender); err != nil {
+		return fmt.Errorf("invalid sender %v, %w", m.Sender, err)
+	}
+	if _, err := sdk.AccAddressFromBech32(m.Contract); err != nil {
+		return fmt.Errorf("invalid contract %v, %w", m.Contract, err)
+	}
+	if !json.Valid(m.Msg) {
+		return fmt.Errorf("invalid execute msg, it must be json")
+	}
+	if !m.Funds.IsValid() {
+		return fmt.Errorf("invalid funds %v", m.Funds)
+	}
+	return nil
+}
+
+// GetSigners impl sdk.Msg
+func (m *MsgExecuteContract) GetSigners() []sdk.AccAddress {
+	sender, err := sdk.AccAddressFromBech32(m.Sender)
+	if err != nil {
+		panic(err)
+	}
+	return []sdk.AccAddress{sender}
+}
+
+// Marshal proto encoding
+func (m *MsgExecuteContract) Marshal() ([]byte, error) {
+	var bz []byte
+	if m.Sender != "" {
+		bz = protowire.AppendTag(bz, 1, protowire.BytesType)
+		bz = protowire.AppendString(bz, m.Sender)
+	}
+	if m.Contract != "" {
+		bz = protowire.AppendTag(bz, 2, protowire.BytesType)
+		bz = protowire.AppendString(bz, m.Contract)
+	}
+	if len(m.Msg) > 0 {
+		bz = protowire.AppendTag(bz, 3, protowire.BytesType)
+		bz = protowire.AppendBytes(bz, m.Msg)
+	}
+	for i := range m.Funds {
+		coin, err := m.Funds[i].Marshal()
+		if err != nil {
+			return nil, err
+		}
+		bz = protowire.AppendTag(bz, 5, protowire.BytesType)
+		bz = protowire.AppendBytes(bz, coin)
+	}
+	return bz, nil
+}
+
+// Unmarshal proto decoding
+func (m *MsgExecuteContract) Unmarshal(bz []byte) error {
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		if typ != protowire.BytesType || num < 1 || num > 5 || num == 4 {
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+			if n < 0 {
+				return protowire.ParseError(n)
+			}
+			bz = bz[n:]
+			continue
+		}
+		var value []byte
+		value, n = protowire.ConsumeBytes(bz)
+		if n < 0 {
+			return protowire.ParseError(n)
+		}
+		bz = bz[n:]
+		switch num {
+		case 1:
+			m.Sender = string(value)
+		case 2:
+			m.Contract = string(value)
+		case 3:
+			m.Msg = append([]byte(nil), value...)
+		case 5:
+			var coin sdk.Coin
+			if err := coin.Unmarshal(value); err != nil {
+				return err
+			}
+			m.Funds = append(m.Funds, coin)
+		}
+	}
+	return nil
+}
+
+// Size proto encoding size
+func (m *MsgExecuteContract) Size() int {
+	bz, _ := m.Marshal()
+	return len(bz)
+}
+
+// CW20TransferMsg cw20 `transfer` execute msg
+type CW20TransferMsg struct {
+	Transfer struct {
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+	} `json:"transfer"`
+}
+
+// BuildCW20TransferMsg build the execute msg of cw20 transfer
+func BuildCW20TransferMsg(from, contract, to string, amount *big.Int) (*MsgExecuteContract, error) {
+	var transfer CW20TransferMsg
+	transfer.Transfer.Recipient = to
+	transfer.Transfer.Amount = amount.String()
+	msg, err := json.Marshal(transfer)
+	if err != nil {
+		return nil, err
+	}
+	return &MsgExecuteContract{
+		Sender:   from,
+		Contract: contract,
+		Msg:      msg,
+	}, nil
+}
+
+// CW20TransferFromMsg cw20 `transfer_from` execute msg
+type CW20TransferFromMsg struct {
+	TransferFrom struct {
+		Owner     string `json:"owner"`
+		Recipient string `json:"recipient"`
+		Amount    string `json:"amount"`
+	} `json:"transfer_from"`
+}
+
+// BuildCW20TransferFromMsg build the execute msg of cw20 transfer_from,
+// which transfers the tokens of owner by the allowance of from
+func BuildCW20TransferFromMsg(from, contract, owner, to string, amount *big.Int) (*MsgExecuteContract, error) {
+	var transferFrom CW20TransferFromMsg
+	transferFrom.TransferFrom.Owner = owner
+	transferFrom.TransferFrom.Recipient = to
+	transferFrom.TransferFrom.Amount = amount.String()
+	msg, err := json.Marshal(transferFrom)
+	if err != nil {
+		return nil, err
+	}
+	return &MsgExecuteContract{
+		Sender:   from,
+		Contract: contract,
+		Msg:      msg,
+	}, nil
+}
+
+// QuerySmartContractStateResponse cosmwasm smart query result
+type QuerySmartContractStateResponse struct {
+	Data json.RawMessage `json:"data"`
+}
+
+// CW20BalanceResponse cw20 `balance` query result
+type CW20BalanceResponse struct {
+	Balance sdk.Int `json:"balance"`
+}
+
+// CW20AllowanceResponse cw20 `allowance` query result
+type CW20AllowanceResponse struct {
+	Allowance sdk.Int         `json:"allowance"`
+	Expires   json.RawMessage `json:"expires"`
+}
+
+// CW20TokenInfoResponse cw20 `token_info` query result
+type CW20TokenInfoResponse struct {
+	Name        string  `json:"name"`
+	Symbol      string  `json:"symbol"`
+	Decimals    uint8   `json:"decimals"`
+	TotalSupply sdk.Int `json:"total_supply"`
+}
+
+// QueryContractSmart query the contract state by the json query, and unmarshal the data into result
+func (b *Bridge) QueryContractSmart(contract string, query, result interface{}) error {
+	queryBytes, err := json.Marshal(query)
+	if err != nil {
+		return err
+	}
+	path := WasmSmartQuery + contract + "/smart/" + base64.URLEncoding.EncodeToString(queryBytes)
+	var res *QuerySmartContractStateResponse
+	for _, url := range b.GatewayConfig.AllGatewayURLs {
+		restApi := joinURLPath(url, path)
+		if err = client.RPCGet(&res, restApi); err == nil && res != nil {
+			return json.Unmarshal(res.Data, result)
+		}
+		log.Warn("QueryContractSmart failed", "url", restApi, "err", err)
+	}
+	return wrapRPCQueryError(err, "QueryContractSmart", contract)
+}
+
+// GetCW20Balance get the cw20 token balance of the account
+func (b *Bridge) GetCW20Balance(account, contract string) (sdk.Int, error) {
+	query := map[string]interface{}{"balance": map[string]string{"address": account}}
+	var result CW20BalanceResponse
+	if err := b.QueryContractSmart(contract, query, &result); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	if result.Balance.IsNil() {
+		return sdk.ZeroInt(), nil
+	}
+	return result.Balance, nil
+}
+
+// GetCW20Allowance get the cw20 token amount the spender is allowed to transfer from the owner
+func (b *Bridge) GetCW20Allowance(owner, spender, contract string) (sdk.Int, error) {
+	query := map[string]interface{}{"allowance": map[string]string{"owner": owner, "spender": spender}}
+	var result CW20AllowanceResponse
+	if err := b.QueryContractSmart(contract, query, &result); err != nil {
+		return sdk.ZeroInt(), err
+	}
+	if result.Allowance.IsNil() {
+		return sdk.ZeroInt(), nil
+	}
+	return result.Allowance, nil
+}
+
+// checkCW20Allowance check the cw20 allowance of spender from owner can cover amount
+func (b *Bridge) checkCW20Allowance(owner, spender, contract string, amount *big.Int) error {
+	allowance, err := b.GetCW20Allowance(owner, spender, contract)
+	if err != nil {
+		return err
+	}
+	if allowance.BigInt().Cmp(amount) < 0 {
+		return fmt.Errorf("%w, owner: %v, spender: %v, contract: %v, allowance: %v, amount: %v", ErrCW20AllowanceNotEnough, owner, spender, contract, allowance, amount)
+	}
+	return nil
+}
+
+// GetCW20TokenInfo get the cw20 token info
+func (b *Bridge) GetCW20TokenInfo(contract string) (*CW20TokenInfoResponse, error) {
+	query := map[string]interface{}{"token_info": struct{}{}}
+	var result CW20TokenInfoResponse
+	if err := b.QueryContractSmart(contract, query, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
@@ -0,0 +1,56 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/cosmos/cosmos-sdk/types/bech32"
+)
+
+func TestQueryCW20State(t *testing.T) {
+	contract, err := bech32.ConvertAndEncode("sei", make([]byte, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+	owner, spender := "sei1owner", "sei1spender"
+	b := newTestSeiBridge(t, func(w http.ResponseWriter, r *http.Request) {
+		prefix := WasmSmartQuery + contract + "/smart/"
+		if !strings.HasPrefix(r.URL.Path, prefix) {
+			t.Errorf("unexpected request %v", r.URL)
+			return
+		}
+		query, err := base64.URLEncoding.DecodeString(strings.TrimPrefix(r.URL.Path, prefix))
+		if err != nil {
+			t.Errorf("wrong query encoding %v", r.URL)
+			return
+		}
+		switch string(query) {
+		case `{"balance":{"address":"` + owner + `"}}`:
+			_, _ = w.Write([]byte(`{"data":{"balance":"123456789012345678901"}}`))
+		case `{"balance":{"address":"` + spender + `"}}`:
+			_, _ = w.Write([]byte(`{"data":{"balance":"0"}}`))
+		case `{"allowance":{"owner":"` + owner + `","spender":"` + spender + `"}}`:
+			_, _ = w.Write([]byte(`{"data":{"allowance":"5000","expires":{"never":{}}}}`))
+		default:
+			http.Error(w, "unknown query "+string(query), http.StatusBadRequest)
+		}
+	})
+
+	balance, err := b.GetCW20Balance(owner, contract)
+	if err != nil || balance.String() != "123456789012345678901" {
+		t.Errorf("cw20 balance mismatch, have %v %v", balance, err)
+	}
+	balance, err = b.GetCW20Balance(spender, contract)
+	if err != nil || !balance.IsZero() {
+		t.Errorf("cw20 zero balance mismatch, have %v %v", balance, err)
+	}
+	allowance, err := b.GetCW20Allowance(owner, spender, contract)
+	if err != nil || allowance.Int64() != 5000 {
+		t.Errorf("cw20 allowance mismatch, have %v %v", allowance, err)
+	}
+	if _, err = b.GetCW20Allowance(spender, owner, contract); err == nil {
+		t.Errorf("failed contract query should return error")
+	}
+}
@@ -81,6 +81,8 @@ var (
 	ErrNoAttestationServer    = errors.New("no attesttation server")
 	ErrGetAttestationFailed   = errors.New("get attesttation failed")
 	ErrTxWithoutSigner        = errors.New("tx without signer")
+	ErrStaleAccountData       = errors.New("tx is rejected with stale account data")
+	ErrNoRouterMPC            = errors.New("no router mpc account")
 )
 
 // errors should register in router swap
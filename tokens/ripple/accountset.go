@@ -0,0 +1,221 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// AccountSet flags used in `SetFlag` and `ClearFlag` fields
+// (these are not the same as the `Flags` transaction flags)
+const (
+	AsfRequireDest    uint32 = 1
+	AsfRequireAuth    uint32 = 2
+	AsfDisallowXRP    uint32 = 3
+	AsfDisableMaster  uint32 = 4
+	AsfAccountTxnID   uint32 = 5
+	AsfNoFreeze       uint32 = 6
+	AsfGlobalFreeze   uint32 = 7
+	AsfDefaultRipple  uint32 = 8
+	AsfDepositAuth    uint32 = 9
+	maxAccountSetFlag        = AsfDepositAuth
+
+	// maxDomainLength the max length of account Domain in bytes
+	maxDomainLength = 256
+)
+
+var (
+	// ErrInvalidAccountSet invalid AccountSet tx content
+	ErrInvalidAccountSet = errors.New("invalid account set")
+	// ErrMissCancelSequence the sequence to cancel is not specified
+	ErrMissCancelSequence = errors.New("miss sequence to cancel")
+	// ErrSequenceConsumed the sequence to cancel is already consumed
+	ErrSequenceConsumed = errors.New("sequence is already consumed")
+)
+
+// AccountSetBuilder builds AccountSet tx to manage the account settings
+type AccountSetBuilder struct {
+	setFlag   *uint32
+	clearFlag *uint32
+	domain    *data.VariableLength
+	emailHash *data.Hash128
+	noop      bool
+}
+
+// NewAccountSetBuilder new AccountSet builder
+func NewAccountSetBuilder() *AccountSetBuilder {
+	return &AccountSetBuilder{}
+}
+
+// SetFlag enable the account flag (one of the `Asf` flags)
+func (s *AccountSetBuilder) SetFlag(flag uint32) *AccountSetBuilder {
+	s.setFlag = &flag
+	return s
+}
+
+// ClearFlag disable the account flag (one of the `Asf` flags)
+func (s *AccountSetBuilder) ClearFlag(flag uint32) *AccountSetBuilder {
+	s.clearFlag = &flag
+	return s
+}
+
+// SetDomain set the account domain, empty domain removes it
+func (s *AccountSetBuilder) SetDomain(domain string) *AccountSetBuilder {
+	vl := data.VariableLength(domain)
+	s.domain = &vl
+	return s
+}
+
+// SetEmailHash set the account email hash, zero hash removes it
+func (s *AccountSetBuilder) SetEmailHash(hash data.Hash128) *AccountSetBuilder {
+	s.emailHash = &hash
+	return s
+}
+
+// NoOp build a no-op AccountSet which changes nothing but consumes the sequence
+func (s *AccountSetBuilder) NoOp() *AccountSetBuilder {
+	s.noop = true
+	return s
+}
+
+// Validate check the account settings
+func (s *AccountSetBuilder) Validate() error {
+	isEmpty := s.setFlag == nil && s.clearFlag == nil && s.domain == nil && s.emailHash == nil
+	if s.noop {
+		if !isEmpty {
+			return fmt.Errorf("%w: no-op changes the settings", ErrInvalidAccountSet)
+		}
+		return nil
+	}
+	if isEmpty {
+		return fmt.Errorf("%w: nothing to set", ErrInvalidAccountSet)
+	}
+	if s.setFlag != nil && (*s.setFlag == 0 || *s.setFlag > maxAccountSetFlag) {
+		return fmt.Errorf("%w: unknown set flag %v", ErrInvalidAccountSet, *s.setFlag)
+	}
+	if s.clearFlag != nil && (*s.clearFlag == 0 || *s.clearFlag > maxAccountSetFlag) {
+		return fmt.Errorf("%w: unknown clear flag %v", ErrInvalidAccountSet, *s.clearFlag)
+	}
+	if s.setFlag != nil && s.clearFlag != nil && *s.setFlag == *s.clearFlag {
+		return fmt.Errorf("%w: set and clear the same flag %v", ErrInvalidAccountSet, *s.setFlag)
+	}
+	if s.domain != nil && len(*s.domain) > maxDomainLength {
+		return fmt.Errorf("%w: domain length %v exceeds %v", ErrInvalidAccountSet, len(*s.domain), maxDomainLength)
+	}
+	return nil
+}
+
+// BuildAccountSetTransaction build AccountSet tx to manage the settings of the mpc account from.
+// It is not a swap payout, so it has no swap memo, and its sequence is the account sequence on chain
+// (not allocated from the swap sequences, the payouts of the account should be paused meanwhile).
+// It can not be signed by `MPCSignTransaction`, as the accept nodes verify the signing by rebuilding the swap payout,
+// it is signed by the operator approved signing as the `sendAccountSetTx` tool does.
+func (b *Bridge) BuildAccountSetTransaction(from string, setter *AccountSetBuilder) (rawTx interface{}, err error) {
+	if from == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	sequence, err := b.GetPoolNonce(from, "pending")
+	if err != nil {
+		return nil, err
+	}
+	fee, err := b.getTxFee()
+	if err != nil {
+		return nil, err
+	}
+	return b.buildAccountSet(from, sequence, fee, "", setter)
+}
+
+// BuildCancelSequenceTransaction build a no-op AccountSet tx on the sequence (`Extra.Sequence` of args)
+// allocated to a swap which must be aborted, so that the sequence is consumed deliberately
+// and the sequences of the subsequent swaps are not gapped. The memo records the unique swap identifier of the canceled swap.
+// As BuildAccountSetTransaction, it can not be signed by `MPCSignTransaction`,
+// it is signed by the operator approved signing as the `sendAccountSetTx` tool does.
+func (b *Bridge) BuildCancelSequenceTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	if args.Extra == nil || args.Extra.Sequence == nil {
+		return nil, ErrMissCancelSequence
+	}
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	sequence := *args.Extra.Sequence
+	accountSeq, err := b.GetPoolNonce(args.From, "pending")
+	if err != nil {
+		return nil, err
+	}
+	if sequence < accountSeq {
+		return nil, fmt.Errorf("%w, account: %v, sequence: %v, account sequence: %v", ErrSequenceConsumed, args.From, sequence, accountSeq)
+	}
+	var fee string
+	if args.Extra.Fee != nil {
+		fee = *args.Extra.Fee
+	} else if fee, err = b.getTxFee(); err != nil {
+		return nil, err
+	}
+	log.Info("build tx to cancel sequence", "account", args.From, "sequence", sequence, "swapID", args.SwapID)
+	return b.buildAccountSet(args.From, sequence, fee, b.getSwapMemo(args), NewAccountSetBuilder().NoOp())
+}
+
+// buildAccountSet build AccountSet tx signed by the mpc public key of from
+func (b *Bridge) buildAccountSet(from string, sequence uint64, fee, memo string, setter *AccountSetBuilder) (rawTx interface{}, err error) {
+	mpcPubkey := router.GetMPCPublicKey(from)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
+	return setter.Build(ripplePubKey, nil, uint32(sequence), fee, memo)
+}
+
+// Build build unsigned AccountSet tx
+func (s *AccountSetBuilder) Build(
+	key crypto.Key, keyseq *uint32, txseq uint32,
+	fee, memo string,
+) (data.Transaction, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	tx := &data.AccountSet{
+		SetFlag:   s.setFlag,
+		ClearFlag: s.clearFlag,
+		Domain:    s.domain,
+		EmailHash: s.emailHash,
+	}
+	tx.TransactionType = data.ACCOUNT_SET
+
+	if memo != "" {
+		memoStr := new(data.Memo)
+		memoStr.Memo.MemoData = []byte(memo)
+		tx.Memos = append(tx.Memos, *memoStr)
+	}
+
+	base := tx.GetBase()
+
+	base.Sequence = txseq
+
+	fei, err := ParseNativeFee(fee)
+	if err != nil {
+		return nil, err
+	}
+	base.Fee = *fei
+
+	copy(base.Account[:], key.Id(keyseq))
+
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
+	hash, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Build unsigned account set tx success",
+		"setFlag", s.setFlag, "clearFlag", s.clearFlag, "noop", s.noop, "memo", memo,
+		"fee", fee, "sequence", txseq,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
@@ -0,0 +1,159 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestAccountSetBuilder(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge()
+
+	tx, err := NewAccountSetBuilder().SetFlag(AsfDefaultRipple).SetDomain("example.com").Build(key, nil, 5, "12", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountSet, ok := tx.(*data.AccountSet)
+	if !ok {
+		t.Fatalf("tx should be AccountSet, but have %T", tx)
+	}
+	if accountSet.TransactionType != data.ACCOUNT_SET {
+		t.Errorf("tx type mismatch, have %v", accountSet.TransactionType)
+	}
+	if accountSet.SetFlag == nil || *accountSet.SetFlag != AsfDefaultRipple || accountSet.ClearFlag != nil {
+		t.Errorf("set DefaultRipple flag mismatch, have %v %v", accountSet.SetFlag, accountSet.ClearFlag)
+	}
+	if accountSet.Domain == nil || string(*accountSet.Domain) != "example.com" {
+		t.Errorf("domain mismatch, have %v", accountSet.Domain)
+	}
+	if accountSet.Sequence != 5 || accountSet.Fee.String() != "0.000012" {
+		t.Errorf("sequence or fee mismatch, have %v %v", accountSet.Sequence, accountSet.Fee)
+	}
+	stx, _, err := b.SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatalf("sign account set tx failed: %v", err)
+	}
+	if err = VerifySignedTransactionEncoding(stx.(data.Transaction)); err != nil {
+		t.Errorf("verify account set tx encoding failed: %v", err)
+	}
+
+	tx, err = NewAccountSetBuilder().ClearFlag(AsfRequireDest).Build(key, nil, 6, "12", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountSet = tx.(*data.AccountSet)
+	if accountSet.ClearFlag == nil || *accountSet.ClearFlag != AsfRequireDest || accountSet.SetFlag != nil {
+		t.Errorf("clear RequireDest flag mismatch, have %v %v", accountSet.SetFlag, accountSet.ClearFlag)
+	}
+
+	invalids := []*AccountSetBuilder{
+		NewAccountSetBuilder(),
+		NewAccountSetBuilder().SetFlag(0),
+		NewAccountSetBuilder().ClearFlag(maxAccountSetFlag + 1),
+		NewAccountSetBuilder().SetFlag(AsfDefaultRipple).ClearFlag(AsfDefaultRipple),
+	}
+	for i, invalid := range invalids {
+		if _, err = invalid.Build(key, nil, 7, "12", ""); !errors.Is(err, ErrInvalidAccountSet) {
+			t.Errorf("build invalid account set %v should fail with %v, but have %v", i, ErrInvalidAccountSet, err)
+		}
+	}
+}
+
+func TestBuildCancelSequenceTransaction(t *testing.T) {
+	const mpc = "rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz" // of tSeed (ecdsa)
+	const mpcPubkey = "0x03D49C56E1B185F1BE899AE66A02EFC17F78EA6FC53AF85E0FE54C6E8B7F8C71A8"
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			res := accountInfoResult(mpc, "100000000").(map[string]interface{})
+			res["account_data"].(map[string]interface{})["Sequence"] = 10
+			return res
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	router.SetMPCPublicKey(mpc, mpcPubkey)
+
+	fee := "0.000012"
+	newArgs := func(sequence *uint64) *tokens.BuildTxArgs {
+		args := &tokens.BuildTxArgs{From: mpc, Extra: &tokens.AllExtras{Sequence: sequence, Fee: &fee}}
+		args.SwapID = "0x1111111111111111111111111111111111111111111111111111111111111111"
+		return args
+	}
+
+	stuck := uint64(12)
+	rawTx, err := b.BuildCancelSequenceTransaction(newArgs(&stuck))
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountSet, ok := rawTx.(*data.AccountSet)
+	if !ok {
+		t.Fatalf("tx should be AccountSet, but have %T", rawTx)
+	}
+	if accountSet.Sequence != 12 || accountSet.Account.String() != mpc || accountSet.Fee.String() != fee {
+		t.Errorf("account set sequence, account or fee mismatch, have %v %v %v", accountSet.Sequence, accountSet.Account, accountSet.Fee)
+	}
+	if accountSet.SetFlag != nil || accountSet.ClearFlag != nil || accountSet.Domain != nil || accountSet.EmailHash != nil {
+		t.Errorf("cancel sequence tx should be no-op, but have %+v", accountSet)
+	}
+	if len(accountSet.Memos) != 1 || string(accountSet.Memos[0].Memo.MemoData.Bytes()) != newArgs(nil).GetUniqueSwapIdentifier() {
+		t.Errorf("cancel sequence tx memo mismatch, have %v", accountSet.Memos)
+	}
+
+	consumed := uint64(9)
+	if _, err = b.BuildCancelSequenceTransaction(newArgs(&consumed)); !errors.Is(err, ErrSequenceConsumed) {
+		t.Errorf("cancel consumed sequence should fail with %v, but have %v", ErrSequenceConsumed, err)
+	}
+	if _, err = b.BuildCancelSequenceTransaction(newArgs(nil)); !errors.Is(err, ErrMissCancelSequence) {
+		t.Errorf("cancel without sequence should fail with %v, but have %v", ErrMissCancelSequence, err)
+	}
+	if _, err = NewAccountSetBuilder().NoOp().SetFlag(AsfDefaultRipple).Build(nil, nil, 1, fee, ""); !errors.Is(err, ErrInvalidAccountSet) {
+		t.Errorf("no-op changing settings should fail with %v, but have %v", ErrInvalidAccountSet, err)
+	}
+}
+
+func TestBuildAccountSetTransaction(t *testing.T) {
+	const mpc = "rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz" // of tSeed (ecdsa)
+	const mpcPubkey = "0x03D49C56E1B185F1BE899AE66A02EFC17F78EA6FC53AF85E0FE54C6E8B7F8C71A8"
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			res := accountInfoResult(mpc, "100000000").(map[string]interface{})
+			res["account_data"].(map[string]interface{})["Sequence"] = 10
+			return res
+		case "fee":
+			return map[string]interface{}{"drops": map[string]interface{}{"minimum_fee": "15"}}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	router.SetMPCPublicKey(mpc, mpcPubkey)
+
+	// not a swap payout, the sequence is the account sequence and there is no swap memo
+	rawTx, err := b.BuildAccountSetTransaction(mpc, NewAccountSetBuilder().SetFlag(AsfDefaultRipple))
+	if err != nil {
+		t.Fatal(err)
+	}
+	accountSet, ok := rawTx.(*data.AccountSet)
+	if !ok {
+		t.Fatalf("tx should be AccountSet, but have %T", rawTx)
+	}
+	if accountSet.Sequence != 10 || accountSet.Account.String() != mpc || accountSet.Fee.String() != "0.000015" {
+		t.Errorf("account set sequence, account or fee mismatch, have %v %v %v", accountSet.Sequence, accountSet.Account, accountSet.Fee)
+	}
+	if len(accountSet.Memos) != 0 {
+		t.Errorf("account set tx should have no memo, have %v", accountSet.Memos)
+	}
+	if _, err = b.BuildAccountSetTransaction("", NewAccountSetBuilder().SetFlag(AsfDefaultRipple)); err == nil {
+		t.Error("account set tx with empty sender should fail")
+	}
+}
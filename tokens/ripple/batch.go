@@ -0,0 +1,107 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ErrEmptyBatch the batch has no build args
+	ErrEmptyBatch = errors.New("empty batch")
+	// ErrBatchInParallelMode the sequence is allocated per swap in parallel mode, a range can not be reserved
+	ErrBatchInParallelMode = errors.New("batch build is not supported in parallel swap mode")
+	// ErrBatchSenderMismatch the sender is not the same as the first one of the batch
+	ErrBatchSenderMismatch = errors.New("batch sender mismatch")
+	// ErrBatchWithSequence the build args of a batch has an explicit sequence
+	ErrBatchWithSequence = errors.New("batch build args has explicit sequence")
+)
+
+// BatchTxResult the result of building a tx of the batch
+type BatchTxResult struct {
+	Index    int
+	RawTx    interface{}
+	Sequence uint64 // only set if the tx is built
+	Err      error
+}
+
+// batchTxBuilder build a tx with the allocated sequence
+type batchTxBuilder func(args *tokens.BuildTxArgs, sequence *uint64) (interface{}, error)
+
+// BuildBatch build the txs of the same sender with a contiguous sequence range,
+// which is allocated and reserved under one lock, so that concurrent builds never interleave.
+// every built tx is individually signable. a failed tx is reported in the `Err` of its result
+// and does not consume a sequence, so there are no gaps within the built txs of the batch.
+// the returned error is set only if the whole batch fails (eg. the sequence can not be allocated).
+func (b *Bridge) BuildBatch(args []*tokens.BuildTxArgs) ([]*BatchTxResult, error) {
+	return b.buildBatch(args, b.buildRawTransaction)
+}
+
+func (b *Bridge) buildBatch(args []*tokens.BuildTxArgs, build batchTxBuilder) ([]*BatchTxResult, error) {
+	if len(args) == 0 {
+		return nil, ErrEmptyBatch
+	}
+	if params.IsParallelSwapEnabled() {
+		return nil, ErrBatchInParallelMode
+	}
+	sender := args[0].From
+
+	b.seqLock.Lock()
+	defer b.seqLock.Unlock()
+
+	start, err := b.allocateBatchSequence(sender, uint64(len(args)))
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]*BatchTxResult, len(args))
+	next := start
+	for i, arg := range args {
+		result := &BatchTxResult{Index: i}
+		results[i] = result
+		switch {
+		case !common.IsEqualIgnoreCase(arg.From, sender):
+			result.Err = fmt.Errorf("%w, have %v want %v", ErrBatchSenderMismatch, arg.From, sender)
+		case arg.Extra != nil && arg.Extra.Sequence != nil:
+			result.Err = fmt.Errorf("%w %v", ErrBatchWithSequence, *arg.Extra.Sequence)
+		default:
+			sequence := next
+			result.RawTx, result.Err = build(arg, &sequence)
+			if result.Err == nil {
+				result.Sequence = sequence
+				next++
+			}
+		}
+		if result.Err != nil {
+			log.Warn("build tx of batch failed", "chainID", b.ChainConfig.ChainID, "index", i, "swapID", arg.SwapID, "err", result.Err)
+		}
+	}
+
+	if next > start {
+		b.SetNonce(sender, next)
+	}
+	log.Info("build batch finished", "chainID", b.ChainConfig.ChainID, "sender", sender,
+		"count", len(args), "built", next-start, "firstSequence", start, "nextSequence", next)
+	return results, nil
+}
+
+// allocateBatchSequence get the start of the sequence range of the batch,
+// the whole range must not reach `maxSequenceGap` beyond the account sequence.
+func (b *Bridge) allocateBatchSequence(sender string, count uint64) (uint64, error) {
+	if params.IsAutoSwapNonceEnabled(b.ChainConfig.ChainID) {
+		return b.GetSwapNonce(sender), nil
+	}
+	accountSeq, err := b.GetPoolNonce(sender, "pending")
+	if err != nil {
+		return 0, err
+	}
+	start := b.AdjustNonce(sender, accountSeq)
+	if err = b.checkAllocatedSequenceGap(sender, start+count-1, accountSeq); err != nil {
+		return 0, err
+	}
+	return start, nil
+}
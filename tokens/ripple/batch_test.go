@@ -0,0 +1,138 @@
+package ripple
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// newTestBatchBuilder returns a builder of payments, which fails the args with swap ID `fail`
+func newTestBatchBuilder(t *testing.T, b *Bridge) batchTxBuilder {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return func(args *tokens.BuildTxArgs, sequence *uint64) (interface{}, error) {
+		if args.SwapID == "fail" {
+			return nil, errors.New("build failed")
+		}
+		extra, err := b.setExtraArgsWithSequence(args, sequence)
+		if err != nil {
+			return nil, err
+		}
+		return NewUnsignedPaymentTransaction(key, nil, uint32(*extra.Sequence), tReceiver, nil, "1000000", *extra.Fee, args.SwapID, "", 0)
+	}
+}
+
+func newTestBatchArgs(swapIDs ...string) []*tokens.BuildTxArgs {
+	fee := "0.000012"
+	args := make([]*tokens.BuildTxArgs, len(swapIDs))
+	for i, swapID := range swapIDs {
+		feeCopy := fee
+		args[i] = &tokens.BuildTxArgs{From: tSender, Extra: &tokens.AllExtras{Fee: &feeCopy}}
+		args[i].SwapID = swapID
+	}
+	return args
+}
+
+func TestBuildBatchWithoutGaps(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method != "account_info" {
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		return accountInfoResult(tSender, "20000000") // account sequence is 1
+	})
+	build := newTestBatchBuilder(t, b)
+
+	if _, err := b.buildBatch(nil, build); !errors.Is(err, ErrEmptyBatch) {
+		t.Errorf("empty batch should fail with %v, but have %v", ErrEmptyBatch, err)
+	}
+
+	args := newTestBatchArgs("s0", "fail", "s2", "s3", "s4")
+	args[3].From = tReceiver
+	explicit := uint64(30)
+	args[4].Extra.Sequence = &explicit
+	args = append(args, newTestBatchArgs("s5")...)
+
+	results, err := b.buildBatch(args, build)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantErrs := []error{nil, nil, nil, ErrBatchSenderMismatch, ErrBatchWithSequence, nil}
+	wantSeqs := []uint64{1, 0, 2, 0, 0, 3}
+	for i, result := range results {
+		if result.Index != i || result.Sequence != wantSeqs[i] {
+			t.Errorf("result %v mismatch, have index %v sequence %v want sequence %v", i, result.Index, result.Sequence, wantSeqs[i])
+		}
+		if wantSeqs[i] == 0 {
+			if result.Err == nil || result.RawTx != nil || (wantErrs[i] != nil && !errors.Is(result.Err, wantErrs[i])) {
+				t.Errorf("result %v should fail with %v, but have %v %v", i, wantErrs[i], result.RawTx, result.Err)
+			}
+			continue
+		}
+		payment, ok := result.RawTx.(*data.Payment)
+		if result.Err != nil || !ok {
+			t.Errorf("result %v should be a payment, but have %T %v", i, result.RawTx, result.Err)
+			continue
+		}
+		if uint64(payment.Sequence) != wantSeqs[i] || string(payment.Memos[0].Memo.MemoData.Bytes()) != args[i].SwapID {
+			t.Errorf("result %v payment mismatch, have sequence %v memo %v", i, payment.Sequence, payment.Memos)
+		}
+	}
+	if nonce := b.GetSwapNonce(tSender); nonce != 4 {
+		t.Errorf("the batch range should be reserved, have next sequence %v want 4", nonce)
+	}
+
+	// the next batch continues from the reserved range
+	results, err = b.buildBatch(newTestBatchArgs("t0"), build)
+	if err != nil || results[0].Err != nil || results[0].Sequence != 4 {
+		t.Errorf("next batch should start from 4, but have %+v %v", results[0], err)
+	}
+}
+
+func TestBuildBatchConcurrent(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		return accountInfoResult(tSender, "20000000") // account sequence is 1
+	})
+	build := newTestBatchBuilder(t, b)
+
+	const batches, batchSize = 8, 5
+	ranges := make([][]uint64, batches)
+	var wg sync.WaitGroup
+	for i := 0; i < batches; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results, err := b.buildBatch(newTestBatchArgs("a", "b", "c", "d", "e"), build)
+			if err != nil {
+				t.Errorf("batch %v failed: %v", i, err)
+				return
+			}
+			for _, result := range results {
+				if result.Err != nil {
+					t.Errorf("batch %v tx %v failed: %v", i, result.Index, result.Err)
+				}
+				ranges[i] = append(ranges[i], result.Sequence)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i][0] < ranges[j][0] })
+	want := uint64(1)
+	for i, seqs := range ranges {
+		for _, seq := range seqs {
+			if seq != want {
+				t.Fatalf("batch %v sequences should be contiguous and disjoint, have %v want %v", i, seqs, want)
+			}
+			want++
+		}
+	}
+	if want != 1+batches*batchSize {
+		t.Errorf("total sequences mismatch, have %v want %v", want-1, batches*batchSize)
+	}
+}
@@ -1,15 +1,15 @@
 package ripple
 
 import (
+	"encoding/json"
 	"math/big"
+	"strconv"
 	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
-	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/base"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
 )
 
@@ -38,14 +38,34 @@ const (
 type Bridge struct {
 	*base.NonceSetterBase
 	RPCClientTimeout int
+
+	ledgerCache *ledgerIndexCache
+	signLimiter *mpcSignLimiter
+	reserves    *reservesCache
+	rpcLimiters *sync.Map // url -> *rpcLimiter
+
+	seqLock           sync.Mutex // guards the sequence allocation of GetSeq and BuildBatch
+	refillMonitorOnce sync.Once
 }
 
 // NewCrossChainBridge new bridge
 func NewCrossChainBridge() *Bridge {
-	return &Bridge{
+	b := &Bridge{
 		NonceSetterBase:  base.NewNonceSetterBase(),
 		RPCClientTimeout: 60,
 	}
+	b.ledgerCache = newLedgerIndexCache(b.GetLatestValidatedLedger)
+	b.signLimiter = newMPCSignLimiter(b.getMaxConcurrentMPCSign)
+	b.reserves = new(reservesCache)
+	b.rpcLimiters = new(sync.Map)
+	return b
+}
+
+// SetChainConfig set chain config, and rebuild the rpc limiters
+// as the `rpcRateLimit` customs may be changed by reloading the config
+func (b *Bridge) SetChainConfig(chainCfg *tokens.ChainConfig) {
+	b.CrossChainBridgeBase.SetChainConfig(chainCfg)
+	b.resetRPCLimiters()
 }
 
 // SupportsChainID supports chainID
@@ -99,7 +119,7 @@ func (b *Bridge) GetLatestBlockNumberOf(url string) (num uint64, err error) {
 	rpcParams := map[string]interface{}{}
 	for i := 0; i < rpcRetryTimes; i++ {
 		var res *websockets.LedgerCurrentResult
-		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "ledger_current", rpcParams)
+		err = b.rpcPost(&res, url, "ledger_current", rpcParams)
 		if err == nil && res != nil {
 			return uint64(res.LedgerSequence), nil
 		}
@@ -107,6 +127,41 @@ func (b *Bridge) GetLatestBlockNumberOf(url string) (num uint64, err error) {
 	return 0, wrapRPCQueryError(err, "GetLatestBlockNumber")
 }
 
+// ValidatedLedgerResult result of ledger of `validated` ledger index
+type ValidatedLedgerResult struct {
+	LedgerIndex json.Number `json:"ledger_index"`
+	Validated   bool        `json:"validated"`
+}
+
+// GetLatestValidatedLedger gets latest validated ledger index.
+// Confirmations should only be counted with validated ledgers,
+// as the current ledger is provisional and may still change.
+func (b *Bridge) GetLatestValidatedLedger() (num uint64, err error) {
+	rpcParams := map[string]interface{}{
+		"ledger_index": "validated",
+	}
+	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *ValidatedLedgerResult
+			err = b.rpcPost(&res, url, "ledger", rpcParams)
+			if err != nil || res == nil {
+				continue
+			}
+			if !res.Validated {
+				err = tokens.ErrTxIsNotValidated
+				continue
+			}
+			num, err = strconv.ParseUint(res.LedgerIndex.String(), 10, 64)
+			if err == nil {
+				return num, nil
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return 0, wrapRPCQueryError(err, "GetLatestValidatedLedger")
+}
+
 // GetTransaction impl
 func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
 	return b.GetTransactionByHash(txHash)
@@ -117,11 +172,11 @@ func (b *Bridge) GetTransactionByHash(txHash string) (txRes *websockets.TxResult
 	rpcParams := map[string]interface{}{
 		"transaction": txHash,
 	}
-	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	urls := b.getTxQueryURLs()
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.TxResult
-			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "tx", rpcParams)
+			err = b.rpcPost(&res, url, "tx", rpcParams)
 			if err == nil && res != nil {
 				return res, nil
 			}
@@ -146,17 +201,28 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus, e
 		return nil, errTxResultType
 	}
 
+	// the result of a tx in a not validated ledger is provisional
+	if !txres.Validated {
+		log.Debug("Ripple tx is not validated", "txHash", txHash, "ledger", txres.LedgerSequence)
+		return nil, tokens.ErrTxIsNotValidated
+	}
+
 	// Check tx status
 	if !txres.TransactionWithMetaData.MetaData.TransactionResult.Success() {
 		log.Warn("Ripple tx status is not success", "result", txres.TransactionWithMetaData.MetaData.TransactionResult)
 		return nil, tokens.ErrTxWithWrongStatus
 	}
 
+	if err = b.checkDeliveredAmount(&txres.TransactionWithMetaData); err != nil {
+		log.Warn("Ripple tx delivered amount is not enough", "txHash", txHash, "err", err)
+		return nil, err
+	}
+
 	status.Receipt = nil
 	inledger := txres.LedgerSequence
 	status.BlockHeight = uint64(inledger)
 
-	if latest, err := b.GetLatestBlockNumber(); err == nil && latest > uint64(inledger) {
+	if latest, err := b.GetLatestLedgerNumber(); err == nil && latest > uint64(inledger) {
 		status.Confirmations = latest - uint64(inledger)
 	}
 	return status, nil
@@ -186,7 +252,7 @@ func (b *Bridge) GetAccount(address string) (acctRes *websockets.AccountInfoResu
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.AccountInfoResult
-			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "account_info", rpcParams)
+			err = b.rpcPost(&res, url, "account_info", rpcParams)
 			if err == nil && res != nil {
 				return res, nil
 			}
@@ -196,8 +262,8 @@ func (b *Bridge) GetAccount(address string) (acctRes *websockets.AccountInfoResu
 	return nil, wrapRPCQueryError(err, "GetAccount")
 }
 
-// GetAccountLine get account line
-func (b *Bridge) GetAccountLine(currency, issuer, accountAddress string) (line *data.AccountLine, err error) {
+// GetAccountLine get the trust line of the account with the issuer, including the per-line flags
+func (b *Bridge) GetAccountLine(currency, issuer, accountAddress string) (line *TrustLine, err error) {
 	rpcParams := map[string]interface{}{
 		"account":      accountAddress,
 		"peer":         issuer,
@@ -205,14 +271,14 @@ func (b *Bridge) GetAccountLine(currency, issuer, accountAddress string) (line *
 		"ledger_index": "current",
 	}
 	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
-	var acclRes *websockets.AccountLinesResult
+	var acclRes *accountLinesResult
 PAGE_LOOP:
 	for {
 	RETRY_LOOP:
 		for i := 0; i < rpcRetryTimes; i++ {
 			for _, url := range urls {
-				var res *websockets.AccountLinesResult
-				err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "account_lines", rpcParams)
+				var res *accountLinesResult
+				err = b.rpcPost(&res, url, "account_lines", rpcParams)
 				if err == nil && res != nil {
 					acclRes = res
 					break RETRY_LOOP
@@ -248,7 +314,7 @@ func (b *Bridge) GetFee() (feeRes *websockets.FeeResult, err error) {
 	for i := 0; i < rpcRetryTimes; i++ {
 		for _, url := range urls {
 			var res *websockets.FeeResult
-			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &res, url, "fee", rpcParams)
+			err = b.rpcPost(&res, url, "fee", rpcParams)
 			if err == nil && res != nil {
 				return res, nil
 			}
@@ -0,0 +1,130 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const tTxHash = "C53ECF838647FA5A4C780377025FEC7999AB4182590510CA461444B207AB74A9"
+
+func tTxResult(validated bool) json.RawMessage {
+	validatedStr := "false"
+	if validated {
+		validatedStr = "true"
+	}
+	return json.RawMessage(`{
+		"Account": "` + tSender + `",
+		"Amount": "1000000",
+		"Destination": "` + tReceiver + `",
+		"Fee": "12",
+		"Flags": 2147483648,
+		"Sequence": 7,
+		"SigningPubKey": "0330E7FC9D56BB25D6893BA3F317AE5BCF33B3291BD63DB32654A313222F7FD020",
+		"TransactionType": "Payment",
+		"TxnSignature": "3045022100D184EB4AE5956FF600E7536EE459345C7BBCF097A84CC61A93B9AF7197EDB98702201CEA8009B7BEEBAA2AACC0359B41C427C1C5B550A4CA4B80CF2174AF2D6D5DCE",
+		"hash": "` + tTxHash + `",
+		"ledger_index": 1000,
+		"meta": {
+			"AffectedNodes": [],
+			"TransactionIndex": 0,
+			"TransactionResult": "tesSUCCESS",
+			"delivered_amount": "1000000"
+		},
+		"validated": ` + validatedStr + `
+	}`)
+}
+
+func TestTxStatusOnlyCountsValidatedLedgers(t *testing.T) {
+	var txValidated, ledgerValidated bool
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "tx":
+			return tTxResult(txValidated)
+		case "ledger":
+			return map[string]interface{}{"ledger_index": 1005, "validated": ledgerValidated}
+		case "ledger_current":
+			return map[string]interface{}{"ledger_current_index": 1010}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	defer SetRPCRetryTimes(rpcRetryTimes)
+	SetRPCRetryTimes(1)
+	defer func(interval time.Duration) { rpcRetryInterval = interval }(rpcRetryInterval)
+	rpcRetryInterval = 10 * time.Millisecond
+
+	// tx in a not validated ledger is unconfirmed
+	status, err := b.GetTransactionStatus(tTxHash)
+	if !errors.Is(err, tokens.ErrTxIsNotValidated) || status.IsSwapTxOnChain() {
+		t.Errorf("not validated tx should be unconfirmed, but have status %v err %v", status, err)
+	}
+	if height, _ := b.GetTxBlockInfo(tTxHash); height != 0 {
+		t.Errorf("not validated tx should have no block height, but have %v", height)
+	}
+
+	// confirmations are counted with the latest validated ledger
+	txValidated, ledgerValidated = true, true
+	status, err = b.GetTransactionStatus(tTxHash)
+	if err != nil {
+		t.Fatalf("get validated tx status failed: %v", err)
+	}
+	if status.BlockHeight != 1000 || status.Confirmations != 5 {
+		t.Errorf("validated tx status mismatch, have height %v confirmations %v", status.BlockHeight, status.Confirmations)
+	}
+
+	// not validated latest ledger is not counted
+	ledgerValidated = false
+	if _, err = b.GetLatestValidatedLedger(); err == nil {
+		t.Error("get not validated latest ledger should fail")
+	}
+	b.ledgerCache = newLedgerIndexCache(b.GetLatestValidatedLedger)
+	status, err = b.GetTransactionStatus(tTxHash)
+	if err != nil {
+		t.Fatalf("get validated tx status failed: %v", err)
+	}
+	if status.Confirmations != 0 {
+		t.Errorf("confirmations should not be counted with not validated ledger, but have %v", status.Confirmations)
+	}
+}
+
+func TestWaitTransactionStatus(t *testing.T) {
+	var txQueries, validatedAfter int
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "tx":
+			txQueries++
+			return tTxResult(txQueries > validatedAfter)
+		case "ledger":
+			return map[string]interface{}{"ledger_index": 1005, "validated": true}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"statusPollInterval": "0.01", "statusPollTimeout": "0.1"})
+
+	// validated before timeout
+	validatedAfter = 3
+	status, err := b.WaitTransactionStatus(tTxHash)
+	if err != nil {
+		t.Fatalf("wait tx status should succeed before timeout, but have %v", err)
+	}
+	if status.BlockHeight != 1000 || txQueries != validatedAfter+1 {
+		t.Errorf("wait tx status mismatch, have height %v after %v queries", status.BlockHeight, txQueries)
+	}
+
+	// still pending when timeout
+	txQueries, validatedAfter = 0, 1000
+	start := time.Now()
+	if _, err = b.WaitTransactionStatus(tTxHash); !errors.Is(err, ErrTxStatusTimeout) {
+		t.Errorf("wait pending tx status should fail with %v, but have %v", ErrTxStatusTimeout, err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second || txQueries < 5 {
+		t.Errorf("wait tx status should poll until timeout, have %v queries in %v", txQueries, elapsed)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"strconv"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -18,13 +19,42 @@ var (
 	defaultFee       int64  = 10
 	accountReserve          = big.NewInt(10000000)
 	tfPartialPayment uint32 = 0x00020000
+
+	// ErrInsufficientXRPForFees the sender has not enough XRP to pay fee and keep reserve
+	ErrInsufficientXRPForFees = errors.New("insufficient XRP for fees")
+	// ErrIssuerFrozen the issuer has frozen the IOU globally or the sender's trust line
+	ErrIssuerFrozen = errors.New("issuer frozen")
+	// ErrNonDefaultLineQuality the trust line quality would alter the delivered amount
+	ErrNonDefaultLineQuality = errors.New("trust line has non default quality")
+	// ErrPaymentBelowBaseReserve the payment to an unfunded account is less than the base reserve
+	ErrPaymentBelowBaseReserve = errors.New("payment to unfunded account is less than base reserve")
+	// ErrTrustLineLimitExceeded the receiver's trust line can not accept the issued amount
+	ErrTrustLineLimitExceeded = errors.New("trust line limit exceeded")
+	// ErrTrustLineNotAuthorized the issuer requires auth but has not authorized the receiver's trust line
+	ErrTrustLineNotAuthorized = errors.New("trust line is not authorized by issuer")
+	// ErrNoRippleThroughIssuer the issuer set no ripple on both the sender's and the receiver's trust lines
+	ErrNoRippleThroughIssuer = errors.New("issuer does not allow rippling between the trust lines")
+	// ErrMissSigningKey the unsigned tx is built without the signing key
+	ErrMissSigningKey = errors.New("miss signing key")
+	// ErrSequenceGapTooLarge the allocated sequence is too far beyond the account sequence
+	ErrSequenceGapTooLarge = errors.New("allocated sequence gap is too large")
+	// ErrSequenceBelowAccount the explicit sequence is below the account sequence and is already consumed
+	ErrSequenceBelowAccount = errors.New("explicit sequence is below account sequence")
 )
 
 // BuildRawTransaction build raw tx
-//nolint:funlen,gocyclo // ok
 func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
-	if !params.IsTestMode && args.ToChainID.String() != b.ChainConfig.ChainID {
-		return nil, tokens.ErrToChainIDMismatch
+	return b.buildRawTransaction(args, nil)
+}
+
+// buildRawTransaction build raw tx, use the allocated sequence if it is not nil (eg. in a batch)
+//nolint:funlen,gocyclo // ok
+func (b *Bridge) buildRawTransaction(args *tokens.BuildTxArgs, allocated *uint64) (rawTx interface{}, err error) {
+	if args.ToChainID.String() != b.ChainConfig.ChainID {
+		if !params.IsTestMode {
+			return nil, tokens.ErrToChainIDMismatch
+		}
+		log.Warn("TEST MODE: bypass to chain ID check", "swapID", args.SwapID, "toChainID", args.ToChainID, "chainID", b.ChainConfig.ChainID)
 	}
 	if args.From == "" {
 		return nil, fmt.Errorf("forbid empty sender")
@@ -38,10 +68,9 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, tokens.ErrSenderMismatch
 	}
 
-	switch args.SwapType {
-	case tokens.ERC20SwapType:
-	default:
-		return nil, tokens.ErrSwapTypeNotSupported
+	payout, err := b.getPayout(args)
+	if err != nil {
+		return nil, err
 	}
 
 	mpcPubkey := router.GetMPCPublicKey(args.From)
@@ -49,36 +78,25 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, tokens.ErrMissMPCPublicKey
 	}
 
-	erc20SwapInfo := args.ERC20SwapInfo
-	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, args.ToChainID.String())
-	if multichainToken == "" {
-		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", args.ToChainID)
-		return nil, tokens.ErrMissTokenConfig
-	}
-
-	token := b.GetTokenConfig(multichainToken)
-	if token == nil {
-		return nil, tokens.ErrMissTokenConfig
-	}
+	token, asset, receiver, toTag := payout.token, payout.asset, payout.receiver, payout.toTag
+	amount, amt := payout.amount, payout.amt
+	args.SwapValue = amount // SwapValue
 
-	assetI, exist := assetMap.Load(token.ContractAddress)
-	if !exist {
-		return nil, fmt.Errorf("non exist asset %v", token.ContractAddress)
+	paths, err := b.getPaymentPaths(args, token, asset)
+	if err != nil {
+		return nil, err
 	}
-	asset := assetI.(*data.Asset)
-
-	receiver, toTag, amount, err := b.getReceiverAndAmount(args, multichainToken)
+	cond, err := b.getDeliveryConditions(token, asset, args.From, receiver, paths)
 	if err != nil {
 		return nil, err
 	}
-	args.SwapValue = amount // SwapValue
-
-	amt, err := getPaymentAmount(amount, token)
+	mode, err := resolveDeliveryMode(cond)
 	if err != nil {
 		return nil, err
 	}
 
-	if asset.IsNative() {
+	var sendMax *data.Amount // set if the payment is cross currency or the issuer charges transfer fee
+	if mode == DeliveryNative {
 		needAmount := new(big.Int).Add(amount, b.getMinReserveFee())
 		err = b.checkNativeBalance(args.From, needAmount, true)
 		if err != nil {
@@ -93,28 +111,160 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		if err != nil {
 			return nil, err
 		}
-		err = b.checkNonNativeBalance(asset.Currency, asset.Issuer, args.From, receiver, amt)
+		switch {
+		case args.Extra != nil && args.Extra.SendMax != nil:
+			sendMax, err = data.NewAmount(*args.Extra.SendMax)
+		case cond.Paths != "":
+			sendMax, err = b.getPathSendMax(token, asset, args.From, receiver, amount, amt)
+		case cond.TransferFee:
+			sendMax, err = b.getSendMax(asset, args.From, amount, token)
+		}
+		if err != nil {
+			return nil, err
+		}
+		// the SendMax is passed in the args so that the accept nodes rebuild the same tx
+		if sendMax != nil {
+			if args.Extra == nil {
+				args.Extra = &tokens.AllExtras{}
+			}
+			sendMaxStr := sendMax.String()
+			args.Extra.SendMax = &sendMaxStr
+		}
+		if sendMax == nil && cond.needSendMax() {
+			return nil, fmt.Errorf("%w, delivery mode: %v, token: %v", ErrMissSendMax, mode, token.TokenID)
+		}
+		cost := amt
+		if sendMax != nil {
+			cost = sendMax
+		}
+		if asset.Matches(cost) {
+			err = b.checkNonNativeBalance(asset.Currency, asset.Issuer, args.From, receiver, cost)
+		} else {
+			// cross currency, the path is found by ripple_path_find already
+			err = b.checkSendAssetBalance(args.From, cost)
+		}
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	extra, err := b.setExtraArgs(args)
+	extra, err := b.setExtraArgsWithSequence(args, allocated)
 	if err != nil {
 		return nil, err
 	}
+	if mode != DeliveryNative {
+		err = b.checkFeeBalance(args.From, *extra.Fee)
+		if err != nil {
+			return nil, err
+		}
+	}
 
 	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
-	memo := args.GetUniqueSwapIdentifier()
+	memo := b.getSwapMemo(args)
+
+	useCheck, err := b.useCheckDelivery(token, receiver)
+	if err != nil {
+		return nil, err
+	}
+	if useCheck {
+		// the expiration is passed in the args so that the accept nodes rebuild the same tx
+		if extra.Expiration == nil {
+			if extra.Expiration, err = b.getCheckExpiration(); err != nil {
+				return nil, err
+			}
+		}
+		checkSendMax := amt
+		if sendMax != nil && asset.Matches(sendMax) {
+			checkSendMax = sendMax
+		}
+		tx, errf := NewUnsignedCheckCreateTransaction(
+			ripplePubKey, nil, uint32(*extra.Sequence),
+			receiver, toTag, checkSendMax.String(), *extra.Fee, memo, extra.Expiration)
+		if errf != nil {
+			return nil, errf
+		}
+		setLastLedgerSequence(tx, extra)
+		b.setInvoiceID(tx, args)
+		return tx, nil
+	}
 
 	flags := uint32(0)
-	if token.ContractVersion == uint64(tfPartialPayment) {
-		flags = uint32(tfPartialPayment)
+	if mode == DeliveryPartial {
+		flags = tfPartialPayment
 	}
 
-	return NewUnsignedPaymentTransaction(
+	tx, err := NewUnsignedPaymentTransaction(
 		ripplePubKey, nil, uint32(*extra.Sequence),
-		receiver, toTag, amt.String(), *extra.Fee, memo, "", flags)
+		receiver, toTag, amt.String(), *extra.Fee, memo, paths, flags)
+	if err != nil {
+		return nil, err
+	}
+	if sendMax != nil {
+		tx.(*data.Payment).SendMax = sendMax
+	}
+	if mode == DeliveryPartial {
+		if tx.(*data.Payment).DeliverMin, err = b.getDeliverMin(amount, token); err != nil {
+			return nil, err
+		}
+	}
+	setLastLedgerSequence(tx, extra)
+	b.setInvoiceID(tx, args)
+	return tx, nil
+}
+
+// payout the payout derived from the build args
+type payout struct {
+	token    *tokens.TokenConfig
+	asset    *data.Asset
+	receiver string
+	toTag    *uint32
+	amount   *big.Int
+	amt      *data.Amount
+}
+
+func (b *Bridge) getPayout(args *tokens.BuildTxArgs) (*payout, error) {
+	switch args.SwapType {
+	case tokens.ERC20SwapType:
+	default:
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+
+	erc20SwapInfo := args.ERC20SwapInfo
+	multichainToken := router.GetCachedMultichainToken(erc20SwapInfo.TokenID, args.ToChainID.String())
+	if multichainToken == "" {
+		log.Warn("get multichain token failed", "tokenID", erc20SwapInfo.TokenID, "chainID", args.ToChainID)
+		return nil, tokens.ErrMissTokenConfig
+	}
+
+	token := b.GetTokenConfig(multichainToken)
+	if token == nil {
+		return nil, tokens.ErrMissTokenConfig
+	}
+
+	assetI, exist := assetMap.Load(token.ContractAddress)
+	if !exist {
+		return nil, fmt.Errorf("non exist asset %v", token.ContractAddress)
+	}
+	asset := assetI.(*data.Asset)
+
+	receiver, toTag, amount, err := b.getReceiverAndAmount(args, multichainToken)
+	if err != nil {
+		return nil, err
+	}
+
+	amt, err := getPaymentAmount(amount, token)
+	if err != nil {
+		return nil, err
+	}
+
+	return &payout{
+		token:    token,
+		asset:    asset,
+		receiver: receiver,
+		toTag:    toTag,
+		amount:   amount,
+		amt:      amt,
+	}, nil
 }
 
 func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken string) (receiver string, destTag *uint32, amount *big.Int, err error) {
@@ -126,6 +276,17 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 		log.Warn("swapout to wrong receiver", "receiver", args.Bind)
 		return receiver, destTag, amount, errors.New("can not swapout to empty or invalid receiver")
 	}
+	if err = b.checkReceiverList(receiver); err != nil {
+		return receiver, destTag, amount, err
+	}
+	if err = b.checkSpecialReceiver(receiver); err != nil {
+		return receiver, destTag, amount, err
+	}
+	destTag, err = b.checkDestinationTag(args.GetTokenID(), receiver, destTag)
+	if err != nil {
+		log.Warn("swapout with wrong destination tag", "swapID", args.SwapID, "bind", args.Bind, "err", err)
+		return receiver, destTag, amount, err
+	}
 	fromBridge := router.GetBridgeByChainID(args.FromChainID.String())
 	if fromBridge == nil {
 		return receiver, destTag, amount, tokens.ErrNoBridgeForChainID
@@ -140,10 +301,29 @@ func (b *Bridge) getReceiverAndAmount(args *tokens.BuildTxArgs, multichainToken
 	if toTokenCfg == nil {
 		return receiver, destTag, amount, tokens.ErrMissTokenConfig
 	}
+	asset, err := convertToAsset(toTokenCfg.ContractAddress)
+	if err != nil {
+		return receiver, destTag, amount, err
+	}
+	if err = b.checkIssuerReceiver(receiver, asset); err != nil {
+		return receiver, destTag, amount, err
+	}
 	amount = tokens.CalcSwapValue(erc20SwapInfo.TokenID, args.FromChainID.String(), b.ChainConfig.ChainID, args.OriginValue, fromTokenCfg.Decimals, toTokenCfg.Decimals, args.OriginFrom, args.OriginTxTo)
+	if err = checkPaymentValue(amount, args.OriginValue); err != nil {
+		log.Warn("swapout with non positive amount", "swapID", args.SwapID, "originValue", args.OriginValue, "amount", amount)
+		return receiver, destTag, amount, err
+	}
 	return receiver, destTag, amount, err
 }
 
+// checkPaymentValue the swap value left after deducting fees must be positive
+func checkPaymentValue(amount, originValue *big.Int) error {
+	if amount == nil || amount.Sign() <= 0 {
+		return fmt.Errorf("%w: payment amount %v is not positive (origin value %v)", tokens.ErrTxWithWrongValue, amount, originValue)
+	}
+	return nil
+}
+
 func getPaymentAmount(amount *big.Int, token *tokens.TokenConfig) (*data.Amount, error) {
 	assetI, exist := assetMap.Load(token.ContractAddress)
 	if !exist {
@@ -198,39 +378,127 @@ func (b *Bridge) getMinReserveFee() *big.Int {
 }
 
 func (b *Bridge) setExtraArgs(args *tokens.BuildTxArgs) (*tokens.AllExtras, error) {
+	return b.setExtraArgsWithSequence(args, nil)
+}
+
+// setExtraArgsWithSequence set the extra args, the allocated sequence (if not nil)
+// is already reserved by the caller and is used without checking.
+func (b *Bridge) setExtraArgsWithSequence(args *tokens.BuildTxArgs, allocated *uint64) (*tokens.AllExtras, error) {
 	if args.Extra == nil {
 		args.Extra = &tokens.AllExtras{}
 	}
 	extra := args.Extra
 	extra.EthExtra = nil // clear this which may be set in replace job
 
-	if extra.Sequence == nil {
+	if allocated != nil {
+		extra.Sequence = allocated
+	} else if extra.Sequence == nil {
 		seq, err := b.GetSeq(args)
 		if err != nil {
 			log.Warn("get sequence failed", "err", err)
 			return nil, err
 		}
 		extra.Sequence = seq
+	} else if extra.SequenceOverride {
+		// the sequence allocated by the swap server (or reused by replacing) is not checked
+		if err := b.checkSequenceOverride(args.From, *extra.Sequence); err != nil {
+			return nil, err
+		}
 	}
 
 	if extra.Fee == nil {
-		feeRes, err := b.GetFee()
+		fee, err := b.getTxFee()
 		if err != nil {
-			log.Warn("get fee failed", "err", err)
 			return nil, err
 		}
-		feeAmount := feeRes.Drops.MinimumFee.Drops()
-		if feeAmount < defaultFee {
-			feeAmount = defaultFee
-		}
-		feeVal, _ := data.NewNativeValue(feeAmount)
-		fee := feeVal.String()
 		extra.Fee = &fee
 	}
 
+	// the accept nodes rebuild the tx with the expiry ledger in the args,
+	// only the swap server recalculates it when replacing as the old one may have passed
+	if extra.ExpiryHeight == nil || (extra.ReplaceNum > 0 && params.IsSwapServer) {
+		expiry, err := b.getLastLedgerSequence()
+		if err != nil {
+			return nil, err
+		}
+		extra.ExpiryHeight = expiry
+	}
+
 	return extra, nil
 }
 
+// getTxFee get the minimum tx fee (not less than the default fee)
+func (b *Bridge) getTxFee() (string, error) {
+	feeRes, err := b.GetFee()
+	if err != nil {
+		log.Warn("get fee failed", "err", err)
+		return "", err
+	}
+	feeAmount := feeRes.Drops.MinimumFee.Drops()
+	if feeAmount < defaultFee {
+		feeAmount = defaultFee
+	}
+	feeVal, _ := data.NewNativeValue(feeAmount)
+	return feeVal.String(), nil
+}
+
+// defaultMaxSequenceGap default max gap between the explicit (or allocated) sequence and the account sequence
+const defaultMaxSequenceGap uint64 = 100
+
+// getMaxSequenceGap get the max gap between the sequence and the account sequence,
+// which is configed by `maxSequenceGap` custom (default to defaultMaxSequenceGap)
+func (b *Bridge) getMaxSequenceGap() (uint64, error) {
+	maxGapStr := params.GetCustom(b.ChainConfig.ChainID, "maxSequenceGap")
+	if maxGapStr == "" {
+		return defaultMaxSequenceGap, nil
+	}
+	maxGap, err := common.GetUint64FromStr(maxGapStr)
+	if err != nil {
+		return 0, fmt.Errorf("wrong maxSequenceGap %v", maxGapStr)
+	}
+	return maxGap, nil
+}
+
+// checkSequenceOverride the explicit sequence is authoritative (bypass `GetSeq` and `AdjustNonce`),
+// but it must not be below the account sequence (the tx fails with `tefPAST_SEQ`),
+// nor far beyond it (configed by `maxSequenceGap` custom), otherwise the tx can not be applied until the gap is filled.
+func (b *Bridge) checkSequenceOverride(account string, sequence uint64) error {
+	accountSeq, err := b.GetPoolNonce(account, "pending")
+	if err != nil {
+		return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get account sequence failed")
+	}
+	maxGap, err := b.getMaxSequenceGap()
+	if err != nil {
+		return err
+	}
+	log.Warn("build tx with explicit sequence", "chainID", b.ChainConfig.ChainID, "account", account,
+		"sequence", sequence, "accountSequence", accountSeq, "maxGap", maxGap)
+	if sequence < accountSeq {
+		return fmt.Errorf("%w, sequence: %v, account sequence: %v", ErrSequenceBelowAccount, sequence, accountSeq)
+	}
+	if sequence > accountSeq+maxGap {
+		return fmt.Errorf("explicit sequence %v is too far beyond account sequence %v (max gap %v)", sequence, accountSeq, maxGap)
+	}
+	return nil
+}
+
+// checkAllocatedSequenceGap refuse to allocate the sequence once the gap between it and the account sequence
+// reaches `maxSequenceGap`, as many in-flight or stuck txs are ahead and the account may be stalled.
+func (b *Bridge) checkAllocatedSequenceGap(account string, sequence, accountSeq uint64) error {
+	maxGap, err := b.getMaxSequenceGap()
+	if err != nil {
+		return err
+	}
+	if sequence < accountSeq+maxGap {
+		return nil
+	}
+	log.Error("allocated sequence is too far beyond account sequence, refuse to build tx",
+		"chainID", b.ChainConfig.ChainID, "account", account,
+		"sequence", sequence, "accountSequence", accountSeq, "maxGap", maxGap)
+	return fmt.Errorf("%w, account: %v, sequence: %v, account sequence: %v, max gap: %v",
+		ErrSequenceGapTooLarge, account, sequence, accountSeq, maxGap)
+}
+
 func (b *Bridge) checkNativeBalance(account string, amount *big.Int, isPay bool) error {
 	balance, err := b.GetBalance(account)
 	if err != nil && balance == nil {
@@ -246,41 +514,194 @@ func (b *Bridge) checkNativeBalance(account string, amount *big.Int, isPay bool)
 		}
 	}
 
-	if remain.Cmp(accountReserve) < 0 {
+	baseReserve := b.getReserves().Base
+	if remain.Cmp(baseReserve) < 0 {
 		if isPay {
 			return fmt.Errorf("insufficient native balance, sender: %v", account)
 		}
+		if balance.Sign() == 0 && amount != nil {
+			// the receiver is not funded yet, the payment creates the account
+			// and it must deliver at least the base reserve.
+			return fmt.Errorf("%w, receiver: %v, amount: %v, base reserve: %v", ErrPaymentBelowBaseReserve, account, amount, baseReserve)
+		}
 		return fmt.Errorf("insufficient native balance, receiver: %v", account)
 	}
 
 	return nil
 }
 
+// checkFeeBalance checks sender's XRP balance can cover the tx fee plus the current account reserve
+// (the base reserve plus the owner reserve of the owned objects), as fees must be paid in XRP even if the payout is an IOU.
+func (b *Bridge) checkFeeBalance(account, fee string) error {
+	if !params.IsSwapServer {
+		return nil
+	}
+	feeVal, err := ParseNativeFee(fee)
+	if err != nil {
+		return err
+	}
+	acct, err := b.GetAccount(account)
+	if err != nil {
+		return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get sender XRP balance failed")
+	}
+	accountData := acct.AccountData
+	balance := big.NewInt(0)
+	if accountData.Balance != nil {
+		balance.SetInt64(accountData.Balance.Drops())
+	}
+	var ownerCount uint32
+	if accountData.OwnerCount != nil {
+		ownerCount = *accountData.OwnerCount
+	}
+
+	need := b.getReserves().AccountReserve(ownerCount)
+	need.Add(need, big.NewInt(feeVal.Drops()))
+	if balance.Cmp(need) < 0 {
+		log.Error("insufficient XRP for fees", "chainID", b.ChainConfig.ChainID, "account", account, "balance", balance, "need", need)
+		return fmt.Errorf("%w, account: %v, balance: %v, need: %v", ErrInsufficientXRPForFees, account, balance, need)
+	}
+
+	b.checkBalanceRefill(account, balance, ownerCount)
+	return nil
+}
+
 func (b *Bridge) checkNonNativeBalance(currency, issuer, account, receiver string, amount *data.Amount) error {
 	if !params.IsSwapServer {
 		return nil
 	}
-	_, err := b.GetAccountLine(currency, issuer, receiver)
+	receiverLine, err := b.GetAccountLine(currency, issuer, receiver)
 	if err != nil {
 		log.Error("get receiver account line failed", "currency", currency, "issuer", issuer, "receiver", receiver, "err", err)
 		return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get receiver account line failed")
 	}
+	if err = b.checkLineQuality("quality_in", receiverLine.QualityIn, receiver, currency, issuer); err != nil {
+		return err
+	}
 
 	if issuer == account {
+		// the issuer can issue as many as the receiver's trust line accepts
+		if capacity := receiverLineCapacity(receiverLine); capacity.Cmp(amount.Value.Rat()) < 0 {
+			return fmt.Errorf("%w, currency: %v, issuer: %v, receiver: %v, capacity: %v, amount: %v",
+				ErrTrustLineLimitExceeded, currency, issuer, receiver, capacity.FloatString(6), amount.Value)
+		}
 		return nil
 	}
 
+	if err = b.checkIssuerFlags(issuer, receiver, receiverLine); err != nil {
+		return err
+	}
+
 	accl, err := b.GetAccountLine(currency, issuer, account)
 	if err != nil {
 		return fmt.Errorf("sender account line: %w", err)
 	}
-	if accl.Balance.Value.Compare(*amount.Value) < 0 {
-		return fmt.Errorf("insufficient %v balance, issuer: %v, account: %v", currency, issuer, account)
+	if accl.IsFrozenByIssuer() {
+		return fmt.Errorf("%w, trust line is frozen, currency: %v, issuer: %v, account: %v", ErrIssuerFrozen, currency, issuer, account)
+	}
+	if accl.IsNoRippleByIssuer() && receiverLine.IsNoRippleByIssuer() {
+		return fmt.Errorf("%w, currency: %v, issuer: %v, account: %v, receiver: %v", ErrNoRippleThroughIssuer, currency, issuer, account, receiver)
+	}
+	if err = b.checkLineQuality("quality_out", accl.QualityOut, account, currency, issuer); err != nil {
+		return err
+	}
+	if available := holderAvailable(accl); available.Cmp(amount.Value.Rat()) < 0 {
+		return fmt.Errorf("insufficient %v balance, issuer: %v, account: %v, balance: %v", currency, issuer, account, accl.Balance.Value)
 	}
 
 	return nil
 }
 
+// checkSendAssetBalance checks sender's balance of the source asset can cover the SendMax of the cross currency payment
+func (b *Bridge) checkSendAssetBalance(account string, sendMax *data.Amount) error {
+	if !params.IsSwapServer {
+		return nil
+	}
+	if sendMax.IsNative() {
+		needAmount := new(big.Int).Add(big.NewInt(sendMax.Drops()), b.getMinReserveFee())
+		return b.checkNativeBalance(account, needAmount, true)
+	}
+	currency, issuer := sendMax.Currency.String(), sendMax.Issuer.String()
+	if issuer == account {
+		return nil
+	}
+	accl, err := b.GetAccountLine(currency, issuer, account)
+	if err != nil {
+		return fmt.Errorf("sender account line: %w", err)
+	}
+	if accl.IsFrozenByIssuer() {
+		return fmt.Errorf("%w, trust line is frozen, currency: %v, issuer: %v, account: %v", ErrIssuerFrozen, currency, issuer, account)
+	}
+	if available := holderAvailable(accl); available.Cmp(sendMax.Value.Rat()) < 0 {
+		return fmt.Errorf("insufficient %v balance, issuer: %v, account: %v, balance: %v", currency, issuer, account, accl.Balance.Value)
+	}
+	return nil
+}
+
+// holderAvailable the IOUs the holder can send on its trust line with the issuer.
+// The balance is from the holder's perspective, a negative balance means
+// the holder is a net debtor (it owes the issuer) and has nothing to send.
+func holderAvailable(line *TrustLine) *big.Rat {
+	balance := line.Balance.Value.Rat()
+	if balance.Sign() <= 0 {
+		return new(big.Rat)
+	}
+	return balance
+}
+
+// receiverLineCapacity the IOUs the receiver can still accept on its trust line with the issuer.
+// The balance is from the receiver's perspective, a negative balance (the receiver owes the issuer)
+// is paid off first and increases the capacity.
+func receiverLineCapacity(line *TrustLine) *big.Rat {
+	capacity := new(big.Rat).Sub(line.Limit.Value.Rat(), line.Balance.Value.Rat())
+	if capacity.Sign() <= 0 {
+		return new(big.Rat)
+	}
+	return capacity
+}
+
+// lineQualityOne trust line quality of 1:1 (quality is in parts per billion)
+const lineQualityOne uint32 = 1000000000
+
+// IsDefaultLineQuality quality of 0 or 1e9 means 1:1
+func IsDefaultLineQuality(quality uint32) bool {
+	return quality == 0 || quality == lineQualityOne
+}
+
+// checkLineQuality non default trust line quality alters the amount delivered,
+// warn it and reject it if `rejectNonDefaultQuality` custom is set to true.
+func (b *Bridge) checkLineQuality(name string, quality uint32, account, currency, issuer string) error {
+	if IsDefaultLineQuality(quality) {
+		return nil
+	}
+	rate := float64(quality) / float64(lineQualityOne)
+	log.Warn("trust line has non default quality", "name", name, "quality", quality, "rate", rate,
+		"account", account, "currency", currency, "issuer", issuer, "chainID", b.ChainConfig.ChainID)
+	if reject, _ := strconv.ParseBool(params.GetCustom(b.ChainConfig.ChainID, "rejectNonDefaultQuality")); reject {
+		return fmt.Errorf("%w, %v: %v, account: %v, currency: %v, issuer: %v", ErrNonDefaultLineQuality, name, quality, account, currency, issuer)
+	}
+	return nil
+}
+
+// checkIssuerFlags IOUs of globally frozen issuer can only be sent to the issuer,
+// and if the issuer requires auth, the receiver's trust line must be authorized.
+func (b *Bridge) checkIssuerFlags(issuer, receiver string, receiverLine *TrustLine) error {
+	acct, err := b.GetAccount(issuer)
+	if err != nil {
+		return fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get issuer account failed")
+	}
+	flags := acct.AccountData.Flags
+	if flags == nil {
+		return nil
+	}
+	if *flags&data.LsGlobalFreeze != 0 {
+		return fmt.Errorf("%w, global freeze is set, issuer: %v", ErrIssuerFrozen, issuer)
+	}
+	if *flags&data.LsRequireAuth != 0 && !receiverLine.PeerAuthorized {
+		return fmt.Errorf("%w, issuer: %v, receiver: %v", ErrTrustLineNotAuthorized, issuer, receiver)
+	}
+	return nil
+}
+
 // GetTxBlockInfo impl NonceSetter interface
 func (b *Bridge) GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64) {
 	txStatus, err := b.GetTransactionStatus(txHash)
@@ -307,6 +728,10 @@ func (b *Bridge) GetPoolNonce(address, _height string) (uint64, error) {
 func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error) {
 	var nonce uint64
 
+	// wait for the batch which is reserving a sequence range
+	b.seqLock.Lock()
+	defer b.seqLock.Unlock()
+
 	if params.IsParallelSwapEnabled() {
 		nonce, err = b.AllocateNonce(args)
 		return &nonce, err
@@ -317,11 +742,14 @@ func (b *Bridge) GetSeq(args *tokens.BuildTxArgs) (nonceptr *uint64, err error)
 		return &nonce, nil
 	}
 
-	nonce, err = b.GetPoolNonce(args.From, "pending")
+	accountSeq, err := b.GetPoolNonce(args.From, "pending")
 	if err != nil {
 		return nil, err
 	}
-	nonce = b.AdjustNonce(args.From, nonce)
+	nonce = b.AdjustNonce(args.From, accountSeq)
+	if err = b.checkAllocatedSequenceGap(args.From, nonce, accountSeq); err != nil {
+		return nil, err
+	}
 	return &nonce, nil
 }
 
@@ -331,13 +759,16 @@ func NewUnsignedPaymentTransaction(
 	dest string, destinationTag *uint32,
 	amt, fee, memo, path string, flags uint32,
 ) (data.Transaction, error) {
+	if key == nil {
+		return nil, ErrMissSigningKey
+	}
 	destination, err := data.NewAccountFromAddress(dest)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w, wrong destination %v", err, dest)
 	}
 	amount, err := data.NewAmount(amt)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w, wrong amount %v", err, amt)
 	}
 	tx := &data.Payment{
 		Destination:    *destination,
@@ -358,7 +789,7 @@ func NewUnsignedPaymentTransaction(
 	if path != "" {
 		tx.Paths, err = ParsePaths(path)
 		if err != nil {
-			return nil, err
+			return nil, fmt.Errorf("%w, wrong paths %v", err, path)
 		}
 	}
 
@@ -366,7 +797,7 @@ func NewUnsignedPaymentTransaction(
 
 	base.Sequence = txseq
 
-	fei, err := data.NewValue(fee, true)
+	fei, err := ParseNativeFee(fee)
 	if err != nil {
 		return nil, err
 	}
@@ -378,7 +809,7 @@ func NewUnsignedPaymentTransaction(
 	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
 	hash, msg, err := data.SigningHash(tx)
 	if err != nil {
-		return nil, err
+		return nil, fmt.Errorf("%w, get signing hash failed", err)
 	}
 	log.Info("Build unsigned payment tx success",
 		"destination", dest, "amount", amt, "memo", memo,
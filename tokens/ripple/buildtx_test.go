@@ -0,0 +1,574 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const tSender = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+
+// newTestRippleBridge returns a bridge whose rpc calls are served by handler,
+// which is given the rpc method and returns the `result` part of the response.
+func newTestRippleBridge(t *testing.T, handler func(method string, params []map[string]interface{}) interface{}) *Bridge {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage          `json:"id"`
+			Method string                   `json:"method"`
+			Params []map[string]interface{} `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode rpc request failed: %v", err)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"jsonrpc": "2.0",
+			"id":      req.ID,
+			"result":  handler(req.Method, req.Params),
+		})
+	}))
+	t.Cleanup(srv.Close)
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{srv.URL}})
+	return b
+}
+
+// setTestCustoms set the customs of the chain, they are reset when the test finishes
+func setTestCustoms(t *testing.T, chainID string, customs map[string]string) {
+	err := params.SetExtraConfig(&params.ExtraConfig{
+		Customs: map[string]map[string]string{chainID: customs},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = params.SetExtraConfig(&params.ExtraConfig{}) })
+}
+
+func accountInfoResult(account, balance string) interface{} {
+	return map[string]interface{}{
+		"ledger_current_index": 100,
+		"account_data": map[string]interface{}{
+			"Account":  account,
+			"Balance":  balance,
+			"Sequence": 1,
+		},
+	}
+}
+
+func TestCheckFeeBalance(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = false
+	// the account holds plenty of IOU, but only a little more XRP than the reserve
+	var queries int
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		queries++
+		switch method {
+		case "server_state":
+			return serverStateResult()
+		case "account_info":
+			result := accountInfoResult(tSender, "1200005").(map[string]interface{})
+			result["account_data"].(map[string]interface{})["OwnerCount"] = 1
+			return result
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	if err := b.checkFeeBalance(tSender, "10"); err != nil || queries != 0 {
+		t.Errorf("fee balance should not be checked if not swap server, have %v queries, err %v", queries, err)
+	}
+
+	// reserve is 1 XRP base plus 1 object of 0.2 XRP
+	params.IsSwapServer = true
+	err := b.checkFeeBalance(tSender, "10")
+	if !errors.Is(err, ErrInsufficientXRPForFees) {
+		t.Errorf("check fee balance should fail with %v, but have %v", ErrInsufficientXRPForFees, err)
+	}
+
+	err = b.checkFeeBalance(tSender, "0.000005")
+	if err != nil {
+		t.Errorf("check fee balance failed: %v", err)
+	}
+
+	if err = b.checkFeeBalance(tSender, "12abc"); !errors.Is(err, ErrInvalidFee) {
+		t.Errorf("check fee balance should fail with %v, but have %v", ErrInvalidFee, err)
+	}
+}
+
+func TestFeeBalanceRefillAlert(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = true
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method == "server_state" {
+			return serverStateResult()
+		}
+		return accountInfoResult(tSender, "20000000")
+	})
+	chainID := b.GetChainConfig().ChainID
+	setTestCustoms(t, chainID, map[string]string{"refillFeeBuffer": "10000000", "refillPendingPayouts": "2"})
+
+	var hooked *RefillAlert
+	defer func(hook func(*RefillAlert)) { RefillAlertHook = hook }(RefillAlertHook)
+	RefillAlertHook = func(alert *RefillAlert) { hooked = alert }
+
+	// threshold is the reserve of 1 XRP base, and 2 pending payouts of 10 XRP
+	if err := b.checkFeeBalance(tSender, "10"); err != nil {
+		t.Fatalf("check fee balance failed: %v", err)
+	}
+	if hooked == nil || hooked.Account != tSender || hooked.Balance.Int64() != 20000000 || hooked.Threshold.Int64() != 21000000 {
+		t.Errorf("refill alert mismatch, have %+v", hooked)
+	}
+}
+
+func TestPaymentToUnfundedReceiver(t *testing.T) {
+	var receiverBalance string
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "server_state":
+			return serverStateResult()
+		case "account_info":
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		if receiverBalance == "" {
+			return map[string]interface{}{"error": "actNotFound", "status": "error"}
+		}
+		return accountInfoResult(tReceiver, receiverBalance)
+	})
+
+	// the base reserve of the validated ledger
+	baseReserve := big.NewInt(1000000)
+	below := new(big.Int).Sub(baseReserve, big.NewInt(1))
+
+	// unfunded receiver, the payment must be at least the base reserve
+	receiverBalance = ""
+	if err := b.checkNativeBalance(tReceiver, baseReserve, false); err != nil {
+		t.Errorf("payment of exactly base reserve to unfunded receiver should pass, but have %v", err)
+	}
+	if err := b.checkNativeBalance(tReceiver, below, false); !errors.Is(err, ErrPaymentBelowBaseReserve) {
+		t.Errorf("payment below base reserve to unfunded receiver should fail with %v, but have %v", ErrPaymentBelowBaseReserve, err)
+	}
+
+	// funded receiver, any payment is ok
+	receiverBalance = baseReserve.String()
+	if err := b.checkNativeBalance(tReceiver, big.NewInt(1), false); err != nil {
+		t.Errorf("payment to funded receiver should pass, but have %v", err)
+	}
+}
+
+func TestFeeExceedsOriginValue(t *testing.T) {
+	tokens.InitRouterSwapType("erc20swap")
+	tokenID, fromChainID, toChainID := "XRP", "1", GetStubChainID(testnetNetWork).String()
+
+	fixedFee := big.NewInt(2e18) // 2 XRP in 18 decimals
+	toMap := new(sync.Map)
+	toMap.Store(toChainID, &tokens.FeeConfig{MinimumSwapFee: fixedFee, MaximumSwapFee: fixedFee})
+	fromMap := new(sync.Map)
+	fromMap.Store(fromChainID, toMap)
+	feeCfgs := new(sync.Map)
+	feeCfgs.Store(tokenID, fromMap)
+	tokens.SetFeeConfigs(feeCfgs)
+	t.Cleanup(func() { tokens.SetFeeConfigs(new(sync.Map)) })
+
+	for _, originValue := range []*big.Int{big.NewInt(1e18), big.NewInt(2e18)} {
+		amount := tokens.CalcSwapValue(tokenID, fromChainID, toChainID, originValue, 18, 6, "", "")
+		if err := checkPaymentValue(amount, originValue); !errors.Is(err, tokens.ErrTxWithWrongValue) {
+			t.Errorf("origin value %v with fee %v should fail with %v, but have %v", originValue, fixedFee, tokens.ErrTxWithWrongValue, err)
+		}
+	}
+
+	originValue := big.NewInt(3e18)
+	amount := tokens.CalcSwapValue(tokenID, fromChainID, toChainID, originValue, 18, 6, "", "")
+	if err := checkPaymentValue(amount, originValue); err != nil {
+		t.Errorf("check payment value failed: %v", err)
+	}
+	if amount.Cmp(big.NewInt(1e6)) != 0 {
+		t.Errorf("payment amount mismatch, have %v want %v", amount, 1e6)
+	}
+
+	if err := checkPaymentValue(big.NewInt(-1), originValue); err == nil {
+		t.Error("negative payment amount should fail")
+	}
+}
+
+func TestIssuerFrozen(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = true
+
+	var issuerFlags uint32
+	var freezePeer bool
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			if rpcParams[0]["account"] != tIssuer {
+				t.Errorf("unexpected account info query of %v", rpcParams[0]["account"])
+			}
+			result := accountInfoResult(tIssuer, "100000000").(map[string]interface{})
+			result["account_data"].(map[string]interface{})["Flags"] = issuerFlags
+			return result
+		case "account_lines":
+			return map[string]interface{}{
+				"account": rpcParams[0]["account"],
+				"lines": []map[string]interface{}{{
+					"account":     tIssuer,
+					"balance":     "100",
+					"currency":    "USD",
+					"limit":       "1000",
+					"limit_peer":  "0",
+					"freeze_peer": freezePeer,
+				}},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	amount, err := data.NewAmount("10/USD/" + tIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name        string
+		issuerFlags uint32
+		freezePeer  bool
+		frozen      bool
+	}{
+		{"not frozen", uint32(data.LsDefaultRipple), false, false},
+		{"global freeze", uint32(data.LsGlobalFreeze | data.LsDefaultRipple), false, true},
+		{"trust line freeze", 0, true, true},
+	}
+	for _, test := range tests {
+		issuerFlags, freezePeer = test.issuerFlags, test.freezePeer
+		err = b.checkNonNativeBalance("USD", tIssuer, tSender, tReceiver, amount)
+		if frozen := errors.Is(err, ErrIssuerFrozen); frozen != test.frozen || (!test.frozen && err != nil) {
+			t.Errorf("%v: check non native balance mismatch, have %v", test.name, err)
+		}
+	}
+
+	// issuer pays out its own IOUs is not affected by freeze
+	issuerFlags, freezePeer = uint32(data.LsGlobalFreeze), true
+	if err = b.checkNonNativeBalance("USD", tIssuer, tIssuer, tReceiver, amount); err != nil {
+		t.Errorf("issuer payout should not be affected by freeze, but have %v", err)
+	}
+}
+
+func TestNonDefaultLineQuality(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = true
+
+	var qualityIn, qualityOut uint32
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			return accountInfoResult(tIssuer, "100000000")
+		case "account_lines":
+			return map[string]interface{}{
+				"account": rpcParams[0]["account"],
+				"lines": []map[string]interface{}{{
+					"account":     tIssuer,
+					"balance":     "100",
+					"currency":    "USD",
+					"limit":       "1000",
+					"limit_peer":  "0",
+					"quality_in":  qualityIn,
+					"quality_out": qualityOut,
+				}},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	amount, err := data.NewAmount("10/USD/" + tIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name                  string
+		qualityIn, qualityOut uint32
+		reject                bool
+		wantErr               bool
+	}{
+		{"default quality", 0, 0, true, false},
+		{"unity quality", 1000000000, 1000000000, true, false},
+		{"non unity quality in warns only", 990000000, 0, false, false},
+		{"non unity quality in", 990000000, 0, true, true},
+		{"non unity quality out", 0, 1010000000, true, true},
+	}
+	for _, test := range tests {
+		qualityIn, qualityOut = test.qualityIn, test.qualityOut
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"rejectNonDefaultQuality": strconv.FormatBool(test.reject)})
+		err = b.checkNonNativeBalance("USD", tIssuer, tSender, tReceiver, amount)
+		if hasErr := errors.Is(err, ErrNonDefaultLineQuality); hasErr != test.wantErr || (!test.wantErr && err != nil) {
+			t.Errorf("%v: check non native balance mismatch, have %v", test.name, err)
+		}
+	}
+}
+
+func TestTrustLineBalanceSign(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = true
+
+	var senderBalance, receiverBalance string
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			return accountInfoResult(tIssuer, "100000000")
+		case "account_lines":
+			balance := senderBalance
+			if rpcParams[0]["account"] == tReceiver {
+				balance = receiverBalance
+			}
+			return map[string]interface{}{
+				"account": rpcParams[0]["account"],
+				"lines": []map[string]interface{}{{
+					"account":    tIssuer,
+					"balance":    balance,
+					"currency":   "USD",
+					"limit":      "1000",
+					"limit_peer": "0",
+				}},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	amount, err := data.NewAmount("10/USD/" + tIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// the sender holds the issuer's IOUs
+	receiverBalance = "0"
+	holderTests := []struct {
+		balance string
+		wantErr bool
+	}{
+		{"100", false},
+		{"10", false},
+		{"9.5", true},
+		{"0", true},
+		{"-100", true}, // net debtor has nothing to send
+	}
+	for _, test := range holderTests {
+		senderBalance = test.balance
+		err = b.checkNonNativeBalance("USD", tIssuer, tSender, tReceiver, amount)
+		if (err != nil) != test.wantErr {
+			t.Errorf("holder with balance %v: check non native balance mismatch, have %v", test.balance, err)
+		}
+	}
+
+	// the issuer pays out its own IOUs, limited by the receiver's trust line
+	issuerTests := []struct {
+		receiverBalance string
+		wantErr         bool
+	}{
+		{"0", false},
+		{"990", false},
+		{"995", true},
+		{"-100", false}, // receiver owes the issuer
+	}
+	for _, test := range issuerTests {
+		receiverBalance = test.receiverBalance
+		err = b.checkNonNativeBalance("USD", tIssuer, tIssuer, tReceiver, amount)
+		if hasErr := errors.Is(err, ErrTrustLineLimitExceeded); hasErr != test.wantErr || (!test.wantErr && err != nil) {
+			t.Errorf("issuer with receiver balance %v: check non native balance mismatch, have %v", test.receiverBalance, err)
+		}
+	}
+}
+
+func TestSetExtraArgsSequenceOverride(t *testing.T) {
+	var accountQueries int
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method != "account_info" {
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		accountQueries++
+		return accountInfoResult(tSender, "20000000")
+	})
+
+	fee := "0.000012"
+	sequence := uint64(50)
+	args := &tokens.BuildTxArgs{From: tSender, Extra: &tokens.AllExtras{Sequence: &sequence, Fee: &fee, SequenceOverride: true}}
+	extra, err := b.setExtraArgs(args)
+	if err != nil {
+		t.Fatalf("set extra args with explicit sequence failed: %v", err)
+	}
+	if *extra.Sequence != sequence {
+		t.Errorf("explicit sequence should be honored, have %v want %v", *extra.Sequence, sequence)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"maxSequenceGap": "10"})
+	if _, err = b.setExtraArgs(args); err == nil {
+		t.Errorf("explicit sequence far beyond the account sequence should be refused")
+	}
+	sequence = 11
+	if _, err = b.setExtraArgs(args); err != nil {
+		t.Errorf("explicit sequence within the max gap should be honored, but have %v", err)
+	}
+	// the account sequence is 1
+	sequence = 0
+	if _, err = b.setExtraArgs(args); !errors.Is(err, ErrSequenceBelowAccount) {
+		t.Errorf("explicit sequence below the account sequence should be refused, but have %v", err)
+	}
+
+	// the sequence without the override flag (allocated by the swap server, or of replacing) is not checked
+	accountQueries = 0
+	args.Extra.SequenceOverride = false
+	if extra, err = b.setExtraArgs(args); err != nil || *extra.Sequence != sequence {
+		t.Errorf("sequence without the override flag should be used as is, have %v %v", extra, err)
+	}
+	args.Extra.ReplaceNum = 1
+	if extra, err = b.setExtraArgs(args); err != nil || *extra.Sequence != sequence {
+		t.Errorf("sequence of replacing should be used as is, have %v %v", extra, err)
+	}
+	if accountQueries != 0 {
+		t.Errorf("account sequence should not be queried without the override flag, have %v queries", accountQueries)
+	}
+}
+
+func TestNewUnsignedPaymentTransactionErrors(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name    string
+		key     crypto.Key
+		dest    string
+		amount  string
+		fee     string
+		path    string
+		wantErr error
+	}{
+		{"miss signing key", nil, tReceiver, "1000000", "12", "", ErrMissSigningKey},
+		{"wrong destination", key, "rInvalidDestination", "1000000", "12", "", nil},
+		{"wrong amount", key, tReceiver, "not an amount", "12", "", nil},
+		{"wrong paths", key, tReceiver, "1000000", "12", "not a path", nil},
+		{"wrong fee", key, tReceiver, "1000000", "12abc", "", ErrInvalidFee},
+	}
+	for _, test := range tests {
+		tx, err := NewUnsignedPaymentTransaction(test.key, nil, 3, test.dest, nil, test.amount, test.fee, "", test.path, 0)
+		if err == nil || tx != nil {
+			t.Errorf("%v: build payment should fail, but have tx %v err %v", test.name, tx, err)
+			continue
+		}
+		if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+			t.Errorf("%v: build payment should fail with %v, but have %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestGetSeqRefusedByMaxSequenceGap(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method != "account_info" {
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		return accountInfoResult(tSender, "20000000") // account sequence is 1
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"maxSequenceGap": "3"})
+
+	args := &tokens.BuildTxArgs{From: tSender}
+	for _, test := range []struct {
+		allocated uint64
+		wantErr   bool
+	}{
+		{0, false}, // not allocated yet, use the account sequence
+		{2, false},
+		{3, false},
+		{4, true}, // gap reached
+		{10, true},
+	} {
+		b.SetNonce(tSender, test.allocated)
+		nonce, err := b.GetSeq(args)
+		if test.wantErr {
+			if !errors.Is(err, ErrSequenceGapTooLarge) {
+				t.Errorf("allocated sequence %v should fail with %v, but have %v", test.allocated, ErrSequenceGapTooLarge, err)
+			}
+			continue
+		}
+		want := test.allocated
+		if want < 1 {
+			want = 1
+		}
+		if err != nil || *nonce != want {
+			t.Errorf("allocated sequence %v: get seq mismatch, have %v %v want %v", test.allocated, nonce, err, want)
+		}
+	}
+}
+
+// tMPC the mpc account of tSeed (ecdsa) and its public key
+const (
+	tMPC       = "rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz"
+	tMPCPubkey = "0x03D49C56E1B185F1BE899AE66A02EFC17F78EA6FC53AF85E0FE54C6E8B7F8C71A8"
+)
+
+// newTestBuildTxBridge new a bridge routed with `tMPC` as mpc for the token of tokenAddr and delivery mode,
+// and return it with a builder of swapin args paying 1 token (of 6 decimals) to `tReceiver`.
+func newTestBuildTxBridge(t *testing.T, tokenAddr, deliveryMode string, handler func(method string, params []map[string]interface{}) interface{}) (*Bridge, func() *tokens.BuildTxArgs) {
+	b := newTestRippleBridge(t, handler)
+
+	swapType := tokens.GetRouterSwapType()
+	tokens.InitRouterSwapType("erc20swap")
+	tokenID, fromChainID, toChainID := "RTOKEN", big.NewInt(1), b.ChainConfig.ChainID
+	fromBridge := NewCrossChainBridge()
+	fromBridge.CrossChainBridgeBase.SetTokenConfig("0xtoken", &tokens.TokenConfig{TokenID: tokenID, Decimals: 6})
+	b.SetTokenConfig(tokenAddr, &tokens.TokenConfig{TokenID: tokenID, Decimals: 6, ContractAddress: tokenAddr, RouterContract: "router", Extra: deliveryMode})
+	router.SetBridge(fromChainID.String(), fromBridge)
+	router.SetBridge(toChainID, b)
+	router.SetMultichainToken(tokenID, toChainID, tokenAddr)
+	router.SetRouterInfo("router", toChainID, &router.SwapRouterInfo{RouterMPC: tMPC})
+	router.SetMPCPublicKey(tMPC, tMPCPubkey)
+	toMap := new(sync.Map)
+	toMap.Store(toChainID, &tokens.FeeConfig{MinimumSwapFee: big.NewInt(0), MaximumSwapFee: big.NewInt(0)})
+	fromMap := new(sync.Map)
+	fromMap.Store(fromChainID.String(), toMap)
+	feeCfgs := new(sync.Map)
+	feeCfgs.Store(tokenID, fromMap)
+	tokens.SetFeeConfigs(feeCfgs)
+	t.Cleanup(func() {
+		tokens.SetRouterSwapType(swapType)
+		tokens.SetFeeConfigs(new(sync.Map))
+		router.SetBridge(fromChainID.String(), nil)
+		router.SetBridge(toChainID, nil)
+		router.SetMultichainTokens(tokenID, nil)
+	})
+
+	newArgs := func() *tokens.BuildTxArgs {
+		fee := "0.000012"
+		return &tokens.BuildTxArgs{
+			SwapArgs: tokens.SwapArgs{
+				SwapInfo:    tokens.SwapInfo{ERC20SwapInfo: &tokens.ERC20SwapInfo{Token: "0xtoken", TokenID: tokenID}},
+				SwapID:      "0x1111111111111111111111111111111111111111111111111111111111111111",
+				SwapType:    tokens.ERC20SwapType,
+				Bind:        tReceiver,
+				FromChainID: fromChainID,
+				ToChainID:   GetStubChainID(testnetNetWork),
+			},
+			From:        tMPC,
+			OriginValue: big.NewInt(1000000),
+			Extra:       &tokens.AllExtras{Fee: &fee},
+		}
+	}
+	return b, newArgs
+}
@@ -0,0 +1,136 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// delivery modes, configed in token config `Extra` item
+const (
+	// DeliveryModePayment pay to receiver directly (default)
+	DeliveryModePayment = ""
+	// DeliveryModeCheck create a Check which the receiver cashes later
+	DeliveryModeCheck = "check"
+	// DeliveryModeCheckIfDepositAuth create a Check if the receiver has DepositAuth enabled,
+	// otherwise pay to receiver directly
+	DeliveryModeCheckIfDepositAuth = "checkIfDepositAuth"
+	// DeliveryModeChannel pay by payment channel, which is declined (see ErrChannelDeliveryNotSupported)
+	DeliveryModeChannel = "channel"
+)
+
+// ErrChannelDeliveryNotSupported the off-ledger claims of payment channel are not verifiable by the mpc nodes
+var ErrChannelDeliveryNotSupported = errors.New("payment channel delivery is not supported")
+
+// GetDeliveryMode get delivery mode of token
+func GetDeliveryMode(token *tokens.TokenConfig) string {
+	return strings.TrimSpace(token.Extra)
+}
+
+// useCheckDelivery whether deliver to receiver by creating a Check
+func (b *Bridge) useCheckDelivery(token *tokens.TokenConfig, receiver string) (bool, error) {
+	switch mode := GetDeliveryMode(token); mode {
+	case DeliveryModePayment:
+		return false, nil
+	case DeliveryModeCheck:
+		return true, nil
+	case DeliveryModeChannel:
+		return false, fmt.Errorf("%w, token: %v", ErrChannelDeliveryNotSupported, token.TokenID)
+	case DeliveryModeCheckIfDepositAuth:
+		acct, err := b.GetAccount(receiver)
+		if err != nil {
+			return false, err
+		}
+		flags := acct.AccountData.Flags
+		return flags != nil && *flags&data.LsDepositAuth != 0, nil
+	default:
+		return false, fmt.Errorf("unknown delivery mode '%v' of token %v", mode, token.TokenID)
+	}
+}
+
+// getCheckExpiration get expiration of created Check,
+// which is configed by `checkExpiration` custom (in seconds, 0 means never expire)
+func (b *Bridge) getCheckExpiration() (*uint32, error) {
+	expStr := params.GetCustom(b.ChainConfig.ChainID, "checkExpiration")
+	if expStr == "" {
+		return nil, nil
+	}
+	seconds, err := common.GetUint32FromStr(expStr)
+	if err != nil {
+		return nil, fmt.Errorf("wrong checkExpiration %v", expStr)
+	}
+	if seconds == 0 {
+		return nil, nil
+	}
+	expiration := data.Now().Uint32() + seconds
+	return &expiration, nil
+}
+
+// NewUnsignedCheckCreateTransaction build ripple check create tx
+func NewUnsignedCheckCreateTransaction(
+	key crypto.Key, keyseq *uint32, txseq uint32,
+	dest string, destinationTag *uint32,
+	sendMax, fee, memo string, expiration *uint32,
+) (data.Transaction, error) {
+	if key == nil {
+		return nil, ErrMissSigningKey
+	}
+	destination, err := data.NewAccountFromAddress(dest)
+	if err != nil {
+		return nil, err
+	}
+	amount, err := data.NewAmount(sendMax)
+	if err != nil {
+		return nil, err
+	}
+	tx := &data.CheckCreate{
+		Destination:    *destination,
+		SendMax:        *amount,
+		DestinationTag: destinationTag,
+		Expiration:     expiration,
+	}
+	tx.TransactionType = data.CHECK_CREATE
+
+	if memo != "" {
+		memoStr := new(data.Memo)
+		memoStr.Memo.MemoData = []byte(memo)
+		tx.Memos = append(tx.Memos, *memoStr)
+	}
+
+	base := tx.GetBase()
+
+	base.Sequence = txseq
+
+	fei, err := ParseNativeFee(fee)
+	if err != nil {
+		return nil, err
+	}
+	base.Fee = *fei
+
+	copy(base.Account[:], key.Id(keyseq))
+
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
+	hash, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	var expireAt string
+	if expiration != nil {
+		expireAt = data.NewRippleTime(*expiration).Time().UTC().Format(time.RFC3339)
+	}
+	log.Info("Build unsigned check create tx success",
+		"destination", dest, "sendMax", sendMax, "memo", memo,
+		"fee", fee, "sequence", txseq, "expiration", expireAt,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
@@ -0,0 +1,220 @@
+package ripple
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const tIssuer = "rvYAfWj5gh67oV6fW32ZzP3Aw4Eubs59B"
+
+func TestNewUnsignedCheckCreateTransaction(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := uint32(8)
+	expiration := uint32(750000000)
+	sendMax := "1.5/USD/" + tIssuer
+
+	if _, err = NewUnsignedCheckCreateTransaction(nil, nil, 9, tReceiver, &tag, sendMax, "12", "swap memo", &expiration); !errors.Is(err, ErrMissSigningKey) {
+		t.Errorf("check create without signing key should fail with %v, but have %v", ErrMissSigningKey, err)
+	}
+	tx, err := NewUnsignedCheckCreateTransaction(key, nil, 9, tReceiver, &tag, sendMax, "12", "swap memo", &expiration)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCreate, ok := tx.(*data.CheckCreate)
+	if !ok {
+		t.Fatalf("tx should be CheckCreate, but have %T", tx)
+	}
+	if checkCreate.TransactionType != data.CHECK_CREATE {
+		t.Errorf("tx type mismatch, have %v", checkCreate.TransactionType)
+	}
+	if checkCreate.Destination.String() != tReceiver || *checkCreate.DestinationTag != tag {
+		t.Errorf("check destination mismatch, have %v:%v", checkCreate.Destination, *checkCreate.DestinationTag)
+	}
+	want, _ := data.NewAmount(sendMax)
+	if !checkCreate.SendMax.Equals(*want) {
+		t.Errorf("check send max mismatch, have %v want %v", checkCreate.SendMax, want)
+	}
+	if checkCreate.Expiration == nil || *checkCreate.Expiration != expiration {
+		t.Errorf("check expiration mismatch, have %v want %v", checkCreate.Expiration, expiration)
+	}
+	if checkCreate.Sequence != 9 || checkCreate.Fee.String() != "0.000012" {
+		t.Errorf("check sequence or fee mismatch, have %v %v", checkCreate.Sequence, checkCreate.Fee)
+	}
+
+	args := &tokens.BuildTxArgs{}
+	args.Bind = tReceiver + ":8"
+	b := NewCrossChainBridge()
+	if err = b.verifyTransactionWithArgs(tx, args); err != nil {
+		t.Errorf("verify check create tx failed: %v", err)
+	}
+	stx, _, err := b.SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatalf("sign check create tx failed: %v", err)
+	}
+	if err = VerifySignedTransactionEncoding(stx.(data.Transaction)); err != nil {
+		t.Errorf("verify check create tx encoding failed: %v", err)
+	}
+}
+
+func TestUseCheckDelivery(t *testing.T) {
+	var flags uint32
+	b := newTestRippleBridge(t, func(string, []map[string]interface{}) interface{} {
+		return map[string]interface{}{
+			"account_data": map[string]interface{}{
+				"Account": tReceiver,
+				"Balance": "20000000",
+				"Flags":   flags,
+			},
+		}
+	})
+
+	token := &tokens.TokenConfig{TokenID: "XRP"}
+	tests := []struct {
+		mode  string
+		flags uint32
+		want  bool
+	}{
+		{DeliveryModePayment, uint32(data.LsDepositAuth), false},
+		{DeliveryModeCheck, 0, true},
+		{DeliveryModeCheckIfDepositAuth, 0, false},
+		{DeliveryModeCheckIfDepositAuth, uint32(data.LsDepositAuth | data.LsRequireDestTag), true},
+	}
+	for _, test := range tests {
+		token.Extra = test.mode
+		flags = test.flags
+		have, err := b.useCheckDelivery(token, tReceiver)
+		if err != nil {
+			t.Errorf("delivery mode %v failed: %v", test.mode, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("delivery mode %v with flags %x, have %v want %v", test.mode, test.flags, have, test.want)
+		}
+	}
+
+	token.Extra = "unknown"
+	if _, err := b.useCheckDelivery(token, tReceiver); err == nil {
+		t.Error("unknown delivery mode should fail")
+	}
+	token.Extra = DeliveryModeChannel
+	if _, err := b.useCheckDelivery(token, tReceiver); !errors.Is(err, ErrChannelDeliveryNotSupported) {
+		t.Errorf("channel delivery should fail with %v, but have %v", ErrChannelDeliveryNotSupported, err)
+	}
+}
+
+func TestCheckExpiration(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(mainnetNetWork).String()})
+	if exp, err := b.getCheckExpiration(); err != nil || exp != nil {
+		t.Errorf("check should not expire by default, have %v %v", exp, err)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"checkExpiration": "86400"})
+
+	now := data.Now().Uint32()
+	exp, err := b.getCheckExpiration()
+	if err != nil || exp == nil {
+		t.Fatalf("get check expiration failed: %v", err)
+	}
+	if *exp < now+86400 || *exp > now+86400+5 {
+		t.Errorf("check expiration should be one day later, have %v now %v", *exp, now)
+	}
+}
+
+func TestPaymentInvoiceID(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"paymentInvoiceID": "true"})
+
+	args := &tokens.BuildTxArgs{}
+	args.Bind = tReceiver
+	args.FromChainID = big.NewInt(1)
+	args.SwapID = "0x1111111111111111111111111111111111111111111111111111111111111111"
+
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "1000000", "12", "memo", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.verifyTransactionWithArgs(tx, args); !errors.Is(err, ErrInvoiceIDMismatch) {
+		t.Errorf("verify payment without invoice id should fail with %v, but have %v", ErrInvoiceIDMismatch, err)
+	}
+	b.setInvoiceID(tx, args)
+
+	// read back from the signed blob
+	stx, _, err := b.SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, raw, err := data.Raw(stx.(data.Transaction))
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := data.ReadTransaction(bytes.NewReader(raw))
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment := decoded.(*data.Payment)
+	want := GetSwapInvoiceID(args)
+	if payment.InvoiceID == nil || *payment.InvoiceID != want {
+		t.Fatalf("invoice id mismatch, have %v want %v", payment.InvoiceID, want)
+	}
+	if err = b.verifyTransactionWithArgs(payment, args); err != nil {
+		t.Errorf("verify payment with invoice id failed: %v", err)
+	}
+
+	args.LogIndex = 1 // another swap
+	if err = b.verifyTransactionWithArgs(payment, args); !errors.Is(err, ErrInvoiceIDMismatch) {
+		t.Errorf("verify payment of another swap should fail with %v, but have %v", ErrInvoiceIDMismatch, err)
+	}
+}
+
+func TestBuildCheckCreateWithExpiration(t *testing.T) {
+	b, newArgs := newTestBuildTxBridge(t, "XRP", DeliveryModeCheck, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			return accountInfoResult(rpcParams[0]["account"].(string), "100000000")
+		case "server_state":
+			return serverStateResult()
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"checkExpiration": "86400"})
+
+	// the expiration is recorded in the args on the first build
+	args := newArgs()
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkCreate, ok := rawTx.(*data.CheckCreate)
+	if !ok {
+		t.Fatalf("tx should be CheckCreate, but have %T", rawTx)
+	}
+	if args.Extra.Expiration == nil || checkCreate.Expiration == nil || *checkCreate.Expiration != *args.Extra.Expiration {
+		t.Fatalf("check expiration mismatch, have %v in tx %v", args.Extra.Expiration, checkCreate.Expiration)
+	}
+
+	// and reused on rebuild (eg. by the accept nodes) later
+	expiration := *args.Extra.Expiration - 100
+	args.Extra.Expiration = &expiration
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	if checkCreate = rawTx.(*data.CheckCreate); *checkCreate.Expiration != expiration {
+		t.Errorf("rebuilt check expiration mismatch, have %v want %v", *checkCreate.Expiration, expiration)
+	}
+}
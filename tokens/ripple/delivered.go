@@ -0,0 +1,74 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// ErrUnderDelivered the payment delivered less than the requested amount
+var ErrUnderDelivered = errors.New("ripple payment delivered less than the requested amount")
+
+// getDeliveredAmountTolerance get the tolerance ratio of the IOU delivered amount being less than
+// the requested amount, as IOU amounts are rounded to 15 significant digits by the ledger.
+// It is configed by `deliveredAmountTolerance` custom (eg. `1e-12`, default to 0).
+func (b *Bridge) getDeliveredAmountTolerance() *big.Rat {
+	toleranceStr := params.GetCustom(b.ChainConfig.ChainID, "deliveredAmountTolerance")
+	if toleranceStr == "" {
+		return new(big.Rat)
+	}
+	tolerance, ok := new(big.Rat).SetString(toleranceStr)
+	if !ok || tolerance.Sign() < 0 || tolerance.Cmp(big.NewRat(1, 1)) >= 0 {
+		log.Warn("wrong deliveredAmountTolerance custom", "chainID", b.ChainConfig.ChainID, "value", toleranceStr)
+		return new(big.Rat)
+	}
+	return tolerance
+}
+
+// checkDeliveredAmount check the delivered amount of the payment is not less than the requested amount.
+// XRP must be delivered exactly, an IOU shortfall within the tolerance is regarded as rounding.
+// A partial payment is only required to deliver its DeliverMin, which the ledger has enforced already.
+func (b *Bridge) checkDeliveredAmount(txmeta *data.TransactionWithMetaData) error {
+	payment, ok := txmeta.Transaction.(*data.Payment)
+	if !ok {
+		return nil
+	}
+	delivered := txmeta.MetaData.DeliveredAmount
+	if delivered == nil {
+		// not available of payments in very early ledgers
+		return nil
+	}
+	requested := payment.Amount
+	if IsSameAmount(delivered, &requested) {
+		return nil
+	}
+	if !delivered.Asset().Matches(&requested) {
+		return fmt.Errorf("%w, delivered asset %v, requested %v", ErrUnderDelivered, delivered.Asset(), requested.Asset())
+	}
+	if payment.Flags != nil && *payment.Flags&data.TxPartialPayment != 0 {
+		if payment.DeliverMin != nil && delivered.Rat().Cmp(payment.DeliverMin.Rat()) < 0 {
+			return fmt.Errorf("%w, txHash: %v, deliverMin: %v, delivered: %v", ErrUnderDelivered, payment.Hash.String(), payment.DeliverMin, delivered)
+		}
+		return nil
+	}
+	shortfall := new(big.Rat).Sub(requested.Rat(), delivered.Rat())
+	if shortfall.Sign() <= 0 {
+		return nil
+	}
+	if !requested.IsNative() {
+		tolerance := b.getDeliveredAmountTolerance()
+		// shortfall / requested <= tolerance
+		ratio := new(big.Rat).Quo(shortfall, requested.Rat())
+		if ratio.Cmp(tolerance) <= 0 {
+			log.Info("ripple payment delivered amount is rounded", "chainID", b.ChainConfig.ChainID,
+				"txHash", payment.Hash.String(), "requested", requested, "delivered", delivered,
+				"shortfall", shortfall.FloatString(15), "tolerance", tolerance.FloatString(15))
+			return nil
+		}
+	}
+	return fmt.Errorf("%w, txHash: %v, requested: %v, delivered: %v", ErrUnderDelivered, payment.Hash.String(), requested, delivered)
+}
@@ -0,0 +1,109 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestCheckDeliveredAmount(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		t.Errorf("unexpected rpc method %v", method)
+		return nil
+	})
+
+	newTxMeta := func(requested, delivered string) *data.TransactionWithMetaData {
+		amount, err := data.NewAmount(requested)
+		if err != nil {
+			t.Fatal(err)
+		}
+		txmeta := &data.TransactionWithMetaData{Transaction: &data.Payment{Amount: *amount}}
+		if delivered != "" {
+			if txmeta.MetaData.DeliveredAmount, err = data.NewAmount(delivered); err != nil {
+				t.Fatal(err)
+			}
+		}
+		return txmeta
+	}
+
+	usd := "/USD/" + tIssuer
+	tests := []struct {
+		name      string
+		tolerance string
+		requested string
+		delivered string
+		wantErr   bool
+	}{
+		{"exact iou", "", "100" + usd, "100" + usd, false},
+		{"exact xrp", "", "1000000", "1000000", false},
+		{"exact iou of hex currency", "", "100" + usd, "100/0000000000000000000000005553440000000000/" + tIssuer, false},
+		{"no delivered amount", "", "100" + usd, "", false},
+		{"rounded iou without tolerance", "", "100" + usd, "99.9999999999999" + usd, true},
+		{"iou at tolerance", "0.00000001", "100" + usd, "99.999999" + usd, false},
+		{"iou within tolerance", "0.00000001", "100" + usd, "99.9999999999999" + usd, false},
+		{"iou beyond tolerance", "0.00000001", "100" + usd, "99.9999989" + usd, true},
+		{"iou real shortfall", "0.00000001", "100" + usd, "90" + usd, true},
+		{"xrp shortfall ignores tolerance", "0.000001", "1000000", "999999", true},
+		{"other asset", "0.00000001", "100" + usd, "100/EUR/" + tIssuer, true},
+		{"wrong tolerance is strict", "abc", "100" + usd, "99.9999999999999" + usd, true},
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"deliveredAmountTolerance": test.tolerance})
+		err := b.checkDeliveredAmount(newTxMeta(test.requested, test.delivered))
+		if test.wantErr != errors.Is(err, ErrUnderDelivered) || (!test.wantErr && err != nil) {
+			t.Errorf("%v: check delivered amount mismatch, want error %v, have %v", test.name, test.wantErr, err)
+		}
+	}
+
+	// partial payments
+	setTestCustoms(t, b.ChainConfig.ChainID, nil)
+	partialTests := []struct {
+		name       string
+		deliverMin string
+		delivered  string
+		wantErr    bool
+	}{
+		{"partial without deliver min", "", "50" + usd, false},
+		{"partial above deliver min", "90" + usd, "95" + usd, false},
+		{"partial at deliver min", "90" + usd, "90" + usd, false},
+		{"partial below deliver min", "90" + usd, "89" + usd, true},
+	}
+	for _, test := range partialTests {
+		txmeta := newTxMeta("100"+usd, test.delivered)
+		payment := txmeta.Transaction.(*data.Payment)
+		flags := data.TxPartialPayment
+		payment.Flags = &flags
+		if test.deliverMin != "" {
+			deliverMin, err := data.NewAmount(test.deliverMin)
+			if err != nil {
+				t.Fatal(err)
+			}
+			payment.DeliverMin = deliverMin
+		}
+		err := b.checkDeliveredAmount(txmeta)
+		if test.wantErr != errors.Is(err, ErrUnderDelivered) || (!test.wantErr && err != nil) {
+			t.Errorf("%v: check delivered amount mismatch, want error %v, have %v", test.name, test.wantErr, err)
+		}
+	}
+}
+
+func TestTxStatusUnderDelivered(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "tx":
+			return json.RawMessage(strings.Replace(string(tTxResult(true)), `"delivered_amount": "1000000"`, `"delivered_amount": "999999"`, 1))
+		case "ledger":
+			return map[string]interface{}{"ledger_index": 1005, "validated": true}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	if _, err := b.WaitTransactionStatus(tTxHash); !errors.Is(err, ErrUnderDelivered) {
+		t.Errorf("under delivered payout should fail with %v, but have %v", ErrUnderDelivered, err)
+	}
+}
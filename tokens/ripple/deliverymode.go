@@ -0,0 +1,126 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// delivery modes of the payment payouts
+const (
+	DeliveryNative  = "native"  // direct XRP payment
+	DeliveryIOU     = "iou"     // direct IOU payment, with SendMax if the issuer charges transfer fee
+	DeliveryPath    = "path"    // cross currency IOU payment along the operator supplied paths, with SendMax
+	DeliveryPartial = "partial" // IOU partial payment with DeliverMin
+)
+
+// defaultPartialPaymentTolerance the default ratio of the amount a partial payment may deliver less
+var defaultPartialPaymentTolerance = big.NewRat(1, 100)
+
+var (
+	// ErrPartialNativePayment XRP to XRP payment must not be partial (temBAD_SEND_XRP_PARTIAL)
+	ErrPartialNativePayment = errors.New("partial payment is not allowed for XRP payment")
+	// ErrNoReceiverTrustLine the receiver has no trust line of the IOU and can not hold it
+	ErrNoReceiverTrustLine = errors.New("receiver has no trust line of the IOU")
+	// ErrMissSendMax the payment of the delivery mode requires SendMax
+	ErrMissSendMax = errors.New("payment requires send max")
+)
+
+// deliveryConditions the conditions of the payout deciding its delivery mode
+type deliveryConditions struct {
+	Native            bool   // the payout asset is XRP
+	Partial           bool   // partial payment is enabled for the token (ContractVersion is tfPartialPayment)
+	Paths             string // the paths supplied by the operator, see getPaymentPaths
+	ReceiverTrustLine bool   // the receiver can hold the IOU (it has a trust line of the IOU, or it is the issuer)
+	TransferFee       bool   // the issuer charges transfer fee of the payout (TransferRate, and the sender is not the issuer)
+}
+
+// needSendMax whether the payment must have SendMax, which is a cross currency payment,
+// or the issuer charges transfer fee so that the sender pays more than the delivered amount
+func (cond *deliveryConditions) needSendMax() bool {
+	return !cond.Native && (cond.Paths != "" || cond.TransferFee)
+}
+
+// getDeliveryConditions get the delivery conditions of the payout from the token config,
+// the supplied paths, and the ledger state of the receiver's trust line and the issuer's TransferRate.
+func (b *Bridge) getDeliveryConditions(token *tokens.TokenConfig, asset *data.Asset, from, receiver, paths string) (*deliveryConditions, error) {
+	cond := &deliveryConditions{
+		Native:  asset.IsNative(),
+		Partial: token.ContractVersion == uint64(tfPartialPayment),
+		Paths:   paths,
+	}
+	if cond.Native {
+		return cond, nil
+	}
+	if receiver == asset.Issuer {
+		cond.ReceiverTrustLine = true
+	} else {
+		_, err := b.GetAccountLine(asset.Currency, asset.Issuer, receiver)
+		switch {
+		case err == nil:
+			cond.ReceiverTrustLine = true
+		case !errors.Is(err, tokens.ErrNotFound):
+			log.Warn("get receiver trust line failed", "currency", asset.Currency, "issuer", asset.Issuer, "receiver", receiver, "err", err)
+			return nil, fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get receiver account line failed")
+		}
+	}
+	if from != asset.Issuer {
+		rate, err := b.getIssuerTransferRate(asset.Issuer)
+		if err != nil {
+			return nil, err
+		}
+		cond.TransferFee = rate > transferRateBase
+	}
+	return cond, nil
+}
+
+// resolveDeliveryMode choose the construction of the payment payout by the conditions:
+// XRP is paid directly (paths and partial payment are refused), an IOU is refused if the receiver
+// can not hold it, or is paid partially if enabled (along the paths if supplied),
+// or along the supplied paths, otherwise directly.
+func resolveDeliveryMode(cond *deliveryConditions) (string, error) {
+	switch {
+	case cond.Native && cond.Paths != "":
+		return "", ErrPathsForNativePayment
+	case cond.Native && cond.Partial:
+		return "", ErrPartialNativePayment
+	case cond.Native:
+		return DeliveryNative, nil
+	case !cond.ReceiverTrustLine:
+		return "", ErrNoReceiverTrustLine
+	case cond.Partial:
+		return DeliveryPartial, nil
+	case cond.Paths != "":
+		return DeliveryPath, nil
+	default:
+		return DeliveryIOU, nil
+	}
+}
+
+// getPartialPaymentTolerance get the ratio of the amount a partial payment may deliver less,
+// configed by `partialPaymentTolerance` custom (eg. `0.005`, default to 0.01).
+func (b *Bridge) getPartialPaymentTolerance() *big.Rat {
+	toleranceStr := params.GetCustom(b.ChainConfig.ChainID, "partialPaymentTolerance")
+	if toleranceStr == "" {
+		return defaultPartialPaymentTolerance
+	}
+	tolerance, ok := new(big.Rat).SetString(toleranceStr)
+	if !ok || tolerance.Sign() < 0 || tolerance.Cmp(big.NewRat(1, 1)) >= 0 {
+		log.Warn("wrong partialPaymentTolerance custom", "chainID", b.ChainConfig.ChainID, "value", toleranceStr)
+		return defaultPartialPaymentTolerance
+	}
+	return tolerance
+}
+
+// getDeliverMin get the DeliverMin of the partial payment, which is the amount less the `partialPaymentTolerance`
+// (rounded down), so that a payment delivering less than it fails on ledger instead.
+func (b *Bridge) getDeliverMin(amount *big.Int, token *tokens.TokenConfig) (*data.Amount, error) {
+	ratio := new(big.Rat).Sub(big.NewRat(1, 1), b.getPartialPaymentTolerance())
+	deliverMin := new(big.Rat).Mul(new(big.Rat).SetInt(amount), ratio)
+	return getPaymentAmount(new(big.Int).Quo(deliverMin.Num(), deliverMin.Denom()), token)
+}
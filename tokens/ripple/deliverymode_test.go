@@ -0,0 +1,127 @@
+package ripple
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestResolveDeliveryMode(t *testing.T) {
+	paths := "EUR/" + tIssuer + " => " + tReceiver
+	tests := []struct {
+		name        string
+		cond        deliveryConditions
+		want        string
+		wantErr     error
+		needSendMax bool
+	}{
+		{"xrp", deliveryConditions{Native: true}, DeliveryNative, nil, false},
+		{"xrp with paths", deliveryConditions{Native: true, Paths: paths}, "", ErrPathsForNativePayment, false},
+		{"partial xrp", deliveryConditions{Native: true, Partial: true}, "", ErrPartialNativePayment, false},
+		{"iou without trust line", deliveryConditions{}, "", ErrNoReceiverTrustLine, false},
+		{"iou", deliveryConditions{ReceiverTrustLine: true}, DeliveryIOU, nil, false},
+		{"iou with transfer fee", deliveryConditions{ReceiverTrustLine: true, TransferFee: true}, DeliveryIOU, nil, true},
+		{"iou with paths", deliveryConditions{ReceiverTrustLine: true, Paths: paths}, DeliveryPath, nil, true},
+		{"partial iou", deliveryConditions{ReceiverTrustLine: true, Partial: true}, DeliveryPartial, nil, false},
+		{"partial iou with paths", deliveryConditions{ReceiverTrustLine: true, Partial: true, Paths: paths}, DeliveryPartial, nil, true},
+		{"partial iou without trust line", deliveryConditions{Partial: true}, "", ErrNoReceiverTrustLine, false},
+	}
+	for _, test := range tests {
+		cond := test.cond
+		mode, err := resolveDeliveryMode(&cond)
+		if !errors.Is(err, test.wantErr) || mode != test.want {
+			t.Errorf("%v: delivery mode mismatch, have %q %v want %q %v", test.name, mode, err, test.want, test.wantErr)
+		}
+		if cond.needSendMax() != test.needSendMax {
+			t.Errorf("%v: need send max mismatch, have %v want %v", test.name, cond.needSendMax(), test.needSendMax)
+		}
+	}
+}
+
+func TestGetDeliveryConditions(t *testing.T) {
+	var hasLine bool
+	var transferRate uint32
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_lines":
+			var lines []interface{}
+			if hasLine {
+				lines = append(lines, map[string]interface{}{"account": tIssuer, "currency": "USD", "balance": "0", "limit": "1000", "limit_peer": "0"})
+			}
+			return map[string]interface{}{"account": rpcParams[0]["account"], "lines": lines}
+		case "account_info":
+			result := accountInfoResult(tIssuer, "100000000").(map[string]interface{})
+			if transferRate != 0 {
+				result["account_data"].(map[string]interface{})["TransferRate"] = transferRate
+			}
+			return result
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	usd := &data.Asset{Currency: "USD", Issuer: tIssuer}
+	partialUSD := &tokens.TokenConfig{ContractVersion: uint64(tfPartialPayment)}
+	tests := []struct {
+		name         string
+		token        *tokens.TokenConfig
+		asset        *data.Asset
+		from         string
+		receiver     string
+		hasLine      bool
+		transferRate uint32
+		want         deliveryConditions
+	}{
+		{"xrp", &tokens.TokenConfig{}, &data.Asset{Currency: "XRP"}, tSender, tReceiver, false, 0, deliveryConditions{Native: true}},
+		{"iou", &tokens.TokenConfig{}, usd, tSender, tReceiver, true, 0, deliveryConditions{ReceiverTrustLine: true}},
+		{"iou without trust line", &tokens.TokenConfig{}, usd, tSender, tReceiver, false, 0, deliveryConditions{}},
+		{"iou with transfer fee", partialUSD, usd, tSender, tReceiver, true, 1005000000,
+			deliveryConditions{Partial: true, ReceiverTrustLine: true, TransferFee: true}},
+		{"iou with default transfer rate", &tokens.TokenConfig{}, usd, tSender, tReceiver, true, transferRateBase,
+			deliveryConditions{ReceiverTrustLine: true}},
+		{"issued by the sender", &tokens.TokenConfig{}, usd, tIssuer, tReceiver, true, 1005000000, deliveryConditions{ReceiverTrustLine: true}},
+		{"redeemed to the issuer", &tokens.TokenConfig{}, usd, tSender, tIssuer, false, 1005000000,
+			deliveryConditions{ReceiverTrustLine: true, TransferFee: true}},
+	}
+	for _, test := range tests {
+		hasLine, transferRate = test.hasLine, test.transferRate
+		cond, err := b.getDeliveryConditions(test.token, test.asset, test.from, test.receiver, "")
+		if err != nil || *cond != test.want {
+			t.Errorf("%v: delivery conditions mismatch, have %+v %v want %+v", test.name, cond, err, test.want)
+		}
+	}
+}
+
+func TestGetDeliverMin(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	token := &tokens.TokenConfig{TokenID: "USD", ContractAddress: "USD/" + tIssuer, Decimals: 6}
+	if err := b.VerifyTokenConfig(token); err != nil {
+		t.Fatal(err)
+	}
+	amount := big.NewInt(1000000)
+	if deliverMin, err := b.getDeliverMin(amount, token); err != nil || deliverMin.String() != "0.99/USD/"+tIssuer {
+		t.Errorf("deliver min with the default tolerance mismatch, have %v %v", deliverMin, err)
+	}
+
+	tests := []struct {
+		tolerance string
+		want      string
+	}{
+		{"0.0000015", "0.999998"}, // 1000000 * (1 - 0.0000015) = 999998.5, rounded down
+		{"0", "1"},
+		{"1", "0.99"},     // wrong config uses the default
+		{"wrong", "0.99"}, // wrong config uses the default
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"partialPaymentTolerance": test.tolerance})
+		if deliverMin, err := b.getDeliverMin(amount, token); err != nil || deliverMin.String() != test.want+"/USD/"+tIssuer {
+			t.Errorf("deliver min with tolerance %q mismatch, have %v %v want %v", test.tolerance, deliverMin, err, test.want)
+		}
+	}
+}
@@ -0,0 +1,102 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+)
+
+// destination tag policies, configed by `destTagPolicy` customs
+const (
+	// DestTagOptional destination tag is used if present (default)
+	DestTagOptional = "optional"
+	// DestTagRequired destination tag must be present (eg. exchange receivers)
+	DestTagRequired = "required"
+	// DestTagForbidden destination tag must not be present
+	DestTagForbidden = "forbidden"
+	// DestTagStrip destination tag is dropped if present
+	DestTagStrip = "strip"
+)
+
+var (
+	// ErrDestTagRequired receiver requires destination tag but it is missing
+	ErrDestTagRequired = errors.New("destination tag is required")
+	// ErrDestTagForbidden receiver forbids destination tag but it is present
+	ErrDestTagForbidden = errors.New("destination tag is forbidden")
+)
+
+// getDestTagPolicy get destination tag policy of the receiver and token.
+// `destTagPolicy:<receiver>` takes precedence over the first matched `destTagPolicyPatterns`,
+// then `destTagPolicy:<tokenID>`, and then `destTagPolicy`.
+func (b *Bridge) getDestTagPolicy(tokenID, receiver string) string {
+	chainID := b.ChainConfig.ChainID
+	policy := params.GetCustom(chainID, "destTagPolicy:"+receiver)
+	if policy == "" {
+		policy = b.matchDestTagPolicyPattern(receiver)
+	}
+	if policy == "" && tokenID != "" {
+		policy = params.GetCustom(chainID, "destTagPolicy:"+tokenID)
+	}
+	if policy == "" {
+		policy = params.GetCustom(chainID, "destTagPolicy")
+	}
+	switch policy {
+	case DestTagOptional, DestTagRequired, DestTagForbidden, DestTagStrip:
+		return policy
+	case "":
+	default:
+		log.Warn("unknown destination tag policy, use default", "chainID", chainID, "tokenID", tokenID, "receiver", receiver, "policy", policy)
+	}
+	return DestTagOptional
+}
+
+// matchDestTagPolicyPattern get the policy of the first receiver pattern matching the receiver.
+// `destTagPolicyPatterns` is comma separated `<pattern>=<policy>` (eg. `rExchange*=required`),
+// the pattern syntax is the same as `path.Match`.
+func (b *Bridge) matchDestTagPolicyPattern(receiver string) string {
+	chainID := b.ChainConfig.ChainID
+	patterns := params.GetCustom(chainID, "destTagPolicyPatterns")
+	if patterns == "" {
+		return ""
+	}
+	for _, item := range strings.Split(patterns, ",") {
+		parts := strings.SplitN(strings.TrimSpace(item), "=", 2)
+		if len(parts) != 2 {
+			log.Warn("wrong destination tag policy pattern", "chainID", chainID, "pattern", item)
+			continue
+		}
+		matched, err := path.Match(strings.TrimSpace(parts[0]), receiver)
+		if err != nil {
+			log.Warn("wrong destination tag policy pattern", "chainID", chainID, "pattern", item, "err", err)
+			continue
+		}
+		if matched {
+			return strings.TrimSpace(parts[1])
+		}
+	}
+	return ""
+}
+
+// checkDestinationTag enforce the destination tag policy, returns the destination tag to use
+func (b *Bridge) checkDestinationTag(tokenID, receiver string, destTag *uint32) (*uint32, error) {
+	switch b.getDestTagPolicy(tokenID, receiver) {
+	case DestTagRequired:
+		if destTag == nil {
+			return nil, fmt.Errorf("%w, receiver: %v, tokenID: %v", ErrDestTagRequired, receiver, tokenID)
+		}
+	case DestTagForbidden:
+		if destTag != nil {
+			return nil, fmt.Errorf("%w, receiver: %v, tokenID: %v, tag: %v", ErrDestTagForbidden, receiver, tokenID, *destTag)
+		}
+	case DestTagStrip:
+		if destTag != nil {
+			log.Info("strip forbidden destination tag", "receiver", receiver, "tokenID", tokenID, "tag", *destTag)
+			return nil, nil
+		}
+	}
+	return destTag, nil
+}
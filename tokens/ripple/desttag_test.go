@@ -0,0 +1,74 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCheckDestinationTag(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	tag := uint32(123)
+	tests := []struct {
+		policy  string
+		destTag *uint32
+		wantTag *uint32
+		wantErr error
+	}{
+		{DestTagOptional, nil, nil, nil},
+		{DestTagOptional, &tag, &tag, nil},
+		{DestTagRequired, &tag, &tag, nil},
+		{DestTagRequired, nil, nil, ErrDestTagRequired},
+		{DestTagForbidden, nil, nil, nil},
+		{DestTagForbidden, &tag, nil, ErrDestTagForbidden},
+		{DestTagStrip, &tag, nil, nil},
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"destTagPolicy:XRP": test.policy})
+		destTag, err := b.checkDestinationTag("XRP", tReceiver, test.destTag)
+		if !errors.Is(err, test.wantErr) {
+			t.Errorf("policy %v with tag %v should return error %v, but have %v", test.policy, test.destTag, test.wantErr, err)
+		}
+		if err == nil && destTag != test.wantTag {
+			t.Errorf("policy %v with tag %v should return tag %v, but have %v", test.policy, test.destTag, test.wantTag, destTag)
+		}
+	}
+
+	// receiver policy takes precedence over token policy
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"destTagPolicy":              DestTagForbidden,
+		"destTagPolicy:XRP":          DestTagOptional,
+		"destTagPolicy:" + tReceiver: DestTagRequired,
+	})
+	if _, err := b.checkDestinationTag("XRP", tReceiver, nil); !errors.Is(err, ErrDestTagRequired) {
+		t.Errorf("receiver policy should take precedence, but have %v", err)
+	}
+	if _, err := b.checkDestinationTag("XRP", tSender, &tag); err != nil {
+		t.Errorf("token policy should take precedence over default, but have %v", err)
+	}
+	if _, err := b.checkDestinationTag("USD", tSender, &tag); !errors.Is(err, ErrDestTagForbidden) {
+		t.Errorf("default policy should be used, but have %v", err)
+	}
+
+	// receiver pattern takes precedence over token policy, the first matched one is used
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"destTagPolicyPatterns": "[=" + DestTagStrip + "," + tReceiver[:4] + "*=" + DestTagRequired + ",r*=" + DestTagStrip,
+		"destTagPolicy:XRP":     DestTagOptional,
+	})
+	if _, err := b.checkDestinationTag("XRP", tReceiver, nil); !errors.Is(err, ErrDestTagRequired) {
+		t.Errorf("matched receiver pattern should take precedence, but have %v", err)
+	}
+	if destTag, err := b.checkDestinationTag("XRP", tSender, &tag); err != nil || destTag != nil {
+		t.Errorf("the first matched receiver pattern should be used, but have %v %v", destTag, err)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"destTagPolicyPatterns":      tReceiver[:4] + "*=" + DestTagRequired,
+		"destTagPolicy:" + tReceiver: DestTagOptional,
+	})
+	if _, err := b.checkDestinationTag("XRP", tReceiver, nil); err != nil {
+		t.Errorf("receiver policy should take precedence over receiver pattern, but have %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package ripple
+
+import (
+	"encoding/json"
+	"strconv"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+// getLastLedgerOffset get the number of ledgers a built tx stays valid,
+// which is configed by `lastLedgerOffset` custom (0 means never expire)
+func (b *Bridge) getLastLedgerOffset() uint64 {
+	offsetStr := params.GetCustom(b.ChainConfig.ChainID, "lastLedgerOffset")
+	if offsetStr == "" {
+		return 0
+	}
+	offset, err := strconv.ParseUint(offsetStr, 10, 32)
+	if err != nil {
+		log.Warn("wrong lastLedgerOffset config", "chainID", b.ChainConfig.ChainID, "value", offsetStr, "err", err)
+		return 0
+	}
+	return offset
+}
+
+// getLastLedgerSequence get the `LastLedgerSequence` of a new tx,
+// it is nil if `lastLedgerOffset` custom is not configed
+func (b *Bridge) getLastLedgerSequence() (*uint64, error) {
+	offset := b.getLastLedgerOffset()
+	if offset == 0 {
+		return nil, nil
+	}
+	latest, err := b.GetLatestValidatedLedger()
+	if err != nil {
+		log.Warn("get latest validated ledger failed", "err", err)
+		return nil, err
+	}
+	expiry := latest + offset
+	return &expiry, nil
+}
+
+// setLastLedgerSequence set `LastLedgerSequence` of the tx to the expiry height
+func setLastLedgerSequence(tx data.Transaction, extra *tokens.AllExtras) {
+	if extra == nil || extra.ExpiryHeight == nil {
+		return
+	}
+	lastLedger := uint32(*extra.ExpiryHeight)
+	tx.GetBase().LastLedgerSequence = &lastLedger
+}
+
+// rpcErrTxnNotFound rpc error of the tx not found in the ledgers the node has
+const rpcErrTxnNotFound = "txnNotFound"
+
+// IsLedgerExpired is the expiry ledger (eg. recorded in the swap store) passed.
+// A tx with this `LastLedgerSequence` not validated yet can never succeed then.
+// Zero expiry ledger means never expire.
+func (b *Bridge) IsLedgerExpired(expiryLedger uint64) (bool, error) {
+	if expiryLedger == 0 {
+		return false, nil
+	}
+	latest, err := b.GetLatestLedgerNumber()
+	if err != nil {
+		return false, err
+	}
+	return latest >= expiryLedger, nil
+}
+
+// IsTransactionExpired is the tx not validated before its `LastLedgerSequence`,
+// if so it can never succeed, and it's safe to rebuild the swap with a new sequence.
+// expiryLedger is the `LastLedgerSequence` recorded when building the tx (the swap expiry),
+// which is used if the tx is not found (eg. never broadcasted successfully), zero means never expire.
+func (b *Bridge) IsTransactionExpired(txHash string, expiryLedger uint64) (bool, error) {
+	// get the latest ledger before the tx, so that a tx validated in between is not treated as expired
+	latest, err := b.GetLatestLedgerNumber()
+	if err != nil {
+		return false, err
+	}
+	txres, notFound, err := b.getTransactionOrNotFound(txHash)
+	if err != nil {
+		return false, err
+	}
+	lastLedger := expiryLedger
+	if !notFound {
+		if txres.Validated {
+			return false, nil
+		}
+		if txLastLedger := txres.GetBase().LastLedgerSequence; txLastLedger != nil {
+			lastLedger = uint64(*txLastLedger)
+		}
+	}
+	if lastLedger == 0 || latest < lastLedger {
+		return false, nil
+	}
+	log.Info("ripple tx is expired", "txHash", txHash, "notFound", notFound, "lastLedgerSequence", lastLedger, "latest", latest)
+	return true, nil
+}
+
+// getTransactionOrNotFound get the tx, notFound is true if the nodes respond `txnNotFound`
+func (b *Bridge) getTransactionOrNotFound(txHash string) (txres *websockets.TxResult, notFound bool, err error) {
+	rpcParams := map[string]interface{}{
+		"transaction": txHash,
+	}
+	for _, url := range b.getTxQueryURLs() {
+		var raw json.RawMessage
+		if err = b.rpcPost(&raw, url, "tx", rpcParams); err != nil {
+			continue
+		}
+		var errRes rpcErrorResult
+		if json.Unmarshal(raw, &errRes) == nil && errRes.Error == rpcErrTxnNotFound {
+			notFound = true
+			continue
+		}
+		if err = json.Unmarshal(raw, &txres); err == nil && txres != nil && txres.Transaction != nil {
+			return txres, false, nil
+		}
+	}
+	if notFound {
+		return nil, true, nil
+	}
+	return nil, false, wrapRPCQueryError(err, "GetTransaction", txHash)
+}
@@ -0,0 +1,130 @@
+package ripple
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func tTxResultWithLastLedger(validated bool, lastLedger uint32) interface{} {
+	var res map[string]interface{}
+	_ = json.Unmarshal(tTxResult(validated), &res)
+	if lastLedger != 0 {
+		res["LastLedgerSequence"] = lastLedger
+	}
+	return res
+}
+
+func TestIsTransactionExpired(t *testing.T) {
+	var validated, notFound bool
+	var lastLedger uint32
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "tx":
+			if notFound {
+				return map[string]interface{}{"error": rpcErrTxnNotFound, "status": "error"}
+			}
+			return tTxResultWithLastLedger(validated, lastLedger)
+		case "ledger":
+			return map[string]interface{}{"ledger_index": 1005, "validated": true}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	tests := []struct {
+		name         string
+		validated    bool
+		notFound     bool
+		lastLedger   uint32
+		expiryLedger uint64
+		expired      bool
+	}{
+		{"not yet expired", false, false, 1010, 0, false},
+		{"expired", false, false, 1003, 0, true},
+		{"expired at last ledger", false, false, 1005, 0, true},
+		{"validated", true, false, 1003, 1003, false},
+		{"no last ledger", false, false, 0, 0, false},
+		{"no last ledger but recorded expiry", false, false, 0, 1003, true},
+		{"not found before expiry", false, true, 0, 1010, false},
+		{"not found past expiry", false, true, 0, 1003, true},
+		{"not found without expiry", false, true, 0, 0, false},
+	}
+	for _, tt := range tests {
+		validated, notFound, lastLedger = tt.validated, tt.notFound, tt.lastLedger
+		expired, err := b.IsTransactionExpired(tTxHash, tt.expiryLedger)
+		if err != nil {
+			t.Errorf("%v: check tx expired failed: %v", tt.name, err)
+			continue
+		}
+		if expired != tt.expired {
+			t.Errorf("%v: tx expired mismatch, have %v want %v", tt.name, expired, tt.expired)
+		}
+	}
+
+	// the expiry ledger recorded in the swap store
+	for expiryLedger, want := range map[uint64]bool{0: false, 1004: true, 1005: true, 1006: false} {
+		if expired, err := b.IsLedgerExpired(expiryLedger); err != nil || expired != want {
+			t.Errorf("ledger %v expired mismatch, have %v want %v, err %v", expiryLedger, expired, want, err)
+		}
+	}
+}
+
+func TestSetExtraArgsExpiryHeight(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "ledger":
+			return map[string]interface{}{"ledger_index": 1005, "validated": true}
+		case "account_info":
+			return accountInfoResult(tSender, "100000000")
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+	sequence, fee := uint64(8), "10"
+	stale := uint64(900)
+	args := &tokens.BuildTxArgs{From: tSender, Extra: &tokens.AllExtras{Sequence: &sequence, Fee: &fee}}
+
+	// never expire if not configed
+	extra, err := b.setExtraArgs(args)
+	if err != nil {
+		t.Fatalf("set extra args failed: %v", err)
+	}
+	if extra.ExpiryHeight != nil {
+		t.Errorf("expiry height should not be set without lastLedgerOffset, but have %v", *extra.ExpiryHeight)
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"lastLedgerOffset": "20"})
+	extra, err = b.setExtraArgs(args)
+	if err != nil {
+		t.Fatalf("set extra args failed: %v", err)
+	}
+	if extra.ExpiryHeight == nil || *extra.ExpiryHeight != 1025 {
+		t.Fatalf("expiry height mismatch, have %v want 1025", extra.ExpiryHeight)
+	}
+
+	// the accept nodes rebuild with the expiry height in the args
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = false
+	args.Extra.ExpiryHeight, args.Extra.ReplaceNum = &stale, 1
+	if extra, err = b.setExtraArgs(args); err != nil || *extra.ExpiryHeight != stale {
+		t.Errorf("expiry height in args should be kept, have %v, err %v", *extra.ExpiryHeight, err)
+	}
+
+	// the swap server recalculates the passed expiry height when replacing
+	params.IsSwapServer = true
+	if extra, err = b.setExtraArgs(args); err != nil || *extra.ExpiryHeight != 1025 {
+		t.Fatalf("expiry height should be recalculated when replacing, have %v, err %v", *extra.ExpiryHeight, err)
+	}
+
+	tx := &data.Payment{}
+	setLastLedgerSequence(tx, extra)
+	if tx.LastLedgerSequence == nil || *tx.LastLedgerSequence != 1025 {
+		t.Errorf("tx last ledger sequence mismatch, have %v want 1025", tx.LastLedgerSequence)
+	}
+}
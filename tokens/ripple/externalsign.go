@@ -0,0 +1,55 @@
+package ripple
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// SigningMessage the signing message of a tx for external signers (eg. HSM, external MPC)
+type SigningMessage struct {
+	KeyType     string       `json:"keyType"`     // KeyTypeSecp256k1 or KeyTypeEd25519, detected from SigningPubKey of the tx
+	SigningHash data.Hash256 `json:"signingHash"` // SHA-512Half of Message, signed by secp256k1 keys
+	Message     []byte       `json:"message"`     // signing prefix + msg, signed by ed25519 keys
+	SignContent string       `json:"signContent"` // the content requested to mpc sign, see getSignContent
+}
+
+// GetSigningMessage returns the signing message of the unsigned tx, which is exactly what MPCSignTransaction signs
+// (including the `signingPrefix` custom). The external signer signs the signing hash with secp256k1 key
+// (the signature is R || S || V in hex, V is ignored), or the message with ed25519 key (the signature in hex),
+// and the signed tx is made by MakeSignedTransaction with the public key and the signature.
+func (b *Bridge) GetSigningMessage(rawTx interface{}) (*SigningMessage, error) {
+	tx, ok := rawTx.(data.Transaction)
+	if !ok {
+		return nil, tokens.ErrWrongRawTx
+	}
+	pubkey := tx.GetPublicKey()
+	if pubkey == nil || len(pubkey.Bytes()) == 0 {
+		return nil, fmt.Errorf("%w, tx has no signing public key", tokens.ErrWrongRawTx)
+	}
+	keyType := KeyTypeSecp256k1
+	if isEd25519Pubkey(pubkey.Bytes()) {
+		keyType = KeyTypeEd25519
+	}
+	msgHash, msg, err := b.GetSigningHash(tx)
+	if err != nil {
+		return nil, fmt.Errorf("get transaction signing hash failed: %w", err)
+	}
+	return &SigningMessage{
+		KeyType:     keyType,
+		SigningHash: msgHash,
+		Message:     msg,
+		SignContent: getSignContent(keyType, msgHash, msg),
+	}, nil
+}
+
+// getSignContent get the content requested to mpc sign, the signing hash for secp256k1 key,
+// and the hex encoding of the message for ed25519 key (the mpc should do hex decoding).
+func getSignContent(keyType string, msgHash data.Hash256, msg []byte) string {
+	if keyType == KeyTypeEd25519 {
+		return common.ToHex(msg)
+	}
+	return msgHash.String()
+}
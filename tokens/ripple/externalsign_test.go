@@ -0,0 +1,91 @@
+package ripple
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	rcrypto "github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestGetSigningMessage(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(devnetNetWork).String()})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"signingPrefix": "0x53545801"})
+
+	for cryptoType, wantKeyType := range map[string]string{"ecdsa": KeyTypeSecp256k1, "ed25519": KeyTypeEd25519} {
+		key, err := ImportKeyFromSeed(tSeed, cryptoType)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag := uint32(12345) // the same tx as signTestPaymentWithBridge
+		tx, err := NewUnsignedPaymentTransaction(key, nil, 7, tReceiver, &tag, "1000000", "10", "swap memo", "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		signingMsg, err := b.GetSigningMessage(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signingMsg.KeyType != wantKeyType {
+			t.Errorf("%v key type mismatch, have %v want %v", cryptoType, signingMsg.KeyType, wantKeyType)
+		}
+
+		// the same as the internal sign path
+		msgHash, msg, err := b.GetSigningHash(tx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if signingMsg.SigningHash != msgHash || !bytes.Equal(signingMsg.Message, msg) {
+			t.Errorf("%v signing message mismatch, have %v %X want %v %X", cryptoType, signingMsg.SigningHash, signingMsg.Message, msgHash, msg)
+		}
+		if !bytes.Equal(signingMsg.Message[:4], []byte{0x53, 0x54, 0x58, 0x01}) {
+			t.Errorf("%v signing message should start with custom prefix, but have %X", cryptoType, signingMsg.Message[:4])
+		}
+		wantContent := msgHash.String()
+		if wantKeyType == KeyTypeEd25519 {
+			wantContent = common.ToHex(msg)
+		}
+		if signingMsg.SignContent != wantContent {
+			t.Errorf("%v sign content mismatch, have %v want %v", cryptoType, signingMsg.SignContent, wantContent)
+		}
+
+		// sign externally and feed the signature back
+		sig, err := rcrypto.Sign(key.Private(nil), signingMsg.SigningHash.Bytes(), signingMsg.Message)
+		if err != nil {
+			t.Fatal(err)
+		}
+		rsv := fmt.Sprintf("%X", sig)
+		if wantKeyType == KeyTypeSecp256k1 {
+			signature, errf := btcec.ParseSignature(sig, btcec.S256())
+			if errf != nil {
+				t.Fatal(errf)
+			}
+			rsv = fmt.Sprintf("%064X%064X00", signature.R, signature.S)
+		}
+		signedTx, err := MakeSignedTransaction(key.Public(nil), rsv, tx)
+		if err != nil {
+			t.Fatalf("make %v signed tx failed: %v", cryptoType, err)
+		}
+		_, blob, err := data.Raw(signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, wantBlob, err := data.Raw(signTestPaymentWithBridge(t, b, cryptoType))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blob, wantBlob) {
+			t.Errorf("%v externally signed tx mismatch, have %X want %X", cryptoType, blob, wantBlob)
+		}
+	}
+
+	if _, err := b.GetSigningMessage(&data.Payment{}); !errors.Is(err, tokens.ErrWrongRawTx) {
+		t.Errorf("tx without signing public key should fail with %v, but have %v", tokens.ErrWrongRawTx, err)
+	}
+}
@@ -0,0 +1,121 @@
+package ripple
+
+import (
+	"math/big"
+	"sort"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// HealthSnapshot the health snapshot of the mpc accounts of the chain,
+// a failed query is reported in the `Error` of the account or trust line
+// so that the other fields are still available to dashboards.
+type HealthSnapshot struct {
+	ChainID  string           `json:"chainID"`
+	Accounts []*AccountHealth `json:"accounts"`
+}
+
+// AccountHealth the health of a mpc account, amounts of XRP are in drops
+type AccountHealth struct {
+	Account           string             `json:"account"`
+	Balance           *big.Int           `json:"balance,omitempty"`
+	OwnerCount        uint32             `json:"ownerCount"`
+	Reserve           *big.Int           `json:"reserve,omitempty"`   // base reserve plus owner reserve
+	Available         *big.Int           `json:"available,omitempty"` // balance above the reserve
+	Sequence          uint64             `json:"sequence"`            // on ledger sequence
+	AllocatedSequence uint64             `json:"allocatedSequence"`   // latest allocated sequence
+	SequenceGap       uint64             `json:"sequenceGap"`
+	MaxSequenceGap    uint64             `json:"maxSequenceGap"`
+	TrustLines        []*TrustLineHealth `json:"trustLines,omitempty"`
+	Error             string             `json:"error,omitempty"`
+}
+
+// TrustLineHealth the trust line balance of a token paid by the mpc account
+type TrustLineHealth struct {
+	TokenID  string `json:"tokenID"`
+	Currency string `json:"currency"`
+	Issuer   string `json:"issuer"`
+	Balance  string `json:"balance,omitempty"`
+	Limit    string `json:"limit,omitempty"`
+	Frozen   bool   `json:"frozen"`
+	Error    string `json:"error,omitempty"`
+}
+
+// GetHealthSnapshot get the health snapshot of the mpc accounts (the router contract
+// of the chain and of the tokens), including the XRP balance and reserve, the gap between
+// the allocated and the on ledger sequence, and the trust line balances of the IOU tokens.
+func (b *Bridge) GetHealthSnapshot() (*HealthSnapshot, error) {
+	mpcs, tokenCfgs, err := b.GetMPCTokenConfigs(router.GetRouterMPC)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := &HealthSnapshot{ChainID: b.ChainConfig.ChainID}
+	for _, mpc := range mpcs {
+		snapshot.Accounts = append(snapshot.Accounts, b.getAccountHealth(mpc, tokenCfgs[mpc]))
+	}
+	return snapshot, nil
+}
+
+func (b *Bridge) getAccountHealth(account string, tokenCfgs []*tokens.TokenConfig) *AccountHealth {
+	health := &AccountHealth{
+		Account:           account,
+		AllocatedSequence: b.GetSwapNonce(account),
+	}
+	if maxGap, err := b.getMaxSequenceGap(); err == nil {
+		health.MaxSequenceGap = maxGap
+	}
+
+	acct, err := b.GetAccount(account)
+	if err != nil {
+		log.Warn("get account health failed", "chainID", b.ChainConfig.ChainID, "account", account, "err", err)
+		health.Error = err.Error()
+	} else {
+		accountData := acct.AccountData
+		health.Balance = big.NewInt(0)
+		if accountData.Balance != nil {
+			health.Balance.SetInt64(accountData.Balance.Drops())
+		}
+		if accountData.OwnerCount != nil {
+			health.OwnerCount = *accountData.OwnerCount
+		}
+		health.Reserve = b.getReserves().AccountReserve(health.OwnerCount)
+		health.Available = new(big.Int).Sub(health.Balance, health.Reserve)
+		if health.Available.Sign() < 0 {
+			health.Available.SetInt64(0)
+		}
+		if accountData.Sequence != nil {
+			health.Sequence = uint64(*accountData.Sequence)
+		}
+		if health.AllocatedSequence > health.Sequence {
+			health.SequenceGap = health.AllocatedSequence - health.Sequence
+		}
+	}
+
+	sort.Slice(tokenCfgs, func(i, j int) bool { return tokenCfgs[i].TokenID < tokenCfgs[j].TokenID })
+	for _, tokenCfg := range tokenCfgs {
+		asset, errf := convertToAsset(tokenCfg.ContractAddress)
+		if errf != nil || asset.IsNative() {
+			continue
+		}
+		lineHealth := &TrustLineHealth{
+			TokenID:  tokenCfg.TokenID,
+			Currency: asset.Currency,
+			Issuer:   asset.Issuer,
+		}
+		line, errf := b.GetAccountLine(asset.Currency, asset.Issuer, account)
+		if errf != nil {
+			log.Warn("get trust line health failed", "chainID", b.ChainConfig.ChainID, "account", account,
+				"tokenID", tokenCfg.TokenID, "err", errf)
+			lineHealth.Error = errf.Error()
+		} else {
+			lineHealth.Balance = line.Balance.String()
+			lineHealth.Limit = line.Limit.String()
+			lineHealth.Frozen = line.IsFrozenByIssuer()
+		}
+		health.TrustLines = append(health.TrustLines, lineHealth)
+	}
+	return health
+}
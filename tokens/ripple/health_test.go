@@ -0,0 +1,117 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// serverStateResult server_state result with the reserves of 1 XRP base plus 0.2 XRP per object
+func serverStateResult() interface{} {
+	return map[string]interface{}{
+		"state": map[string]interface{}{
+			"validated_ledger": map[string]interface{}{"seq": 1000, "reserve_base": 1000000, "reserve_inc": 200000},
+		},
+	}
+}
+
+func TestGetHealthSnapshot(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		if account := rpcParams[0]["account"]; method != "server_state" && account != tSender {
+			t.Errorf("unexpected account %v", account)
+		}
+		switch method {
+		case "server_state":
+			return serverStateResult()
+		case "account_info":
+			result := accountInfoResult(tSender, "30000000").(map[string]interface{})
+			result["account_data"].(map[string]interface{})["OwnerCount"] = 3
+			return result
+		case "account_lines":
+			return map[string]interface{}{
+				"account": tSender,
+				"lines": []map[string]interface{}{{
+					"account":     tIssuer,
+					"balance":     "250.5",
+					"currency":    "USD",
+					"limit":       "1000",
+					"limit_peer":  "0",
+					"freeze_peer": true,
+				}},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	if _, err := b.GetHealthSnapshot(); !errors.Is(err, tokens.ErrNoRouterMPC) {
+		t.Errorf("snapshot without mpc should fail with %v, but have %v", tokens.ErrNoRouterMPC, err)
+	}
+
+	b.ChainConfig.RouterContract = tSender
+	b.CrossChainBridgeBase.SetTokenConfig("XRP", &tokens.TokenConfig{TokenID: "XRP", ContractAddress: "XRP", Decimals: 6})
+	b.CrossChainBridgeBase.SetTokenConfig("USD/"+tIssuer, &tokens.TokenConfig{TokenID: "USD", ContractAddress: "USD/" + tIssuer, Decimals: 6})
+	b.SetNonce(tSender, 5)
+
+	snapshot, err := b.GetHealthSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.ChainID != b.ChainConfig.ChainID || len(snapshot.Accounts) != 1 {
+		t.Fatalf("snapshot mismatch, have %+v", snapshot)
+	}
+	health := snapshot.Accounts[0]
+	if health.Error != "" {
+		t.Fatalf("account health has error %v", health.Error)
+	}
+	// reserve is 1 XRP base plus 3 objects of 0.2 XRP
+	if health.Account != tSender || health.Balance.Int64() != 30000000 || health.OwnerCount != 3 ||
+		health.Reserve.Int64() != 1600000 || health.Available.Int64() != 28400000 {
+		t.Errorf("account balance health mismatch, have %+v", health)
+	}
+	if health.Sequence != 1 || health.AllocatedSequence != 5 || health.SequenceGap != 4 || health.MaxSequenceGap != defaultMaxSequenceGap {
+		t.Errorf("account sequence health mismatch, have %+v", health)
+	}
+	if len(health.TrustLines) != 1 {
+		t.Fatalf("trust lines of IOU tokens mismatch, have %v", len(health.TrustLines))
+	}
+	line := health.TrustLines[0]
+	if line.TokenID != "USD" || line.Currency != "USD" || line.Issuer != tIssuer ||
+		line.Balance != "250.5" || line.Limit != "1000" || !line.Frozen || line.Error != "" {
+		t.Errorf("trust line health mismatch, have %+v", line)
+	}
+}
+
+func TestGetReserves(t *testing.T) {
+	var stateQueries int
+	var stateDown bool
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method != "server_state" {
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+		stateQueries++
+		if stateDown {
+			return map[string]interface{}{"error": "noNetwork", "status": "error"}
+		}
+		return serverStateResult()
+	})
+
+	// the default reserves are used if they can not be queried
+	stateDown = true
+	if reserve := b.getReserves().AccountReserve(3); reserve.Cmp(defaultReserves().AccountReserve(3)) != 0 {
+		t.Errorf("default account reserve mismatch, have %v", reserve)
+	}
+
+	stateDown = false
+	for i := 0; i < 2; i++ {
+		if reserve := b.getReserves().AccountReserve(3); reserve.Int64() != 1600000 {
+			t.Errorf("account reserve mismatch, have %v", reserve)
+		}
+	}
+	if stateQueries != 2 {
+		t.Errorf("reserves should be cached, have %v queries", stateQueries)
+	}
+}
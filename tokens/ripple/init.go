@@ -1,7 +1,10 @@
 package ripple
 
 import (
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
 	"sync"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
@@ -14,6 +17,9 @@ var (
 	currencyMap = new(sync.Map)
 	issuerMap   = new(sync.Map)
 	assetMap    = new(sync.Map)
+
+	// ErrTokenAssetMismatch the token configs do not agree with the loaded currencies, issuers and assets
+	ErrTokenAssetMismatch = errors.New("token config mismatch with loaded assets")
 )
 
 // ripple token address format is "XRP" or "Currency/Issuser"
@@ -73,6 +79,51 @@ func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
 	return nil
 }
 
+// CheckTokenConfigs check every token config has its currency, issuer and asset loaded,
+// otherwise the mismatch surfaces only at the first payout (see getPaymentAmount).
+// It returns one error listing all the mismatches.
+func (b *Bridge) CheckTokenConfigs() error {
+	var mismatches []string
+	b.TokenConfigMap.Range(func(key, value interface{}) bool {
+		tokenCfg, ok := value.(*tokens.TokenConfig)
+		if !ok || tokenCfg == nil {
+			return true
+		}
+		if err := checkTokenAssetLoaded(tokenCfg); err != nil {
+			mismatches = append(mismatches, fmt.Sprintf("%v (%v): %v", tokenCfg.TokenID, tokenCfg.ContractAddress, err))
+		}
+		return true
+	})
+	if len(mismatches) == 0 {
+		return nil
+	}
+	sort.Strings(mismatches)
+	return fmt.Errorf("%w, %v", ErrTokenAssetMismatch, strings.Join(mismatches, "; "))
+}
+
+func checkTokenAssetLoaded(tokenCfg *tokens.TokenConfig) error {
+	asset, err := convertToAsset(tokenCfg.ContractAddress)
+	if err != nil {
+		return err
+	}
+	if _, exist := currencyMap.Load(asset.Currency); !exist {
+		return fmt.Errorf("currency %v is not loaded", asset.Currency)
+	}
+	if !asset.IsNative() {
+		if _, exist := issuerMap.Load(asset.Issuer); !exist {
+			return fmt.Errorf("issuer %v is not loaded", asset.Issuer)
+		}
+	}
+	assetI, exist := assetMap.Load(tokenCfg.ContractAddress)
+	if !exist {
+		return fmt.Errorf("asset is not loaded")
+	}
+	if loaded := assetI.(*data.Asset); *loaded != *asset {
+		return fmt.Errorf("loaded asset %v is not %v", loaded, asset)
+	}
+	return nil
+}
+
 // InitRouterInfo init router info (in ripple routerContract is routerMPC)
 func (b *Bridge) InitRouterInfo(routerContract, routerVersion string) (err error) {
 	chainID := b.ChainConfig.ChainID
@@ -88,10 +139,17 @@ func (b *Bridge) InitRouterInfo(routerContract, routerVersion string) (err error
 		log.Warn("get mpc public key failed", "mpc", routerMPC, "err", err)
 		return err
 	}
+	// fail fast on a malformed public key, the detected key type is cached and used when signing
+	mpcKey, err := ParseMPCPubKey(routerMPCPubkey)
+	if err != nil {
+		log.Warn("detect mpc key type failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
+		return err
+	}
 	if err = VerifyMPCPubKey(routerMPC, routerMPCPubkey); err != nil {
 		log.Warn("verify mpc public key failed", "mpc", routerMPC, "mpcPubkey", routerMPCPubkey, "err", err)
 		return err
 	}
+	mpcKeyMap.Store(routerMPC, mpcKey)
 	router.SetRouterInfo(
 		routerContract,
 		chainID,
@@ -100,9 +158,10 @@ func (b *Bridge) InitRouterInfo(routerContract, routerVersion string) (err error
 		},
 	)
 	router.SetMPCPublicKey(routerMPC, routerMPCPubkey)
+	b.startRefillMonitor()
 
 	log.Info(fmt.Sprintf("[%5v] init router info success", chainID),
-		"routerContract", routerContract, "routerMPC", routerMPC)
+		"routerContract", routerContract, "routerMPC", routerMPC, "keyType", mpcKey.Type)
 
 	return nil
 }
@@ -0,0 +1,50 @@
+package ripple
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCheckTokenConfigs(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	good := &tokens.TokenConfig{TokenID: "USD", ContractAddress: "USD/" + tIssuer, Decimals: 6}
+	if err := b.VerifyTokenConfig(good); err != nil {
+		t.Fatal(err)
+	}
+	b.CrossChainBridgeBase.SetTokenConfig(good.ContractAddress, good)
+	native := &tokens.TokenConfig{TokenID: "XRP", ContractAddress: "XRP", Decimals: 6}
+	if err := b.VerifyTokenConfig(native); err != nil {
+		t.Fatal(err)
+	}
+	b.CrossChainBridgeBase.SetTokenConfig(native.ContractAddress, native)
+	if err := b.CheckTokenConfigs(); err != nil {
+		t.Fatalf("check loaded token configs failed: %v", err)
+	}
+
+	// set without verifying, so the issuer and the asset are not loaded
+	missIssuer := "rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz"
+	bad := &tokens.TokenConfig{TokenID: "BAD", ContractAddress: "USD/" + missIssuer, Decimals: 6}
+	b.CrossChainBridgeBase.SetTokenConfig(bad.ContractAddress, bad)
+	wrong := &tokens.TokenConfig{TokenID: "WRONG", ContractAddress: "wrong", Decimals: 6}
+	b.CrossChainBridgeBase.SetTokenConfig(wrong.ContractAddress, wrong)
+
+	err := b.CheckTokenConfigs()
+	if !errors.Is(err, ErrTokenAssetMismatch) {
+		t.Fatalf("check token configs should fail with %v, but have %v", ErrTokenAssetMismatch, err)
+	}
+	for _, want := range []string{"BAD (USD/" + missIssuer + "): issuer " + missIssuer + " is not loaded", "WRONG (wrong)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("mismatches should contain %q, have %v", want, err)
+		}
+	}
+	for _, loaded := range []string{"USD (", "XRP ("} {
+		if strings.Contains(err.Error(), loaded) {
+			t.Errorf("mismatches should not contain loaded token %q, have %v", loaded, err)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	rcrypto "github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+var (
+	// ErrInvoiceIDMismatch the invoice id of the tx is not derived from the swap
+	ErrInvoiceIDMismatch = errors.New("invoice id mismatch")
+)
+
+// useInvoiceID is setting `InvoiceID` on payouts enabled, configed by `paymentInvoiceID` custom
+func (b *Bridge) useInvoiceID() bool {
+	if b.ChainConfig == nil {
+		return false
+	}
+	return params.GetCustom(b.ChainConfig.ChainID, "paymentInvoiceID") == "true"
+}
+
+// GetSwapInvoiceID get the invoice id of the swap,
+// which is the SHA-512Half of the unique swap identifier (`fromChainID:swapID:logIndex`)
+func GetSwapInvoiceID(args *tokens.BuildTxArgs) data.Hash256 {
+	var invoiceID data.Hash256
+	copy(invoiceID[:], rcrypto.Sha512Half([]byte(args.GetUniqueSwapIdentifier())))
+	return invoiceID
+}
+
+// setInvoiceID set `InvoiceID` of the payout tx if it is enabled
+func (b *Bridge) setInvoiceID(tx data.Transaction, args *tokens.BuildTxArgs) {
+	if !b.useInvoiceID() {
+		return
+	}
+	invoiceID := GetSwapInvoiceID(args)
+	switch tx := tx.(type) {
+	case *data.Payment:
+		tx.InvoiceID = &invoiceID
+	case *data.CheckCreate:
+		tx.InvoiceID = &invoiceID
+	}
+}
+
+// checkInvoiceID verify the invoice id is derived from the swap,
+// it is required if `paymentInvoiceID` is enabled.
+func (b *Bridge) checkInvoiceID(invoiceID *data.Hash256, args *tokens.BuildTxArgs) error {
+	if invoiceID == nil {
+		if b.useInvoiceID() {
+			return fmt.Errorf("%w, miss invoice id", ErrInvoiceIDMismatch)
+		}
+		return nil
+	}
+	if want := GetSwapInvoiceID(args); *invoiceID != want {
+		return fmt.Errorf("%w, have %v want %v", ErrInvoiceIDMismatch, invoiceID, want)
+	}
+	return nil
+}
@@ -1,9 +1,11 @@
 package ripple
 
 import (
+	"crypto/ed25519"
 	"encoding/hex"
 	"fmt"
 	"math/big"
+	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
 )
@@ -17,6 +19,8 @@ const (
 
 const (
 	pubkeyCompressed byte = 0x2
+	// ed25519PubkeyPrefix ripple ed25519 pubkey prefix
+	ed25519PubkeyPrefix byte = 0xED
 )
 
 // ImportKeyFromSeed converts seed to ripple key
@@ -37,9 +41,49 @@ func ImportKeyFromSeed(seed, cryptoType string) (crypto.Key, error) {
 	}
 }
 
-// ImportPublicKey converts pubkey to ripple pubkey
+// ImportPublicKey converts pubkey to ripple pubkey.
+// ed25519 pubkey can be either with 0xED prefix (ripple format)
+// or without it (raw 32 bytes, as mpc ed public key).
 func ImportPublicKey(pubkey []byte) crypto.Key {
-	return &EcdsaPublic{pub: pubkey}
+	switch {
+	case isEd25519Pubkey(pubkey):
+		return &Ed25519Public{pub: pubkey}
+	case len(pubkey) == ed25519.PublicKeySize:
+		return &Ed25519Public{pub: append([]byte{ed25519PubkeyPrefix}, pubkey...)}
+	default:
+		return &EcdsaPublic{pub: pubkey}
+	}
+}
+
+func isEd25519Pubkey(pubkey []byte) bool {
+	return len(pubkey) == ed25519.PublicKeySize+1 && pubkey[0] == ed25519PubkeyPrefix
+}
+
+// getMPCEd25519Pubkey get mpc ed public key (without 0xED prefix) from ripple ed25519 pubkey hex
+func getMPCEd25519Pubkey(pubkeyHex string) string {
+	pubkeyHex = strings.TrimPrefix(strings.TrimPrefix(pubkeyHex, "0x"), "0X")
+	return pubkeyHex[2:]
+}
+
+// Ed25519Public struct ripple ed25519 pubkey key
+type Ed25519Public struct {
+	pub []byte // with 0xED prefix
+}
+
+// Id returns account id from ripple key
+//nolint:golint,stylecheck // ok
+func (k *Ed25519Public) Id(sequence *uint32) []byte {
+	return crypto.Sha256RipeMD160(k.Public(sequence))
+}
+
+// Private not used
+func (k *Ed25519Public) Private(sequence *uint32) []byte {
+	return nil
+}
+
+// Public returns pubkey bytes with 0xED prefix
+func (k *Ed25519Public) Public(sequence *uint32) []byte {
+	return k.pub
 }
 
 // EcdsaPublic struct ripple ecdsa pubkey key
@@ -0,0 +1,53 @@
+package ripple
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+const (
+	// ed25519 pubkey and account of the `masterpassphrase` seed
+	tEd25519Pubkey  = "EDAAC3F98BB94F451804EF5993C847DAAA4E6154F455635659D88AA5C80F156303"
+	tEd25519Account = "rGWrZyQqhTp9Xu7G5Pkayo7bXjH4k4QYpf"
+	// secp256k1 account of the `masterpassphrase` seed
+	tEcdsaAccount = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh"
+)
+
+func TestImportEd25519PublicKey(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pubkey := hex.EncodeToString(key.Public(nil)); !strings.EqualFold(pubkey, tEd25519Pubkey) {
+		t.Fatalf("ed25519 pubkey mismatch, have %v want %v", pubkey, tEd25519Pubkey)
+	}
+
+	for _, pubkeyHex := range []string{tEd25519Pubkey, "0x" + tEd25519Pubkey} {
+		address, err := PublicKeyHexToAddress(strings.TrimPrefix(pubkeyHex, "0x"))
+		if err != nil || address != tEd25519Account {
+			t.Errorf("derive account from ed25519 mpc pubkey %v failed, have %v %v want %v", pubkeyHex, address, err, tEd25519Account)
+		}
+		if err = VerifyMPCPubKey(tEd25519Account, strings.TrimPrefix(pubkeyHex, "0x")); err != nil {
+			t.Errorf("verify ed25519 mpc pubkey failed: %v", err)
+		}
+		if mpcPubkey := getMPCEd25519Pubkey(pubkeyHex); mpcPubkey != tEd25519Pubkey[2:] {
+			t.Errorf("mpc ed public key should have no 0xED prefix, have %v", mpcPubkey)
+		}
+	}
+
+	// raw mpc ed public key without 0xED prefix
+	rawPubkey, _ := hex.DecodeString(tEd25519Pubkey[2:])
+	if address := GetAddress(ImportPublicKey(rawPubkey), nil); address != tEd25519Account {
+		t.Errorf("derive account from raw ed25519 pubkey failed, have %v want %v", address, tEd25519Account)
+	}
+
+	ecKey, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	seq := uint32(0)
+	if address := PublicKeyToAddress(ecKey.Public(&seq)); address != tEcdsaAccount {
+		t.Errorf("derive account from ecdsa pubkey failed, have %v want %v", address, tEcdsaAccount)
+	}
+}
@@ -0,0 +1,81 @@
+package ripple
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+// key types of the mpc public key
+const (
+	KeyTypeSecp256k1 = "secp256k1"
+	KeyTypeEd25519   = "ed25519"
+)
+
+// ErrMalformedPubKey the mpc public key has wrong length or prefix, or is not a valid curve point
+var ErrMalformedPubKey = errors.New("malformed mpc public key")
+
+// mpcKeyMap mpc address -> *MPCKey
+var mpcKeyMap = new(sync.Map)
+
+// MPCKey the key of the mpc account, detected from its public key
+type MPCKey struct {
+	Type       string // KeyTypeSecp256k1 or KeyTypeEd25519
+	PublicKey  []byte // ripple format, ed25519 is with 0xED prefix and secp256k1 is compressed
+	SignPubKey string // the public key requested to sign, ed25519 is without 0xED prefix and secp256k1 is as configed
+}
+
+// ParseMPCPubKey detect the key type of the mpc public key (hex) and validate its length and prefix.
+// secp256k1 public key is compressed (0x02 or 0x03 prefix) or uncompressed (0x04 prefix),
+// ed25519 public key is with 0xED prefix (ripple format) or without it (raw 32 bytes, as mpc ed public key).
+func ParseMPCPubKey(mpcPubkey string) (*MPCKey, error) {
+	pubkeyHex := strings.TrimPrefix(strings.TrimPrefix(mpcPubkey, "0x"), "0X")
+	pubkey, err := hex.DecodeString(pubkeyHex)
+	if err != nil {
+		return nil, fmt.Errorf("%w, %v", ErrMalformedPubKey, err)
+	}
+	switch {
+	case isEd25519Pubkey(pubkey):
+		return &MPCKey{Type: KeyTypeEd25519, PublicKey: pubkey, SignPubKey: pubkeyHex[2:]}, nil
+	case len(pubkey) == ed25519.PublicKeySize:
+		return &MPCKey{Type: KeyTypeEd25519, PublicKey: append([]byte{ed25519PubkeyPrefix}, pubkey...), SignPubKey: pubkeyHex}, nil
+	case len(pubkey) == PubKeyBytesLenCompressed && (pubkey[0] == 0x2 || pubkey[0] == 0x3),
+		len(pubkey) == PubKeyBytesLenUncompressed && pubkey[0] == 0x4:
+		ecPubkey, errf := btcec.ParsePubKey(pubkey, btcec.S256())
+		if errf != nil {
+			return nil, fmt.Errorf("%w, %v", ErrMalformedPubKey, errf)
+		}
+		return &MPCKey{Type: KeyTypeSecp256k1, PublicKey: ecPubkey.SerializeCompressed(), SignPubKey: mpcPubkey}, nil
+	case len(pubkey) == 0:
+		return nil, fmt.Errorf("%w, empty public key", ErrMalformedPubKey)
+	default:
+		return nil, fmt.Errorf("%w, length %v with prefix 0x%02x", ErrMalformedPubKey, len(pubkey), pubkey[0])
+	}
+}
+
+// getMPCKey get the cached key of the mpc account,
+// the key of the mpc not initialized by InitRouterInfo is detected and cached on the first use.
+func getMPCKey(mpc string) (*MPCKey, error) {
+	if key, exist := mpcKeyMap.Load(mpc); exist {
+		return key.(*MPCKey), nil
+	}
+	pubkeyHex := router.GetMPCPublicKey(mpc)
+	if pubkeyHex == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	key, err := ParseMPCPubKey(pubkeyHex)
+	if err != nil {
+		return nil, err
+	}
+	mpcKeyMap.Store(mpc, key)
+	log.Info("detect mpc key type success", "mpc", mpc, "keyType", key.Type)
+	return key, nil
+}
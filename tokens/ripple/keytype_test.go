@@ -0,0 +1,80 @@
+package ripple
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+func TestParseMPCPubKey(t *testing.T) {
+	const ecdsaPubkey = "03D49C56E1B185F1BE899AE66A02EFC17F78EA6FC53AF85E0FE54C6E8B7F8C71A8"
+	ecPubkey, err := btcec.ParsePubKey(common.FromHex(ecdsaPubkey), btcec.S256())
+	if err != nil {
+		t.Fatal(err)
+	}
+	uncompressed := hex.EncodeToString(ecPubkey.SerializeUncompressed())
+
+	tests := []struct {
+		pubkey     string
+		keyType    string
+		publicKey  string
+		signPubKey string
+	}{
+		{"0x" + ecdsaPubkey, KeyTypeSecp256k1, ecdsaPubkey, "0x" + ecdsaPubkey},
+		{uncompressed, KeyTypeSecp256k1, ecdsaPubkey, uncompressed},
+		{tEd25519Pubkey, KeyTypeEd25519, tEd25519Pubkey, tEd25519Pubkey[2:]},
+		{"0x" + tEd25519Pubkey[2:], KeyTypeEd25519, tEd25519Pubkey, tEd25519Pubkey[2:]}, // raw mpc ed public key
+	}
+	for _, test := range tests {
+		key, err := ParseMPCPubKey(test.pubkey)
+		if err != nil {
+			t.Errorf("parse mpc pubkey %v failed: %v", test.pubkey, err)
+			continue
+		}
+		if key.Type != test.keyType || !strings.EqualFold(hex.EncodeToString(key.PublicKey), test.publicKey) || key.SignPubKey != test.signPubKey {
+			t.Errorf("parse mpc pubkey %v mismatch, have %v %X %v", test.pubkey, key.Type, key.PublicKey, key.SignPubKey)
+		}
+	}
+
+	malformeds := []string{
+		"",
+		"0xnothex",
+		ecdsaPubkey[:62],                         // 31 bytes
+		"05" + ecdsaPubkey[2:],                   // wrong prefix
+		"02" + strings.Repeat("00", 31) + "05",   // not on curve
+		"04" + ecdsaPubkey[2:] + ecdsaPubkey[2:], // uncompressed not on curve
+		tEd25519Pubkey + "00",                    // wrong length
+	}
+	for _, pubkey := range malformeds {
+		if _, err := ParseMPCPubKey(pubkey); !errors.Is(err, ErrMalformedPubKey) {
+			t.Errorf("parse malformed mpc pubkey %q should fail with %v, but have %v", pubkey, ErrMalformedPubKey, err)
+		}
+	}
+}
+
+func TestGetMPCKeyCached(t *testing.T) {
+	router.SetMPCPublicKey(tEd25519Account, tEd25519Pubkey[2:])
+	key, err := getMPCKey(tEd25519Account)
+	if err != nil || key.Type != KeyTypeEd25519 {
+		t.Fatalf("get mpc key failed, have %v %v", key, err)
+	}
+	if address := PublicKeyToAddress(key.PublicKey); address != tEd25519Account {
+		t.Errorf("mpc key address mismatch, have %v want %v", address, tEd25519Account)
+	}
+	// the cached key is used, no ambiguity per sign
+	router.SetMPCPublicKey(tEd25519Account, "")
+	if cached, err := getMPCKey(tEd25519Account); err != nil || cached != key {
+		t.Errorf("mpc key should be cached, have %v %v", cached, err)
+	}
+
+	const malformedMPC = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+	router.SetMPCPublicKey(malformedMPC, "05"+strings.Repeat("00", 32))
+	if _, err = getMPCKey(malformedMPC); !errors.Is(err, ErrMalformedPubKey) {
+		t.Errorf("malformed mpc pubkey should fail with %v, but have %v", ErrMalformedPubKey, err)
+	}
+}
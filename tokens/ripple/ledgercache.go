@@ -0,0 +1,91 @@
+package ripple
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+var (
+	// ledgerCacheTTL ledgers are closed every 3-5 seconds
+	ledgerCacheTTL = 3 * time.Second
+	// ledgerCacheMaxStale stale cached ledger is returned while refreshing in background
+	ledgerCacheMaxStale = 10 * time.Second
+)
+
+// ledgerIndexCache caches the latest validated ledger index.
+// It is shared by the goroutines using the same bridge.
+type ledgerIndexCache struct {
+	mu         sync.RWMutex
+	index      uint64
+	updatedAt  time.Time
+	refreshing int32
+
+	fetch func() (uint64, error)
+}
+
+func newLedgerIndexCache(fetch func() (uint64, error)) *ledgerIndexCache {
+	return &ledgerIndexCache{fetch: fetch}
+}
+
+func (c *ledgerIndexCache) get() (index uint64, updatedAt time.Time) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.index, c.updatedAt
+}
+
+func (c *ledgerIndexCache) set(index uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// ledger index never goes back
+	if index > c.index {
+		c.index = index
+	}
+	c.updatedAt = time.Now()
+}
+
+func (c *ledgerIndexCache) refresh() (uint64, error) {
+	index, err := c.fetch()
+	if err != nil {
+		return 0, err
+	}
+	c.set(index)
+	cached, _ := c.get()
+	return cached, nil
+}
+
+func (c *ledgerIndexCache) refreshInBackground() {
+	if !atomic.CompareAndSwapInt32(&c.refreshing, 0, 1) {
+		return
+	}
+	go func() {
+		defer atomic.StoreInt32(&c.refreshing, 0)
+		if _, err := c.refresh(); err != nil {
+			log.Warn("refresh ripple latest ledger failed", "err", err)
+		}
+	}()
+}
+
+// Get returns the cached ledger index if it's fresh,
+// refreshes it in background if it's a little stale,
+// and refreshes it synchronously if it's too stale.
+func (c *ledgerIndexCache) Get() (uint64, error) {
+	index, updatedAt := c.get()
+	age := time.Since(updatedAt)
+	switch {
+	case index > 0 && age < ledgerCacheTTL:
+		return index, nil
+	case index > 0 && age < ledgerCacheMaxStale:
+		c.refreshInBackground()
+		return index, nil
+	default:
+		return c.refresh()
+	}
+}
+
+// GetLatestLedgerNumber gets latest validated ledger index from cache
+func (b *Bridge) GetLatestLedgerNumber() (uint64, error) {
+	return b.ledgerCache.Get()
+}
@@ -0,0 +1,81 @@
+package ripple
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func newCountingLedgerCache(calls *int64) *ledgerIndexCache {
+	return newLedgerIndexCache(func() (uint64, error) {
+		return uint64(1000 + atomic.AddInt64(calls, 1)), nil
+	})
+}
+
+func TestLedgerIndexCacheConcurrent(t *testing.T) {
+	var calls int64
+	cache := newCountingLedgerCache(&calls)
+	if _, err := cache.Get(); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				if index, err := cache.Get(); err != nil || index < 1001 {
+					t.Errorf("get cached ledger index failed, have %v %v", index, err)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if have := atomic.LoadInt64(&calls); have != 1 {
+		t.Errorf("fresh ledger index should be read from cache, but fetched %v times", have)
+	}
+}
+
+func TestLedgerIndexCacheRefresh(t *testing.T) {
+	var calls int64
+	cache := newCountingLedgerCache(&calls)
+	if index, _ := cache.Get(); index != 1001 {
+		t.Fatalf("first get should fetch ledger index, have %v", index)
+	}
+
+	// a little stale, returns cached and refresh in background
+	cache.mu.Lock()
+	cache.updatedAt = time.Now().Add(-ledgerCacheTTL)
+	cache.mu.Unlock()
+	if index, _ := cache.Get(); index != 1001 {
+		t.Errorf("stale ledger index should be returned while refreshing, have %v", index)
+	}
+	for i := 0; i < 100 && atomic.LoadInt32(&cache.refreshing) != 0; i++ {
+		time.Sleep(time.Millisecond)
+	}
+	if index, _ := cache.get(); index != 1002 {
+		t.Errorf("ledger index should be refreshed in background, have %v", index)
+	}
+
+	// too stale, refresh synchronously
+	cache.mu.Lock()
+	cache.updatedAt = time.Now().Add(-ledgerCacheMaxStale)
+	cache.mu.Unlock()
+	if index, _ := cache.Get(); index != 1003 {
+		t.Errorf("too stale ledger index should be refreshed, have %v", index)
+	}
+}
+
+func BenchmarkLedgerIndexCache(b *testing.B) {
+	var calls int64
+	cache := newCountingLedgerCache(&calls)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			_, _ = cache.Get()
+		}
+	})
+}
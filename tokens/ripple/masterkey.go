@@ -0,0 +1,42 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+var (
+	// ErrMasterKeyDisabled the account disabled its master key, which the signing key is
+	ErrMasterKeyDisabled = errors.New("master key is disabled")
+	// ErrRegularKeyMismatch the signing key is neither the master key nor the regular key of the account
+	ErrRegularKeyMismatch = errors.New("signing key is not the regular key")
+)
+
+// checkSigningKey check the account accepts the signing key before signing.
+// If the account disabled its master key (lsfDisableMaster), txs signed with the master key
+// always fail (tefMASTER_DISABLED), so the signing key must be the regular key of the account.
+func (b *Bridge) checkSigningKey(account string, pubkey []byte) error {
+	acct, err := b.GetAccount(account)
+	if err != nil {
+		return fmt.Errorf("get signer account failed: %w", err)
+	}
+	var regularKey string
+	if acct.AccountData.RegularKey != nil {
+		regularKey = acct.AccountData.RegularKey.String()
+	}
+	signer := PublicKeyToAddress(pubkey)
+	if signer == account {
+		if flags := acct.AccountData.Flags; flags != nil && *flags&data.LsDisableMaster != 0 {
+			log.Error("master key of account is disabled", "account", account, "regularKey", regularKey)
+			return fmt.Errorf("%w, account: %v, regular key: %v", ErrMasterKeyDisabled, account, regularKey)
+		}
+		return nil
+	}
+	if regularKey == "" || signer != regularKey {
+		return fmt.Errorf("%w, account: %v, signer: %v, regular key: %v", ErrRegularKeyMismatch, account, signer, regularKey)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestCheckSigningKey(t *testing.T) {
+	masterKey, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := GetAddress(masterKey, nil)
+	masterPubKey := masterKey.Public(nil)
+	otherKey, err := ImportKeyFromSeed(tSeed, "ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherPubKey := otherKey.Public(nil)
+	otherAddress := GetAddress(otherKey, nil)
+
+	var flags uint32
+	var regularKey string
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		if method != "account_info" || rpcParams[0]["account"] != account {
+			t.Errorf("unexpected rpc call %v %v", method, rpcParams)
+		}
+		result := accountInfoResult(account, "100000000").(map[string]interface{})
+		accountData := result["account_data"].(map[string]interface{})
+		accountData["Flags"] = flags
+		if regularKey != "" {
+			accountData["RegularKey"] = regularKey
+		}
+		return result
+	})
+
+	if err = b.checkSigningKey(account, masterPubKey); err != nil {
+		t.Errorf("sign with master key failed: %v", err)
+	}
+
+	// master key is disabled
+	flags = uint32(data.LsDisableMaster)
+	regularKey = otherAddress
+	if err = b.checkSigningKey(account, masterPubKey); !errors.Is(err, ErrMasterKeyDisabled) {
+		t.Errorf("sign with disabled master key should fail with %v, but have %v", ErrMasterKeyDisabled, err)
+	}
+	if err = b.checkSigningKey(account, otherPubKey); err != nil {
+		t.Errorf("sign with regular key failed: %v", err)
+	}
+
+	// signing key is not the regular key
+	regularKey = tReceiver
+	if err = b.checkSigningKey(account, otherPubKey); !errors.Is(err, ErrRegularKeyMismatch) {
+		t.Errorf("sign with wrong regular key should fail with %v, but have %v", ErrRegularKeyMismatch, err)
+	}
+	regularKey = ""
+	if err = b.checkSigningKey(account, otherPubKey); !errors.Is(err, ErrRegularKeyMismatch) {
+		t.Errorf("sign without regular key should fail with %v, but have %v", ErrRegularKeyMismatch, err)
+	}
+}
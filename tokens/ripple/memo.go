@@ -0,0 +1,89 @@
+package ripple
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// memo encodings of `MemoData`
+const (
+	MemoEncodingRaw = "raw" // the memo bytes
+	MemoEncodingHex = "hex" // the hex encoding of the memo bytes
+)
+
+var (
+	// ErrSwapMemoMismatch the memo of the tx is not the unique swap identifier
+	ErrSwapMemoMismatch = errors.New("swap memo mismatch")
+)
+
+// getMemoEncoding get the encoding of `MemoData`, configed by `memoEncoding` custom (`raw` or `hex`).
+// configed is false if it is not set, and the raw encoding is used then.
+func (b *Bridge) getMemoEncoding() (encoding string, configed bool) {
+	if b.ChainConfig == nil {
+		return MemoEncodingRaw, false
+	}
+	encoding = params.GetCustom(b.ChainConfig.ChainID, "memoEncoding")
+	switch encoding {
+	case "":
+		return MemoEncodingRaw, false
+	case MemoEncodingRaw, MemoEncodingHex:
+		return encoding, true
+	default:
+		log.Warn("unknown memo encoding, use raw", "chainID", b.ChainConfig.ChainID, "memoEncoding", encoding)
+		return MemoEncodingRaw, true
+	}
+}
+
+// encodeMemo encode the memo with the encoding, the result is set as `MemoData` bytes
+func encodeMemo(memo, encoding string) string {
+	if encoding == MemoEncodingHex {
+		return strings.ToUpper(hex.EncodeToString([]byte(memo)))
+	}
+	return memo
+}
+
+// decodeMemo decode `MemoData` bytes with the encoding
+func decodeMemo(memoData []byte, encoding string) (string, error) {
+	if encoding == MemoEncodingHex {
+		memo, err := hex.DecodeString(string(memoData))
+		if err != nil {
+			return "", fmt.Errorf("wrong hex memo %q, %w", memoData, err)
+		}
+		return string(memo), nil
+	}
+	return string(memoData), nil
+}
+
+// getSwapMemo get the memo of the payout, which is the unique swap identifier with the memo encoding
+func (b *Bridge) getSwapMemo(args *tokens.BuildTxArgs) string {
+	encoding, _ := b.getMemoEncoding()
+	return encodeMemo(args.GetUniqueSwapIdentifier(), encoding)
+}
+
+// checkSwapMemo verify the memo of the payout decodes to the unique swap identifier with the memo encoding,
+// it is checked only if `memoEncoding` is configed.
+func (b *Bridge) checkSwapMemo(memos data.Memos, args *tokens.BuildTxArgs) error {
+	encoding, configed := b.getMemoEncoding()
+	if !configed {
+		return nil
+	}
+	want := args.GetUniqueSwapIdentifier()
+	if len(memos) != 1 {
+		return fmt.Errorf("%w, have %v memos", ErrSwapMemoMismatch, len(memos))
+	}
+	memo, err := decodeMemo(memos[0].Memo.MemoData.Bytes(), encoding)
+	if err != nil {
+		return fmt.Errorf("%w, %v", ErrSwapMemoMismatch, err)
+	}
+	if memo != want {
+		return fmt.Errorf("%w, have %q want %q", ErrSwapMemoMismatch, memo, want)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package ripple
+
+import (
+	"bytes"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestSwapMemoEncoding(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	setEncoding := func(encoding string) {
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"memoEncoding": encoding})
+	}
+
+	args := &tokens.BuildTxArgs{}
+	args.Bind = tReceiver
+	args.FromChainID = big.NewInt(1)
+	args.SwapID = "0x1111111111111111111111111111111111111111111111111111111111111111"
+	identifier := args.GetUniqueSwapIdentifier()
+
+	wantMemoData := map[string]string{
+		MemoEncodingRaw: identifier,
+		MemoEncodingHex: "313A3078" + strings.Repeat("31", 64) + "3A30", // "1:0x11..11:0"
+	}
+	for encoding, want := range wantMemoData {
+		setEncoding(encoding)
+		tx, err := NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "1000000", "12", b.getSwapMemo(args), "", 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// read back from the signed blob
+		stx, _, err := b.SignTransactionWithRippleKey(tx, key, nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, raw, err := data.Raw(stx.(data.Transaction))
+		if err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := data.ReadTransaction(bytes.NewReader(raw))
+		if err != nil {
+			t.Fatal(err)
+		}
+		memos := decoded.GetBase().Memos
+		if len(memos) != 1 || string(memos[0].Memo.MemoData.Bytes()) != want {
+			t.Fatalf("%v memo data mismatch, have %v want %v", encoding, memos, want)
+		}
+		if memo, errf := decodeMemo(memos[0].Memo.MemoData.Bytes(), encoding); errf != nil || memo != identifier {
+			t.Errorf("%v memo round trip mismatch, have %q %v want %q", encoding, memo, errf, identifier)
+		}
+		if err = b.verifyTransactionWithArgs(decoded, args); err != nil {
+			t.Errorf("verify payment with %v memo failed: %v", encoding, err)
+		}
+
+		// decoded with the other convention
+		other := MemoEncodingHex
+		if encoding == MemoEncodingHex {
+			other = MemoEncodingRaw
+		}
+		setEncoding(other)
+		if err = b.verifyTransactionWithArgs(decoded, args); !errors.Is(err, ErrSwapMemoMismatch) {
+			t.Errorf("verify %v memo as %v should fail with %v, but have %v", encoding, other, ErrSwapMemoMismatch, err)
+		}
+	}
+
+	// not verified by default
+	setTestCustoms(t, b.ChainConfig.ChainID, nil)
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "1000000", "12", "other memo", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = b.verifyTransactionWithArgs(tx, args); err != nil {
+		t.Errorf("memo should not be verified by default, but have %v", err)
+	}
+}
+
+func TestParseSwapMemosEncoding(t *testing.T) {
+	dstChainID := GetStubChainID(testnetNetWork)
+	router.SetBridge(dstChainID.String(), NewCrossChainBridge())
+	t.Cleanup(func() { router.SetBridge(dstChainID.String(), nil) })
+
+	bind := tReceiver + ":" + dstChainID.String()
+	for _, encoding := range []string{MemoEncodingRaw, MemoEncodingHex} {
+		// the raw bind memo is accepted in hex encoding too
+		for _, memoData := range []string{encodeMemo(bind, encoding), bind} {
+			memos := data.Memos{{}}
+			memos[0].Memo.MemoData = []byte(memoData)
+			swapInfo := &tokens.SwapTxInfo{}
+			if !parseSwapMemos(swapInfo, memos, encoding) || swapInfo.Bind != tReceiver || swapInfo.ToChainID.Cmp(dstChainID) != 0 {
+				t.Errorf("parse %v memo %q failed, have %v %v", encoding, memoData, swapInfo.Bind, swapInfo.ToChainID)
+			}
+		}
+	}
+}
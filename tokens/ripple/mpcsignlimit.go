@@ -0,0 +1,94 @@
+package ripple
+
+import (
+	"strconv"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// mpcSignLimiter limits the concurrent mpc sign requests of a bridge.
+// The limit is read on every acquiring, so reloading the config takes effect at once.
+type mpcSignLimiter struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	active  int
+	waiting int
+
+	getLimit func() int
+}
+
+func newMPCSignLimiter(getLimit func() int) *mpcSignLimiter {
+	l := &mpcSignLimiter{getLimit: getLimit}
+	l.cond = sync.NewCond(&l.mu)
+	return l
+}
+
+// acquire wait for the concurrency limit, the queue depth is reported to the gauge
+func (l *mpcSignLimiter) acquire(queueGauge metrics.Gauge) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.waiting++
+	queueGauge.Update(int64(l.waiting))
+	for limit := l.getLimit(); limit > 0 && l.active >= limit; limit = l.getLimit() {
+		l.cond.Wait()
+	}
+	l.waiting--
+	queueGauge.Update(int64(l.waiting))
+	l.active++
+}
+
+func (l *mpcSignLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.active--
+	l.cond.Broadcast()
+}
+
+func (l *mpcSignLimiter) stats() (active, waiting int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.active, l.waiting
+}
+
+// getMaxConcurrentMPCSign get the max concurrent mpc sign requests,
+// configed by `maxConcurrentMPCSign` custom, no limit if not set
+func (b *Bridge) getMaxConcurrentMPCSign() int {
+	if b.ChainConfig == nil {
+		return 0
+	}
+	limitStr := params.GetCustom(b.ChainConfig.ChainID, "maxConcurrentMPCSign")
+	if limitStr == "" {
+		return 0
+	}
+	limit, err := strconv.Atoi(limitStr)
+	if err != nil || limit < 0 {
+		log.Warn("wrong maxConcurrentMPCSign custom", "chainID", b.ChainConfig.ChainID, "value", limitStr)
+		return 0
+	}
+	return limit
+}
+
+// GetMPCSignQueueDepth get the count of mpc sign requests waiting for the concurrency limit
+func (b *Bridge) GetMPCSignQueueDepth() int {
+	_, waiting := b.signLimiter.stats()
+	return waiting
+}
+
+// getMPCSignQueueGauge get the gauge of the mpc sign queue depth of the chain
+// (`ripple/<chainID>/mpcsign/queue` in the default metrics registry)
+func (b *Bridge) getMPCSignQueueGauge() metrics.Gauge {
+	return metrics.GetOrRegisterGauge("ripple/"+b.ChainConfig.ChainID+"/mpcsign/queue", nil)
+}
+
+// doMPCSign call the mpc sign function under the concurrency limit
+func (b *Bridge) doMPCSign(signFn func() (string, []string, error)) (keyID string, rsvs []string, err error) {
+	b.signLimiter.acquire(b.getMPCSignQueueGauge())
+	defer b.signLimiter.release()
+	if active, waiting := b.signLimiter.stats(); waiting > 0 {
+		log.Info("mpc sign requests are queued", "chainID", b.ChainConfig.ChainID, "active", active, "queue", waiting)
+	}
+	return signFn()
+}
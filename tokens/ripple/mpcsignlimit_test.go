@@ -0,0 +1,106 @@
+package ripple
+
+import (
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+func TestMPCSignConcurrencyLimit(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"maxConcurrentMPCSign": "3"})
+
+	// the gauge is a nil gauge unless metrics are enabled when it is registered
+	defer func(enabled bool) { metrics.Enabled = enabled }(metrics.Enabled)
+	metrics.Enabled = true
+	gaugeName := "ripple/" + b.ChainConfig.ChainID + "/mpcsign/queue"
+	metrics.DefaultRegistry.Unregister(gaugeName)
+	t.Cleanup(func() { metrics.DefaultRegistry.Unregister(gaugeName) })
+
+	const burst = 20
+	var running, maxRunning, maxQueue, maxGauge, calls int32
+	var wg sync.WaitGroup
+	for i := 0; i < burst; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, rsvs, err := b.doMPCSign(func() (string, []string, error) {
+				n := atomic.AddInt32(&running, 1)
+				defer atomic.AddInt32(&running, -1)
+				for {
+					old := atomic.LoadInt32(&maxRunning)
+					if n <= old || atomic.CompareAndSwapInt32(&maxRunning, old, n) {
+						break
+					}
+				}
+				if depth := int32(b.GetMPCSignQueueDepth()); depth > atomic.LoadInt32(&maxQueue) {
+					atomic.StoreInt32(&maxQueue, depth)
+				}
+				if depth := int32(b.getMPCSignQueueGauge().Value()); depth > atomic.LoadInt32(&maxGauge) {
+					atomic.StoreInt32(&maxGauge, depth)
+				}
+				atomic.AddInt32(&calls, 1)
+				time.Sleep(10 * time.Millisecond)
+				return "keyID", []string{"rsv"}, nil
+			})
+			if err != nil || len(rsvs) != 1 {
+				t.Errorf("mpc sign failed, rsvs %v err %v", rsvs, err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if calls != burst {
+		t.Errorf("all sign requests should be done, have %v want %v", calls, burst)
+	}
+	if maxRunning > 3 {
+		t.Errorf("concurrent mpc sign exceeds the limit, have %v want at most 3", maxRunning)
+	}
+	if maxQueue == 0 {
+		t.Errorf("sign requests exceeding the limit should be queued")
+	}
+	if depth := b.GetMPCSignQueueDepth(); depth != 0 {
+		t.Errorf("queue should be empty after the burst, have %v", depth)
+	}
+	gauge, ok := metrics.DefaultRegistry.Get(gaugeName).(metrics.Gauge)
+	if !ok || maxGauge == 0 || gauge.Value() != 0 {
+		t.Errorf("queue depth should be reported to gauge %v, have max %v", gaugeName, maxGauge)
+	}
+}
+
+func TestMPCSignWithoutLimit(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.ChainConfig = &tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()}
+
+	const burst = 10
+	var running int32
+	started := make(chan struct{}, burst)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	for i := 0; i < burst; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, _, _ = b.doMPCSign(func() (string, []string, error) {
+				atomic.AddInt32(&running, 1)
+				started <- struct{}{}
+				<-done
+				return "", nil, nil
+			})
+		}()
+	}
+	for i := 0; i < burst; i++ {
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("sign requests should not be limited, only %v started", atomic.LoadInt32(&running))
+		}
+	}
+	close(done)
+	wg.Wait()
+}
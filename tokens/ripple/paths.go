@@ -0,0 +1,44 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// ErrPathsForNativePayment XRP to XRP payment must not have paths (temBAD_SEND_XRP_PATHS)
+var ErrPathsForNativePayment = errors.New("paths are not allowed for XRP payment")
+
+// getPaymentPaths get the paths supplied by the operator for a problematic route,
+// which is `Extra.Paths` of the build args, or configed by `paymentPaths:<tokenID>` custom.
+// the paths are validated by ParsePaths and used verbatim, empty if not supplied.
+func (b *Bridge) getPaymentPaths(args *tokens.BuildTxArgs, token *tokens.TokenConfig, asset *data.Asset) (string, error) {
+	var paths string
+	if args.Extra != nil && args.Extra.Paths != nil {
+		paths = *args.Extra.Paths
+	} else {
+		paths = params.GetCustom(b.ChainConfig.ChainID, "paymentPaths:"+token.TokenID)
+	}
+	if paths = strings.TrimSpace(paths); paths == "" {
+		return "", nil
+	}
+	if asset.IsNative() {
+		return "", fmt.Errorf("%w, paths: %v", ErrPathsForNativePayment, paths)
+	}
+	if _, err := ParsePaths(paths); err != nil {
+		return "", fmt.Errorf("wrong payment paths %v, %w", paths, err)
+	}
+	log.Info("build payment with supplied paths", "chainID", b.ChainConfig.ChainID,
+		"swapID", args.SwapID, "tokenID", token.TokenID, "paths", paths)
+	// record the configed paths, so that the accept nodes rebuild the same tx
+	if args.Extra == nil {
+		args.Extra = &tokens.AllExtras{}
+	}
+	args.Extra.Paths = &paths
+	return paths, nil
+}
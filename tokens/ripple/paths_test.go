@@ -0,0 +1,73 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestPaymentPaths(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	token := &tokens.TokenConfig{TokenID: "USD"}
+	asset := &data.Asset{Currency: "USD", Issuer: tIssuer}
+	args := &tokens.BuildTxArgs{}
+
+	if paths, err := b.getPaymentPaths(args, token, asset); err != nil || paths != "" {
+		t.Errorf("no paths should be supplied by default, have %q %v", paths, err)
+	}
+
+	configed := "EUR/" + tIssuer + " => " + tReceiver
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"paymentPaths:USD": configed})
+	paths, err := b.getPaymentPaths(args, token, asset)
+	if err != nil || paths != configed {
+		t.Errorf("configed paths mismatch, have %q %v want %q", paths, err, configed)
+	}
+	// the accept nodes rebuild the tx with the paths in the args
+	if args.Extra == nil || args.Extra.Paths == nil || *args.Extra.Paths != configed {
+		t.Errorf("configed paths should be recorded in the build args, have %+v", args.Extra)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, nil)
+	if paths, err = b.getPaymentPaths(args, token, asset); err != nil || paths != configed {
+		t.Errorf("recorded paths should be used regardless of the config, have %q %v want %q", paths, err, configed)
+	}
+
+	// the paths of the build args take precedence
+	supplied := tReceiver + "," + "XRP/" + tIssuer + " => USD/" + tIssuer
+	args.Extra = &tokens.AllExtras{Paths: &supplied}
+	if paths, err = b.getPaymentPaths(args, token, asset); err != nil || paths != supplied {
+		t.Fatalf("supplied paths mismatch, have %q %v want %q", paths, err, supplied)
+	}
+
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "10/USD/"+tIssuer, "12", "", paths, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pathSet := tx.(*data.Payment).Paths
+	if pathSet == nil || len(*pathSet) != 2 {
+		t.Fatalf("payment should have the supplied paths, have %v", pathSet)
+	}
+	want := []string{tReceiver, "XRP/" + tIssuer + " => USD/" + tIssuer}
+	for i, path := range *pathSet {
+		if path.String() != want[i] {
+			t.Errorf("path %v mismatch, have %v want %v", i, path, want[i])
+		}
+	}
+
+	if _, err = b.getPaymentPaths(args, &tokens.TokenConfig{TokenID: "XRP"}, &data.Asset{Currency: "XRP"}); !errors.Is(err, ErrPathsForNativePayment) {
+		t.Errorf("paths of XRP payment should fail with %v, but have %v", ErrPathsForNativePayment, err)
+	}
+	for _, malformed := range []string{tReceiver + ",", "USD/" + tIssuer + " => rInvalid", "USD/" + tIssuer + "/extra"} {
+		args.Extra.Paths = &malformed
+		if _, err = b.getPaymentPaths(args, token, asset); err == nil {
+			t.Errorf("malformed paths %q should fail", malformed)
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// defaultChannelSettleDelay default settle delay (in seconds) of the created payment channel
+const defaultChannelSettleDelay uint32 = 3600
+
+var (
+	// ErrPaymentChannelNotNative payment channel only holds XRP
+	ErrPaymentChannelNotNative = errors.New("payment channel only supports XRP")
+	// ErrWrongChannelID the payment channel id is not a 256 bits hash
+	ErrWrongChannelID = errors.New("wrong payment channel id")
+)
+
+// getPaymentChannelTimes get the settle delay and cancel after time of the created payment channel,
+// which are configed by `channelSettleDelay` (in seconds, default to 3600)
+// and `channelCancelAfter` (in seconds, 0 means never expire) customs.
+func (b *Bridge) getPaymentChannelTimes() (settleDelay uint32, cancelAfter *uint32, err error) {
+	settleDelay = defaultChannelSettleDelay
+	if delayStr := params.GetCustom(b.ChainConfig.ChainID, "channelSettleDelay"); delayStr != "" {
+		settleDelay, err = common.GetUint32FromStr(delayStr)
+		if err != nil {
+			return 0, nil, fmt.Errorf("wrong channelSettleDelay %v", delayStr)
+		}
+	}
+	if cancelStr := params.GetCustom(b.ChainConfig.ChainID, "channelCancelAfter"); cancelStr != "" {
+		seconds, errf := common.GetUint32FromStr(cancelStr)
+		if errf != nil {
+			return 0, nil, fmt.Errorf("wrong channelCancelAfter %v", cancelStr)
+		}
+		if seconds != 0 {
+			cancelAt := data.Now().Uint32() + seconds
+			cancelAfter = &cancelAt
+		}
+	}
+	return settleDelay, cancelAfter, nil
+}
+
+// NewUnsignedPaymentChannelCreateTransaction build ripple payment channel create tx.
+// The channel is funded with amount (XRP) from the key's account to dest,
+// and the key's public key is the one which signs the off-ledger claims of the channel.
+func NewUnsignedPaymentChannelCreateTransaction(
+	key crypto.Key, keyseq *uint32, txseq uint32,
+	dest string, destinationTag *uint32,
+	amount, fee, memo string, settleDelay uint32, cancelAfter *uint32,
+) (data.Transaction, error) {
+	destination, err := data.NewAccountFromAddress(dest)
+	if err != nil {
+		return nil, err
+	}
+	amt, err := data.NewAmount(amount)
+	if err != nil {
+		return nil, err
+	}
+	if !amt.IsNative() {
+		return nil, fmt.Errorf("%w, amount %v", ErrPaymentChannelNotNative, amount)
+	}
+	tx := &data.PaymentChannelCreate{
+		Destination:    *destination,
+		Amount:         *amt,
+		SettleDelay:    settleDelay,
+		CancelAfter:    cancelAfter,
+		DestinationTag: destinationTag,
+	}
+	tx.TransactionType = data.PAYCHAN_CREATE
+	copy(tx.PublicKey.Bytes(), key.Public(keyseq))
+
+	if memo != "" {
+		memoStr := new(data.Memo)
+		memoStr.Memo.MemoData = []byte(memo)
+		tx.Memos = append(tx.Memos, *memoStr)
+	}
+
+	base := tx.GetBase()
+
+	base.Sequence = txseq
+
+	fei, err := ParseNativeFee(fee)
+	if err != nil {
+		return nil, err
+	}
+	base.Fee = *fei
+
+	copy(base.Account[:], key.Id(keyseq))
+
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
+	hash, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	var cancelAt string
+	if cancelAfter != nil {
+		cancelAt = data.NewRippleTime(*cancelAfter).Time().UTC().Format(time.RFC3339)
+	}
+	log.Info("Build unsigned payment channel create tx success",
+		"destination", dest, "amount", amount, "memo", memo,
+		"fee", fee, "sequence", txseq, "settleDelay", settleDelay, "cancelAfter", cancelAt,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
+
+// NewUnsignedPaymentChannelClaimTransaction build ripple payment channel claim tx sent by the channel source,
+// which settles the channel by delivering the cumulative balance (XRP) to the destination,
+// and requests to close the channel if closeChannel is true.
+func NewUnsignedPaymentChannelClaimTransaction(
+	key crypto.Key, keyseq *uint32, txseq uint32,
+	channel, balance, fee, memo string, closeChannel bool,
+) (data.Transaction, error) {
+	channelID, err := data.NewHash256(channel)
+	if err != nil || channelID.IsZero() {
+		return nil, fmt.Errorf("%w, %v", ErrWrongChannelID, channel)
+	}
+	tx := &data.PaymentChannelClaim{Channel: *channelID}
+	tx.TransactionType = data.PAYCHAN_CLAIM
+	if balance != "" {
+		bal, errf := data.NewAmount(balance)
+		if errf != nil {
+			return nil, errf
+		}
+		if !bal.IsNative() {
+			return nil, fmt.Errorf("%w, balance %v", ErrPaymentChannelNotNative, balance)
+		}
+		tx.Balance = bal
+	}
+	if closeChannel {
+		tx.Flags = new(data.TransactionFlag)
+		*tx.Flags = data.TxClose
+	}
+
+	if memo != "" {
+		memoStr := new(data.Memo)
+		memoStr.Memo.MemoData = []byte(memo)
+		tx.Memos = append(tx.Memos, *memoStr)
+	}
+
+	base := tx.GetBase()
+
+	base.Sequence = txseq
+
+	fei, err := ParseNativeFee(fee)
+	if err != nil {
+		return nil, err
+	}
+	base.Fee = *fei
+
+	copy(base.Account[:], key.Id(keyseq))
+
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), key.Public(keyseq))
+	hash, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("Build unsigned payment channel claim tx success",
+		"channel", channel, "balance", balance, "close", closeChannel, "memo", memo,
+		"fee", fee, "sequence", txseq,
+		"signing hash", hash.String(), "blob", fmt.Sprintf("%X", msg))
+
+	return tx, nil
+}
+
+// BuildPaymentChannelCreateTransaction build the payment channel funded with amount (in drops) from args.From
+// to the counterparty dest, for the integrations settling with it by off-ledger claims.
+// The returned raw tx is not a swap payout, it can not be signed by the mpc nodes (which rebuild the payout from the args),
+// but only by the private key signer (`SignWithPrivateKey` of the mpc config).
+func (b *Bridge) BuildPaymentChannelCreateTransaction(args *tokens.BuildTxArgs, dest string, destTag *uint32, amount *big.Int) (rawTx interface{}, err error) {
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	if amount == nil || amount.Sign() <= 0 || !amount.IsInt64() {
+		return nil, fmt.Errorf("wrong payment channel amount %v", amount)
+	}
+	amt, err := data.NewAmount(amount.Int64())
+	if err != nil {
+		return nil, err
+	}
+	settleDelay, cancelAfter, err := b.getPaymentChannelTimes()
+	if err != nil {
+		return nil, err
+	}
+	extra, err := b.setExtraArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
+	tx, err := NewUnsignedPaymentChannelCreateTransaction(
+		ripplePubKey, nil, uint32(*extra.Sequence),
+		dest, destTag, amt.String(), *extra.Fee, b.getSwapMemo(args), settleDelay, cancelAfter)
+	if err != nil {
+		return nil, err
+	}
+	setLastLedgerSequence(tx, extra)
+	return tx, nil
+}
+
+// BuildPaymentChannelClaimTransaction build the periodical settlement of the payment channel created by args.From,
+// balance (in drops) is the cumulative amount delivered to the destination of the channel.
+// As the create tx, it can only be signed by the private key signer.
+func (b *Bridge) BuildPaymentChannelClaimTransaction(args *tokens.BuildTxArgs, channel string, balance *big.Int, closeChannel bool) (rawTx interface{}, err error) {
+	if args.From == "" {
+		return nil, fmt.Errorf("forbid empty sender")
+	}
+	mpcPubkey := router.GetMPCPublicKey(args.From)
+	if mpcPubkey == "" {
+		return nil, tokens.ErrMissMPCPublicKey
+	}
+	var balanceStr string
+	if balance != nil {
+		if balance.Sign() <= 0 || !balance.IsInt64() {
+			return nil, fmt.Errorf("wrong payment channel balance %v", balance)
+		}
+		bal, errf := data.NewAmount(balance.Int64())
+		if errf != nil {
+			return nil, errf
+		}
+		balanceStr = bal.String()
+	}
+	extra, err := b.setExtraArgs(args)
+	if err != nil {
+		return nil, err
+	}
+	ripplePubKey := ImportPublicKey(common.FromHex(mpcPubkey))
+	tx, err := NewUnsignedPaymentChannelClaimTransaction(
+		ripplePubKey, nil, uint32(*extra.Sequence),
+		channel, balanceStr, *extra.Fee, b.getSwapMemo(args), closeChannel)
+	if err != nil {
+		return nil, err
+	}
+	setLastLedgerSequence(tx, extra)
+	return tx, nil
+}
@@ -0,0 +1,125 @@
+package ripple
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const tChannelID = "C1AE6DDDEEC05CF2978C0BAD6FE302948E9533691DC749DCDD3B9E5992CA6198"
+
+func TestNewUnsignedPaymentChannelCreateTransaction(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := uint32(8)
+	cancelAfter := uint32(750000000)
+
+	tx, err := NewUnsignedPaymentChannelCreateTransaction(key, nil, 9, tReceiver, &tag, "100", "12", "swap memo", 86400, &cancelAfter)
+	if err != nil {
+		t.Fatal(err)
+	}
+	channelCreate, ok := tx.(*data.PaymentChannelCreate)
+	if !ok {
+		t.Fatalf("tx should be PaymentChannelCreate, but have %T", tx)
+	}
+	if channelCreate.TransactionType != data.PAYCHAN_CREATE {
+		t.Errorf("tx type mismatch, have %v", channelCreate.TransactionType)
+	}
+	if channelCreate.Destination.String() != tReceiver || *channelCreate.DestinationTag != tag {
+		t.Errorf("channel destination mismatch, have %v:%v", channelCreate.Destination, *channelCreate.DestinationTag)
+	}
+	if !channelCreate.Amount.IsNative() || channelCreate.Amount.Drops() != 100 {
+		t.Errorf("channel amount mismatch, have %v", channelCreate.Amount)
+	}
+	if channelCreate.SettleDelay != 86400 || channelCreate.CancelAfter == nil || *channelCreate.CancelAfter != cancelAfter {
+		t.Errorf("channel times mismatch, have settle delay %v cancel after %v", channelCreate.SettleDelay, channelCreate.CancelAfter)
+	}
+	if !bytes.Equal(channelCreate.PublicKey.Bytes(), key.Public(nil)) {
+		t.Errorf("channel public key mismatch, have %X", channelCreate.PublicKey.Bytes())
+	}
+	if channelCreate.Account.String() != "rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz" || channelCreate.Sequence != 9 || channelCreate.Fee.String() != "0.000012" {
+		t.Errorf("channel account, sequence or fee mismatch, have %v %v %v", channelCreate.Account, channelCreate.Sequence, channelCreate.Fee)
+	}
+
+	b := NewCrossChainBridge()
+	stx, _, err := b.SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatalf("sign payment channel create tx failed: %v", err)
+	}
+	if err = VerifySignedTransactionEncoding(stx.(data.Transaction)); err != nil {
+		t.Errorf("verify payment channel create tx encoding failed: %v", err)
+	}
+
+	if _, err = NewUnsignedPaymentChannelCreateTransaction(key, nil, 9, tReceiver, nil, "1.5/USD/"+tIssuer, "12", "", 86400, nil); !errors.Is(err, ErrPaymentChannelNotNative) {
+		t.Errorf("IOU payment channel should fail with %v, but have %v", ErrPaymentChannelNotNative, err)
+	}
+}
+
+func TestNewUnsignedPaymentChannelClaimTransaction(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx, err := NewUnsignedPaymentChannelClaimTransaction(key, nil, 10, tChannelID, "25", "12", "settle", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	claim, ok := tx.(*data.PaymentChannelClaim)
+	if !ok {
+		t.Fatalf("tx should be PaymentChannelClaim, but have %T", tx)
+	}
+	if claim.TransactionType != data.PAYCHAN_CLAIM || claim.Channel.String() != tChannelID {
+		t.Errorf("claim type or channel mismatch, have %v %v", claim.TransactionType, claim.Channel)
+	}
+	if claim.Balance == nil || claim.Balance.Drops() != 25 || claim.Amount != nil || claim.Signature != nil {
+		t.Errorf("claim balance mismatch, have %v", claim.Balance)
+	}
+	if claim.Flags == nil || *claim.Flags&data.TxClose == 0 {
+		t.Errorf("claim should request to close the channel, have flags %v", claim.Flags)
+	}
+	stx, _, err := NewCrossChainBridge().SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatalf("sign payment channel claim tx failed: %v", err)
+	}
+	if err = VerifySignedTransactionEncoding(stx.(data.Transaction)); err != nil {
+		t.Errorf("verify payment channel claim tx encoding failed: %v", err)
+	}
+
+	if tx, err = NewUnsignedPaymentChannelClaimTransaction(key, nil, 10, tChannelID, "", "12", "", false); err != nil {
+		t.Errorf("claim without balance failed: %v", err)
+	} else if claim = tx.(*data.PaymentChannelClaim); claim.Balance != nil || claim.Flags != nil {
+		t.Errorf("claim without balance mismatch, have balance %v flags %v", claim.Balance, claim.Flags)
+	}
+	for _, channel := range []string{"", "abc", "0000000000000000000000000000000000000000000000000000000000000000"} {
+		if _, err = NewUnsignedPaymentChannelClaimTransaction(key, nil, 10, channel, "25", "12", "", false); !errors.Is(err, ErrWrongChannelID) {
+			t.Errorf("claim of channel %q should fail with %v, but have %v", channel, ErrWrongChannelID, err)
+		}
+	}
+}
+
+func TestGetPaymentChannelTimes(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(mainnetNetWork).String()})
+
+	if delay, cancelAfter, err := b.getPaymentChannelTimes(); err != nil || delay != defaultChannelSettleDelay || cancelAfter != nil {
+		t.Errorf("default payment channel times mismatch, have %v %v %v", delay, cancelAfter, err)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"channelSettleDelay": "600", "channelCancelAfter": "86400"})
+	delay, cancelAfter, err := b.getPaymentChannelTimes()
+	if err != nil || delay != 600 || cancelAfter == nil {
+		t.Fatalf("configed payment channel times mismatch, have %v %v %v", delay, cancelAfter, err)
+	}
+	if diff := int64(*cancelAfter) - int64(data.Now().Uint32()); diff < 86400-5 || diff > 86400 {
+		t.Errorf("payment channel cancel after mismatch, have %v seconds later", diff)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"channelSettleDelay": "abc"})
+	if _, _, err = b.getPaymentChannelTimes(); err == nil {
+		t.Error("wrong channelSettleDelay should fail")
+	}
+}
@@ -0,0 +1,71 @@
+package ripple
+
+import (
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// ownerReserve default reserve of each object owned by the account (eg. a Check), see getReserves
+var ownerReserve = big.NewInt(2000000)
+
+// PayoutCost the XRP impact (in drops) of a payout on the mpc account
+type PayoutCost struct {
+	Amount             *big.Int `json:"amount"`             // delivered XRP, zero for IOU payout
+	Fee                *big.Int `json:"fee"`                // tx fee
+	OwnerReserve       *big.Int `json:"ownerReserve"`       // reserve locked by the created object
+	CreatesDestination bool     `json:"createsDestination"` // the payout funds a new account
+	Total              *big.Int `json:"total"`              // sum of amount, fee and owner reserve
+}
+
+// EstimatePayoutCost estimate the XRP impact of the payout of the args
+func (b *Bridge) EstimatePayoutCost(args *tokens.BuildTxArgs) (*PayoutCost, error) {
+	payout, err := b.getPayout(args)
+	if err != nil {
+		return nil, err
+	}
+	var fee string
+	if args.Extra != nil && args.Extra.Fee != nil {
+		fee = *args.Extra.Fee
+	} else if fee, err = b.getTxFee(); err != nil {
+		return nil, err
+	}
+	return b.estimatePayoutCost(payout, fee)
+}
+
+func (b *Bridge) estimatePayoutCost(payout *payout, fee string) (*PayoutCost, error) {
+	feeVal, err := data.NewValue(fee, true)
+	if err != nil {
+		return nil, err
+	}
+	cost := &PayoutCost{
+		Amount:       big.NewInt(0),
+		Fee:          big.NewInt(feeVal.Drops()),
+		OwnerReserve: big.NewInt(0),
+	}
+
+	useCheck, err := b.useCheckDelivery(payout.token, payout.receiver)
+	if err != nil {
+		return nil, err
+	}
+	if useCheck {
+		// the Check is owned by the sender until it is cashed or canceled
+		cost.OwnerReserve.Set(b.getReserves().Increment)
+	} else if payout.asset.IsNative() {
+		cost.Amount.Set(payout.amount)
+		acct, errf := b.GetAccount(payout.receiver)
+		if errf != nil {
+			return nil, errf
+		}
+		cost.CreatesDestination = acct.AccountData.Balance == nil
+	}
+
+	cost.Total = new(big.Int).Add(cost.Amount, cost.Fee)
+	cost.Total.Add(cost.Total, cost.OwnerReserve)
+	log.Info("estimate payout cost", "chainID", b.ChainConfig.ChainID, "receiver", payout.receiver,
+		"amount", cost.Amount, "fee", cost.Fee, "ownerReserve", cost.OwnerReserve,
+		"createsDestination", cost.CreatesDestination, "total", cost.Total)
+	return cost, nil
+}
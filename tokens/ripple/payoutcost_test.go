@@ -0,0 +1,74 @@
+package ripple
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestEstimatePayoutCost(t *testing.T) {
+	var receiverExists bool
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		switch method {
+		case "server_state":
+			return serverStateResult()
+		case "account_info":
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		if !receiverExists {
+			return map[string]interface{}{"error": "actNotFound", "status": "error"}
+		}
+		return accountInfoResult(tReceiver, "20000000")
+	})
+
+	xrp, _ := data.NewAsset("XRP")
+	usd, _ := data.NewAsset("USD/" + tIssuer)
+	tests := []struct {
+		name           string
+		payout         *payout
+		receiverExists bool
+		want           PayoutCost
+	}{
+		{
+			name:           "native payout",
+			payout:         &payout{token: &tokens.TokenConfig{}, asset: xrp, receiver: tReceiver, amount: big.NewInt(5000000)},
+			receiverExists: true,
+			want:           PayoutCost{Amount: big.NewInt(5000000), Fee: big.NewInt(12), OwnerReserve: big.NewInt(0), Total: big.NewInt(5000012)},
+		},
+		{
+			name:   "native payout creating destination",
+			payout: &payout{token: &tokens.TokenConfig{}, asset: xrp, receiver: tReceiver, amount: big.NewInt(20000000)},
+			want: PayoutCost{Amount: big.NewInt(20000000), Fee: big.NewInt(12), OwnerReserve: big.NewInt(0),
+				CreatesDestination: true, Total: big.NewInt(20000012)},
+		},
+		{
+			name:           "IOU payout",
+			payout:         &payout{token: &tokens.TokenConfig{}, asset: usd, receiver: tReceiver, amount: big.NewInt(1500000)},
+			receiverExists: true,
+			want:           PayoutCost{Amount: big.NewInt(0), Fee: big.NewInt(12), OwnerReserve: big.NewInt(0), Total: big.NewInt(12)},
+		},
+		{
+			// the owner reserve of the validated ledger
+			name:           "IOU payout by check",
+			payout:         &payout{token: &tokens.TokenConfig{Extra: DeliveryModeCheck}, asset: usd, receiver: tReceiver, amount: big.NewInt(1500000)},
+			receiverExists: true,
+			want:           PayoutCost{Amount: big.NewInt(0), Fee: big.NewInt(12), OwnerReserve: big.NewInt(200000), Total: big.NewInt(200012)},
+		},
+	}
+	for _, test := range tests {
+		receiverExists = test.receiverExists
+		cost, err := b.estimatePayoutCost(test.payout, "0.000012")
+		if err != nil {
+			t.Errorf("%v: estimate payout cost failed: %v", test.name, err)
+			continue
+		}
+		if cost.Amount.Cmp(test.want.Amount) != 0 || cost.Fee.Cmp(test.want.Fee) != 0 ||
+			cost.OwnerReserve.Cmp(test.want.OwnerReserve) != 0 || cost.Total.Cmp(test.want.Total) != 0 ||
+			cost.CreatesDestination != test.want.CreatesDestination {
+			t.Errorf("%v: payout cost mismatch, have %+v want %+v", test.name, cost, test.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+var (
+	// ErrReceiverDenied receiver is in the `receiverDenyList` custom
+	ErrReceiverDenied = errors.New("receiver is denied")
+	// ErrReceiverNotAllowed receiver is not in the `receiverAllowList` custom
+	ErrReceiverNotAllowed = errors.New("receiver is not allowed")
+	// ErrSpecialReceiver receiver is a special address (see `specialReceivers` custom) or the issuer of the payout token
+	ErrSpecialReceiver = errors.New("receiver is a special address")
+
+	// defaultSpecialReceivers the special addresses which payouts to are mistakes,
+	// ACCOUNT_ZERO, ACCOUNT_ONE and the genesis account
+	defaultSpecialReceivers = []string{
+		"rrrrrrrrrrrrrrrrrrrrrhoLvTp",
+		"rrrrrrrrrrrrrrrrrrrrBZbvji",
+		"rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh",
+	}
+)
+
+// noSpecialReceivers `specialReceivers` custom value to disable the special receivers check
+const noSpecialReceivers = "none"
+
+// getReceiverList get comma separated addresses of the custom
+func (b *Bridge) getReceiverList(key string) []string {
+	var list []string
+	for _, addr := range strings.Split(params.GetCustom(b.ChainConfig.ChainID, key), ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			list = append(list, addr)
+		}
+	}
+	return list
+}
+
+func containsAddress(list []string, addr string) bool {
+	for _, item := range list {
+		if item == addr {
+			return true
+		}
+	}
+	return false
+}
+
+// checkReceiverList refuse payouts to the receivers in the deny list,
+// and to the receivers not in the allow list if the allow list is set.
+// The lists are read from customs on every check, so they are reloaded with the config.
+func (b *Bridge) checkReceiverList(receiver string) error {
+	if containsAddress(b.getReceiverList("receiverDenyList"), receiver) {
+		log.Warn("blocked payout to denied receiver", "chainID", b.ChainConfig.ChainID, "receiver", receiver)
+		return fmt.Errorf("%w, receiver: %v", ErrReceiverDenied, receiver)
+	}
+	if allowList := b.getReceiverList("receiverAllowList"); len(allowList) > 0 && !containsAddress(allowList, receiver) {
+		log.Warn("blocked payout to not allowed receiver", "chainID", b.ChainConfig.ChainID, "receiver", receiver)
+		return fmt.Errorf("%w, receiver: %v", ErrReceiverNotAllowed, receiver)
+	}
+	return nil
+}
+
+// getSpecialReceivers get the special receivers configed by `specialReceivers` custom
+// (comma separated addresses, replace the default ones), or `none` to disable it.
+func (b *Bridge) getSpecialReceivers() []string {
+	switch strings.TrimSpace(params.GetCustom(b.ChainConfig.ChainID, "specialReceivers")) {
+	case "":
+		return defaultSpecialReceivers
+	case noSpecialReceivers:
+		return nil
+	default:
+		return b.getReceiverList("specialReceivers")
+	}
+}
+
+// checkSpecialReceiver refuse payouts to the special receivers
+func (b *Bridge) checkSpecialReceiver(receiver string) error {
+	if containsAddress(b.getSpecialReceivers(), receiver) {
+		log.Warn("blocked payout to special receiver", "chainID", b.ChainConfig.ChainID, "receiver", receiver)
+		return fmt.Errorf("%w, receiver: %v", ErrSpecialReceiver, receiver)
+	}
+	return nil
+}
+
+// checkIssuerReceiver refuse IOU payouts to the issuer, which redeem the IOU instead of delivering it
+func (b *Bridge) checkIssuerReceiver(receiver string, asset *data.Asset) error {
+	if !asset.IsNative() && asset.Issuer == receiver {
+		log.Warn("blocked payout to the issuer", "chainID", b.ChainConfig.ChainID, "receiver", receiver, "currency", asset.Currency)
+		return fmt.Errorf("%w, receiver is the issuer of %v", ErrSpecialReceiver, asset.Currency)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func TestCheckReceiverList(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	// no lists, all receivers are allowed
+	if err := b.checkReceiverList(tReceiver); err != nil {
+		t.Errorf("receiver should be allowed without lists, but have %v", err)
+	}
+
+	// deny list only
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"receiverDenyList": tSender + ", " + tIssuer})
+	if err := b.checkReceiverList(tIssuer); !errors.Is(err, ErrReceiverDenied) {
+		t.Errorf("denied receiver should fail with %v, but have %v", ErrReceiverDenied, err)
+	}
+	if err := b.checkReceiverList(tReceiver); err != nil {
+		t.Errorf("not listed receiver should be allowed in deny list mode, but have %v", err)
+	}
+
+	// allow list, deny list takes precedence
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"receiverAllowList": tReceiver + "," + tSender,
+		"receiverDenyList":  tSender,
+	})
+	if err := b.checkReceiverList(tReceiver); err != nil {
+		t.Errorf("allowed receiver should pass, but have %v", err)
+	}
+	if err := b.checkReceiverList(tIssuer); !errors.Is(err, ErrReceiverNotAllowed) {
+		t.Errorf("not listed receiver should fail with %v in allow list mode, but have %v", ErrReceiverNotAllowed, err)
+	}
+	if err := b.checkReceiverList(tSender); !errors.Is(err, ErrReceiverDenied) {
+		t.Errorf("denied receiver should fail with %v even if allowed, but have %v", ErrReceiverDenied, err)
+	}
+
+	// reloaded lists take effect at once
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"receiverAllowList": tIssuer})
+	if err := b.checkReceiverList(tIssuer); err != nil {
+		t.Errorf("reloaded allow list should take effect, but have %v", err)
+	}
+}
+
+func TestCheckSpecialReceiver(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+
+	for _, receiver := range defaultSpecialReceivers {
+		if err := b.checkSpecialReceiver(receiver); !errors.Is(err, ErrSpecialReceiver) {
+			t.Errorf("payout to %v should fail with %v, but have %v", receiver, ErrSpecialReceiver, err)
+		}
+	}
+	// refused in getReceiverAndAmount before any query
+	args := &tokens.BuildTxArgs{}
+	args.Bind = "rHb9CJAWyB4rj91VRWn96DkukG4bwdtyTh:8"
+	if _, _, _, err := b.getReceiverAndAmount(args, "XRP"); !errors.Is(err, ErrSpecialReceiver) {
+		t.Errorf("payout to genesis account should fail with %v, but have %v", ErrSpecialReceiver, err)
+	}
+	if err := b.checkSpecialReceiver(tReceiver); err != nil {
+		t.Errorf("receiver should be allowed, but have %v", err)
+	}
+
+	// configed list replaces the default one
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"specialReceivers": tReceiver})
+	if err := b.checkSpecialReceiver(tReceiver); !errors.Is(err, ErrSpecialReceiver) {
+		t.Errorf("configed special receiver should fail with %v, but have %v", ErrSpecialReceiver, err)
+	}
+	if err := b.checkSpecialReceiver(defaultSpecialReceivers[1]); err != nil {
+		t.Errorf("default special receiver should be replaced, but have %v", err)
+	}
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"specialReceivers": "none"})
+	if err := b.checkSpecialReceiver(defaultSpecialReceivers[1]); err != nil {
+		t.Errorf("special receivers check should be disabled, but have %v", err)
+	}
+
+	// IOU payout to the issuer is refused
+	iou, _ := convertToAsset("USD/" + tIssuer)
+	if err := b.checkIssuerReceiver(tIssuer, iou); !errors.Is(err, ErrSpecialReceiver) {
+		t.Errorf("payout to the issuer should fail with %v, but have %v", ErrSpecialReceiver, err)
+	}
+	if err := b.checkIssuerReceiver(tReceiver, iou); err != nil {
+		t.Errorf("payout to other receiver should be allowed, but have %v", err)
+	}
+	xrp, _ := convertToAsset("XRP")
+	if err := b.checkIssuerReceiver(tIssuer, xrp); err != nil {
+		t.Errorf("native payout has no issuer, but have %v", err)
+	}
+}
@@ -0,0 +1,190 @@
+package ripple
+
+import (
+	"fmt"
+	"math/big"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/router"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+var (
+	// defaultRefillFeeBuffer default XRP (in drops) kept for each pending payout, the fee and the min reserve fee
+	defaultRefillFeeBuffer = big.NewInt(100000)
+	// defaultRefillPendingPayouts default count of the pending payouts to keep the fee buffer for
+	defaultRefillPendingPayouts int64 = 10
+
+	refillWebhookTimeout = 10 // seconds
+
+	// RefillAlertHook is called when the refill monitor finds the XRP balance of a mpc account
+	// is below its reserve plus the fee buffer of the pending payouts.
+	RefillAlertHook func(alert *RefillAlert)
+)
+
+// RefillAlert the alert of a mpc account which needs refilling, amounts of XRP are in drops
+type RefillAlert struct {
+	ChainID   string   `json:"chainID"`
+	Account   string   `json:"account"`
+	Balance   *big.Int `json:"balance"`
+	Reserve   *big.Int `json:"reserve"`   // base reserve plus owner reserve
+	Threshold *big.Int `json:"threshold"` // reserve plus fee buffer of the pending payouts
+}
+
+// getRefillCheckInterval get the interval of the refill monitor,
+// which is configed by `refillCheckInterval` custom (in seconds, the monitor is disabled if not set)
+func (b *Bridge) getRefillCheckInterval() time.Duration {
+	intervalStr := params.GetCustom(b.ChainConfig.ChainID, "refillCheckInterval")
+	if intervalStr == "" {
+		return 0
+	}
+	seconds, err := strconv.ParseUint(intervalStr, 10, 64)
+	if err != nil {
+		log.Warn("wrong refillCheckInterval custom", "chainID", b.ChainConfig.ChainID, "value", intervalStr)
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// getRefillFeeBuffer get the fee buffer kept for the pending payouts above the reserve, which is
+// `refillFeeBuffer` custom (in drops, per payout) times `refillPendingPayouts` custom.
+func (b *Bridge) getRefillFeeBuffer() (*big.Int, error) {
+	feeBuffer := new(big.Int).Set(defaultRefillFeeBuffer)
+	if feeStr := params.GetCustom(b.ChainConfig.ChainID, "refillFeeBuffer"); feeStr != "" {
+		fee, err := common.GetBigIntFromStr(feeStr)
+		if err != nil || fee.Sign() < 0 {
+			return nil, fmt.Errorf("wrong refillFeeBuffer %v", feeStr)
+		}
+		feeBuffer.Set(fee)
+	}
+	pending := defaultRefillPendingPayouts
+	if pendingStr := params.GetCustom(b.ChainConfig.ChainID, "refillPendingPayouts"); pendingStr != "" {
+		value, err := strconv.ParseInt(pendingStr, 10, 64)
+		if err != nil || value < 0 {
+			return nil, fmt.Errorf("wrong refillPendingPayouts %v", pendingStr)
+		}
+		pending = value
+	}
+	return feeBuffer.Mul(feeBuffer, big.NewInt(pending)), nil
+}
+
+// CheckRefill check the XRP balance of every mpc account against its reserve plus the fee buffer
+// of the pending payouts, and emit an alert for each account below it (see emitRefillAlert).
+func (b *Bridge) CheckRefill() ([]*RefillAlert, error) {
+	feeBuffer, err := b.getRefillFeeBuffer()
+	if err != nil {
+		return nil, err
+	}
+	mpcs, _, err := b.GetMPCTokenConfigs(router.GetRouterMPC)
+	if err != nil {
+		return nil, err
+	}
+
+	var alerts []*RefillAlert
+	for _, mpc := range mpcs {
+		alert, errf := b.checkAccountRefill(mpc, feeBuffer)
+		if errf != nil {
+			log.Warn("check account refill failed", "chainID", b.ChainConfig.ChainID, "account", mpc, "err", errf)
+			continue
+		}
+		if alert != nil {
+			b.emitRefillAlert(alert)
+			alerts = append(alerts, alert)
+		}
+	}
+	return alerts, nil
+}
+
+func (b *Bridge) checkAccountRefill(account string, feeBuffer *big.Int) (*RefillAlert, error) {
+	acct, err := b.GetAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	accountData := acct.AccountData
+	balance := big.NewInt(0)
+	if accountData.Balance != nil {
+		balance.SetInt64(accountData.Balance.Drops())
+	}
+	var ownerCount uint32
+	if accountData.OwnerCount != nil {
+		ownerCount = *accountData.OwnerCount
+	}
+	return b.getRefillAlert(account, balance, ownerCount, feeBuffer), nil
+}
+
+// getRefillAlert get the alert if the balance is below the reserve of the account plus the fee buffer, nil if not
+func (b *Bridge) getRefillAlert(account string, balance *big.Int, ownerCount uint32, feeBuffer *big.Int) *RefillAlert {
+	reserve := b.getReserves().AccountReserve(ownerCount)
+	threshold := new(big.Int).Add(reserve, feeBuffer)
+	if balance.Cmp(threshold) >= 0 {
+		return nil
+	}
+	return &RefillAlert{
+		ChainID:   b.ChainConfig.ChainID,
+		Account:   account,
+		Balance:   balance,
+		Reserve:   reserve,
+		Threshold: threshold,
+	}
+}
+
+// checkBalanceRefill emit the alert if the balance of the account (queried when building a payout)
+// needs refilling, by the same threshold as the refill monitor.
+func (b *Bridge) checkBalanceRefill(account string, balance *big.Int, ownerCount uint32) {
+	feeBuffer, err := b.getRefillFeeBuffer()
+	if err != nil {
+		log.Warn("get refill fee buffer failed", "chainID", b.ChainConfig.ChainID, "err", err)
+		return
+	}
+	if alert := b.getRefillAlert(account, balance, ownerCount, feeBuffer); alert != nil {
+		b.emitRefillAlert(alert)
+	}
+}
+
+// emitRefillAlert log the alert, call RefillAlertHook,
+// and post the alert to `refillWebhook` custom (an url) if it is configed
+func (b *Bridge) emitRefillAlert(alert *RefillAlert) {
+	log.Warn("XRP balance of mpc is below reserve plus fee buffer, please refill", "chainID", alert.ChainID,
+		"account", alert.Account, "balance", alert.Balance, "reserve", alert.Reserve, "threshold", alert.Threshold)
+	if RefillAlertHook != nil {
+		RefillAlertHook(alert)
+	}
+	webhook := params.GetCustom(b.ChainConfig.ChainID, "refillWebhook")
+	if webhook == "" {
+		return
+	}
+	resp, err := client.HTTPPost(webhook, alert, nil, nil, refillWebhookTimeout)
+	if err != nil {
+		log.Warn("post refill alert failed", "chainID", alert.ChainID, "account", alert.Account, "err", err)
+		return
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		log.Warn("post refill alert failed", "chainID", alert.ChainID, "account", alert.Account, "status", resp.StatusCode)
+	}
+}
+
+// startRefillMonitor start checking the refill of the mpc accounts periodically
+// if `refillCheckInterval` custom is set
+func (b *Bridge) startRefillMonitor() {
+	interval := b.getRefillCheckInterval()
+	if interval == 0 {
+		return
+	}
+	b.refillMonitorOnce.Do(func() {
+		log.Info("start refill monitor", "chainID", b.ChainConfig.ChainID, "interval", interval)
+		go func() {
+			for {
+				time.Sleep(interval)
+				if _, err := b.CheckRefill(); err != nil {
+					log.Warn("check refill failed", "chainID", b.ChainConfig.ChainID, "err", err)
+				}
+			}
+		}()
+	})
+}
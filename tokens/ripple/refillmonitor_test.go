@@ -0,0 +1,75 @@
+package ripple
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCheckRefill(t *testing.T) {
+	balance := "1800000"
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "server_state":
+			return serverStateResult()
+		case "account_info":
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+		result := accountInfoResult(tSender, balance).(map[string]interface{})
+		result["account_data"].(map[string]interface{})["OwnerCount"] = 3
+		return result
+	})
+	b.ChainConfig.RouterContract = tSender
+
+	posted := make(chan *RefillAlert, 1)
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var alert RefillAlert
+		if err := json.NewDecoder(r.Body).Decode(&alert); err != nil {
+			t.Errorf("decode posted alert failed, %v", err)
+		}
+		posted <- &alert
+	}))
+	defer webhook.Close()
+
+	var hooked []*RefillAlert
+	defer func(hook func(*RefillAlert)) { RefillAlertHook = hook }(RefillAlertHook)
+	RefillAlertHook = func(alert *RefillAlert) { hooked = append(hooked, alert) }
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{
+		"refillPendingPayouts": "2",
+		"refillWebhook":        webhook.URL,
+	})
+
+	// threshold is the reserve of 1 XRP base plus 3 objects of 0.2 XRP, and 2 pending payouts of 0.1 XRP
+	alerts, err := b.CheckRefill()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 0 || len(hooked) != 0 {
+		t.Fatalf("balance at the threshold should not alert, have %v", alerts)
+	}
+
+	balance = "1799999"
+	if alerts, err = b.CheckRefill(); err != nil {
+		t.Fatal(err)
+	}
+	if len(alerts) != 1 || len(hooked) != 1 || hooked[0] != alerts[0] {
+		t.Fatalf("balance below the threshold should alert once, have %v hooked %v", alerts, hooked)
+	}
+	alert := alerts[0]
+	if alert.ChainID != b.ChainConfig.ChainID || alert.Account != tSender || alert.Balance.Int64() != 1799999 ||
+		alert.Reserve.Int64() != 1600000 || alert.Threshold.Int64() != 1800000 {
+		t.Errorf("alert mismatch, have %+v", alert)
+	}
+	select {
+	case webhookAlert := <-posted:
+		if webhookAlert.Account != tSender || webhookAlert.Threshold.Cmp(alert.Threshold) != 0 {
+			t.Errorf("posted alert mismatch, have %+v", webhookAlert)
+		}
+	default:
+		t.Error("alert is not posted to the webhook")
+	}
+}
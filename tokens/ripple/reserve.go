@@ -0,0 +1,88 @@
+package ripple
+
+import (
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+// reservesCacheTTL the reserves are changed rarely (by fee voting on flag ledgers)
+var reservesCacheTTL = 10 * time.Minute
+
+// ServerStateResult server_state result, the reserves of the validated ledger are in drops
+type ServerStateResult struct {
+	State *struct {
+		ValidatedLedger *struct {
+			ReserveBase uint64 `json:"reserve_base"`
+			ReserveInc  uint64 `json:"reserve_inc"`
+		} `json:"validated_ledger"`
+	} `json:"state"`
+}
+
+// Reserves the reserves of the validated ledger in drops
+type Reserves struct {
+	Base      *big.Int // base reserve of an account
+	Increment *big.Int // owner reserve of each object owned by the account
+}
+
+// AccountReserve get the reserve of the account, the base reserve plus the owner reserve of the owned objects
+func (r *Reserves) AccountReserve(ownerCount uint32) *big.Int {
+	reserve := new(big.Int).Mul(r.Increment, big.NewInt(int64(ownerCount)))
+	return reserve.Add(reserve, r.Base)
+}
+
+// defaultReserves the reserves used if they can not be queried
+func defaultReserves() *Reserves {
+	return &Reserves{
+		Base:      new(big.Int).Set(accountReserve),
+		Increment: new(big.Int).Set(ownerReserve),
+	}
+}
+
+// reservesCache caches the reserves of the validated ledger
+type reservesCache struct {
+	mu        sync.Mutex
+	reserves  *Reserves
+	updatedAt time.Time
+}
+
+// GetReserves get the reserves of the validated ledger by `server_state`
+func (b *Bridge) GetReserves() (reserves *Reserves, err error) {
+	rpcParams := map[string]interface{}{}
+	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	for _, url := range urls {
+		var res *ServerStateResult
+		err = b.rpcPost(&res, url, "server_state", rpcParams)
+		if err == nil && res != nil && res.State != nil && res.State.ValidatedLedger != nil {
+			ledger := res.State.ValidatedLedger
+			return &Reserves{
+				Base:      new(big.Int).SetUint64(ledger.ReserveBase),
+				Increment: new(big.Int).SetUint64(ledger.ReserveInc),
+			}, nil
+		}
+	}
+	return nil, wrapRPCQueryError(err, "server_state")
+}
+
+// getReserves get the cached reserves of the validated ledger (refreshed every reservesCacheTTL),
+// the last known reserves (or the default ones) are used if the query fails.
+func (b *Bridge) getReserves() *Reserves {
+	c := b.reserves
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.reserves != nil && time.Since(c.updatedAt) < reservesCacheTTL {
+		return c.reserves
+	}
+	reserves, err := b.GetReserves()
+	if err != nil {
+		log.Warn("get ripple reserves failed", "chainID", b.ChainConfig.ChainID, "err", err)
+		if c.reserves != nil {
+			return c.reserves
+		}
+		return defaultReserves()
+	}
+	c.reserves, c.updatedAt = reserves, time.Now()
+	return reserves
+}
@@ -0,0 +1,137 @@
+package ripple
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
+)
+
+var (
+	// ErrRPCBusy the node is rate limiting or too busy
+	ErrRPCBusy = errors.New("ripple rpc is busy")
+
+	rpcBusyRetryTimes   = 5
+	rpcBusyBackoff      = 500 * time.Millisecond
+	rpcBusyBackoffLimit = 8 * time.Second
+
+	// rippled error codes of rate limiting and overloading
+	rpcBusyErrors = map[string]bool{
+		"slowDown": true,
+		"tooBusy":  true,
+	}
+)
+
+// rpcLimiter limits the request rate to an api by spacing requests evenly
+type rpcLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+func (l *rpcLimiter) wait() {
+	if l.interval <= 0 {
+		return
+	}
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	waitTime := l.next.Sub(now)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if waitTime > 0 {
+		time.Sleep(waitTime)
+	}
+}
+
+// getRPCLimiter get limiter of api, the limit (requests per second) is configed by
+// `rpcRateLimit:<url>` custom, or `rpcRateLimit` custom for all apis (no limit if not set)
+func (b *Bridge) getRPCLimiter(url string) *rpcLimiter {
+	if limiter, exist := b.rpcLimiters.Load(url); exist {
+		return limiter.(*rpcLimiter)
+	}
+	limiter := &rpcLimiter{}
+	for _, key := range []string{"rpcRateLimit:" + url, "rpcRateLimit"} {
+		limitStr := params.GetCustom(b.ChainConfig.ChainID, key)
+		if limitStr == "" {
+			continue
+		}
+		limit, err := strconv.ParseFloat(limitStr, 64)
+		if err != nil || limit < 0 {
+			log.Warn("wrong rpc rate limit config", "chainID", b.ChainConfig.ChainID, "key", key, "value", limitStr)
+			continue
+		}
+		if limit > 0 {
+			limiter.interval = time.Duration(float64(time.Second) / limit)
+		}
+		break
+	}
+	actual, _ := b.rpcLimiters.LoadOrStore(url, limiter)
+	return actual.(*rpcLimiter)
+}
+
+// resetRPCLimiters drop the limiters, they are rebuilt with the current customs on the next request
+func (b *Bridge) resetRPCLimiters() {
+	b.rpcLimiters.Range(func(key, _ interface{}) bool {
+		b.rpcLimiters.Delete(key)
+		return true
+	})
+}
+
+type rpcErrorResult struct {
+	Error  string `json:"error"`
+	Status string `json:"status"`
+}
+
+func isRPCBusy(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, ErrRPCBusy) {
+		return true
+	}
+	errMsg := err.Error()
+	return strings.Contains(errMsg, "wrong response status 429") ||
+		strings.Contains(errMsg, "wrong response status 503")
+}
+
+// rpcPost post rpc request with rate limiting, and retry with
+// exponential backoff if the node returns busy responses.
+func (b *Bridge) rpcPost(result interface{}, url, method string, params ...interface{}) (err error) {
+	limiter := b.getRPCLimiter(url)
+	backoff := rpcBusyBackoff
+	var raw json.RawMessage
+	for i := 0; ; i++ {
+		limiter.wait()
+		raw = nil
+		err = client.RPCPostWithTimeout(b.RPCClientTimeout, &raw, url, method, params...)
+		if err == nil {
+			var errRes rpcErrorResult
+			if json.Unmarshal(raw, &errRes) == nil && rpcBusyErrors[errRes.Error] {
+				err = fmt.Errorf("%w, %v", ErrRPCBusy, errRes.Error)
+			}
+		}
+		if !isRPCBusy(err) || i >= rpcBusyRetryTimes {
+			break
+		}
+		log.Warn("ripple rpc is busy, retry later", "url", url, "method", method, "times", i+1, "backoff", backoff, "err", err)
+		time.Sleep(backoff)
+		if backoff *= 2; backoff > rpcBusyBackoffLimit {
+			backoff = rpcBusyBackoffLimit
+		}
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, result)
+}
@@ -0,0 +1,109 @@
+package ripple
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+func setTestRPCBackoff(t *testing.T, backoff time.Duration) {
+	oldBackoff, oldLimit := rpcBusyBackoff, rpcBusyBackoffLimit
+	rpcBusyBackoff, rpcBusyBackoffLimit = backoff, 8*backoff
+	t.Cleanup(func() { rpcBusyBackoff, rpcBusyBackoffLimit = oldBackoff, oldLimit })
+}
+
+func TestRPCBackoffOnBusy(t *testing.T) {
+	setTestRPCBackoff(t, 10*time.Millisecond)
+
+	var calls int
+	var busyTimes int
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		calls++
+		if calls <= busyTimes {
+			return map[string]interface{}{"error": "slowDown", "status": "error"}
+		}
+		return accountInfoResult(tSender, "20000000")
+	})
+
+	busyTimes = 3
+	start := time.Now()
+	res, err := b.GetAccount(tSender)
+	if err != nil || res.AccountData.Balance == nil {
+		t.Fatalf("get account should succeed after busy responses, have %v %v", res, err)
+	}
+	if calls != busyTimes+1 {
+		t.Errorf("busy rpc should be retried, have %v calls want %v", calls, busyTimes+1)
+	}
+	// backoff 10ms + 20ms + 40ms
+	if elapsed := time.Since(start); elapsed < 70*time.Millisecond {
+		t.Errorf("busy rpc should be retried with exponential backoff, but only %v elapsed", elapsed)
+	}
+
+	calls, busyTimes = 0, 100
+	var result interface{}
+	if err = b.rpcPost(&result, b.GetGatewayConfig().APIAddress[0], "account_info"); !errors.Is(err, ErrRPCBusy) {
+		t.Errorf("always busy rpc should fail with %v, have %v", ErrRPCBusy, err)
+	}
+	if calls != rpcBusyRetryTimes+1 {
+		t.Errorf("busy rpc retry times mismatch, have %v calls want %v", calls, rpcBusyRetryTimes+1)
+	}
+}
+
+func TestRPCBackoffOnTooManyRequests(t *testing.T) {
+	setTestRPCBackoff(t, time.Millisecond)
+
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls <= 2 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":{"ledger_current_index":100}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{srv.URL}})
+	if num, err := b.GetLatestBlockNumberOf(srv.URL); err != nil || num != 100 || calls != 3 {
+		t.Errorf("rate limited rpc should be retried, have %v %v with %v calls", num, err, calls)
+	}
+}
+
+func TestRPCRateLimit(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		return map[string]interface{}{"ledger_current_index": 100}
+	})
+	url := b.GetGatewayConfig().APIAddress[0]
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"rpcRateLimit": "1000", "rpcRateLimit:" + url: "50"})
+
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		if _, err := b.GetLatestBlockNumberOf(url); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 50 requests per second, 5 requests take at least 4 intervals of 20ms
+	if elapsed := time.Since(start); elapsed < 80*time.Millisecond {
+		t.Errorf("rpc requests should be rate limited, but only %v elapsed", elapsed)
+	}
+
+	// the reloaded rate limit takes effect once the chain config is set
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"rpcRateLimit:" + url: "2"})
+	b.SetChainConfig(b.ChainConfig)
+	start = time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := b.GetLatestBlockNumberOf(url); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// 2 requests per second, 2 requests take at least 1 interval of 500ms
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond {
+		t.Errorf("reloaded rpc rate limit should take effect, but only %v elapsed", elapsed)
+	}
+}
@@ -67,6 +67,7 @@ const (
 	LsNoFreeze       LedgerEntryFlag = 0x00200000
 	LsGlobalFreeze   LedgerEntryFlag = 0x00400000
 	LsDefaultRipple  LedgerEntryFlag = 0x00800000
+	LsDepositAuth    LedgerEntryFlag = 0x01000000
 
 	// Offer flags
 	LsPassive LedgerEntryFlag = 0x00010000
@@ -132,6 +133,7 @@ var leFlagNames = map[LedgerEntryType][]struct {
 		{LsDisallowXRP, "DisallowXRP"},
 		{LsDisableMaster, "DisableMaster"},
 		{LsNoFreeze, "NoFreeze"},
+		{LsDepositAuth, "DepositAuth"},
 	},
 	OFFER: {
 		{LsPassive, "Passive"},
@@ -150,6 +150,8 @@ type AccountLine struct {
 	LimitPeer    NonNativeValue `json:"limit_peer"`
 	NoRipple     bool           `json:"no_ripple"`
 	NoRipplePeer bool           `json:"no_ripple_peer"`
+	Freeze       bool           `json:"freeze"`
+	FreezePeer   bool           `json:"freeze_peer"`
 	QualityIn    uint32         `json:"quality_in"`
 	QualityOut   uint32         `json:"quality_out"`
 }
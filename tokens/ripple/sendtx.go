@@ -1,15 +1,12 @@
 package ripple
 
 import (
-	"fmt"
 	"time"
 
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/params"
-	"github.com/anyswap/CrossChain-Router/v3/rpc/client"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
-	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
 )
 
 // SendTransaction send signed tx
@@ -18,31 +15,35 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error
 	if !ok {
 		return "", tokens.ErrWrongRawTx
 	}
-	_, raw, err := data.Raw(tx)
-	if err != nil {
-		return "", err
-	}
-	rpcParams := map[string]interface{}{
-		"tx_blob": fmt.Sprintf("%X", raw),
-	}
-	var success bool
+	var success, rejected bool
 	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
 	for i := 0; i < rpcRetryTimes; i++ {
 		// try send to all remotes
 		for _, url := range urls {
-			var resp *websockets.SubmitResult
-			err = client.RPCPostWithTimeout(b.RPCClientTimeout, &resp, url, "submit", rpcParams)
-			if err != nil || resp == nil {
-				log.Warn("Try sending transaction failed", "error", err)
+			res, errf := b.Submit(url, tx)
+			if errf != nil {
+				log.Warn("Try sending transaction failed", "error", errf)
+				err = errf
+				continue
+			}
+			if res.Status() == SubmitTerminal {
+				log.Warn("send tx is rejected", "url", url, "result", res.EngineResult,
+					"code", res.EngineResultCode, "message", res.EngineResultMessage)
+				err = res.Err()
+				rejected = true
 				continue
 			}
-			if !resp.EngineResult.Success() {
-				log.Warn("send tx with error result", "result", resp.EngineResult, "message", resp.EngineResultMessage)
+			if res.Err() != nil {
+				log.Warn("send tx with error result", "url", url, "result", res.EngineResult,
+					"code", res.EngineResultCode, "status", res.Status(), "message", res.EngineResultMessage)
+			} else if !res.IsSuccess() {
+				log.Info("send tx with provisional result", "url", url, "result", res.EngineResult,
+					"code", res.EngineResultCode, "status", res.Status(), "message", res.EngineResultMessage)
 			}
 			txHash = tx.GetBase().Hash.String()
 			success = true
 		}
-		if success {
+		if success || rejected {
 			break
 		}
 		time.Sleep(rpcRetryInterval)
@@ -0,0 +1,91 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+var (
+	// ErrSequenceTxNotFound the sequence is consumed but the tx is not found in the searched account history
+	ErrSequenceTxNotFound = errors.New("tx of consumed sequence not found")
+
+	accountTxPageSize = 200
+	accountTxMaxPages = 10
+)
+
+// GetTransactionBySequence find the validated tx sent by the account which consumed the sequence,
+// to diagnose what is occupying a sequence when the sequences are stuck or gapped.
+// It returns nil if the sequence is not consumed yet (not less than the account sequence).
+// The account history is searched from the newest tx backward (at most `accountTxMaxPages` pages),
+// and ErrSequenceTxNotFound is returned if the tx is not found, eg. it's still not validated,
+// or it's older than the searched history.
+func (b *Bridge) GetTransactionBySequence(account string, sequence uint32) (*data.TransactionWithMetaData, error) {
+	acct, err := b.GetAccount(account)
+	if err != nil {
+		return nil, err
+	}
+	if acct.AccountData.Sequence == nil {
+		return nil, fmt.Errorf("account %v has no sequence", account)
+	}
+	if accountSeq := *acct.AccountData.Sequence; sequence >= accountSeq {
+		log.Info("sequence is not consumed yet", "account", account, "sequence", sequence, "accountSequence", accountSeq)
+		return nil, nil
+	}
+
+	var marker map[string]interface{}
+	for page := 0; page < accountTxMaxPages; page++ {
+		res, err := b.getAccountTx(account, marker)
+		if err != nil {
+			return nil, err
+		}
+		for _, tx := range res.Transactions {
+			base := tx.GetBase()
+			// tx using a ticket has a zero sequence
+			if base.Account.String() != account || base.Sequence == 0 {
+				continue
+			}
+			switch {
+			case base.Sequence == sequence:
+				return tx, nil
+			case base.Sequence < sequence:
+				// txs are in descending order, the sequence is skipped
+				return nil, fmt.Errorf("%w, account: %v, sequence: %v", ErrSequenceTxNotFound, account, sequence)
+			}
+		}
+		if len(res.Marker) == 0 {
+			break
+		}
+		marker = res.Marker
+	}
+	return nil, fmt.Errorf("%w, account: %v, sequence: %v", ErrSequenceTxNotFound, account, sequence)
+}
+
+// getAccountTx get a page of the validated txs of account, newest first
+func (b *Bridge) getAccountTx(account string, marker map[string]interface{}) (txRes *websockets.AccountTxResult, err error) {
+	rpcParams := map[string]interface{}{
+		"account":          account,
+		"ledger_index_min": -1,
+		"ledger_index_max": -1,
+		"limit":            accountTxPageSize,
+	}
+	if len(marker) > 0 {
+		rpcParams["marker"] = marker
+	}
+	urls := b.getTxQueryURLs()
+	for i := 0; i < rpcRetryTimes; i++ {
+		for _, url := range urls {
+			var res *websockets.AccountTxResult
+			err = b.rpcPost(&res, url, "account_tx", rpcParams)
+			if err == nil && res != nil {
+				return res, nil
+			}
+		}
+		time.Sleep(rpcRetryInterval)
+	}
+	return nil, wrapRPCQueryError(err, "GetAccountTx")
+}
@@ -0,0 +1,102 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+)
+
+func accountTxEntry(account string, sequence uint32) interface{} {
+	return map[string]interface{}{
+		"tx": map[string]interface{}{
+			"TransactionType": "Payment",
+			"Account":         account,
+			"Destination":     tReceiver,
+			"Amount":          "1000000",
+			"Fee":             "10",
+			"Flags":           0,
+			"Sequence":        sequence,
+			"SigningPubKey":   "",
+			"hash":            "0000000000000000000000000000000000000000000000000000000000000000",
+		},
+		"meta": map[string]interface{}{
+			"TransactionIndex":  0,
+			"TransactionResult": "tesSUCCESS",
+		},
+		"validated": true,
+	}
+}
+
+func TestGetTransactionBySequence(t *testing.T) {
+	pages := []interface{}{
+		map[string]interface{}{
+			"account": tSender,
+			"marker":  map[string]interface{}{"ledger": 90, "seq": 0},
+			"transactions": []interface{}{
+				accountTxEntry(tSender, 9),
+				accountTxEntry(tReceiver, 20), // incoming payment
+				accountTxEntry(tSender, 8),
+			},
+		},
+		map[string]interface{}{
+			"account": tSender,
+			"transactions": []interface{}{
+				accountTxEntry(tSender, 7),
+				accountTxEntry(tSender, 5),
+			},
+		},
+	}
+	var accountTxCalls int
+	b := newTestRippleBridge(t, func(method string, params []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			return map[string]interface{}{
+				"account_data": map[string]interface{}{
+					"Account":  tSender,
+					"Balance":  "100000000",
+					"Sequence": 10,
+				},
+			}
+		case "account_tx":
+			accountTxCalls++
+			if params[0]["account"] != tSender {
+				t.Errorf("account_tx of wrong account %v", params[0]["account"])
+			}
+			if _, hasMarker := params[0]["marker"]; hasMarker {
+				return pages[1]
+			}
+			return pages[0]
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	for _, seq := range []uint32{9, 7, 5} {
+		tx, err := b.GetTransactionBySequence(tSender, seq)
+		if err != nil {
+			t.Fatalf("get tx of sequence %v failed: %v", seq, err)
+		}
+		if tx == nil || tx.GetBase().Sequence != seq || tx.GetBase().Account.String() != tSender {
+			t.Errorf("get tx of sequence %v mismatch, have %+v", seq, tx)
+		}
+	}
+
+	// sequences not consumed yet
+	accountTxCalls = 0
+	for _, seq := range []uint32{10, 11} {
+		tx, err := b.GetTransactionBySequence(tSender, seq)
+		if err != nil || tx != nil {
+			t.Errorf("sequence %v is not consumed, have tx %v err %v", seq, tx, err)
+		}
+	}
+	if accountTxCalls != 0 {
+		t.Errorf("account history should not be searched for sequences not consumed")
+	}
+
+	// gapped or older sequences
+	for _, seq := range []uint32{6, 4} {
+		if _, err := b.GetTransactionBySequence(tSender, seq); !errors.Is(err, ErrSequenceTxNotFound) {
+			t.Errorf("tx of sequence %v should not be found, have %v", seq, err)
+		}
+	}
+}
@@ -0,0 +1,186 @@
+package ripple
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+)
+
+var (
+	serverInfoCache          = new(sync.Map) // url -> *cachedServerInfo
+	serverInfoCacheExpiresIn = 10 * time.Minute
+)
+
+type cachedServerInfo struct {
+	info      *ServerInfo
+	expiresAt time.Time
+}
+
+// ServerInfo server info of rippled
+type ServerInfo struct {
+	BuildVersion      string          `json:"build_version"`
+	ServerState       string          `json:"server_state"`
+	CompleteLedgers   string          `json:"complete_ledgers"`
+	AmendmentBlocked  bool            `json:"amendment_blocked"`
+	ValidatedLedger   ValidatedLedger `json:"validated_ledger"`
+	EnabledAmendments []string        `json:"enabled_amendments,omitempty"`
+}
+
+// ValidatedLedger validated ledger in server info
+type ValidatedLedger struct {
+	Seq uint32 `json:"seq"`
+}
+
+// ServerInfoResult server_info result
+type ServerInfoResult struct {
+	Info *ServerInfo `json:"info"`
+}
+
+// FeatureResult feature result
+type FeatureResult struct {
+	Features map[string]*Feature `json:"features"`
+}
+
+// Feature amendment status
+type Feature struct {
+	Name      string `json:"name"`
+	Enabled   bool   `json:"enabled"`
+	Supported bool   `json:"supported"`
+}
+
+// LedgerRange ledger range [Min, Max]
+type LedgerRange struct {
+	Min uint64
+	Max uint64
+}
+
+// GetServerInfo get server info (with enabled amendments) of the first available api
+func (b *Bridge) GetServerInfo() (info *ServerInfo, err error) {
+	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	for _, url := range urls {
+		info, err = b.GetServerInfoOf(url)
+		if err == nil {
+			return info, nil
+		}
+	}
+	return nil, err
+}
+
+// GetServerInfoOf get server info (with enabled amendments) of single api
+func (b *Bridge) GetServerInfoOf(url string) (*ServerInfo, error) {
+	rpcParams := map[string]interface{}{}
+	var res *ServerInfoResult
+	err := b.rpcPost(&res, url, "server_info", rpcParams)
+	if err != nil || res == nil || res.Info == nil {
+		return nil, wrapRPCQueryError(err, "GetServerInfo")
+	}
+	info := res.Info
+
+	var feature *FeatureResult
+	err = b.rpcPost(&feature, url, "feature", rpcParams)
+	if err != nil || feature == nil {
+		// feature is an admin method on some servers
+		log.Debug("get ripple features failed", "url", url, "err", err)
+		return info, nil
+	}
+	for id, f := range feature.Features {
+		if f == nil || !f.Enabled {
+			continue
+		}
+		name := f.Name
+		if name == "" {
+			name = id
+		}
+		info.EnabledAmendments = append(info.EnabledAmendments, name)
+	}
+	sort.Strings(info.EnabledAmendments)
+	return info, nil
+}
+
+// IsAmendmentEnabled is amendment enabled
+func (info *ServerInfo) IsAmendmentEnabled(name string) bool {
+	for _, amendment := range info.EnabledAmendments {
+		if strings.EqualFold(amendment, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasLedger whether the server has the ledger in its complete ledgers
+func (info *ServerInfo) HasLedger(seq uint64) bool {
+	ranges, err := ParseCompleteLedgers(info.CompleteLedgers)
+	if err != nil {
+		return false
+	}
+	for _, r := range ranges {
+		if seq >= r.Min && seq <= r.Max {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseCompleteLedgers parse complete ledgers of format like "32570-100000,100005"
+func ParseCompleteLedgers(completeLedgers string) (ranges []LedgerRange, err error) {
+	completeLedgers = strings.TrimSpace(completeLedgers)
+	if completeLedgers == "" || completeLedgers == "empty" {
+		return nil, nil
+	}
+	for _, part := range strings.Split(completeLedgers, ",") {
+		var r LedgerRange
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		if r.Min, err = strconv.ParseUint(bounds[0], 10, 64); err != nil {
+			return nil, fmt.Errorf("wrong complete ledgers '%v'", completeLedgers)
+		}
+		r.Max = r.Min
+		if len(bounds) == 2 {
+			if r.Max, err = strconv.ParseUint(bounds[1], 10, 64); err != nil || r.Max < r.Min {
+				return nil, fmt.Errorf("wrong complete ledgers '%v'", completeLedgers)
+			}
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges, nil
+}
+
+func (b *Bridge) getCachedServerInfo(url string) *ServerInfo {
+	if cached, exist := serverInfoCache.Load(url); exist {
+		c := cached.(*cachedServerInfo)
+		if time.Now().Before(c.expiresAt) {
+			return c.info
+		}
+	}
+	info, err := b.GetServerInfoOf(url)
+	if err != nil {
+		log.Debug("get ripple server info failed", "url", url, "err", err)
+		return nil
+	}
+	serverInfoCache.Store(url, &cachedServerInfo{info: info, expiresAt: time.Now().Add(serverInfoCacheExpiresIn)})
+	return info
+}
+
+// getTxQueryURLs returns apis to query txs, apis which have
+// the complete history since the initial height are put first.
+func (b *Bridge) getTxQueryURLs() []string {
+	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	initialHeight := b.GetChainConfig().InitialHeight
+	if initialHeight == 0 || len(urls) < 2 {
+		return urls
+	}
+	fullHistory := make([]string, 0, len(urls))
+	others := make([]string, 0, len(urls))
+	for _, url := range urls {
+		if info := b.getCachedServerInfo(url); info != nil && info.HasLedger(initialHeight) {
+			fullHistory = append(fullHistory, url)
+		} else {
+			others = append(others, url)
+		}
+	}
+	return append(fullHistory, others...)
+}
@@ -0,0 +1,98 @@
+package ripple
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+const tServerInfoResult = `{
+	"info": {
+		"build_version": "1.9.4",
+		"complete_ledgers": "32570-75443455,75443460-75443500",
+		"amendment_blocked": false,
+		"server_state": "full",
+		"validated_ledger": {"age": 2, "base_fee_xrp": 0.00001, "hash": "", "reserve_base_xrp": 10, "reserve_inc_xrp": 2, "seq": 75443500}
+	},
+	"status": "success"
+}`
+
+const tFeatureResult = `{
+	"features": {
+		"157D2D480E006395B76F948E3E07A45A05FE10230D88A7993C71F97AE4B1F2D1": {"enabled": true, "name": "Checks", "supported": true},
+		"F64E1EABBE79D55B3BB82020516CEC2C582A98A6BFE20FBE9BB6A0D233418064": {"enabled": true, "name": "DepositAuth", "supported": true},
+		"C1CE18F2A268E6A849C27B3DE485006771B4C01B2FCEC4F18356FE92ECD6BB74": {"enabled": false, "name": "XChainBridge", "supported": true}
+	},
+	"status": "success"
+}`
+
+func newTestServerInfoBridge(t *testing.T, serverInfo string) *Bridge {
+	return newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		var result json.RawMessage
+		switch method {
+		case "server_info":
+			result = json.RawMessage(serverInfo)
+		case "feature":
+			result = json.RawMessage(tFeatureResult)
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		return result
+	})
+}
+
+func TestGetServerInfo(t *testing.T) {
+	b := newTestServerInfoBridge(t, tServerInfoResult)
+
+	info, err := b.GetServerInfo()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.ServerState != "full" || info.BuildVersion != "1.9.4" || info.ValidatedLedger.Seq != 75443500 {
+		t.Errorf("server info mismatch, have %+v", info)
+	}
+	if want := []string{"Checks", "DepositAuth"}; !reflect.DeepEqual(info.EnabledAmendments, want) {
+		t.Errorf("enabled amendments mismatch, have %v want %v", info.EnabledAmendments, want)
+	}
+	if !info.IsAmendmentEnabled("checks") || info.IsAmendmentEnabled("XChainBridge") {
+		t.Errorf("amendment enabled status mismatch")
+	}
+
+	for seq, want := range map[uint64]bool{
+		32569:    false,
+		32570:    true,
+		75443455: true,
+		75443457: false,
+		75443460: true,
+		75443501: false,
+	} {
+		if have := info.HasLedger(seq); have != want {
+			t.Errorf("has ledger %v mismatch, have %v want %v", seq, have, want)
+		}
+	}
+
+	if _, err = ParseCompleteLedgers("100-50"); err == nil {
+		t.Error("parse wrong complete ledgers should fail")
+	}
+	if ranges, err := ParseCompleteLedgers("empty"); err != nil || len(ranges) != 0 {
+		t.Errorf("parse empty complete ledgers failed, have %v %v", ranges, err)
+	}
+}
+
+func TestTxQueryURLsPreferFullHistory(t *testing.T) {
+	partial := newTestServerInfoBridge(t, `{"info":{"complete_ledgers":"75000000-75443500","server_state":"full"}}`)
+	full := newTestServerInfoBridge(t, tServerInfoResult)
+
+	partialURL := partial.GetGatewayConfig().APIAddress[0]
+	fullURL := full.GetGatewayConfig().APIAddress[0]
+
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{InitialHeight: 70000000})
+	b.SetGatewayConfig(&tokens.GatewayConfig{APIAddress: []string{partialURL, fullURL}})
+
+	if urls := b.getTxQueryURLs(); !reflect.DeepEqual(urls, []string{fullURL, partialURL}) {
+		t.Errorf("tx query urls should prefer full history api, have %v", urls)
+	}
+}
@@ -1,18 +1,18 @@
 package ripple
 
 import (
-	"crypto/ed25519"
+	"bytes"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/common"
 	"github.com/anyswap/CrossChain-Router/v3/log"
 	"github.com/anyswap/CrossChain-Router/v3/mpc"
 	"github.com/anyswap/CrossChain-Router/v3/params"
-	"github.com/anyswap/CrossChain-Router/v3/router"
 	"github.com/anyswap/CrossChain-Router/v3/tokens"
 	rcrypto "github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
@@ -21,29 +21,50 @@ import (
 )
 
 func (b *Bridge) verifyTransactionWithArgs(tx data.Transaction, args *tokens.BuildTxArgs) error {
-	if tx.GetTransactionType() != data.PAYMENT {
+	var to string
+	var toTag *uint32
+	var invoiceID *data.Hash256
+
+	switch tx.GetTransactionType() {
+	case data.PAYMENT:
+		payment, ok := tx.(*data.Payment)
+		if !ok {
+			return tokens.ErrWrongRawTx
+		}
+		to = payment.Destination.String()
+		toTag = payment.DestinationTag
+		invoiceID = payment.InvoiceID
+	case data.CHECK_CREATE:
+		checkCreate, ok := tx.(*data.CheckCreate)
+		if !ok {
+			return tokens.ErrWrongRawTx
+		}
+		to = checkCreate.Destination.String()
+		toTag = checkCreate.DestinationTag
+		invoiceID = checkCreate.InvoiceID
+	default:
 		return nil
 	}
 
-	payment, ok := tx.(*data.Payment)
-	if !ok {
-		return tokens.ErrWrongRawTx
-	}
-
-	to := payment.Destination.String()
-	toTag := payment.DestinationTag
-
 	checkReceiver, checkTag, err := GetAddressAndTag(args.Bind)
 	if err != nil {
 		return err
 	}
 
 	if !strings.EqualFold(to, checkReceiver) {
-		return fmt.Errorf("[sign] verify payment tx receiver failed")
+		return fmt.Errorf("[sign] verify %v tx receiver failed", tx.GetTransactionType())
 	}
 
 	if ((toTag == nil) != (checkTag == nil)) || (toTag != nil && *toTag != *checkTag) {
-		return fmt.Errorf("[sign] verify payment tx destination tag failed")
+		return fmt.Errorf("[sign] verify %v tx destination tag failed", tx.GetTransactionType())
+	}
+
+	if err = b.checkSwapMemo(tx.GetBase().Memos, args); err != nil {
+		return fmt.Errorf("[sign] verify %v tx memo failed, %w", tx.GetTransactionType(), err)
+	}
+
+	if err = b.checkInvoiceID(invoiceID, args); err != nil {
+		return fmt.Errorf("[sign] verify %v tx invoice id failed, %w", tx.GetTransactionType(), err)
 	}
 
 	return nil
@@ -62,6 +83,10 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 		return nil, "", err
 	}
 
+	if txJSON, errf := b.ToTxJSON(tx); errf == nil {
+		log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction start", "txid", args.SwapID, "tx", txJSON)
+	}
+
 	mpcParams := params.GetMPCConfig(b.UseFastMPC)
 	if mpcParams.SignWithPrivateKey {
 		priKey := mpcParams.GetSignerPrivateKey(b.ChainConfig.ChainID)
@@ -70,51 +95,44 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 
 	jsondata, _ := json.Marshal(args.GetExtraArgs())
 	msgContext := string(jsondata)
-	msgHash, msg, err := data.SigningHash(tx)
+	msgHash, msg, err := b.GetSigningHash(tx)
 	if err != nil {
 		return nil, "", fmt.Errorf("get transaction signing hash failed: %w", err)
 	}
-	msg = append(tx.SigningPrefix().Bytes(), msg...)
 
-	pubkeyStr := router.GetMPCPublicKey(args.From)
-	pubkey := common.FromHex(pubkeyStr)
-	isEd := isEd25519Pubkey(pubkey)
+	mpcKey, err := getMPCKey(args.From)
+	if err != nil {
+		return nil, "", err
+	}
+	pubkey := mpcKey.PublicKey
+	isEd := mpcKey.Type == KeyTypeEd25519
+
+	err = b.checkSigningKey(tx.GetBase().Account.String(), pubkey)
+	if err != nil {
+		log.Warn("check signing key failed", "txid", args.SwapID, "err", err)
+		return nil, "", err
+	}
 
-	var keyID string
-	var rsvs []string
+	var signFn func() (string, []string, error)
 
 	mpcConfig := mpc.GetMPCConfig(b.UseFastMPC)
+	// mpc ed public key has no 0xed prefix
+	signPubKey := mpcKey.SignPubKey
+	signContent := getSignContent(mpcKey.Type, msgHash, msg)
 	if isEd {
-		// mpc ed public key has no 0xed prefix
-		signPubKey := pubkeyStr[2:]
-		// the real sign content is (signing prefix + msg)
-		// when we hex encoding here, the mpc should do hex decoding there.
-		signContent := common.ToHex(msg)
-		keyID, rsvs, err = mpcConfig.DoSignOneED(signPubKey, signContent, msgContext)
+		signFn = func() (string, []string, error) {
+			return mpcConfig.DoSignOneED(signPubKey, signContent, msgContext)
+		}
 	} else {
-		signPubKey := pubkeyStr
-		signContent := msgHash.String()
-		keyID, rsvs, err = mpcConfig.DoSignOneEC(signPubKey, signContent, msgContext)
+		signFn = func() (string, []string, error) {
+			return mpcConfig.DoSignOneEC(signPubKey, signContent, msgContext)
+		}
 	}
 
+	rsv, err := b.mpcSignAndVerify(signFn, pubkey, msgHash, msg, args.SwapID)
 	if err != nil {
-		log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction failed", "keyID", keyID, "txid", args.SwapID, "err", err)
 		return nil, "", err
 	}
-	log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction finished", "keyID", keyID, "txid", args.SwapID)
-
-	if len(rsvs) != 1 {
-		return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
-	}
-
-	rsv := rsvs[0]
-	log.Trace(b.ChainConfig.BlockChain+" MPCSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
-
-	sig := rsvToSig(rsv, isEd)
-	valid, err := rcrypto.Verify(pubkey, msgHash.Bytes(), msg, sig)
-	if !valid || err != nil {
-		return nil, "", fmt.Errorf("verify signature error (valid: %v): %v", valid, err)
-	}
 
 	signedTx, err := MakeSignedTransaction(pubkey, rsv, rawTx)
 	if err != nil {
@@ -126,6 +144,59 @@ func (b *Bridge) MPCSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs)
 	return signedTx, txhash, nil
 }
 
+// getMPCSignRetries get the times of re-requesting mpc sign if the signature is invalid,
+// which is configed by `mpcSignRetries` custom (default to 0, ie. no retry)
+func (b *Bridge) getMPCSignRetries() int {
+	retriesStr := params.GetCustom(b.ChainConfig.ChainID, "mpcSignRetries")
+	if retriesStr == "" {
+		return 0
+	}
+	retries, err := strconv.Atoi(retriesStr)
+	if err != nil || retries < 0 {
+		log.Warn("wrong mpcSignRetries custom", "chainID", b.ChainConfig.ChainID, "value", retriesStr)
+		return 0
+	}
+	return retries
+}
+
+// mpcSignAndVerify request mpc sign and verify the signature.
+// an invalid signature may be caused by a transient mpc fault, and re-requesting
+// is safe as the same signing hash (with the same sequence) is signed again.
+func (b *Bridge) mpcSignAndVerify(
+	signFn func() (string, []string, error),
+	pubkey []byte, msgHash data.Hash256, msg []byte, swapID string,
+) (rsv string, err error) {
+	isEd := isEd25519Pubkey(pubkey)
+	retries := b.getMPCSignRetries()
+	for i := 0; ; i++ {
+		keyID, rsvs, errf := b.doMPCSign(signFn)
+		if errf != nil {
+			log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction failed", "keyID", keyID, "txid", swapID, "err", errf)
+			return "", errf
+		}
+		log.Info(b.ChainConfig.BlockChain+" MPCSignTransaction finished", "keyID", keyID, "txid", swapID)
+
+		if len(rsvs) != 1 {
+			return "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
+		}
+
+		rsv = rsvs[0]
+		log.Trace(b.ChainConfig.BlockChain+" MPCSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
+
+		sig := rsvToSig(rsv, isEd)
+		valid, errf := rcrypto.Verify(pubkey, msgHash.Bytes(), msg, sig)
+		if valid && errf == nil {
+			return rsv, nil
+		}
+		err = fmt.Errorf("verify signature error (valid: %v): %v", valid, errf)
+		if i >= retries {
+			return "", err
+		}
+		log.Warn(b.ChainConfig.BlockChain+" MPCSignTransaction retry as signature is invalid",
+			"keyID", keyID, "txid", swapID, "retry", i+1, "retries", retries, "err", err)
+	}
+}
+
 // SignTransactionWithPrivateKey sign tx with ECDSA private key
 func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey string) (signTx interface{}, txHash string, err error) {
 	ecPrikey, err := crypto.HexToECDSA(privKey)
@@ -142,11 +213,10 @@ func (b *Bridge) SignTransactionWithRippleKey(rawTx interface{}, key rcrypto.Key
 		return nil, "", tokens.ErrWrongRawTx
 	}
 
-	msgHash, msg, err := data.SigningHash(tx)
+	msgHash, msg, err := b.GetSigningHash(tx)
 	if err != nil {
 		return nil, "", err
 	}
-	msg = append(tx.SigningPrefix().Bytes(), msg...)
 	log.Info("Prepare to sign", "signing hash", msgHash.String(), "blob", fmt.Sprintf("%X", msg))
 
 	sig, err := rcrypto.Sign(key.Private(keyseq), msgHash.Bytes(), msg)
@@ -179,6 +249,40 @@ func (b *Bridge) SignTransactionWithRippleKey(rawTx interface{}, key rcrypto.Key
 	return stx, tx.GetHash().String(), nil
 }
 
+// GetSigningHash returns the signing hash and the signing content (signing prefix + msg).
+// The signing prefix can be overridden by the `signingPrefix` custom (4 bytes hex string)
+// to support XRPL forks which use a different prefix.
+func (b *Bridge) GetSigningHash(tx data.Transaction) (msgHash data.Hash256, msg []byte, err error) {
+	msgHash, msg, err = data.SigningHash(tx)
+	if err != nil {
+		return msgHash, nil, err
+	}
+	prefix, err := b.getSigningPrefix(tx)
+	if err != nil {
+		return msgHash, nil, err
+	}
+	msg = append(prefix.Bytes(), msg...)
+	if prefix != tx.SigningPrefix() {
+		copy(msgHash[:], rcrypto.Sha512Half(msg))
+	}
+	return msgHash, msg, nil
+}
+
+func (b *Bridge) getSigningPrefix(tx data.Transaction) (data.HashPrefix, error) {
+	if b.ChainConfig == nil {
+		return tx.SigningPrefix(), nil
+	}
+	prefixStr := params.GetCustom(b.ChainConfig.ChainID, "signingPrefix")
+	if prefixStr == "" {
+		return tx.SigningPrefix(), nil
+	}
+	prefix, err := common.GetUint32FromStr(prefixStr)
+	if err != nil {
+		return 0, fmt.Errorf("wrong signingPrefix %v", prefixStr)
+	}
+	return data.HashPrefix(prefix), nil
+}
+
 // MakeSignedTransaction make signed transaction
 func MakeSignedTransaction(pubkey []byte, rsv string, transaction interface{}) (signedTx data.Transaction, err error) {
 	sig := rsvToSig(rsv, isEd25519Pubkey(pubkey))
@@ -193,11 +297,38 @@ func MakeSignedTransaction(pubkey []byte, rsv string, transaction interface{}) (
 		return nil, err
 	}
 	copy(tx.GetHash().Bytes(), hash.Bytes())
+	if err = VerifySignedTransactionEncoding(tx); err != nil {
+		log.Warn("verify ripple tx encoding error", "error", err)
+		return nil, err
+	}
 	return tx, nil
 }
 
-func isEd25519Pubkey(pubkey []byte) bool {
-	return len(pubkey) == ed25519.PublicKeySize+1 && pubkey[0] == 0xED
+// VerifySignedTransactionEncoding re-parses the encoded blob of a signed tx,
+// and checks that it round-trips to the identical blob and hash.
+func VerifySignedTransactionEncoding(tx data.Transaction) error {
+	hash, blob, err := data.Raw(tx)
+	if err != nil {
+		return err
+	}
+	decoded, err := data.ReadTransaction(bytes.NewReader(blob))
+	if err != nil {
+		return fmt.Errorf("decode signed tx blob failed: %w", err)
+	}
+	rehash, reblob, err := data.Raw(decoded)
+	if err != nil {
+		return fmt.Errorf("re-encode signed tx failed: %w", err)
+	}
+	if !bytes.Equal(blob, reblob) {
+		return fmt.Errorf("signed tx blob is not canonical, have %X, re-encoded %X", blob, reblob)
+	}
+	if hash != rehash {
+		return fmt.Errorf("signed tx hash mismatch, have %v, re-encoded %v", hash, rehash)
+	}
+	if txHash := tx.GetHash(); txHash != nil && !txHash.IsZero() && *txHash != hash {
+		return fmt.Errorf("signed tx hash mismatch, have %v, encoded %v", txHash, hash)
+	}
+	return nil
 }
 
 func rsvToSig(rsv string, isEd bool) []byte {
@@ -0,0 +1,175 @@
+package ripple
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/common"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	rcrypto "github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/crypto"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const (
+	tSeed     = "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+	tReceiver = "rPT1Sjq2YGrBMTttX4GZHjKu9dyfzbpAYe"
+)
+
+func signTestPayment(t *testing.T, cryptoType string) data.Transaction {
+	return signTestPaymentWithBridge(t, NewCrossChainBridge(), cryptoType)
+}
+
+func signTestPaymentWithBridge(t *testing.T, b *Bridge, cryptoType string) data.Transaction {
+	key, err := ImportKeyFromSeed(tSeed, cryptoType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := uint32(12345)
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 7, tReceiver, &tag, "1000000", "10", "swap memo", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stx, txHash, err := b.SignTransactionWithRippleKey(tx, key, nil)
+	if err != nil {
+		t.Fatalf("sign %v tx failed: %v", cryptoType, err)
+	}
+	signedTx := stx.(data.Transaction)
+	if signedTx.GetHash().String() != txHash {
+		t.Fatalf("sign %v tx hash mismatch, have %v want %v", cryptoType, signedTx.GetHash(), txHash)
+	}
+	return signedTx
+}
+
+func TestSignedTransactionRoundTrip(t *testing.T) {
+	for _, cryptoType := range []string{"ecdsa", "ed25519"} {
+		signedTx := signTestPayment(t, cryptoType)
+
+		if err := VerifySignedTransactionEncoding(signedTx); err != nil {
+			t.Errorf("verify %v tx encoding failed: %v", cryptoType, err)
+		}
+
+		hash, blob, err := data.Raw(signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hash != *signedTx.GetHash() {
+			t.Errorf("%v tx hash mismatch, have %v want %v", cryptoType, signedTx.GetHash(), hash)
+		}
+		decoded, err := data.ReadTransaction(bytes.NewReader(blob))
+		if err != nil {
+			t.Fatal(err)
+		}
+		rehash, _, err := data.Raw(decoded)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if rehash != hash {
+			t.Errorf("%v tx re-encoded hash mismatch, have %v want %v", cryptoType, rehash, hash)
+		}
+
+		// signing the same tx again must produce the identical blob
+		again := signTestPayment(t, cryptoType)
+		_, blobAgain, err := data.Raw(again)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(blob, blobAgain) {
+			t.Errorf("%v tx encoding is not deterministic", cryptoType)
+		}
+	}
+}
+
+func TestCustomSigningPrefix(t *testing.T) {
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(devnetNetWork).String()})
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"signingPrefix": "0x53545801"})
+
+	for _, cryptoType := range []string{"ecdsa", "ed25519"} {
+		signedTx := signTestPaymentWithBridge(t, b, cryptoType)
+
+		msgHash, msg, err := b.GetSigningHash(signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.Equal(msg[:4], []byte{0x53, 0x54, 0x58, 0x01}) {
+			t.Errorf("%v signing content should start with custom prefix, but have %X", cryptoType, msg[:4])
+		}
+		defaultHash, defaultMsg, err := NewCrossChainBridge().GetSigningHash(signedTx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if defaultHash == msgHash {
+			t.Errorf("%v signing hash should differ from the default prefix one", cryptoType)
+		}
+
+		pubkey := signedTx.GetPublicKey().Bytes()
+		sig := *signedTx.GetSignature()
+		if valid, err := rcrypto.Verify(pubkey, msgHash.Bytes(), msg, sig); !valid || err != nil {
+			t.Errorf("%v signature should be verified with custom prefix, valid: %v, err: %v", cryptoType, valid, err)
+		}
+		if valid, _ := rcrypto.Verify(pubkey, defaultHash.Bytes(), defaultMsg, sig); valid {
+			t.Errorf("%v signature should not be verified with default prefix", cryptoType)
+		}
+	}
+}
+
+func TestMPCSignRetryOnInvalidSignature(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ed25519")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 7, tReceiver, nil, "1000000", "10", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge()
+	b.SetChainConfig(&tokens.ChainConfig{ChainID: GetStubChainID(testnetNetWork).String()})
+	msgHash, msg, err := b.GetSigningHash(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sig, err := rcrypto.Sign(key.Private(nil), msgHash.Bytes(), msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodRsv := common.ToHex(sig)
+	badRsv := common.ToHex(make([]byte, len(sig)))
+
+	var signed []string
+	signFn := func() (string, []string, error) {
+		// one bad signature (transient mpc fault) then a good one
+		rsv := goodRsv
+		if len(signed) == 0 {
+			rsv = badRsv
+		}
+		signed = append(signed, rsv)
+		return "keyID", []string{rsv}, nil
+	}
+
+	// no retry by default
+	if _, err = b.mpcSignAndVerify(signFn, key.Public(nil), msgHash, msg, "swapID"); err == nil || len(signed) != 1 {
+		t.Errorf("invalid signature should fail without retry, but have err %v after %v signs", err, len(signed))
+	}
+
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"mpcSignRetries": "2"})
+	signed = nil
+	rsv, err := b.mpcSignAndVerify(signFn, key.Public(nil), msgHash, msg, "swapID")
+	if err != nil {
+		t.Fatalf("sign should succeed after retry, but have %v", err)
+	}
+	if rsv != goodRsv || len(signed) != 2 {
+		t.Errorf("sign retry mismatch, have rsv %v after %v signs", rsv, len(signed))
+	}
+
+	// the same tx (and sequence) is signed
+	signedTx, err := MakeSignedTransaction(key.Public(nil), rsv, tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if signedTx.GetBase().Sequence != 7 {
+		t.Errorf("signed tx sequence changed, have %v", signedTx.GetBase().Sequence)
+	}
+	if err = VerifySignedTransactionEncoding(signedTx); err != nil {
+		t.Errorf("verify signed tx failed: %v", err)
+	}
+}
@@ -0,0 +1,68 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+var (
+	// ErrTxStatusTimeout the tx is still pending (not validated) when polling its status timeout
+	ErrTxStatusTimeout = errors.New("ripple tx status is pending after polling timeout")
+
+	// ledgers are closed every 3-5 seconds
+	defaultStatusPollInterval = 4 * time.Second
+	defaultStatusPollTimeout  = 60 * time.Second
+)
+
+// getStatusPollConfig get the polling interval and the overall timeout of waiting tx status,
+// which are configed by `statusPollInterval` and `statusPollTimeout` customs (in seconds)
+func (b *Bridge) getStatusPollConfig() (interval, timeout time.Duration) {
+	getSeconds := func(key string, defVal time.Duration) time.Duration {
+		valStr := params.GetCustom(b.ChainConfig.ChainID, key)
+		if valStr == "" {
+			return defVal
+		}
+		seconds, err := strconv.ParseFloat(valStr, 64)
+		if err != nil || seconds <= 0 {
+			log.Warn("wrong tx status poll config", "chainID", b.ChainConfig.ChainID, "key", key, "value", valStr)
+			return defVal
+		}
+		return time.Duration(seconds * float64(time.Second))
+	}
+	return getSeconds("statusPollInterval", defaultStatusPollInterval),
+		getSeconds("statusPollTimeout", defaultStatusPollTimeout)
+}
+
+// isFinalTxStatusError is the tx status error not changed by waiting
+func isFinalTxStatusError(err error) bool {
+	return errors.Is(err, tokens.ErrTxWithWrongStatus) || errors.Is(err, errTxResultType) ||
+		errors.Is(err, ErrUnderDelivered)
+}
+
+// WaitTransactionStatus poll the tx status until the tx is validated.
+// It returns ErrTxStatusTimeout if the tx is still pending when timeout,
+// so the caller can decide whether to keep waiting or to retry.
+func (b *Bridge) WaitTransactionStatus(txHash string) (*tokens.TxStatus, error) {
+	interval, timeout := b.getStatusPollConfig()
+	deadline := time.Now().Add(timeout)
+	for {
+		status, err := b.GetTransactionStatus(txHash)
+		if err == nil {
+			return status, nil
+		}
+		if isFinalTxStatusError(err) {
+			return nil, err
+		}
+		if time.Now().Add(interval).After(deadline) {
+			return nil, fmt.Errorf("%w, txHash: %v, timeout: %v, last error: %v", ErrTxStatusTimeout, txHash, timeout, err)
+		}
+		log.Debug("ripple tx status is pending, poll later", "txHash", txHash, "interval", interval, "err", err)
+		time.Sleep(interval)
+	}
+}
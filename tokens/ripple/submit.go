@@ -0,0 +1,113 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// ErrSubmitRejected the submitted tx is rejected and can never succeed
+var ErrSubmitRejected = errors.New("ripple submit rejected")
+
+// SubmitStatus status of submit classified by engine result
+type SubmitStatus int
+
+// submit status
+const (
+	// SubmitProvisional tx is applied to the open ledger (tes and tec),
+	// the final outcome is decided when the ledger is validated.
+	SubmitProvisional SubmitStatus = iota
+	// SubmitQueued tx is held in the queue until the fee escalation drops (terQUEUED)
+	SubmitQueued
+	// SubmitRetryable tx is not applied, but may succeed later (ter, tel and tef)
+	SubmitRetryable
+	// SubmitAlreadyApplied tx or its sequence is already applied (tefALREADY and tefPAST_SEQ),
+	// which is the normal result of resubmitting a sent tx
+	SubmitAlreadyApplied
+	// SubmitTerminal tx is malformed and can never succeed (tem)
+	SubmitTerminal
+)
+
+func (s SubmitStatus) String() string {
+	switch s {
+	case SubmitProvisional:
+		return "provisional"
+	case SubmitQueued:
+		return "queued"
+	case SubmitRetryable:
+		return "retryable"
+	case SubmitAlreadyApplied:
+		return "already applied"
+	case SubmitTerminal:
+		return "terminal"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// SubmitResult result of `submit` rpc
+type SubmitResult struct {
+	EngineResult        string `json:"engine_result"`
+	EngineResultCode    int    `json:"engine_result_code"`
+	EngineResultMessage string `json:"engine_result_message"`
+}
+
+// Status classify the engine result by its code range
+func (r *SubmitResult) Status() SubmitStatus {
+	code := r.EngineResultCode
+	switch {
+	case r.EngineResult == "terQUEUED":
+		return SubmitQueued
+	case r.EngineResult == "tefALREADY", r.EngineResult == "tefPAST_SEQ":
+		return SubmitAlreadyApplied
+	case code == 0, code >= 100: // tes, tec
+		return SubmitProvisional
+	case code >= -299 && code <= -200: // tem
+		return SubmitTerminal
+	default: // ter, tef, tel
+		return SubmitRetryable
+	}
+}
+
+// IsSuccess is tesSUCCESS
+func (r *SubmitResult) IsSuccess() bool {
+	return r.EngineResultCode == 0
+}
+
+// ShouldRetry tx is not applied and should be submitted again later
+func (r *SubmitResult) ShouldRetry() bool {
+	return r.Status() == SubmitRetryable
+}
+
+// Err returns error if tx is not applied, queued or already applied
+func (r *SubmitResult) Err() error {
+	switch r.Status() {
+	case SubmitProvisional, SubmitQueued, SubmitAlreadyApplied:
+		return nil
+	case SubmitTerminal:
+		return fmt.Errorf("%w, %v(%v): %v", ErrSubmitRejected, r.EngineResult, r.EngineResultCode, r.EngineResultMessage)
+	default:
+		return fmt.Errorf("submit tx failed, %v(%v): %v", r.EngineResult, r.EngineResultCode, r.EngineResultMessage)
+	}
+}
+
+// Submit submit signed tx to the api and returns the engine result
+func (b *Bridge) Submit(url string, tx data.Transaction) (*SubmitResult, error) {
+	_, raw, err := data.Raw(tx)
+	if err != nil {
+		return nil, err
+	}
+	rpcParams := map[string]interface{}{
+		"tx_blob": fmt.Sprintf("%X", raw),
+	}
+	var result *SubmitResult
+	err = b.rpcPost(&result, url, "submit", rpcParams)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.EngineResult == "" {
+		return nil, fmt.Errorf("submit tx without engine result")
+	}
+	return result, nil
+}
@@ -0,0 +1,116 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestSubmitResultStatus(t *testing.T) {
+	tests := []struct {
+		result string
+		code   int
+		status SubmitStatus
+		retry  bool
+	}{
+		{"tesSUCCESS", 0, SubmitProvisional, false},
+		{"tecUNFUNDED_PAYMENT", 104, SubmitProvisional, false},
+		{"tecPATH_DRY", 128, SubmitProvisional, false},
+		{"terQUEUED", -89, SubmitQueued, false},
+		{"terPRE_SEQ", -92, SubmitRetryable, true},
+		{"terINSUF_FEE_B", -97, SubmitRetryable, true},
+		{"telINSUF_FEE_P", -394, SubmitRetryable, true},
+		{"telCAN_NOT_QUEUE", -392, SubmitRetryable, true},
+		{"tefPAST_SEQ", -190, SubmitAlreadyApplied, false},
+		{"tefALREADY", -198, SubmitAlreadyApplied, false},
+		{"tefMAX_LEDGER", -186, SubmitRetryable, true},
+		{"temBAD_FEE", -295, SubmitTerminal, false},
+		{"temBAD_AMOUNT", -298, SubmitTerminal, false},
+	}
+	for _, test := range tests {
+		res := &SubmitResult{EngineResult: test.result, EngineResultCode: test.code}
+		if status := res.Status(); status != test.status {
+			t.Errorf("%v: status mismatch, have %v want %v", test.result, status, test.status)
+		}
+		if retry := res.ShouldRetry(); retry != test.retry {
+			t.Errorf("%v: retry decision mismatch, have %v want %v", test.result, retry, test.retry)
+		}
+		err := res.Err()
+		switch test.status {
+		case SubmitProvisional, SubmitQueued, SubmitAlreadyApplied:
+			if err != nil {
+				t.Errorf("%v: should not return error, have %v", test.result, err)
+			}
+		case SubmitTerminal:
+			if !errors.Is(err, ErrSubmitRejected) {
+				t.Errorf("%v: should return %v, have %v", test.result, ErrSubmitRejected, err)
+			}
+		default:
+			if err == nil || errors.Is(err, ErrSubmitRejected) {
+				t.Errorf("%v: should return retryable error, have %v", test.result, err)
+			}
+		}
+	}
+}
+
+func TestSendTransactionSubmitResult(t *testing.T) {
+	oldInterval := rpcRetryInterval
+	rpcRetryInterval = time.Millisecond
+	t.Cleanup(func() { rpcRetryInterval = oldInterval })
+
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	rawTx, err := NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "1000000", "12", "", "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := NewCrossChainBridge()
+	signedTx, txHash, err := b.SignTransactionWithRippleKey(rawTx, key, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var calls int
+	var result map[string]interface{}
+	b = newTestRippleBridge(t, func(method string, _ []map[string]interface{}) interface{} {
+		if method != "submit" {
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		calls++
+		return result
+	})
+
+	result = map[string]interface{}{"engine_result": "terQUEUED", "engine_result_code": -89}
+	if have, errf := b.SendTransaction(signedTx); errf != nil || have != txHash {
+		t.Errorf("queued tx should be sent, have %v %v", have, errf)
+	}
+
+	// resubmitting an applied tx
+	for _, res := range []string{"tefPAST_SEQ", "tefALREADY"} {
+		result = map[string]interface{}{"engine_result": res, "engine_result_code": -190}
+		if have, errf := b.SendTransaction(signedTx); errf != nil || have != txHash {
+			t.Errorf("%v tx should be treated as sent, have %v %v", res, have, errf)
+		}
+	}
+
+	// left to the retry layer as before
+	calls = 0
+	result = map[string]interface{}{"engine_result": "terPRE_SEQ", "engine_result_code": -92}
+	if have, errf := b.SendTransaction(signedTx); errf != nil || have != txHash {
+		t.Errorf("tx with retryable result should be treated as sent, have %v %v", have, errf)
+	}
+	if calls != 1 {
+		t.Errorf("retryable result should not be resubmitted in place, have %v calls", calls)
+	}
+
+	calls = 0
+	result = map[string]interface{}{"engine_result": "temBAD_FEE", "engine_result_code": -295}
+	if _, err = b.SendTransaction(signedTx); !errors.Is(err, ErrSubmitRejected) {
+		t.Errorf("send tx with terminal result should fail with %v, have %v", ErrSubmitRejected, err)
+	}
+	if calls != 1 {
+		t.Errorf("terminal result should not be retried, have %v calls", calls)
+	}
+}
@@ -0,0 +1,241 @@
+package ripple
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/anyswap/CrossChain-Router/v3/log"
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/websockets"
+)
+
+// ErrNoPathFound no path is found to deliver the amount from the source asset
+var ErrNoPathFound = errors.New("no ripple path found")
+
+// transferRateBase the TransferRate meaning no transfer fee (1 billion),
+// a zero (or unset) TransferRate means no transfer fee too.
+const transferRateBase uint32 = 1000000000
+
+// maxSendMaxSlippage the max slippage (in percent) of SendMax to cap the total spend
+var maxSendMaxSlippage = big.NewRat(5, 1)
+
+// getIssuerTransferRate get the TransferRate of the issuer
+func (b *Bridge) getIssuerTransferRate(issuer string) (uint32, error) {
+	acct, err := b.GetAccount(issuer)
+	if err != nil {
+		return 0, fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "get issuer account failed")
+	}
+	if rate := acct.AccountData.TransferRate; rate != nil {
+		return *rate, nil
+	}
+	return 0, nil
+}
+
+// applyTransferRate the cost of delivering amount with the transfer rate, rounded up
+func applyTransferRate(amount *big.Int, rate uint32) *big.Int {
+	cost := new(big.Int).Mul(amount, new(big.Int).SetUint64(uint64(rate)))
+	cost.Add(cost, big.NewInt(int64(transferRateBase-1)))
+	return cost.Div(cost, new(big.Int).SetUint64(uint64(transferRateBase)))
+}
+
+// getSendMax get the SendMax of the IOU payout sent by a holder.
+// The issuer charges the TransferRate when the IOU ripples through it between holders,
+// so the sender pays more than the delivered amount, and a payment without SendMax fails.
+// It returns nil if there is no transfer fee (native, sent by the issuer, or default rate).
+func (b *Bridge) getSendMax(asset *data.Asset, from string, amount *big.Int, token *tokens.TokenConfig) (*data.Amount, error) {
+	if asset.IsNative() || asset.Issuer == from {
+		return nil, nil
+	}
+	rate, err := b.getIssuerTransferRate(asset.Issuer)
+	if err != nil {
+		return nil, err
+	}
+	if rate == 0 || rate == transferRateBase {
+		return nil, nil
+	}
+	if rate < transferRateBase {
+		return nil, fmt.Errorf("wrong transfer rate %v of issuer %v", rate, asset.Issuer)
+	}
+	slippage := b.getSendMaxSlippage()
+	cost := applySendMaxSlippage(applyTransferRate(amount, rate), slippage)
+	sendMax, err := getPaymentAmount(cost, token)
+	if err != nil {
+		return nil, err
+	}
+	log.Warn("issuer charges transfer fee, adjust send max", "chainID", b.ChainConfig.ChainID,
+		"issuer", asset.Issuer, "transferRate", rate, "slippage", slippage.FloatString(4),
+		"amount", amount, "sendMax", sendMax)
+	return sendMax, nil
+}
+
+// getSendMaxSlippage get the slippage (in percent) added to SendMax, so that the payment
+// does not fail with `tecPATH_PARTIAL` due to minor rate moves. It is configed by
+// `sendMaxSlippage` custom (eg. `0.5` for 0.5%, default to 0), and capped by maxSendMaxSlippage.
+func (b *Bridge) getSendMaxSlippage() *big.Rat {
+	slippageStr := params.GetCustom(b.ChainConfig.ChainID, "sendMaxSlippage")
+	if slippageStr == "" {
+		return new(big.Rat)
+	}
+	slippage, ok := new(big.Rat).SetString(slippageStr)
+	if !ok || slippage.Sign() < 0 {
+		log.Warn("wrong sendMaxSlippage custom", "chainID", b.ChainConfig.ChainID, "value", slippageStr)
+		return new(big.Rat)
+	}
+	if slippage.Cmp(maxSendMaxSlippage) > 0 {
+		log.Warn("sendMaxSlippage is capped", "chainID", b.ChainConfig.ChainID,
+			"value", slippageStr, "max", maxSendMaxSlippage.FloatString(0))
+		return new(big.Rat).Set(maxSendMaxSlippage)
+	}
+	return slippage
+}
+
+// applySendMaxSlippage add the slippage (in percent) to the amount, rounded up
+func applySendMaxSlippage(amount *big.Int, slippage *big.Rat) *big.Int {
+	if slippage.Sign() == 0 {
+		return amount
+	}
+	ratio := new(big.Rat).Quo(slippage, big.NewRat(100, 1))
+	ratio.Add(ratio, big.NewRat(1, 1))
+	cost := new(big.Rat).Mul(new(big.Rat).SetInt(amount), ratio)
+	result := new(big.Int).Quo(cost.Num(), cost.Denom())
+	if !cost.IsInt() {
+		result.Add(result, big.NewInt(1))
+	}
+	return result
+}
+
+// applySendMaxSlippageToAmount add the slippage (in percent) to the amount of any asset, rounded up
+// to drops for XRP, or to the 16 significant digits of the IOU value.
+func applySendMaxSlippageToAmount(amount *data.Amount, slippage *big.Rat) (*data.Amount, error) {
+	ratio := new(big.Rat).Quo(slippage, big.NewRat(100, 1))
+	ratio.Add(ratio, big.NewRat(1, 1))
+	cost := new(big.Rat).Mul(amount.Rat(), ratio)
+	if cost.Sign() <= 0 {
+		return nil, fmt.Errorf("wrong send max amount %v", amount)
+	}
+	if amount.IsNative() {
+		return data.NewAmount(ceilRat(cost).Int64())
+	}
+	// scale the mantissa into [10^15, 10^16)
+	var offset int64
+	minMantissa := new(big.Rat).SetInt64(1000000000000000)
+	maxMantissa := new(big.Rat).SetInt64(10000000000000000)
+	for cost.Cmp(minMantissa) < 0 {
+		cost.Mul(cost, big.NewRat(10, 1))
+		offset--
+	}
+	for cost.Cmp(maxMantissa) >= 0 {
+		cost.Quo(cost, big.NewRat(10, 1))
+		offset++
+	}
+	value, err := data.NewNonNativeValue(ceilRat(cost).Int64(), offset)
+	if err != nil {
+		return nil, err
+	}
+	return &data.Amount{Value: value, Currency: amount.Currency, Issuer: amount.Issuer}, nil
+}
+
+func ceilRat(r *big.Rat) *big.Int {
+	result := new(big.Int).Quo(r.Num(), r.Denom())
+	if !r.IsInt() {
+		result.Add(result, big.NewInt(1))
+	}
+	return result
+}
+
+// getPathSendAsset get the source asset of the path payouts, configed by `pathSendAsset:<tokenID>` custom
+// (eg. `EUR/rIssuer` or `XRP`), default to the delivered asset.
+func (b *Bridge) getPathSendAsset(token *tokens.TokenConfig, asset *data.Asset) (*data.Asset, error) {
+	assetStr := params.GetCustom(b.ChainConfig.ChainID, "pathSendAsset:"+token.TokenID)
+	if assetStr == "" {
+		return asset, nil
+	}
+	sendAsset, err := data.NewAsset(assetStr)
+	if err != nil {
+		return nil, fmt.Errorf("wrong pathSendAsset:%v custom %v, %w", token.TokenID, assetStr, err)
+	}
+	return sendAsset, nil
+}
+
+// getPathSendMax get the SendMax of the path payout in the source asset, with the slippage applied.
+// A path payment must have SendMax to limit the spend, or it fails on ledger. The cost of the delivered
+// asset is the amount with the transfer fee, the cost of another asset is found by `ripple_path_find`.
+func (b *Bridge) getPathSendMax(token *tokens.TokenConfig, asset *data.Asset, from, receiver string, amount *big.Int, amt *data.Amount) (*data.Amount, error) {
+	sendAsset, err := b.getPathSendAsset(token, asset)
+	if err != nil {
+		return nil, err
+	}
+	var cost *data.Amount
+	if sendAsset.String() == asset.String() {
+		costValue := amount
+		if !asset.IsNative() && asset.Issuer != from {
+			rate, errf := b.getIssuerTransferRate(asset.Issuer)
+			if errf != nil {
+				return nil, errf
+			}
+			if rate > transferRateBase {
+				costValue = applyTransferRate(amount, rate)
+			}
+		}
+		if cost, err = getPaymentAmount(costValue, token); err != nil {
+			return nil, err
+		}
+	} else if cost, err = b.findPathSourceAmount(from, receiver, amt, sendAsset); err != nil {
+		return nil, err
+	}
+	slippage := b.getSendMaxSlippage()
+	sendMax, err := applySendMaxSlippageToAmount(cost, slippage)
+	if err != nil {
+		return nil, err
+	}
+	log.Info("build path payment with send max", "chainID", b.ChainConfig.ChainID, "sendAsset", sendAsset,
+		"amount", amt, "cost", cost, "slippage", slippage.FloatString(4), "sendMax", sendMax)
+	return sendMax, nil
+}
+
+// findPathSourceAmount find the least amount of the source asset to deliver amt to the receiver by `ripple_path_find`
+func (b *Bridge) findPathSourceAmount(from, receiver string, amt *data.Amount, sendAsset *data.Asset) (*data.Amount, error) {
+	rpcParams := map[string]interface{}{
+		"source_account":      from,
+		"destination_account": receiver,
+		"destination_amount":  amt,
+		"source_currencies":   []*data.Asset{sendAsset},
+	}
+	var result *websockets.RipplePathFindResult
+	var err error
+	urls := append(b.GetGatewayConfig().APIAddress, b.GetGatewayConfig().APIAddressExt...)
+	for i := 0; i < rpcRetryTimes && result == nil; i++ {
+		for _, url := range urls {
+			var res *websockets.RipplePathFindResult
+			if err = b.rpcPost(&res, url, "ripple_path_find", rpcParams); err == nil && res != nil {
+				result = res
+				break
+			}
+		}
+		if result == nil {
+			time.Sleep(rpcRetryInterval)
+		}
+	}
+	if result == nil {
+		log.Warn("ripple path find failed", "from", from, "receiver", receiver, "amount", amt, "sendAsset", sendAsset, "err", err)
+		return nil, fmt.Errorf("%w %v", tokens.ErrBuildTxErrorAndDelay, "ripple path find failed")
+	}
+	var sourceAmount *data.Amount
+	for i := range result.Alternatives {
+		srcAmount := &result.Alternatives[i].SrcAmount
+		if srcAmount.Value == nil || !sendAsset.Matches(srcAmount) {
+			continue
+		}
+		if sourceAmount == nil || srcAmount.Rat().Cmp(sourceAmount.Rat()) < 0 {
+			sourceAmount = srcAmount
+		}
+	}
+	if sourceAmount == nil {
+		return nil, fmt.Errorf("%w, from %v to %v, amount: %v, sendAsset: %v", ErrNoPathFound, from, receiver, amt, sendAsset)
+	}
+	return sourceAmount, nil
+}
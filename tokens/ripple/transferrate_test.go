@@ -0,0 +1,279 @@
+package ripple
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestApplyTransferRate(t *testing.T) {
+	tests := []struct {
+		amount int64
+		rate   uint32
+		want   int64
+	}{
+		{1000000, transferRateBase, 1000000},
+		{1000000, 1005000000, 1005000},
+		{1000000, 2000000000, 2000000},
+		{1, 1005000000, 2}, // rounded up
+		{999, 1005000000, 1004},
+	}
+	for _, test := range tests {
+		if have := applyTransferRate(big.NewInt(test.amount), test.rate); have.Int64() != test.want {
+			t.Errorf("apply transfer rate %v to %v mismatch, have %v want %v", test.rate, test.amount, have, test.want)
+		}
+	}
+}
+
+func TestGetSendMaxWithTransferRate(t *testing.T) {
+	var transferRate uint32
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		if method != "account_info" || rpcParams[0]["account"] != tIssuer {
+			t.Errorf("unexpected rpc call %v %v", method, rpcParams)
+		}
+		result := accountInfoResult(tIssuer, "100000000").(map[string]interface{})
+		if transferRate != 0 {
+			result["account_data"].(map[string]interface{})["TransferRate"] = transferRate
+		}
+		return result
+	})
+
+	token := &tokens.TokenConfig{ContractAddress: "USD/" + tIssuer, Decimals: 6}
+	if err := b.VerifyTokenConfig(token); err != nil {
+		t.Fatal(err)
+	}
+	asset, _ := data.NewAsset(token.ContractAddress)
+	amount := big.NewInt(1000000) // 1 USD
+
+	// 0.5% transfer fee
+	transferRate = 1005000000
+	sendMax, err := b.getSendMax(asset, tSender, amount, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := data.NewAmount("1.005/USD/" + tIssuer)
+	if sendMax == nil || !sendMax.Equals(*want) {
+		t.Errorf("send max mismatch, have %v want %v", sendMax, want)
+	}
+
+	// no transfer fee if the issuer sends
+	if sendMax, err = b.getSendMax(asset, tIssuer, amount, token); err != nil || sendMax != nil {
+		t.Errorf("issuer payout should have no send max, have %v %v", sendMax, err)
+	}
+
+	// default transfer rate
+	for _, rate := range []uint32{0, transferRateBase} {
+		transferRate = rate
+		if sendMax, err = b.getSendMax(asset, tSender, amount, token); err != nil || sendMax != nil {
+			t.Errorf("default transfer rate %v should have no send max, have %v %v", rate, sendMax, err)
+		}
+	}
+
+	// native XRP has no issuer
+	xrp, _ := data.NewAsset("XRP")
+	if sendMax, err = b.getSendMax(xrp, tSender, amount, token); err != nil || sendMax != nil {
+		t.Errorf("native payout should have no send max, have %v %v", sendMax, err)
+	}
+}
+
+func TestApplySendMaxSlippage(t *testing.T) {
+	tests := []struct {
+		amount   int64
+		slippage *big.Rat
+		want     int64
+	}{
+		{1000000, new(big.Rat), 1000000},
+		{1000000, big.NewRat(1, 2), 1005000}, // 0.5%
+		{1005000, big.NewRat(1, 1), 1015050},
+		{999, big.NewRat(1, 1), 1009}, // rounded up
+	}
+	for _, test := range tests {
+		if have := applySendMaxSlippage(big.NewInt(test.amount), test.slippage); have.Int64() != test.want {
+			t.Errorf("apply slippage %v to %v mismatch, have %v want %v", test.slippage, test.amount, have, test.want)
+		}
+	}
+}
+
+func TestGetSendMaxWithSlippage(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		result := accountInfoResult(tIssuer, "100000000").(map[string]interface{})
+		result["account_data"].(map[string]interface{})["TransferRate"] = 1005000000
+		return result
+	})
+
+	token := &tokens.TokenConfig{ContractAddress: "USD/" + tIssuer, Decimals: 6}
+	if err := b.VerifyTokenConfig(token); err != nil {
+		t.Fatal(err)
+	}
+	asset, _ := data.NewAsset(token.ContractAddress)
+	amount := big.NewInt(1000000) // 1 USD
+
+	tests := []struct {
+		slippage string
+		want     string
+	}{
+		{"", "1.005"},
+		{"1", "1.01505"},    // 1.005 * 1.01
+		{"0.5", "1.010025"}, // 1.005 * 1.005
+		{"20", "1.05525"},   // capped to 5%
+		{"-1", "1.005"},     // wrong config is ignored
+		{"wrong", "1.005"},  // wrong config is ignored
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"sendMaxSlippage": test.slippage})
+		sendMax, err := b.getSendMax(asset, tSender, amount, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := data.NewAmount(test.want + "/USD/" + tIssuer)
+		if sendMax == nil || !sendMax.Equals(*want) {
+			t.Errorf("send max with slippage %q mismatch, have %v want %v", test.slippage, sendMax, want)
+		}
+	}
+}
+
+func TestApplySendMaxSlippageToAmount(t *testing.T) {
+	usd := "/USD/" + tIssuer
+	tests := []struct {
+		amount   string
+		slippage *big.Rat
+		want     string
+	}{
+		{"1000000", big.NewRat(1, 2), "1005000"},
+		{"999", big.NewRat(1, 1), "1009"}, // rounded up drops
+		{"1" + usd, new(big.Rat), "1" + usd},
+		{"1" + usd, big.NewRat(1, 2), "1.005" + usd},
+		{"0.3333333333333333" + usd, big.NewRat(1, 1), "0.3366666666666667" + usd}, // rounded up
+		{"123456789" + usd, big.NewRat(5, 1), "129629628.45" + usd},
+	}
+	for _, test := range tests {
+		amount, err := data.NewAmount(test.amount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want, _ := data.NewAmount(test.want)
+		if have, err := applySendMaxSlippageToAmount(amount, test.slippage); err != nil || !have.Equals(*want) {
+			t.Errorf("apply slippage %v to %v mismatch, have %v %v want %v", test.slippage, test.amount, have, err, want)
+		}
+	}
+}
+
+func TestGetPathSendMax(t *testing.T) {
+	var pathFindParams map[string]interface{}
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			return accountInfoResult(tIssuer, "100000000")
+		case "ripple_path_find":
+			pathFindParams = rpcParams[0]
+			return map[string]interface{}{
+				"alternatives": []interface{}{
+					map[string]interface{}{"source_amount": map[string]interface{}{"currency": "EUR", "issuer": tIssuer, "value": "0.95"}},
+					map[string]interface{}{"source_amount": map[string]interface{}{"currency": "EUR", "issuer": tIssuer, "value": "0.9"}},
+					map[string]interface{}{"source_amount": "1200000"},
+				},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	token := &tokens.TokenConfig{TokenID: "USD", ContractAddress: "USD/" + tIssuer, Decimals: 6}
+	if err := b.VerifyTokenConfig(token); err != nil {
+		t.Fatal(err)
+	}
+	asset, _ := data.NewAsset(token.ContractAddress)
+	amount := big.NewInt(1000000) // 1 USD
+	amt, err := getPaymentAmount(amount, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		customs map[string]string
+		want    string
+	}{
+		// the default transfer rate still has send max
+		{"delivered asset", nil, "1/USD/" + tIssuer},
+		{"delivered asset with slippage", map[string]string{"sendMaxSlippage": "1"}, "1.01/USD/" + tIssuer},
+		{"source asset", map[string]string{"pathSendAsset:USD": "EUR/" + tIssuer}, "0.9/EUR/" + tIssuer},
+		{"source asset with slippage", map[string]string{"pathSendAsset:USD": "EUR/" + tIssuer, "sendMaxSlippage": "1"}, "0.909/EUR/" + tIssuer},
+		{"xrp source", map[string]string{"pathSendAsset:USD": "XRP", "sendMaxSlippage": "0.5"}, "1206000"},
+	}
+	for _, test := range tests {
+		setTestCustoms(t, b.ChainConfig.ChainID, test.customs)
+		sendMax, err := b.getPathSendMax(token, asset, tSender, tReceiver, amount, amt)
+		want, _ := data.NewAmount(test.want)
+		if err != nil || sendMax == nil || !sendMax.Equals(*want) {
+			t.Errorf("%v: path send max mismatch, have %v %v want %v", test.name, sendMax, err, want)
+		}
+	}
+	if pathFindParams["source_account"] != tSender || pathFindParams["destination_account"] != tReceiver {
+		t.Errorf("wrong ripple_path_find params %v", pathFindParams)
+	}
+
+	// no alternative of the source asset
+	setTestCustoms(t, b.ChainConfig.ChainID, map[string]string{"pathSendAsset:USD": "JPY/" + tIssuer})
+	if _, err = b.getPathSendMax(token, asset, tSender, tReceiver, amount, amt); !errors.Is(err, ErrNoPathFound) {
+		t.Errorf("path send max without alternatives should fail with %v, have %v", ErrNoPathFound, err)
+	}
+}
+
+func TestBuildPaymentWithSendMaxInArgs(t *testing.T) {
+	transferRate := uint32(1005000000)
+	b, newArgs := newTestBuildTxBridge(t, "USD/"+tIssuer, DeliveryModePayment, func(method string, rpcParams []map[string]interface{}) interface{} {
+		account := rpcParams[0]["account"].(string)
+		switch method {
+		case "account_info":
+			result := accountInfoResult(account, "100000000").(map[string]interface{})
+			if account == tIssuer {
+				result["account_data"].(map[string]interface{})["TransferRate"] = transferRate
+			}
+			return result
+		case "account_lines":
+			return map[string]interface{}{
+				"account": account,
+				"lines": []map[string]interface{}{{
+					"account":    tIssuer,
+					"balance":    "100",
+					"currency":   "USD",
+					"limit":      "1000",
+					"limit_peer": "0",
+				}},
+			}
+		case "server_state":
+			return serverStateResult()
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	// the SendMax with the transfer fee is recorded in the args on the first build
+	args := newArgs()
+	rawTx, err := b.BuildRawTransaction(args)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := data.NewAmount("1.005/USD/" + tIssuer)
+	if sendMax := rawTx.(*data.Payment).SendMax; sendMax == nil || !sendMax.Equals(*want) {
+		t.Fatalf("send max mismatch, have %v want %v", sendMax, want)
+	}
+	if args.Extra.SendMax == nil || *args.Extra.SendMax != want.String() {
+		t.Fatalf("send max in args mismatch, have %v want %v", args.Extra.SendMax, want)
+	}
+
+	// and used verbatim on rebuild (eg. by the accept nodes) even if the transfer rate has changed
+	transferRate = 1010000000
+	if rawTx, err = b.BuildRawTransaction(args); err != nil {
+		t.Fatal(err)
+	}
+	if sendMax := rawTx.(*data.Payment).SendMax; sendMax == nil || !sendMax.Equals(*want) {
+		t.Errorf("rebuilt send max mismatch, have %v want %v", sendMax, want)
+	}
+}
@@ -0,0 +1,32 @@
+package ripple
+
+import (
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// TrustLine the trust line of an account with the issuer (from the account's perspective)
+// with the per-line flags, it is the single source of the trust line pre-checks.
+type TrustLine struct {
+	data.AccountLine
+	// Authorized the account authorized the issuer to hold its IOUs
+	Authorized bool `json:"authorized"`
+	// PeerAuthorized the issuer authorized the account to hold its IOUs (issuer requires auth)
+	PeerAuthorized bool `json:"peer_authorized"`
+}
+
+// IsFrozenByIssuer the issuer froze the trust line
+func (l *TrustLine) IsFrozenByIssuer() bool {
+	return l.FreezePeer
+}
+
+// IsNoRippleByIssuer the issuer set no ripple on its side of the trust line
+func (l *TrustLine) IsNoRippleByIssuer() bool {
+	return l.NoRipplePeer
+}
+
+// accountLinesResult account_lines result with the per-line flags
+type accountLinesResult struct {
+	Account data.Account `json:"account"`
+	Marker  *string      `json:"marker"`
+	Lines   []TrustLine  `json:"lines"`
+}
@@ -0,0 +1,110 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/params"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestGetAccountLineFlags(t *testing.T) {
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		if method != "account_lines" {
+			t.Errorf("unexpected rpc method %v", method)
+		}
+		return map[string]interface{}{
+			"account": rpcParams[0]["account"],
+			"lines": []map[string]interface{}{{
+				"account":         tIssuer,
+				"balance":         "100",
+				"currency":        "USD",
+				"limit":           "1000",
+				"limit_peer":      "0",
+				"freeze":          true,
+				"freeze_peer":     true,
+				"no_ripple":       true,
+				"no_ripple_peer":  true,
+				"authorized":      true,
+				"peer_authorized": true,
+			}},
+		}
+	})
+
+	line, err := b.GetAccountLine("USD", tIssuer, tSender)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !line.Freeze || !line.IsFrozenByIssuer() || !line.NoRipple || !line.IsNoRippleByIssuer() ||
+		!line.Authorized || !line.PeerAuthorized {
+		t.Errorf("trust line flags mismatch, have %+v", line)
+	}
+	if line.Balance.String() != "100" || line.Limit.String() != "1000" {
+		t.Errorf("trust line balance mismatch, have balance %v limit %v", line.Balance, line.Limit)
+	}
+}
+
+func TestTrustLineFlagsPreCheck(t *testing.T) {
+	defer func(isSwapServer bool) { params.IsSwapServer = isSwapServer }(params.IsSwapServer)
+	params.IsSwapServer = true
+
+	var issuerFlags uint32
+	var senderNoRipple, receiverNoRipple, receiverAuthorized bool
+	b := newTestRippleBridge(t, func(method string, rpcParams []map[string]interface{}) interface{} {
+		switch method {
+		case "account_info":
+			result := accountInfoResult(tIssuer, "100000000").(map[string]interface{})
+			result["account_data"].(map[string]interface{})["Flags"] = issuerFlags
+			return result
+		case "account_lines":
+			account := rpcParams[0]["account"]
+			noRipple, authorized := senderNoRipple, true
+			if account == tReceiver {
+				noRipple, authorized = receiverNoRipple, receiverAuthorized
+			}
+			return map[string]interface{}{
+				"account": account,
+				"lines": []map[string]interface{}{{
+					"account":         tIssuer,
+					"balance":         "100",
+					"currency":        "USD",
+					"limit":           "1000",
+					"limit_peer":      "0",
+					"no_ripple_peer":  noRipple,
+					"peer_authorized": authorized,
+				}},
+			}
+		default:
+			t.Errorf("unexpected rpc method %v", method)
+			return nil
+		}
+	})
+
+	amount, err := data.NewAmount("10/USD/" + tIssuer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name               string
+		issuerFlags        uint32
+		senderNoRipple     bool
+		receiverNoRipple   bool
+		receiverAuthorized bool
+		wantErr            error
+	}{
+		{"default", 0, false, false, false, nil},
+		{"no ripple on one line", 0, true, false, false, nil},
+		{"no ripple on both lines", 0, true, true, false, ErrNoRippleThroughIssuer},
+		{"require auth and authorized", uint32(data.LsRequireAuth), false, false, true, nil},
+		{"require auth but not authorized", uint32(data.LsRequireAuth), false, false, false, ErrTrustLineNotAuthorized},
+	}
+	for _, test := range tests {
+		issuerFlags = test.issuerFlags
+		senderNoRipple, receiverNoRipple, receiverAuthorized = test.senderNoRipple, test.receiverNoRipple, test.receiverAuthorized
+		err = b.checkNonNativeBalance("USD", tIssuer, tSender, tReceiver, amount)
+		if (test.wantErr == nil && err != nil) || (test.wantErr != nil && !errors.Is(err, test.wantErr)) {
+			t.Errorf("%v: check non native balance mismatch, have %v want %v", test.name, err, test.wantErr)
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package ripple
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+// ToTxJSON convert the raw tx to the human readable json (the `tx_json` shape of rippled)
+// for audit logs and troubleshooting. Amounts are drops strings or IOU objects,
+// and memo fields are hex strings. Fields which are not set (eg. the signature and
+// hash of an unsigned tx, and the empty memo fields) are omitted like rippled does.
+func (b *Bridge) ToTxJSON(rawTx interface{}) (string, error) {
+	tx, ok := rawTx.(data.Transaction)
+	if !ok {
+		return "", tokens.ErrWrongRawTx
+	}
+	bs, err := json.Marshal(tx)
+	if err != nil {
+		return "", err
+	}
+	var txJSON map[string]interface{}
+	if err = json.Unmarshal(bs, &txJSON); err != nil {
+		return "", err
+	}
+	if sig, _ := txJSON["TxnSignature"].(string); sig == "" {
+		delete(txJSON, "TxnSignature")
+	}
+	if hash := tx.GetHash(); hash == nil || hash.IsZero() {
+		delete(txJSON, "hash")
+	}
+	if memos, _ := txJSON["Memos"].([]interface{}); len(memos) > 0 {
+		for _, memo := range memos {
+			memoObj, _ := memo.(map[string]interface{})
+			fields, _ := memoObj["Memo"].(map[string]interface{})
+			for key, val := range fields {
+				if val == "" {
+					delete(fields, key)
+				}
+			}
+		}
+	}
+	// map keys are sorted, so the output is deterministic
+	bs, err = json.Marshal(txJSON)
+	if err != nil {
+		return "", err
+	}
+	return string(bs), nil
+}
+
+// DecodeTransaction decode the hex encoded (signed) tx blob to the tx of its type (eg. *data.Payment),
+// the hash of the tx is set. It does not need a Bridge, so it can be used to inspect the raw blobs
+// (eg. in incident response), and the decoded tx can be converted to json by ToTxJSON.
+func DecodeTransaction(hexBlob string) (data.Transaction, error) {
+	hexBlob = strings.TrimPrefix(strings.TrimPrefix(strings.TrimSpace(hexBlob), "0x"), "0X")
+	blob, err := hex.DecodeString(hexBlob)
+	if err != nil {
+		return nil, fmt.Errorf("%w, wrong hex blob", err)
+	}
+	tx, err := data.ReadTransaction(bytes.NewReader(blob))
+	if err != nil {
+		return nil, fmt.Errorf("%w, decode tx blob failed", err)
+	}
+	// the blob must be canonical (eg. without trailing bytes) to have the same hash
+	hash, reblob, err := data.Raw(tx)
+	if err != nil {
+		return nil, fmt.Errorf("%w, re-encode tx failed", err)
+	}
+	if !bytes.Equal(blob, reblob) {
+		return nil, fmt.Errorf("tx blob is not canonical, re-encoded %X", reblob)
+	}
+	*tx.GetHash() = hash
+	return tx, nil
+}
@@ -0,0 +1,104 @@
+package ripple
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+func TestToTxJSON(t *testing.T) {
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag := uint32(12345)
+	tx, err := NewUnsignedPaymentTransaction(key, nil, 7, tReceiver, &tag, "1.5/USD/"+tIssuer, "12", "swap memo", "", tfPartialPayment)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := NewCrossChainBridge()
+	have, err := b.ToTxJSON(tx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"Account":"rUXnCWFiA6SbJazSHCNdyu1tQzGfSrafgz",` +
+		`"Amount":{"currency":"USD","issuer":"` + tIssuer + `","value":"1.5"},` +
+		`"Destination":"` + tReceiver + `","DestinationTag":12345,"Fee":"12","Flags":131072,` +
+		`"Memos":[{"Memo":{"MemoData":"73776170206D656D6F"}}],"Sequence":7,` +
+		`"SigningPubKey":"03D49C56E1B185F1BE899AE66A02EFC17F78EA6FC53AF85E0FE54C6E8B7F8C71A8",` +
+		`"TransactionType":"Payment"}`
+	if have != want {
+		t.Errorf("tx json mismatch\nhave %v\nwant %v", have, want)
+	}
+
+	// signed tx has the signature and hash
+	signedTx := signTestPayment(t, "ecdsa")
+	have, err = b.ToTxJSON(signedTx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var txJSON map[string]interface{}
+	if err = json.Unmarshal([]byte(have), &txJSON); err != nil {
+		t.Fatal(err)
+	}
+	if txJSON["hash"] != signedTx.GetHash().String() || txJSON["TxnSignature"] == "" || txJSON["Amount"] != "1000000" {
+		t.Errorf("signed tx json mismatch, have %v", have)
+	}
+
+	if _, err = b.ToTxJSON("not a tx"); err == nil {
+		t.Errorf("convert wrong raw tx should fail")
+	}
+}
+
+// tSignedPaymentBlob the ed25519 signed payment of signTestPayment
+const (
+	tSignedPaymentBlob = "120000220000000024000000072E000030396140000000000F424068400000000000000A" +
+		"7321EDAAC3F98BB94F451804EF5993C847DAAA4E6154F455635659D88AA5C80F156303" +
+		"74402F6473E8A888282C73C402FE670EA56833CA8F0E4A626071B64427F2CB0E01B44BE8F7D16306B7DB7D0B2E7D9A36BD0BFDE35A53F5AF3EA99F8B3C3CEF8FFB06" +
+		"8114AA066C988C712815CC37AF71472B7CBBBD4E2A0A8314F667B0CA50CC7709A220B0561B85E53A48461FA8F9EA7D0973776170206D656D6FE1F1"
+	tSignedPaymentHash = "F251B7ABC0196468C9CC782E2504674596FE1ADC0437BF8BC5DB64CE20381555"
+)
+
+func TestDecodeTransaction(t *testing.T) {
+	tx, err := DecodeTransaction("0x" + tSignedPaymentBlob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	payment, ok := tx.(*data.Payment)
+	if !ok {
+		t.Fatalf("tx should be Payment, but have %T", tx)
+	}
+	if payment.GetHash().String() != tSignedPaymentHash {
+		t.Errorf("tx hash mismatch, have %v want %v", payment.GetHash(), tSignedPaymentHash)
+	}
+	if payment.Destination.String() != tReceiver || payment.DestinationTag == nil || *payment.DestinationTag != 12345 {
+		t.Errorf("payment destination mismatch, have %v:%v", payment.Destination, payment.DestinationTag)
+	}
+	if !payment.Amount.IsNative() || payment.Amount.Value.String() != "1" || payment.Fee.String() != "0.00001" || payment.Sequence != 7 {
+		t.Errorf("payment amount, fee or sequence mismatch, have %v %v %v", payment.Amount, payment.Fee, payment.Sequence)
+	}
+	if len(payment.Memos) != 1 || string(payment.Memos[0].Memo.MemoData.Bytes()) != "swap memo" {
+		t.Errorf("payment memos mismatch, have %v", payment.Memos)
+	}
+	if payment.TxnSignature == nil || len(*payment.TxnSignature) == 0 {
+		t.Errorf("signed payment should have signature")
+	}
+
+	// the same as the signed tx
+	signedTx := signTestPayment(t, "ed25519")
+	if *signedTx.GetHash() != *payment.GetHash() {
+		t.Errorf("decoded tx hash mismatch, have %v want %v", payment.GetHash(), signedTx.GetHash())
+	}
+
+	for _, blob := range []string{
+		"not hex",
+		tSignedPaymentBlob[:len(tSignedPaymentBlob)-10], // truncated
+		tSignedPaymentBlob + "00",                       // trailing bytes
+	} {
+		if _, err := DecodeTransaction(blob); err == nil {
+			t.Errorf("decode wrong blob %v should fail", blob)
+		}
+	}
+}
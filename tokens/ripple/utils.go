@@ -1,11 +1,17 @@
 package ripple
 
 import (
+	"errors"
+	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
 )
 
+// ErrInvalidFee fee is not a positive integer of native XRP drops
+var ErrInvalidFee = errors.New("invalid fee")
+
 // ParsePaths parse paths
 func ParsePaths(s string) (*data.PathSet, error) {
 	ps := data.PathSet{}
@@ -18,3 +24,62 @@ func ParsePaths(s string) (*data.PathSet, error) {
 	}
 	return &ps, nil
 }
+
+// IsSameAsset whether the amount is of the asset.
+// Currency may be standard code (eg. `USD`) or 40 hex chars.
+func IsSameAsset(currency, issuer string, amount *data.Amount) bool {
+	if amount == nil {
+		return false
+	}
+	cur, err := data.NewCurrency(currency)
+	if err != nil || cur != amount.Currency {
+		return false
+	}
+	if cur.IsNative() {
+		return issuer == "" && amount.Issuer.IsZero()
+	}
+	return issuer == amount.Issuer.String()
+}
+
+// IsSameAmount whether the amounts have the same asset and value
+func IsSameAmount(a, b *data.Amount) bool {
+	if a == nil || b == nil || a.Value == nil || b.Value == nil {
+		return false
+	}
+	if a.IsNative() != b.IsNative() || a.Currency != b.Currency {
+		return false
+	}
+	if !a.IsNative() && a.Issuer != b.Issuer {
+		return false
+	}
+	return a.Value.Compare(*b.Value) == 0
+}
+
+// ParseNativeFee parse fee strictly as a positive integer of native XRP drops.
+// `data.NewValue` accepts a leading number prefix (eg. `12` of `12/USD/issuer`)
+// and truncates fractional drops silently, so a misconfigured fee is rejected here.
+// Fee with decimal point (or `/XRP` suffix) is in XRP, otherwise it is in drops.
+func ParseNativeFee(fee string) (*data.Value, error) {
+	amount, err := data.NewAmount(fee)
+	if err != nil {
+		return nil, fmt.Errorf("%w, fee: %v, %v", ErrInvalidFee, fee, err)
+	}
+	if !amount.IsNative() {
+		return nil, fmt.Errorf("%w, fee: %v, fee must be native XRP", ErrInvalidFee, fee)
+	}
+	parts := strings.Split(strings.TrimSpace(fee), "/")
+	drops, ok := new(big.Rat).SetString(parts[0])
+	if !ok {
+		return nil, fmt.Errorf("%w, fee: %v, not a number", ErrInvalidFee, fee)
+	}
+	if strings.Contains(parts[0], ".") || len(parts) > 1 {
+		drops.Mul(drops, big.NewRat(1000000, 1))
+	}
+	if !drops.IsInt() || drops.Sign() <= 0 {
+		return nil, fmt.Errorf("%w, fee: %v, must be positive integer drops", ErrInvalidFee, fee)
+	}
+	if drops.Num().Cmp(big.NewInt(amount.Drops())) != 0 {
+		return nil, fmt.Errorf("%w, fee: %v, parsed drops mismatch", ErrInvalidFee, fee)
+	}
+	return amount.Value, nil
+}
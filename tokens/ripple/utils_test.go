@@ -0,0 +1,110 @@
+package ripple
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens/ripple/rubblelabs/ripple/data"
+)
+
+const tHexCurrency = "534F4C4F00000000000000000000000000000000" // SOLO
+
+func mustNewAmount(t *testing.T, v string) *data.Amount {
+	amount, err := data.NewAmount(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return amount
+}
+
+func TestIsSameAmount(t *testing.T) {
+	tests := []struct {
+		a, b string
+		same bool
+	}{
+		{"1000000", "1000000", true},
+		{"1000000", "1000001", false},
+		{"1/XRP", "1000000", true},
+		{"1.5/USD/" + tIssuer, "1.50/USD/" + tIssuer, true},
+		{"1.5/USD/" + tIssuer, "15e-1/USD/" + tIssuer, true},
+		{"1.5/USD/" + tIssuer, "1.5/0000000000000000000000005553440000000000/" + tIssuer, true},
+		{"1.5/USD/" + tIssuer, "1.5/EUR/" + tIssuer, false},
+		{"1.5/USD/" + tIssuer, "1.5/USD/" + tSender, false},
+		{"1/" + tHexCurrency + "/" + tIssuer, "1/" + "534f4c4f00000000000000000000000000000000/" + tIssuer, true},
+		{"1/" + tHexCurrency + "/" + tIssuer, "1/USD/" + tIssuer, false},
+		{"1000000", "1/USD/" + tIssuer, false},
+	}
+	for _, test := range tests {
+		a, b := mustNewAmount(t, test.a), mustNewAmount(t, test.b)
+		if have := IsSameAmount(a, b); have != test.same {
+			t.Errorf("compare amount %v and %v mismatch, have %v want %v", test.a, test.b, have, test.same)
+		}
+	}
+	if IsSameAmount(nil, mustNewAmount(t, "1")) {
+		t.Error("nil amount should not be the same with any amount")
+	}
+}
+
+func TestIsSameAsset(t *testing.T) {
+	tests := []struct {
+		currency, issuer, amount string
+		same                     bool
+	}{
+		{"XRP", "", "1000000", true},
+		{"XRP", tIssuer, "1000000", false},
+		{"USD", tIssuer, "1/USD/" + tIssuer, true},
+		{"0000000000000000000000005553440000000000", tIssuer, "1/USD/" + tIssuer, true},
+		{"USD", tSender, "1/USD/" + tIssuer, false},
+		{"XRP", "", "1/USD/" + tIssuer, false},
+		{tHexCurrency, tIssuer, "1/" + tHexCurrency + "/" + tIssuer, true},
+		{"SOLO", tIssuer, "1/" + tHexCurrency + "/" + tIssuer, false},
+	}
+	for _, test := range tests {
+		if have := IsSameAsset(test.currency, test.issuer, mustNewAmount(t, test.amount)); have != test.same {
+			t.Errorf("compare asset %v/%v with amount %v mismatch, have %v want %v", test.currency, test.issuer, test.amount, have, test.same)
+		}
+	}
+}
+
+func TestParseNativeFee(t *testing.T) {
+	valids := map[string]int64{
+		"12":          12,
+		"0.000012":    12,
+		"0.00001/XRP": 10,
+	}
+	for fee, want := range valids {
+		value, err := ParseNativeFee(fee)
+		if err != nil {
+			t.Errorf("parse fee %v failed: %v", fee, err)
+			continue
+		}
+		if !value.IsNative() || value.Drops() != want {
+			t.Errorf("parse fee %v mismatch, have %v drops", fee, value.Drops())
+		}
+	}
+
+	invalids := []string{
+		"",
+		"0",
+		"-12",
+		"0.0000125",
+		"12abc",
+		"12/USD/" + tIssuer,
+	}
+	for _, fee := range invalids {
+		if _, err := ParseNativeFee(fee); !errors.Is(err, ErrInvalidFee) {
+			t.Errorf("parse invalid fee %q should fail with %v, but have %v", fee, ErrInvalidFee, err)
+		}
+	}
+
+	key, err := ImportKeyFromSeed(tSeed, "ecdsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, fee := range []string{"0.0000125", "12/USD/" + tIssuer} {
+		_, err = NewUnsignedPaymentTransaction(key, nil, 3, tReceiver, nil, "1000000", fee, "", "", 0)
+		if !errors.Is(err, ErrInvalidFee) {
+			t.Errorf("build payment with fee %q should fail with %v, but have %v", fee, ErrInvalidFee, err)
+		}
+	}
+}
@@ -24,11 +24,10 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHashes []string) (err error
 	if !ok {
 		return fmt.Errorf("ripple tx type error")
 	}
-	msgHash, msg, err := data.SigningHash(tx)
+	msgHash, msg, err := b.GetSigningHash(tx)
 	if err != nil {
 		return fmt.Errorf("rebuild ripple tx msg error, %w", err)
 	}
-	msg = append(tx.SigningPrefix().Bytes(), msg...)
 
 	pubkey := tx.GetPublicKey().Bytes()
 	isEd := isEd25519Pubkey(pubkey)
@@ -84,7 +83,7 @@ func (b *Bridge) verifySwapoutTx(txHash string, _ int, allowUnstable bool) (*tok
 	}
 
 	if !allowUnstable {
-		h, errf := b.GetLatestBlockNumber()
+		h, errf := b.GetLatestLedgerNumber()
 		if errf != nil {
 			return swapInfo, errf
 		}
@@ -133,7 +132,8 @@ func (b *Bridge) verifySwapoutTx(txHash string, _ int, allowUnstable bool) (*tok
 		return swapInfo, err
 	}
 
-	if success := parseSwapMemos(swapInfo, payment.Memos); !success {
+	memoEncoding, _ := b.getMemoEncoding()
+	if success := parseSwapMemos(swapInfo, payment.Memos, memoEncoding); !success {
 		log.Info("wrong memos", "memos", common.ToJSONString(payment.Memos, false))
 		return swapInfo, tokens.ErrWrongBindAddress
 	}
@@ -167,15 +167,8 @@ func (b *Bridge) checkToken(token *tokens.TokenConfig, txmeta *data.TransactionW
 		return fmt.Errorf("non exist asset %v", token.ContractAddress)
 	}
 	asset := assetI.(*data.Asset)
-	if !strings.EqualFold(asset.Currency, txmeta.MetaData.DeliveredAmount.Currency.Machine()) {
-		return fmt.Errorf("ripple currency not match")
-	}
-	if !txmeta.MetaData.DeliveredAmount.Currency.IsNative() {
-		if !strings.EqualFold(asset.Issuer, txmeta.MetaData.DeliveredAmount.Issuer.String()) {
-			return fmt.Errorf("ripple currency issuer not match")
-		}
-	} else if !txmeta.MetaData.DeliveredAmount.Issuer.IsZero() {
-		return fmt.Errorf("ripple native issuer is not zero")
+	if !IsSameAsset(asset.Currency, asset.Issuer, txmeta.MetaData.DeliveredAmount) {
+		return fmt.Errorf("ripple delivered asset %v not match %v", txmeta.MetaData.DeliveredAmount.Asset(), asset)
 	}
 	return nil
 }
@@ -190,9 +183,16 @@ func getTargetMemo(memoStr string) (memo string) {
 	return strings.TrimSpace(memo)
 }
 
-func parseSwapMemos(swapInfo *tokens.SwapTxInfo, memos data.Memos) bool {
+// parseSwapMemos parse the bind memo (`bindAddress:toChainID`) of the deposit,
+// the memo is decoded with the memo encoding, and is used as raw if it's not encoded.
+func parseSwapMemos(swapInfo *tokens.SwapTxInfo, memos data.Memos, memoEncoding string) bool {
 	for _, memo := range memos {
-		memoStr := getTargetMemo(string(memo.Memo.MemoData.Bytes()))
+		memoData := memo.Memo.MemoData.Bytes()
+		decoded, err := decodeMemo(memoData, memoEncoding)
+		if err != nil {
+			decoded = string(memoData)
+		}
+		memoStr := getTargetMemo(decoded)
 		parts := strings.Split(memoStr, ":")
 		if len(parts) < 2 {
 			continue
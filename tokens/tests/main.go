@@ -93,6 +93,7 @@ func initRouter() {
 
 	_ = params.SetExtraConfig(
 		&params.ExtraConfig{
+			AllowTestMode:       true,
 			AllowCallByContract: testCfg.AllowCallByContract,
 		},
 	)
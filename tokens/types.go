@@ -229,6 +229,20 @@ type AllExtras struct {
 	Gas        *uint64       `json:"gas,omitempty"`
 	RawTx      hexutil.Bytes `json:"rawTx,omitempty"`
 	BlockHash  *string       `json:"blockHash,omitempty"`
+	// the `Sequence` is overridden by the operator (eg. ripple recovering a specific tx), checked against the account sequence
+	SequenceOverride bool `json:"sequenceOverride,omitempty"`
+	// the tx can not be included after this height (eg. ripple `LastLedgerSequence`)
+	ExpiryHeight *uint64 `json:"expiryHeight,omitempty"`
+	// the account number of the sender (eg. cosmos `account_number`), bypass querying it from chain
+	AccountNumber *uint64 `json:"accountNumber,omitempty"`
+	// the payment paths supplied by the operator (eg. ripple `Paths`), used verbatim
+	Paths *string `json:"paths,omitempty"`
+	// the expiration (in ripple time) of the created object (eg. ripple Check `Expiration`)
+	Expiration *uint32 `json:"expiration,omitempty"`
+	// the max amount to spend (eg. ripple `SendMax`), used verbatim
+	SendMax *string `json:"sendMax,omitempty"`
+	// the fee granter paying the fee (eg. cosmos feegrant), empty if the sender pays it
+	FeeGranter *string `json:"feeGranter,omitempty"`
 
 	// calculated value
 	BridgeFee *big.Int `json:"bridgeFee,omitempty"`
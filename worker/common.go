@@ -1,6 +1,7 @@
 package worker
 
 import (
+	"errors"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Router/v3/mongodb"
@@ -16,6 +17,7 @@ type MatchTx struct {
 	SwapTime   uint64
 	SwapValue  string
 	SwapNonce  uint64
+	SwapExpiry uint64
 }
 
 // AddInitialSwapResult add initial result
@@ -64,6 +66,7 @@ func updateRouterSwapResult(fromChainID, txid string, logIndex int, mtx *MatchTx
 	if mtx.SwapHeight == 0 {
 		updates.SwapValue = mtx.SwapValue
 		updates.SwapNonce = mtx.SwapNonce
+		updates.SwapExpiry = mtx.SwapExpiry
 		updates.SwapHeight = 0
 		updates.SwapTime = 0
 		if mtx.SwapTx != "" {
@@ -140,6 +143,16 @@ func updateSwapTx(fromChainID, txid string, logIndex int, swapTx string) (err er
 	return err
 }
 
+func updateSwapNonce(fromChainID, txid string, logIndex int, nonce uint64) (err error) {
+	err = mongodb.UpdateRouterSwapNonce(fromChainID, txid, logIndex, nonce)
+	if err != nil {
+		logWorkerError("update", "updateSwapNonce failed", err, "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "nonce", nonce)
+	} else {
+		logWorker("update", "updateSwapNonce success", "chainid", fromChainID, "txid", txid, "logIndex", logIndex, "nonce", nonce)
+	}
+	return err
+}
+
 func markSwapResultUnstable(fromChainID, txid string, logIndex int) (err error) {
 	status := mongodb.MatchTxNotStable
 	timestamp := now()
@@ -198,6 +211,10 @@ SENDTX_LOOP:
 				logWorker("sendtx", "send tx success", "txHash", txHash, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "swapNonce", swapTxNonce, "replaceNum", replaceNum)
 				break SENDTX_LOOP
 			}
+			// the same signed tx will be rejected again, it should be signed again
+			if errors.Is(err, tokens.ErrStaleAccountData) {
+				break SENDTX_LOOP
+			}
 			sleepSeconds(1)
 		}
 
@@ -229,9 +246,87 @@ SENDTX_LOOP:
 		go sendTxLoopUntilSuccess(bridge, txHash, signedTx, args)
 	}
 
+	if waiter, ok := bridge.(txStatusWaiter); ok {
+		go waitSwapTxStatus(waiter, txHash, args)
+	}
+
 	return txHash, nil
 }
 
+// txStatusWaiter is implemented by the bridges which can wait the tx status after sending it
+// (eg. by polling with configed interval and timeout, or by subscribing the tx event),
+// so that the swap result is updated sooner than the stable job finds it.
+type txStatusWaiter interface {
+	WaitTransactionStatus(txHash string) (*tokens.TxStatus, error)
+}
+
+// waitSwapTxStatus wait the status of the sent swap tx and record its block height,
+// it is left to the stable job if the tx is still pending when the waiting timeout.
+func waitSwapTxStatus(waiter txStatusWaiter, txHash string, args *tokens.BuildTxArgs) {
+	txStatus, err := waiter.WaitTransactionStatus(txHash)
+	if err != nil {
+		logWorkerWarn("sendtx", "wait tx status failed, leave it to the stable job", "txHash", txHash, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "err", err)
+		return
+	}
+	if !txStatus.IsSwapTxOnChain() {
+		return
+	}
+	logWorker("sendtx", "wait tx status success", "txHash", txHash, "blockNumber", txStatus.BlockHeight, "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex)
+	matchTx := &MatchTx{
+		SwapTx:     txHash,
+		SwapHeight: txStatus.BlockHeight,
+		SwapTime:   txStatus.BlockTime,
+	}
+	_ = updateRouterSwapResult(args.FromChainID.String(), args.SwapID, args.LogIndex, matchTx)
+}
+
+// sendSignedTransactionWithRebuild send the signed tx. If it is rejected with stale account data,
+// the raw tx is refreshed with the account data from chain, and is signed and sent again once.
+// The swap nonce is updated if it is changed by the refresh before signing again,
+// and onResign is called with the new tx hash before sending it, to update the swap tx in database.
+func sendSignedTransactionWithRebuild(bridge tokens.IBridge, rawTx, signedTx interface{}, args *tokens.BuildTxArgs, onResign func(txHash string) error) (txHash string, err error) {
+	txHash, err = sendSignedTransaction(bridge, signedTx, args)
+	if !errors.Is(err, tokens.ErrStaleAccountData) {
+		return txHash, err
+	}
+	onRefresh := func(nonce uint64) error {
+		return updateSwapNonce(args.FromChainID.String(), args.SwapID, args.LogIndex, nonce)
+	}
+	signedTx, err = resignWithFreshAccountData(bridge, rawTx, args, err, onRefresh, onResign)
+	if err != nil {
+		return "", err
+	}
+	return sendSignedTransaction(bridge, signedTx, args)
+}
+
+// resignWithFreshAccountData refresh the account data of the raw tx rejected with sendErr and sign it again
+func resignWithFreshAccountData(bridge tokens.IBridge, rawTx interface{}, args *tokens.BuildTxArgs, sendErr error, onRefresh func(nonce uint64) error, onResign func(txHash string) error) (signedTx interface{}, err error) {
+	refresher, ok := bridge.(interface {
+		RefreshRawTxAccount(rawTx interface{}, args *tokens.BuildTxArgs) error
+	})
+	if !ok {
+		return nil, sendErr
+	}
+	logWorkerWarn("sendtx", "rebuild tx with fresh account data", "fromChainID", args.FromChainID, "toChainID", args.ToChainID, "txid", args.SwapID, "logIndex", args.LogIndex, "err", sendErr)
+	oldNonce := args.GetTxNonce()
+	if err = refresher.RefreshRawTxAccount(rawTx, args); err != nil {
+		return nil, err
+	}
+	if nonce := args.GetTxNonce(); nonce != oldNonce {
+		if err = onRefresh(nonce); err != nil {
+			return nil, err
+		}
+	}
+	signedTx, txHash, err := bridge.MPCSignTransaction(rawTx, args)
+	if err != nil {
+		return nil, err
+	}
+	if err = onResign(txHash); err != nil {
+		return nil, err
+	}
+	return signedTx, nil
+}
+
 func sendTxLoopUntilSuccess(bridge tokens.IBridge, txHash string, signedTx interface{}, args *tokens.BuildTxArgs) {
 	toChainID := args.ToChainID.String()
 	severCfg := params.GetRouterServerConfig()
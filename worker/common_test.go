@@ -0,0 +1,92 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Router/v3/tokens"
+)
+
+// testRebuildBridge signs the raw tx (the account number) into the tx hash
+type testRebuildBridge struct {
+	tokens.IBridge
+	accountNumber uint64
+	sequence      uint64
+}
+
+type testRawTx struct {
+	accountNumber uint64
+}
+
+func (b *testRebuildBridge) MPCSignTransaction(rawTx interface{}, _ *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	txHash = fmt.Sprintf("tx-%v", rawTx.(*testRawTx).accountNumber)
+	return txHash, txHash, nil
+}
+
+func (b *testRebuildBridge) RefreshRawTxAccount(rawTx interface{}, args *tokens.BuildTxArgs) error {
+	if args.From != "mpc" {
+		return fmt.Errorf("refresh wrong signer %v", args.From)
+	}
+	rawTx.(*testRawTx).accountNumber = b.accountNumber
+	sequence := b.sequence
+	args.Extra = &tokens.AllExtras{Sequence: &sequence}
+	return nil
+}
+
+// testNoRebuildBridge does not support refreshing the raw tx
+type testNoRebuildBridge struct {
+	tokens.IBridge
+}
+
+func TestResignWithFreshAccountData(t *testing.T) {
+	sequence := uint64(3)
+	args := &tokens.BuildTxArgs{From: "mpc", Extra: &tokens.AllExtras{Sequence: &sequence}}
+	sendErr := fmt.Errorf("%w, account number: 5, on chain: 9", tokens.ErrStaleAccountData)
+
+	var (
+		resignedTxHash string
+		refreshedNonce uint64
+	)
+	onRefresh := func(nonce uint64) error {
+		refreshedNonce = nonce
+		return nil
+	}
+	onResign := func(txHash string) error {
+		resignedTxHash = txHash
+		return nil
+	}
+	rawTx := &testRawTx{accountNumber: 5}
+	signedTx, err := resignWithFreshAccountData(&testRebuildBridge{accountNumber: 9, sequence: 4}, rawTx, args, sendErr, onRefresh, onResign)
+	if err != nil || signedTx != "tx-9" || resignedTxHash != "tx-9" || refreshedNonce != 4 {
+		t.Errorf("resign with fresh account data mismatch, have %v %v, resigned %v, nonce %v", signedTx, err, resignedTxHash, refreshedNonce)
+	}
+
+	// the swap nonce is not updated if it is not changed
+	refreshedNonce = 0
+	signedTx, err = resignWithFreshAccountData(&testRebuildBridge{accountNumber: 9, sequence: 4}, rawTx, args, sendErr, onRefresh, onResign)
+	if err != nil || signedTx != "tx-9" || refreshedNonce != 0 {
+		t.Errorf("unchanged swap nonce should not be updated, have %v %v, nonce %v", signedTx, err, refreshedNonce)
+	}
+
+	// the new swap nonce must be recorded before signing
+	errUpdate := errors.New("update swap nonce failed")
+	resignedTxHash = ""
+	signedTx, err = resignWithFreshAccountData(&testRebuildBridge{accountNumber: 9, sequence: 5}, rawTx, args, sendErr, func(uint64) error { return errUpdate }, onResign)
+	if !errors.Is(err, errUpdate) || signedTx != nil || resignedTxHash != "" {
+		t.Errorf("resign should fail if the new swap nonce is not recorded, have %v %v, resigned %v", signedTx, err, resignedTxHash)
+	}
+
+	// the new tx hash must be recorded before sending
+	errUpdate = errors.New("update swap tx failed")
+	signedTx, err = resignWithFreshAccountData(&testRebuildBridge{accountNumber: 9, sequence: 5}, rawTx, args, sendErr, onRefresh, func(string) error { return errUpdate })
+	if !errors.Is(err, errUpdate) || signedTx != nil {
+		t.Errorf("resign should fail if the new tx hash is not recorded, have %v %v", signedTx, err)
+	}
+
+	resignedTxHash = ""
+	signedTx, err = resignWithFreshAccountData(&testNoRebuildBridge{}, rawTx, args, sendErr, onRefresh, onResign)
+	if !errors.Is(err, tokens.ErrStaleAccountData) || signedTx != nil || resignedTxHash != "" {
+		t.Errorf("bridge not supporting rebuild should return the send error, have %v %v, resigned %v", signedTx, err, resignedTxHash)
+	}
+}
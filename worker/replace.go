@@ -296,7 +296,10 @@ func signAndSendReplaceTx(resBridge tokens.IBridge, rawTx interface{}, args *tok
 		return
 	}
 
-	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
+	sentTxHash, err := sendSignedTransactionWithRebuild(resBridge, rawTx, signedTx, args, func(newTxHash string) error {
+		txHash = newTxHash
+		return mongodb.UpdateRouterOldSwapTxs(fromChainID, txid, logIndex, txHash)
+	})
 	if err == nil && txHash != sentTxHash {
 		logWorkerError("replaceSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", res.ToChainID, "txid", txid, "nonce", res.SwapNonce,
@@ -325,6 +328,9 @@ func verifyReplaceSwap(res *mongodb.MgoSwapResult, isManual bool) (*mongodb.MgoS
 	if res.SwapTx == "" && !params.IsParallelSwapEnabled() {
 		return nil, errors.New("swap without swaptx")
 	}
+	if err = checkIBCTransferPending(res); err != nil {
+		return nil, err
+	}
 	if res.SwapNonce == 0 && !isManual {
 		return nil, errors.New("swap nonce is zero")
 	}
@@ -402,3 +408,20 @@ func checkIfSwapNonceHasPassed(bridge tokens.IBridge, res *mongodb.MgoSwapResult
 	}
 	return nil
 }
+
+// checkIBCTransferPending refuse to replace the swap whose ibc transfer is sent on chain,
+// until its ack or timeout is relayed back, otherwise the funds may be sent twice
+func checkIBCTransferPending(res *mongodb.MgoSwapResult) error {
+	if res.SwapTx == "" {
+		return nil
+	}
+	checker, ok := router.GetBridgeByChainID(res.ToChainID).(ibcTransferChecker)
+	if !ok {
+		return nil
+	}
+	// the swap tx is not found on chain if there's error
+	if pending, _, err := checker.CheckIBCTransfers(res.SwapTx); err == nil && pending {
+		return fmt.Errorf("%w, swaptx: %v", errIBCTransferPending, res.SwapTx)
+	}
+	return nil
+}
@@ -56,6 +56,18 @@ func startStableProducer() {
 	}
 }
 
+// ibcTransferChecker is implemented by the bridges whose swap tx may send ibc transfers,
+// which are finished only after the ack or timeout is relayed back to the source chain
+type ibcTransferChecker interface {
+	CheckIBCTransfers(txHash string) (pending, refunded bool, err error)
+}
+
+// swapExpiryChecker is implemented by the bridges whose swap tx can not be included after its expiry height
+// (eg. ripple `LastLedgerSequence`), such swap tx can never succeed once the expiry height is passed
+type swapExpiryChecker interface {
+	IsTransactionExpired(txHash string, expiryHeight uint64) (bool, error)
+}
+
 func findRouterSwapResultsToStable() ([]*mongodb.MgoSwapResult, error) {
 	septime := getSepTimeInFind(maxStableLifetime)
 	return mongodb.FindRouterSwapResultsWithStatus(mongodb.MatchTxNotStable, septime)
@@ -164,6 +176,9 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 		if swap.SwapHeight != 0 {
 			return nil
 		}
+		if expired, errf := checkIfSwapTxHasExpired(resBridge, swap); errf != nil || expired {
+			return errf
+		}
 		return checkIfSwapNonceHasPassed(resBridge, swap, false)
 	}
 
@@ -180,6 +195,22 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 				"swaptime", swap.Timestamp, "nowtime", now())
 			return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
 		}
+		if checker, ok := resBridge.(ibcTransferChecker); ok {
+			pending, refunded, errf := checker.CheckIBCTransfers(swap.SwapTx)
+			if errf != nil {
+				return errf
+			}
+			if pending {
+				logWorker("stable", "wait ibc transfer to be relayed back",
+					"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swap.SwapTx)
+				return nil
+			}
+			if refunded {
+				logWorker("stable", "mark swap result failed as ibc transfer is refunded",
+					"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex, "swaptx", swap.SwapTx)
+				return markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
+			}
+		}
 		return markSwapResultStable(swap.FromChainID, swap.TxID, swap.LogIndex)
 	}
 
@@ -192,3 +223,23 @@ func processRouterSwapStable(swap *mongodb.MgoSwapResult) (err error) {
 	}
 	return updateRouterSwapResult(swap.FromChainID, swap.TxID, swap.LogIndex, matchTx)
 }
+
+// checkIfSwapTxHasExpired mark the swap result failed if its swap tx is expired,
+// so that it can be reswapped instead of waiting a tx which can never succeed
+func checkIfSwapTxHasExpired(bridge tokens.IBridge, swap *mongodb.MgoSwapResult) (bool, error) {
+	if swap.SwapExpiry == 0 || swap.SwapTx == "" {
+		return false, nil
+	}
+	checker, ok := bridge.(swapExpiryChecker)
+	if !ok {
+		return false, nil
+	}
+	expired, err := checker.IsTransactionExpired(swap.SwapTx, swap.SwapExpiry)
+	if err != nil || !expired {
+		return false, err
+	}
+	logWorker("stable", "mark swap result failed as swap tx is expired",
+		"fromChainID", swap.FromChainID, "txid", swap.TxID, "logIndex", swap.LogIndex,
+		"swaptx", swap.SwapTx, "swapexpiry", swap.SwapExpiry)
+	return true, markSwapResultFailed(swap.FromChainID, swap.TxID, swap.LogIndex)
+}
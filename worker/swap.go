@@ -39,6 +39,7 @@ var (
 	errAlreadySwapped     = errors.New("already swapped")
 	errSendTxWithDiffHash = errors.New("send tx with different hash")
 	errChainIsPaused      = errors.New("from or to chain is paused")
+	errIBCTransferPending = errors.New("ibc transfer is not relayed back yet")
 )
 
 // StartSwapJob swap job
@@ -427,6 +428,9 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		SwapValue: args.SwapValue.String(),
 		MPC:       args.From,
 	}
+	if args.Extra != nil && args.Extra.ExpiryHeight != nil {
+		matchTx.SwapExpiry = *args.Extra.ExpiryHeight
+	}
 	err = updateRouterSwapResult(fromChainID, txid, logIndex, matchTx)
 	if err != nil {
 		logWorkerError("doSwap", "update router swap result failed", err, "fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex, "swapNonce", swapTxNonce)
@@ -441,7 +445,11 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	}
 
 	start = time.Now()
-	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
+	sentTxHash, err := sendSignedTransactionWithRebuild(resBridge, rawTx, signedTx, args, func(newTxHash string) error {
+		txHash = newTxHash
+		addSwapHistory(fromChainID, txid, logIndex, txHash)
+		return updateSwapTx(fromChainID, txid, logIndex, txHash)
+	})
 	if err == nil && txHash != sentTxHash {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,
@@ -528,7 +536,11 @@ func signAndSendTx(rawTx interface{}, args *tokens.BuildTxArgs) error {
 	_ = updateSwapTx(fromChainID, txid, logIndex, txHash)
 
 	start = time.Now()
-	sentTxHash, err := sendSignedTransaction(resBridge, signedTx, args)
+	sentTxHash, err := sendSignedTransactionWithRebuild(resBridge, rawTx, signedTx, args, func(newTxHash string) error {
+		txHash = newTxHash
+		addSwapHistory(fromChainID, txid, logIndex, txHash)
+		return updateSwapTx(fromChainID, txid, logIndex, txHash)
+	})
 	if err == nil && txHash != sentTxHash {
 		logWorkerError("doSwap", "send tx success but with different hash", errSendTxWithDiffHash,
 			"fromChainID", fromChainID, "toChainID", toChainID, "txid", txid, "logIndex", logIndex,